@@ -0,0 +1,148 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package backup
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
+)
+
+//go:generate counterfeiter -o fakes/fake_listener.go . Listener
+type Listener interface {
+	Starting()
+	InstancesToBackUp(instances []string)
+	InstanceBackupStarting(instance string, index, totalInstances int)
+	InstanceBackupStartResult(status services.BackupOperationType)
+	WaitingFor(instance string, boshTaskId int)
+	InstanceBackedUp(instance string, result string)
+	Progress(pollingInterval time.Duration, summary Summary, toRetryCount int)
+	Finished(summary Summary)
+}
+
+//go:generate counterfeiter -o fakes/fake_broker_services.go . BrokerServices
+type BrokerServices interface {
+	Instances() ([]string, error)
+	BackupInstance(instance string) (services.BackupOperation, error)
+	LastOperation(instance string, operationData broker.OperationData) (brokerapi.LastOperation, error)
+}
+
+type Summary struct {
+	BackedUp        int
+	Orphans         int
+	Deleted         int
+	NotConfigured   int
+	FailedInstances []string
+}
+
+type backupAll struct {
+	brokerServices  BrokerServices
+	pollingInterval time.Duration
+	listener        Listener
+}
+
+func New(brokerServices BrokerServices, pollingInterval int, listener Listener) backupAll {
+	return backupAll{
+		brokerServices:  brokerServices,
+		pollingInterval: time.Duration(pollingInterval) * time.Second,
+		listener:        listener,
+	}
+}
+
+// BackupAll backs up every instance in turn. A failed backup does not stop the others, but is
+// reported in the returned error once the rest of the fleet has been backed up.
+func (b backupAll) BackupAll() error {
+	var summary Summary
+
+	b.listener.Starting()
+
+	instancesToBackUp, err := b.brokerServices.Instances()
+	if err != nil {
+		return fmt.Errorf("error listing service instances: %s", err)
+	}
+
+	b.listener.InstancesToBackUp(instancesToBackUp)
+
+	for len(instancesToBackUp) > 0 {
+		instancesToBackUp = b.backUpInstances(instancesToBackUp, &summary)
+		retryCount := len(instancesToBackUp)
+
+		b.listener.Progress(b.pollingInterval, summary, retryCount)
+		if retryCount > 0 {
+			time.Sleep(b.pollingInterval)
+		}
+	}
+
+	b.listener.Finished(summary)
+
+	if len(summary.FailedInstances) > 0 {
+		return fmt.Errorf("backup failed for service instances: %s", strings.Join(summary.FailedInstances, ", "))
+	}
+	return nil
+}
+
+func (b backupAll) backUpInstances(instances []string, summary *Summary) []string {
+	var idsToRetry []string
+
+	instanceCount := len(instances)
+	for i, instance := range instances {
+		b.listener.InstanceBackupStarting(instance, i, instanceCount)
+		operation, err := b.brokerServices.BackupInstance(instance)
+		if err != nil {
+			b.listener.InstanceBackedUp(instance, fmt.Sprintf("failure: %s", err))
+			summary.FailedInstances = append(summary.FailedInstances, instance)
+			continue
+		}
+
+		b.listener.InstanceBackupStartResult(operation.Type)
+
+		switch operation.Type {
+		case services.BackupOrphanDeployment:
+			summary.Orphans++
+		case services.BackupInstanceNotFound:
+			summary.Deleted++
+		case services.BackupNotConfigured:
+			summary.NotConfigured++
+		case services.BackupOperationInProgress:
+			idsToRetry = append(idsToRetry, instance)
+		case services.BackupAccepted:
+			if err := b.pollLastOperation(instance, operation.Data); err != nil {
+				b.listener.InstanceBackedUp(instance, fmt.Sprintf("failure: %s", err))
+				summary.FailedInstances = append(summary.FailedInstances, instance)
+				continue
+			}
+			b.listener.InstanceBackedUp(instance, "success")
+			summary.BackedUp++
+		}
+	}
+
+	return idsToRetry
+}
+
+func (b backupAll) pollLastOperation(instance string, data broker.OperationData) error {
+	b.listener.WaitingFor(instance, data.BoshTaskID)
+
+	for {
+		time.Sleep(b.pollingInterval)
+
+		lastOperation, err := b.brokerServices.LastOperation(instance, data)
+		if err != nil {
+			return fmt.Errorf("error getting last operation: %s", err)
+		}
+
+		switch lastOperation.State {
+		case brokerapi.Failed:
+			return fmt.Errorf("bosh task id %d: %s", data.BoshTaskID, lastOperation.Description)
+		case brokerapi.Succeeded:
+			return nil
+		}
+	}
+}
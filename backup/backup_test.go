@@ -0,0 +1,157 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package backup_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/backup"
+	"github.com/pivotal-cf/on-demand-service-broker/backup/fakes"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
+)
+
+var _ = Describe("Backing up all instances", func() {
+	const pollingInterval = 0
+
+	var (
+		actualErr      error
+		fakeListener   *fakes.FakeListener
+		brokerServices *fakes.FakeBrokerServices
+
+		backupAccepted = services.BackupOperation{
+			Type: services.BackupAccepted,
+			Data: broker.OperationData{BoshTaskID: 42, OperationType: broker.OperationTypeBackup},
+		}
+	)
+
+	BeforeEach(func() {
+		fakeListener = new(fakes.FakeListener)
+		brokerServices = new(fakes.FakeBrokerServices)
+		brokerServices.InstancesReturns([]string{"one", "two"}, nil)
+		brokerServices.BackupInstanceReturns(backupAccepted, nil)
+		brokerServices.LastOperationReturns(brokerapi.LastOperation{State: brokerapi.Succeeded}, nil)
+	})
+
+	JustBeforeEach(func() {
+		actualErr = backup.New(brokerServices, pollingInterval, fakeListener).BackupAll()
+	})
+
+	It("backs up each instance and waits for its backup to finish", func() {
+		Expect(actualErr).NotTo(HaveOccurred())
+		Expect(brokerServices.BackupInstanceCallCount()).To(Equal(2))
+		Expect(brokerServices.BackupInstanceArgsForCall(0)).To(Equal("one"))
+		Expect(brokerServices.BackupInstanceArgsForCall(1)).To(Equal("two"))
+
+		actualInstance, actualOperationData := brokerServices.LastOperationArgsForCall(0)
+		Expect(actualInstance).To(Equal("one"))
+		Expect(actualOperationData).To(Equal(backupAccepted.Data))
+	})
+
+	It("reports progress to the listener", func() {
+		Expect(fakeListener.StartingCallCount()).To(Equal(1))
+		Expect(fakeListener.InstancesToBackUpArgsForCall(0)).To(Equal([]string{"one", "two"}))
+
+		instance, index, total := fakeListener.InstanceBackupStartingArgsForCall(1)
+		Expect(instance).To(Equal("two"))
+		Expect(index).To(Equal(1))
+		Expect(total).To(Equal(2))
+
+		Expect(fakeListener.InstanceBackupStartResultArgsForCall(0)).To(Equal(services.BackupAccepted))
+
+		waitingInstance, taskID := fakeListener.WaitingForArgsForCall(0)
+		Expect(waitingInstance).To(Equal("one"))
+		Expect(taskID).To(Equal(42))
+
+		backedUpInstance, result := fakeListener.InstanceBackedUpArgsForCall(0)
+		Expect(backedUpInstance).To(Equal("one"))
+		Expect(result).To(Equal("success"))
+
+		Expect(fakeListener.FinishedArgsForCall(0)).To(Equal(backup.Summary{BackedUp: 2}))
+	})
+
+	Context("when a backup is in progress", func() {
+		BeforeEach(func() {
+			brokerServices.LastOperationReturnsOnCall(0, brokerapi.LastOperation{State: brokerapi.InProgress}, nil)
+		})
+
+		It("polls until it finishes", func() {
+			Expect(actualErr).NotTo(HaveOccurred())
+			Expect(brokerServices.LastOperationCallCount()).To(Equal(3))
+		})
+	})
+
+	Context("when an instance has an operation in progress", func() {
+		BeforeEach(func() {
+			brokerServices.BackupInstanceReturnsOnCall(0, services.BackupOperation{Type: services.BackupOperationInProgress}, nil)
+		})
+
+		It("retries it after the other instances", func() {
+			Expect(actualErr).NotTo(HaveOccurred())
+			Expect(brokerServices.BackupInstanceCallCount()).To(Equal(3))
+			Expect(brokerServices.BackupInstanceArgsForCall(2)).To(Equal("one"))
+
+			_, summary, retryCount := fakeListener.ProgressArgsForCall(0)
+			Expect(summary.BackedUp).To(Equal(1))
+			Expect(retryCount).To(Equal(1))
+		})
+	})
+
+	Context("when instances cannot be backed up", func() {
+		BeforeEach(func() {
+			brokerServices.InstancesReturns([]string{"orphan", "deleted", "no-errand"}, nil)
+			brokerServices.BackupInstanceReturnsOnCall(0, services.BackupOperation{Type: services.BackupOrphanDeployment}, nil)
+			brokerServices.BackupInstanceReturnsOnCall(1, services.BackupOperation{Type: services.BackupInstanceNotFound}, nil)
+			brokerServices.BackupInstanceReturnsOnCall(2, services.BackupOperation{Type: services.BackupNotConfigured}, nil)
+		})
+
+		It("counts them without failing", func() {
+			Expect(actualErr).NotTo(HaveOccurred())
+			Expect(brokerServices.LastOperationCallCount()).To(BeZero())
+			Expect(fakeListener.FinishedArgsForCall(0)).To(Equal(backup.Summary{Orphans: 1, Deleted: 1, NotConfigured: 1}))
+		})
+	})
+
+	Context("when a backup fails", func() {
+		BeforeEach(func() {
+			brokerServices.LastOperationReturnsOnCall(0, brokerapi.LastOperation{State: brokerapi.Failed, Description: "Instance backup failed"}, nil)
+		})
+
+		It("backs up the remaining instances and returns an error naming the failure", func() {
+			Expect(brokerServices.BackupInstanceCallCount()).To(Equal(2))
+			Expect(actualErr).To(MatchError("backup failed for service instances: one"))
+
+			_, result := fakeListener.InstanceBackedUpArgsForCall(0)
+			Expect(result).To(Equal("failure: bosh task id 42: Instance backup failed"))
+			Expect(fakeListener.FinishedArgsForCall(0)).To(Equal(backup.Summary{BackedUp: 1, FailedInstances: []string{"one"}}))
+		})
+	})
+
+	Context("when a backup cannot be started", func() {
+		BeforeEach(func() {
+			brokerServices.BackupInstanceReturnsOnCall(1, services.BackupOperation{}, errors.New("unexpected status code: 500"))
+		})
+
+		It("records the failure", func() {
+			Expect(actualErr).To(MatchError("backup failed for service instances: two"))
+		})
+	})
+
+	Context("when listing instances fails", func() {
+		BeforeEach(func() {
+			brokerServices.InstancesReturns(nil, errors.New("bad status code"))
+		})
+
+		It("returns an error", func() {
+			Expect(actualErr).To(MatchError("error listing service instances: bad status code"))
+			Expect(brokerServices.BackupInstanceCallCount()).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,298 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pivotal-cf/on-demand-service-broker/backup"
+	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
+)
+
+type FakeListener struct {
+	StartingStub                 func()
+	startingMutex                sync.RWMutex
+	startingArgsForCall          []struct{}
+	InstancesToBackUpStub        func(instances []string)
+	instancesToBackUpMutex       sync.RWMutex
+	instancesToBackUpArgsForCall []struct {
+		instances []string
+	}
+	InstanceBackupStartingStub        func(instance string, index, totalInstances int)
+	instanceBackupStartingMutex       sync.RWMutex
+	instanceBackupStartingArgsForCall []struct {
+		instance       string
+		index          int
+		totalInstances int
+	}
+	InstanceBackupStartResultStub        func(status services.BackupOperationType)
+	instanceBackupStartResultMutex       sync.RWMutex
+	instanceBackupStartResultArgsForCall []struct {
+		status services.BackupOperationType
+	}
+	WaitingForStub        func(instance string, boshTaskId int)
+	waitingForMutex       sync.RWMutex
+	waitingForArgsForCall []struct {
+		instance   string
+		boshTaskId int
+	}
+	InstanceBackedUpStub        func(instance string, result string)
+	instanceBackedUpMutex       sync.RWMutex
+	instanceBackedUpArgsForCall []struct {
+		instance string
+		result   string
+	}
+	ProgressStub        func(pollingInterval time.Duration, summary backup.Summary, toRetryCount int)
+	progressMutex       sync.RWMutex
+	progressArgsForCall []struct {
+		pollingInterval time.Duration
+		summary         backup.
+				Summary
+		toRetryCount int
+	}
+	FinishedStub        func(summary backup.Summary)
+	finishedMutex       sync.RWMutex
+	finishedArgsForCall []struct {
+		summary backup.
+			Summary
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeListener) Starting() {
+	fake.startingMutex.Lock()
+	fake.startingArgsForCall = append(fake.startingArgsForCall, struct{}{})
+	fake.recordInvocation("Starting", []interface{}{})
+	fake.startingMutex.Unlock()
+	if fake.StartingStub != nil {
+		fake.StartingStub()
+	}
+}
+
+func (fake *FakeListener) StartingCallCount() int {
+	fake.startingMutex.RLock()
+	defer fake.startingMutex.RUnlock()
+	return len(fake.startingArgsForCall)
+}
+
+func (fake *FakeListener) InstancesToBackUp(instances []string) {
+	var instancesCopy []string
+	if instances != nil {
+		instancesCopy = make([]string, len(instances))
+		copy(instancesCopy, instances)
+	}
+	fake.instancesToBackUpMutex.Lock()
+	fake.instancesToBackUpArgsForCall = append(fake.instancesToBackUpArgsForCall, struct {
+		instances []string
+	}{instancesCopy})
+	fake.recordInvocation("InstancesToBackUp", []interface{}{instancesCopy})
+	fake.instancesToBackUpMutex.Unlock()
+	if fake.InstancesToBackUpStub != nil {
+		fake.InstancesToBackUpStub(instances)
+	}
+}
+
+func (fake *FakeListener) InstancesToBackUpCallCount() int {
+	fake.instancesToBackUpMutex.RLock()
+	defer fake.instancesToBackUpMutex.RUnlock()
+	return len(fake.instancesToBackUpArgsForCall)
+}
+
+func (fake *FakeListener) InstancesToBackUpArgsForCall(i int) []string {
+	fake.instancesToBackUpMutex.RLock()
+	defer fake.instancesToBackUpMutex.RUnlock()
+	return fake.instancesToBackUpArgsForCall[i].instances
+}
+
+func (fake *FakeListener) InstanceBackupStarting(instance string, index int, totalInstances int) {
+	fake.instanceBackupStartingMutex.Lock()
+	fake.instanceBackupStartingArgsForCall = append(fake.instanceBackupStartingArgsForCall, struct {
+		instance       string
+		index          int
+		totalInstances int
+	}{instance, index, totalInstances})
+	fake.recordInvocation("InstanceBackupStarting", []interface{}{instance, index, totalInstances})
+	fake.instanceBackupStartingMutex.Unlock()
+	if fake.InstanceBackupStartingStub != nil {
+		fake.InstanceBackupStartingStub(instance, index, totalInstances)
+	}
+}
+
+func (fake *FakeListener) InstanceBackupStartingCallCount() int {
+	fake.instanceBackupStartingMutex.RLock()
+	defer fake.instanceBackupStartingMutex.RUnlock()
+	return len(fake.instanceBackupStartingArgsForCall)
+}
+
+func (fake *FakeListener) InstanceBackupStartingArgsForCall(i int) (string, int, int) {
+	fake.instanceBackupStartingMutex.RLock()
+	defer fake.instanceBackupStartingMutex.RUnlock()
+	return fake.instanceBackupStartingArgsForCall[i].instance, fake.instanceBackupStartingArgsForCall[i].index, fake.instanceBackupStartingArgsForCall[i].totalInstances
+}
+
+func (fake *FakeListener) InstanceBackupStartResult(status services.BackupOperationType) {
+	fake.instanceBackupStartResultMutex.Lock()
+	fake.instanceBackupStartResultArgsForCall = append(fake.instanceBackupStartResultArgsForCall, struct {
+		status services.BackupOperationType
+	}{status})
+	fake.recordInvocation("InstanceBackupStartResult", []interface{}{status})
+	fake.instanceBackupStartResultMutex.Unlock()
+	if fake.InstanceBackupStartResultStub != nil {
+		fake.InstanceBackupStartResultStub(status)
+	}
+}
+
+func (fake *FakeListener) InstanceBackupStartResultCallCount() int {
+	fake.instanceBackupStartResultMutex.RLock()
+	defer fake.instanceBackupStartResultMutex.RUnlock()
+	return len(fake.instanceBackupStartResultArgsForCall)
+}
+
+func (fake *FakeListener) InstanceBackupStartResultArgsForCall(i int) services.BackupOperationType {
+	fake.instanceBackupStartResultMutex.RLock()
+	defer fake.instanceBackupStartResultMutex.RUnlock()
+	return fake.instanceBackupStartResultArgsForCall[i].status
+}
+
+func (fake *FakeListener) WaitingFor(instance string, boshTaskId int) {
+	fake.waitingForMutex.Lock()
+	fake.waitingForArgsForCall = append(fake.waitingForArgsForCall, struct {
+		instance   string
+		boshTaskId int
+	}{instance, boshTaskId})
+	fake.recordInvocation("WaitingFor", []interface{}{instance, boshTaskId})
+	fake.waitingForMutex.Unlock()
+	if fake.WaitingForStub != nil {
+		fake.WaitingForStub(instance, boshTaskId)
+	}
+}
+
+func (fake *FakeListener) WaitingForCallCount() int {
+	fake.waitingForMutex.RLock()
+	defer fake.waitingForMutex.RUnlock()
+	return len(fake.waitingForArgsForCall)
+}
+
+func (fake *FakeListener) WaitingForArgsForCall(i int) (string, int) {
+	fake.waitingForMutex.RLock()
+	defer fake.waitingForMutex.RUnlock()
+	return fake.waitingForArgsForCall[i].instance, fake.waitingForArgsForCall[i].boshTaskId
+}
+
+func (fake *FakeListener) InstanceBackedUp(instance string, result string) {
+	fake.instanceBackedUpMutex.Lock()
+	fake.instanceBackedUpArgsForCall = append(fake.instanceBackedUpArgsForCall, struct {
+		instance string
+		result   string
+	}{instance, result})
+	fake.recordInvocation("InstanceBackedUp", []interface{}{instance, result})
+	fake.instanceBackedUpMutex.Unlock()
+	if fake.InstanceBackedUpStub != nil {
+		fake.InstanceBackedUpStub(instance, result)
+	}
+}
+
+func (fake *FakeListener) InstanceBackedUpCallCount() int {
+	fake.instanceBackedUpMutex.RLock()
+	defer fake.instanceBackedUpMutex.RUnlock()
+	return len(fake.instanceBackedUpArgsForCall)
+}
+
+func (fake *FakeListener) InstanceBackedUpArgsForCall(i int) (string, string) {
+	fake.instanceBackedUpMutex.RLock()
+	defer fake.instanceBackedUpMutex.RUnlock()
+	return fake.instanceBackedUpArgsForCall[i].instance, fake.instanceBackedUpArgsForCall[i].result
+}
+
+func (fake *FakeListener) Progress(pollingInterval time.Duration, summary backup.
+	Summary, toRetryCount int) {
+	fake.progressMutex.Lock()
+	fake.progressArgsForCall = append(fake.progressArgsForCall, struct {
+		pollingInterval time.Duration
+		summary         backup.
+				Summary
+		toRetryCount int
+	}{pollingInterval, summary, toRetryCount})
+	fake.recordInvocation("Progress", []interface{}{pollingInterval, summary, toRetryCount})
+	fake.progressMutex.Unlock()
+	if fake.ProgressStub != nil {
+		fake.ProgressStub(pollingInterval, summary, toRetryCount)
+	}
+}
+
+func (fake *FakeListener) ProgressCallCount() int {
+	fake.progressMutex.RLock()
+	defer fake.progressMutex.RUnlock()
+	return len(fake.progressArgsForCall)
+}
+
+func (fake *FakeListener) ProgressArgsForCall(i int) (time.Duration, backup.
+	Summary, int) {
+	fake.progressMutex.RLock()
+	defer fake.progressMutex.RUnlock()
+	return fake.progressArgsForCall[i].pollingInterval, fake.progressArgsForCall[i].summary, fake.progressArgsForCall[i].toRetryCount
+}
+
+func (fake *FakeListener) Finished(summary backup.
+	Summary) {
+	fake.finishedMutex.Lock()
+	fake.finishedArgsForCall = append(fake.finishedArgsForCall, struct {
+		summary backup.
+			Summary
+	}{summary})
+	fake.recordInvocation("Finished", []interface{}{summary})
+	fake.finishedMutex.Unlock()
+	if fake.FinishedStub != nil {
+		fake.FinishedStub(summary)
+	}
+}
+
+func (fake *FakeListener) FinishedCallCount() int {
+	fake.finishedMutex.RLock()
+	defer fake.finishedMutex.RUnlock()
+	return len(fake.finishedArgsForCall)
+}
+
+func (fake *FakeListener) FinishedArgsForCall(i int) backup.
+	Summary {
+	fake.finishedMutex.RLock()
+	defer fake.finishedMutex.RUnlock()
+	return fake.finishedArgsForCall[i].summary
+}
+
+func (fake *FakeListener) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.startingMutex.RLock()
+	defer fake.startingMutex.RUnlock()
+	fake.instancesToBackUpMutex.RLock()
+	defer fake.instancesToBackUpMutex.RUnlock()
+	fake.instanceBackupStartingMutex.RLock()
+	defer fake.instanceBackupStartingMutex.RUnlock()
+	fake.instanceBackupStartResultMutex.RLock()
+	defer fake.instanceBackupStartResultMutex.RUnlock()
+	fake.waitingForMutex.RLock()
+	defer fake.waitingForMutex.RUnlock()
+	fake.instanceBackedUpMutex.RLock()
+	defer fake.instanceBackedUpMutex.RUnlock()
+	fake.progressMutex.RLock()
+	defer fake.progressMutex.RUnlock()
+	fake.finishedMutex.RLock()
+	defer fake.finishedMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeListener) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ backup.Listener = new(FakeListener)
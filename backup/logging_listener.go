@@ -0,0 +1,108 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package backup
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
+)
+
+type LoggingListener struct {
+	logger *log.Logger
+}
+
+func NewLoggingListener(logger *log.Logger) Listener {
+	return LoggingListener{logger: logger}
+}
+
+func (ll LoggingListener) Starting() {
+	ll.logger.Println("STARTING BACKUPS")
+}
+
+func (ll LoggingListener) InstancesToBackUp(instances []string) {
+	msg := "Service Instances:"
+	for _, instance := range instances {
+		msg = fmt.Sprintf("%s %s", msg, instance)
+	}
+	ll.logger.Println(msg)
+	ll.logger.Printf("Total Service Instances found in Cloud Foundry: %d\n", len(instances))
+}
+
+func (ll LoggingListener) InstanceBackupStarting(instance string, index, totalInstances int) {
+	ll.logger.Printf("Service instance: %s, backup attempt starting (%d of %d)", instance, index+1, totalInstances)
+}
+
+func (ll LoggingListener) InstanceBackupStartResult(resultType services.BackupOperationType) {
+	var message string
+
+	switch resultType {
+	case services.BackupAccepted:
+		message = "accepted backup"
+	case services.BackupInstanceNotFound:
+		message = "already deleted in CF"
+	case services.BackupOrphanDeployment:
+		message = "orphan CF service instance detected - no corresponding bosh deployment"
+	case services.BackupOperationInProgress:
+		message = "operation in progress"
+	case services.BackupNotConfigured:
+		message = "plan has no backup errand"
+	default:
+		message = "unexpected result"
+	}
+
+	ll.logger.Printf("Result: %s", message)
+}
+
+func (ll LoggingListener) WaitingFor(instance string, boshTaskId int) {
+	ll.logger.Printf("Waiting for backup to complete for %s: bosh task id %d", instance, boshTaskId)
+}
+
+func (ll LoggingListener) InstanceBackedUp(instance string, result string) {
+	ll.logger.Printf("Result: Service Instance %s backup %s\n", instance, result)
+}
+
+func (ll LoggingListener) Progress(pollingInterval time.Duration, summary Summary, toRetryCount int) {
+	ll.logger.Printf("Backup progress summary: "+
+		"Sleep interval until next attempt: %s; "+
+		"Number of successful backups so far: %d; "+
+		"Number of failed backups so far: %d; "+
+		"Number of instances without a backup errand so far: %d; "+
+		"Number of CF service instance orphans detected so far: %d; "+
+		"Number of deleted instances before backup could occur: %d; "+
+		"Number of operations in progress (to retry) so far: %d",
+		pollingInterval,
+		summary.BackedUp,
+		len(summary.FailedInstances),
+		summary.NotConfigured,
+		summary.Orphans,
+		summary.Deleted,
+		toRetryCount,
+	)
+}
+
+func (ll LoggingListener) Finished(summary Summary) {
+	ll.logger.Printf("FINISHED BACKUPS Summary: "+
+		"Number of successful backups: %d; "+
+		"Number of failed backups: %d; "+
+		"Number of instances without a backup errand: %d; "+
+		"Number of CF service instance orphans detected: %d; "+
+		"Number of deleted instances before backup could occur: %d",
+		summary.BackedUp,
+		len(summary.FailedInstances),
+		summary.NotConfigured,
+		summary.Orphans,
+		summary.Deleted,
+	)
+
+	if len(summary.FailedInstances) > 0 {
+		ll.logger.Printf("Failed backups: %s", strings.Join(summary.FailedInstances, ", "))
+	}
+}
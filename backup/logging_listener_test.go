@@ -0,0 +1,106 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package backup_test
+
+import (
+	"io"
+	"log"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/on-demand-service-broker/backup"
+	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+)
+
+var _ = Describe("Logging Listener", func() {
+	It("shows starting message", func() {
+		Expect(logResultsFrom(func(listener backup.Listener) { listener.Starting() })).
+			To(Say("STARTING BACKUPS"))
+	})
+
+	It("shows which instances to back up", func() {
+		Expect(logResultsFrom(func(listener backup.Listener) { listener.InstancesToBackUp([]string{"one", "two"}) })).
+			To(Say("Service Instances: one two"))
+	})
+
+	It("shows which instance has started backing up", func() {
+		buffer := logResultsFrom(func(listener backup.Listener) {
+			listener.InstanceBackupStarting("service-instance", 1, 5)
+		})
+
+		Expect(buffer).To(Say("Service instance: service-instance, backup attempt starting \\(2 of 5\\)"))
+	})
+
+	DescribeTable("instance backup start result",
+		func(result services.BackupOperationType, expectedMessage string) {
+			buffer := logResultsFrom(func(listener backup.Listener) {
+				listener.InstanceBackupStartResult(result)
+			})
+
+			Expect(buffer).To(Say("Result: " + expectedMessage))
+		},
+		Entry("accepted", services.BackupAccepted, "accepted backup"),
+		Entry("not found", services.BackupInstanceNotFound, "already deleted in CF"),
+		Entry("orphan", services.BackupOrphanDeployment, "orphan CF service instance detected - no corresponding bosh deployment"),
+		Entry("in progress", services.BackupOperationInProgress, "operation in progress"),
+		Entry("not configured", services.BackupNotConfigured, "plan has no backup errand"),
+		Entry("unexpected", services.BackupOperationType(-1), "unexpected result"),
+	)
+
+	It("shows the backup result of an instance", func() {
+		buffer := logResultsFrom(func(listener backup.Listener) {
+			listener.InstanceBackedUp("service-instance", "success")
+		})
+
+		Expect(buffer).To(Say("Result: Service Instance service-instance backup success"))
+	})
+
+	It("shows progress", func() {
+		buffer := logResultsFrom(func(listener backup.Listener) {
+			listener.Progress(10*time.Second, backup.Summary{
+				BackedUp:        2,
+				Orphans:         3,
+				Deleted:         4,
+				NotConfigured:   5,
+				FailedInstances: []string{"one"},
+			}, 6)
+		})
+
+		Expect(buffer).To(Say("Sleep interval until next attempt: 10s"))
+		Expect(buffer).To(Say("Number of successful backups so far: 2"))
+		Expect(buffer).To(Say("Number of failed backups so far: 1"))
+		Expect(buffer).To(Say("Number of instances without a backup errand so far: 5"))
+		Expect(buffer).To(Say("Number of CF service instance orphans detected so far: 3"))
+		Expect(buffer).To(Say("Number of deleted instances before backup could occur: 4"))
+		Expect(buffer).To(Say("Number of operations in progress \\(to retry\\) so far: 6"))
+	})
+
+	It("shows a final summary with the failed instances", func() {
+		buffer := logResultsFrom(func(listener backup.Listener) {
+			listener.Finished(backup.Summary{BackedUp: 2, FailedInstances: []string{"one", "two"}})
+		})
+
+		Expect(buffer).To(Say("FINISHED BACKUPS"))
+		Expect(buffer).To(Say("Number of successful backups: 2"))
+		Expect(buffer).To(Say("Number of failed backups: 2"))
+		Expect(buffer).To(Say("Failed backups: one, two"))
+	})
+})
+
+func logResultsFrom(action func(listener backup.Listener)) *Buffer {
+	logBuffer := NewBuffer()
+	loggerFactory := loggerfactory.New(io.MultiWriter(GinkgoWriter, logBuffer), "logging-listener-tests", log.LstdFlags)
+	listener := backup.NewLoggingListener(loggerFactory.New())
+
+	action(listener)
+
+	return logBuffer
+}
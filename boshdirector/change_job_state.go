@@ -0,0 +1,35 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package boshdirector
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+)
+
+type JobState string
+
+const (
+	JobStateRecreate = JobState("recreate")
+	JobStateRestart  = JobState("restart")
+	JobStateStopped  = JobState("stopped")
+	JobStateStarted  = JobState("started")
+)
+
+func (c *Client) ChangeJobState(deploymentName string, state JobState, contextID string, logger *log.Logger) (int, error) {
+	logger.Printf("changing state of all jobs in deployment %s to %s\n", deploymentName, state)
+
+	return c.putAndGetTaskIDCheckingForErrors(
+		fmt.Sprintf("%s/deployments/%s/jobs/*?state=%s", c.url, deploymentName, state),
+		http.StatusFound,
+		nil,
+		"text/yaml",
+		contextID,
+		logger,
+	)
+}
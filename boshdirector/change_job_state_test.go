@@ -0,0 +1,97 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package boshdirector_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/mockhttp/mockbosh"
+)
+
+var _ = Describe("changing the state of a deployment's jobs", func() {
+	const (
+		deploymentName = "some-deployment"
+		taskID         = 5
+	)
+
+	var (
+		state          boshdirector.JobState
+		returnedTaskID int
+		changeErr      error
+	)
+
+	BeforeEach(func() {
+		state = boshdirector.JobStateRecreate
+	})
+
+	JustBeforeEach(func() {
+		returnedTaskID, changeErr = c.ChangeJobState(deploymentName, state, "", logger)
+	})
+
+	Context("when the director accepts the request", func() {
+		BeforeEach(func() {
+			director.VerifyAndMock(
+				mockbosh.ChangeJobState(deploymentName, "recreate").RedirectsToTask(taskID),
+			)
+		})
+
+		It("returns the bosh task ID", func() {
+			Expect(changeErr).NotTo(HaveOccurred())
+			Expect(returnedTaskID).To(Equal(taskID))
+		})
+	})
+
+	Context("when stopping the jobs", func() {
+		BeforeEach(func() {
+			state = boshdirector.JobStateStopped
+			director.VerifyAndMock(
+				mockbosh.ChangeJobState(deploymentName, "stopped").RedirectsToTask(taskID),
+			)
+		})
+
+		It("requests the stopped state", func() {
+			Expect(changeErr).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("when the deployment does not exist", func() {
+		BeforeEach(func() {
+			director.VerifyAndMock(
+				mockbosh.ChangeJobState(deploymentName, "recreate").RespondsNotFoundWith(""),
+			)
+		})
+
+		It("returns a deployment not found error", func() {
+			Expect(changeErr).To(BeAssignableToTypeOf(boshdirector.DeploymentNotFoundError{}))
+		})
+	})
+
+	Context("when the director responds with an error", func() {
+		BeforeEach(func() {
+			director.VerifyAndMock(
+				mockbosh.ChangeJobState(deploymentName, "recreate").RespondsInternalServerErrorWith("because reasons"),
+			)
+		})
+
+		It("returns an error", func() {
+			Expect(changeErr).To(MatchError(ContainSubstring("expected status 302, was 500")))
+		})
+	})
+
+	Context("when the authorization header cannot be built", func() {
+		BeforeEach(func() {
+			authHeaderBuilder.BuildReturns("", errors.New("some-error"))
+		})
+
+		It("returns an error", func() {
+			Expect(changeErr).To(MatchError(ContainSubstring("some-error")))
+		})
+	})
+})
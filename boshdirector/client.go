@@ -27,8 +27,9 @@ type Client struct {
 
 	PollingInterval time.Duration
 
-	authHeaderBuilder AuthHeaderBuilder
-	httpClient        HTTPClient
+	authHeaderBuilder      AuthHeaderBuilder
+	teamAuthHeaderBuilders map[string]AuthHeaderBuilder
+	httpClient             HTTPClient
 }
 
 //go:generate counterfeiter -o fakes/fake_auth_header_builder.go . AuthHeaderBuilder
@@ -59,6 +60,24 @@ func New(url string, authHeaderBuilder AuthHeaderBuilder, disableSSLCertVerifica
 	}, nil
 }
 
+// RegisterTeam configures the identity used to act on behalf of a BOSH team
+func (c *Client) RegisterTeam(team string, authHeaderBuilder AuthHeaderBuilder) {
+	if c.teamAuthHeaderBuilders == nil {
+		c.teamAuthHeaderBuilders = map[string]AuthHeaderBuilder{}
+	}
+	c.teamAuthHeaderBuilders[team] = authHeaderBuilder
+}
+
+func (c *Client) forTeam(team string) (*Client, error) {
+	authHeaderBuilder, found := c.teamAuthHeaderBuilders[team]
+	if !found {
+		return nil, fmt.Errorf("no credentials configured for BOSH team %s", team)
+	}
+	teamClient := *c
+	teamClient.authHeaderBuilder = authHeaderBuilder
+	return &teamClient, nil
+}
+
 type Info struct {
 	Version string
 }
@@ -108,6 +127,10 @@ type DeploymentNotFoundError struct {
 	error
 }
 
+type TaskNotFoundError struct {
+	error
+}
+
 type RequestError struct {
 	error
 }
@@ -175,6 +198,16 @@ func (c *Client) postAndGetTaskIDCheckingForErrors(url string, expectedStatus in
 	return taskId, err
 }
 
+func (c *Client) putAndGetTaskIDCheckingForErrors(url string, expectedStatus int, body []byte, contentType, contextID string, logger *log.Logger) (int, error) {
+	request, err := preparePut(url, body, contentType, contextID)
+	if err != nil {
+		return 0, err
+	}
+	var taskId int
+	err = c.getDeploymentResultCheckingForErrors(request, expectedStatus, extractTaskId(&taskId), logger)
+	return taskId, err
+}
+
 func (c *Client) deleteAndGetTaskIDCheckingForErrors(url string, contextID string, expectedStatus int, logger *log.Logger) (int, error) {
 	request, err := prepareDelete(url, contextID)
 	if err != nil {
@@ -218,7 +251,15 @@ func prepareGet(url string) (*http.Request, error) {
 }
 
 func preparePost(url string, body []byte, contentType, contextID string) (*http.Request, error) {
-	request, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	return prepareRequestWithBody("POST", url, body, contentType, contextID)
+}
+
+func preparePut(url string, body []byte, contentType, contextID string) (*http.Request, error) {
+	return prepareRequestWithBody("PUT", url, body, contentType, contextID)
+}
+
+func prepareRequestWithBody(method, url string, body []byte, contentType, contextID string) (*http.Request, error) {
+	request, err := http.NewRequest(method, url, bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,80 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package boshdirector
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+)
+
+type config struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// GetConfig returns the content of the latest config of the type with the name, if any. Directors
+// that predate the configs API have no configs.
+func (c *Client) GetConfig(configType, name string, logger *log.Logger) ([]byte, bool, error) {
+	logger.Printf("getting %s config %s from bosh", configType, name)
+
+	query := url.Values{"type": {configType}, "name": {name}, "latest": {"true"}}
+	var configs []config
+	err := c.getDataCheckingForErrors(fmt.Sprintf("%s/configs?%s", c.url, query.Encode()), http.StatusOK, &configs, logger)
+	if err, ok := err.(unexpectedStatusError); ok && err.actualStatus == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(configs) == 0 {
+		return nil, false, nil
+	}
+	return []byte(configs[0].Content), true, nil
+}
+
+// UpdateConfig stores the content as the latest config of the type with the name
+func (c *Client) UpdateConfig(configType, name string, content []byte, logger *log.Logger) error {
+	logger.Printf("updating %s config %s in bosh", configType, name)
+
+	body, err := json.Marshal(config{Type: configType, Name: name, Content: string(content)})
+	if err != nil {
+		return err
+	}
+
+	request, err := preparePost(fmt.Sprintf("%s/configs", c.url), body, "application/json", "")
+	if err != nil {
+		return err
+	}
+	return c.getResultCheckingForErrors(request, http.StatusCreated, discardBody, logger)
+}
+
+// DeleteConfig deletes every version of the config of the type with the name. Deleting a config
+// that does not exist is not an error.
+func (c *Client) DeleteConfig(configType, name string, logger *log.Logger) error {
+	logger.Printf("deleting %s config %s from bosh", configType, name)
+
+	query := url.Values{"type": {configType}, "name": {name}}
+	request, err := prepareDelete(fmt.Sprintf("%s/configs?%s", c.url, query.Encode()), "")
+	if err != nil {
+		return err
+	}
+
+	err = c.getResultCheckingForErrors(request, http.StatusNoContent, discardBody, logger)
+	if err, ok := err.(unexpectedStatusError); ok && err.actualStatus == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+func discardBody(*http.Response) error {
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package boshdirector_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/mockhttp/mockbosh"
+)
+
+var _ = Describe("configs", func() {
+	Describe("getting a config", func() {
+		It("returns the content of the latest config", func() {
+			director.VerifyAndMock(
+				mockbosh.Config("some-type", "some-name").RespondsWithContent([]byte("name: some-deployment")),
+			)
+
+			content, found, err := c.GetConfig("some-type", "some-name", logger)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(content).To(Equal([]byte("name: some-deployment")))
+		})
+
+		It("returns not found when there is no config", func() {
+			director.VerifyAndMock(
+				mockbosh.Config("some-type", "some-name").RespondsOKWith("[]"),
+			)
+
+			_, found, err := c.GetConfig("some-type", "some-name", logger)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("returns not found when the director does not have the configs API", func() {
+			director.VerifyAndMock(
+				mockbosh.Config("some-type", "some-name").RespondsNotFoundWith(""),
+			)
+
+			_, found, err := c.GetConfig("some-type", "some-name", logger)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("returns an error when bosh fails", func() {
+			director.VerifyAndMock(
+				mockbosh.Config("some-type", "some-name").RespondsInternalServerErrorWith("because reasons"),
+			)
+
+			_, _, err := c.GetConfig("some-type", "some-name", logger)
+
+			Expect(err).To(MatchError(ContainSubstring("expected status 200, was 500")))
+		})
+	})
+
+	Describe("updating a config", func() {
+		It("posts the config", func() {
+			director.VerifyAndMock(
+				mockbosh.UpdateConfig("some-type", "some-name", []byte("name: some-deployment")).RespondsCreated(),
+			)
+
+			Expect(c.UpdateConfig("some-type", "some-name", []byte("name: some-deployment"), logger)).To(Succeed())
+		})
+
+		It("returns an error when bosh fails", func() {
+			director.VerifyAndMock(
+				mockbosh.UpdateConfig("some-type", "some-name", []byte("name: some-deployment")).RespondsInternalServerErrorWith("because reasons"),
+			)
+
+			err := c.UpdateConfig("some-type", "some-name", []byte("name: some-deployment"), logger)
+
+			Expect(err).To(MatchError(ContainSubstring("expected status 201, was 500")))
+		})
+	})
+
+	Describe("deleting a config", func() {
+		It("deletes the config", func() {
+			director.VerifyAndMock(
+				mockbosh.DeleteConfig("some-type", "some-name").RespondsNoContent(),
+			)
+
+			Expect(c.DeleteConfig("some-type", "some-name", logger)).To(Succeed())
+		})
+
+		It("succeeds when there is no such config", func() {
+			director.VerifyAndMock(
+				mockbosh.DeleteConfig("some-type", "some-name").RespondsNotFoundWith(""),
+			)
+
+			Expect(c.DeleteConfig("some-type", "some-name", logger)).To(Succeed())
+		})
+
+		It("returns an error when bosh fails", func() {
+			director.VerifyAndMock(
+				mockbosh.DeleteConfig("some-type", "some-name").RespondsInternalServerErrorWith("because reasons"),
+			)
+
+			err := c.DeleteConfig("some-type", "some-name", logger)
+
+			Expect(err).To(MatchError(ContainSubstring("expected status 204, was 500")))
+		})
+	})
+})
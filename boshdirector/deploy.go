@@ -22,3 +22,14 @@ func (c *Client) Deploy(manifest []byte, contextID string, logger *log.Logger) (
 		logger,
 	)
 }
+
+// DeployForTeam creates or updates a deployment as the team's client, so that the
+// director assigns the deployment to that team
+func (c *Client) DeployForTeam(manifest []byte, contextID, team string, logger *log.Logger) (int, error) {
+	teamClient, err := c.forTeam(team)
+	if err != nil {
+		return 0, err
+	}
+	logger.Printf("deploying as BOSH team %s", team)
+	return teamClient.Deploy(manifest, contextID, logger)
+}
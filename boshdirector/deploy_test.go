@@ -11,6 +11,7 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector/fakes"
 	"github.com/pivotal-cf/on-demand-service-broker/mockhttp/mockbosh"
 	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
 	"gopkg.in/yaml.v2"
@@ -126,3 +127,65 @@ var _ = Describe("deploying a manifest", func() {
 		})
 	})
 })
+
+var _ = Describe("deploying a manifest as a BOSH team", func() {
+	const taskID = 4
+
+	var (
+		deploymentManifestBytes = []byte("name: team-deployment")
+		teamAuthHeaderBuilder   *fakes.FakeAuthHeaderBuilder
+		team                    string
+
+		returnedTaskID int
+		deployErr      error
+	)
+
+	BeforeEach(func() {
+		team = "some-team"
+		teamAuthHeaderBuilder = new(fakes.FakeAuthHeaderBuilder)
+		teamAuthHeaderBuilder.BuildReturns(expectedAuthHeader, nil)
+	})
+
+	JustBeforeEach(func() {
+		c.RegisterTeam("some-team", teamAuthHeaderBuilder)
+		returnedTaskID, deployErr = c.DeployForTeam(deploymentManifestBytes, "", team, logger)
+	})
+
+	Context("when the team is registered", func() {
+		BeforeEach(func() {
+			director.VerifyAndMock(
+				mockbosh.Deploy().WithRawManifest(deploymentManifestBytes).RedirectsToTask(taskID),
+			)
+		})
+
+		It("authenticates as the team", func() {
+			Expect(teamAuthHeaderBuilder.BuildCallCount()).To(Equal(1))
+			Expect(authHeaderBuilder.BuildCallCount()).To(BeZero())
+		})
+
+		It("returns the bosh task ID", func() {
+			Expect(deployErr).NotTo(HaveOccurred())
+			Expect(returnedTaskID).To(Equal(taskID))
+		})
+	})
+
+	Context("when the team is not registered", func() {
+		BeforeEach(func() {
+			team = "unknown-team"
+		})
+
+		It("returns an error without deploying", func() {
+			Expect(deployErr).To(MatchError("no credentials configured for BOSH team unknown-team"))
+		})
+	})
+
+	Context("when the team authorization header cannot be built", func() {
+		BeforeEach(func() {
+			teamAuthHeaderBuilder.BuildReturns("", errors.New("team-auth-error"))
+		})
+
+		It("returns an error", func() {
+			Expect(deployErr).To(MatchError(ContainSubstring("team-auth-error")))
+		})
+	})
+})
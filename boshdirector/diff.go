@@ -0,0 +1,41 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package boshdirector
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// DeploymentDiff is the director's view of what deploying a manifest would change,
+// including changes coming from the cloud-config and runtime-configs
+type DeploymentDiff struct {
+	Diff [][]interface{} `json:"diff"`
+}
+
+func (d DeploymentDiff) HasChanges() bool {
+	for _, line := range d.Diff {
+		if len(line) > 1 && line[1] != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) GetDeploymentDiff(name string, manifest []byte, logger *log.Logger) (DeploymentDiff, error) {
+	logger.Printf("getting diff from bosh for deployment %s", name)
+
+	request, err := preparePost(fmt.Sprintf("%s/deployments/%s/diff", c.url, name), manifest, "text/yaml", "")
+	if err != nil {
+		return DeploymentDiff{}, err
+	}
+
+	var diff DeploymentDiff
+	err = c.getDeploymentResultCheckingForErrors(request, http.StatusOK, decodeJson(&diff), logger)
+	return diff, err
+}
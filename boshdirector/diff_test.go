@@ -0,0 +1,109 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package boshdirector_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/mockhttp/mockbosh"
+)
+
+var _ = Describe("getting a deployment diff", func() {
+	var (
+		deploymentName = "some-deployment"
+		manifest       = []byte("name: some-deployment")
+		diff           boshdirector.DeploymentDiff
+		diffErr        error
+	)
+
+	JustBeforeEach(func() {
+		diff, diffErr = c.GetDeploymentDiff(deploymentName, manifest, logger)
+	})
+
+	Context("when the director reports no changes", func() {
+		BeforeEach(func() {
+			director.VerifyAndMock(
+				mockbosh.Diff(deploymentName).WithRawManifest(manifest).RespondsWithNoDiff(),
+			)
+		})
+
+		It("returns a diff without changes", func() {
+			Expect(diffErr).NotTo(HaveOccurred())
+			Expect(diff.HasChanges()).To(BeFalse())
+		})
+	})
+
+	Context("when the director reports only unchanged context lines", func() {
+		BeforeEach(func() {
+			director.VerifyAndMock(
+				mockbosh.Diff(deploymentName).WithRawManifest(manifest).RespondsWithDiff(mockbosh.DeploymentDiff{
+					Diff: [][]interface{}{{"name: some-deployment", nil}},
+				}),
+			)
+		})
+
+		It("returns a diff without changes", func() {
+			Expect(diffErr).NotTo(HaveOccurred())
+			Expect(diff.HasChanges()).To(BeFalse())
+		})
+	})
+
+	Context("when the director reports changes", func() {
+		BeforeEach(func() {
+			director.VerifyAndMock(
+				mockbosh.Diff(deploymentName).WithRawManifest(manifest).RespondsWithDiff(mockbosh.DeploymentDiff{
+					Diff: [][]interface{}{
+						{"addons:", "added"},
+						{"- name: some-addon", "added"},
+					},
+				}),
+			)
+		})
+
+		It("returns a diff with changes", func() {
+			Expect(diffErr).NotTo(HaveOccurred())
+			Expect(diff.HasChanges()).To(BeTrue())
+		})
+	})
+
+	Context("when the deployment does not exist", func() {
+		BeforeEach(func() {
+			director.VerifyAndMock(
+				mockbosh.Diff(deploymentName).WithRawManifest(manifest).RespondsNotFoundWith(""),
+			)
+		})
+
+		It("returns a deployment not found error", func() {
+			Expect(diffErr).To(BeAssignableToTypeOf(boshdirector.DeploymentNotFoundError{}))
+		})
+	})
+
+	Context("when the director responds with an error", func() {
+		BeforeEach(func() {
+			director.VerifyAndMock(
+				mockbosh.Diff(deploymentName).WithRawManifest(manifest).RespondsInternalServerErrorWith("because reasons"),
+			)
+		})
+
+		It("returns an error", func() {
+			Expect(diffErr).To(MatchError(ContainSubstring("expected status 200, was 500")))
+		})
+	})
+
+	Context("when the Authorization header cannot be generated", func() {
+		BeforeEach(func() {
+			authHeaderBuilder.BuildReturns("", errors.New("some-error"))
+		})
+
+		It("returns an error", func() {
+			Expect(diffErr).To(MatchError(ContainSubstring("some-error")))
+		})
+	})
+})
@@ -15,8 +15,9 @@ import (
 func (c *Client) GetDeployments(logger *log.Logger) ([]Deployment, error) {
 	logger.Println("getting deployments from bosh")
 
+	// only names are needed, skip the per-deployment release, stemcell and config lookups
 	var deployments []Deployment
-	url := fmt.Sprintf("%s/deployments", c.url)
+	url := fmt.Sprintf("%s/deployments?exclude_configs=true&exclude_releases=true&exclude_stemcells=true", c.url)
 	if err := c.getDataCheckingForErrors(url, http.StatusOK, &deployments, logger); err != nil {
 		return nil, err
 	}
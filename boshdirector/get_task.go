@@ -8,6 +8,7 @@ package boshdirector
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 )
@@ -22,6 +23,9 @@ func (c *Client) GetTask(taskID int, logger *log.Logger) (BoshTask, error) {
 		&getTaskResponse,
 		logger,
 	); err != nil {
+		if statusErr, ok := err.(unexpectedStatusError); ok && statusErr.actualStatus == http.StatusNotFound {
+			return BoshTask{}, TaskNotFoundError{error: err}
+		}
 		return BoshTask{}, err
 	}
 
@@ -55,3 +59,45 @@ func (c *Client) GetTaskOutput(taskID int, logger *log.Logger) ([]BoshTaskOutput
 
 	return outputs, err
 }
+
+const (
+	TaskOutputTypeEvent  = "event"
+	TaskOutputTypeResult = "result"
+	TaskOutputTypeDebug  = "debug"
+)
+
+func (c *Client) StreamTaskOutput(taskID int, outputType string, writer io.Writer, logger *log.Logger) error {
+	logger.Printf("streaming %s output for task %d from bosh\n", outputType, taskID)
+
+	request, err := prepareGet(fmt.Sprintf("%s/tasks/%d/output?type=%s", c.url, taskID, outputType))
+	if err != nil {
+		return err
+	}
+
+	return c.getResultCheckingForErrors(request, http.StatusOK, func(response *http.Response) error {
+		_, err := io.Copy(writer, response.Body)
+		return err
+	}, logger)
+}
+
+// WaitForTask polls the director until the task is no longer queued or processing
+func (c *Client) WaitForTask(taskID int, logger *log.Logger) (BoshTask, error) {
+	logger.Printf("waiting for task %d to finish\n", taskID)
+	var task BoshTask
+
+	poller := &SleepingPoller{pollingInterval: c.PollingInterval}
+	err := poller.PollUntil(func() (bool, error) {
+		var err error
+		task, err = c.GetTask(taskID, logger)
+		if err != nil {
+			return false, err
+		}
+		return task.StateType() != TaskIncomplete, nil
+	})
+	if err != nil {
+		return BoshTask{}, err
+	}
+
+	logger.Printf("Task %d finished: %s\n", taskID, task.ToLog())
+	return task, nil
+}
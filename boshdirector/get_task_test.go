@@ -7,6 +7,8 @@
 package boshdirector_test
 
 import (
+	"bytes"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
@@ -55,6 +57,56 @@ var _ = Describe("get task", func() {
 				Expect(getTaskErr).To(MatchError(ContainSubstring("expected status 200, was 500")))
 			})
 		})
+
+		Context("when the task does not exist", func() {
+			BeforeEach(func() {
+				director.VerifyAndMock(
+					mockbosh.Task(taskID).RespondsNotFoundWith("task not found"),
+				)
+			})
+
+			It("returns a task not found error", func() {
+				Expect(getTaskErr).To(BeAssignableToTypeOf(boshdirector.TaskNotFoundError{}))
+			})
+		})
+	})
+
+	Context("waiting for a task", func() {
+		var (
+			finishedTask boshdirector.BoshTask
+			waitErr      error
+		)
+
+		JustBeforeEach(func() {
+			finishedTask, waitErr = c.WaitForTask(taskID, logger)
+		})
+
+		Context("when the task finishes", func() {
+			BeforeEach(func() {
+				director.VerifyAndMock(
+					mockbosh.Task(taskID).RespondsWithTaskContainingState(boshdirector.TaskQueued),
+					mockbosh.Task(taskID).RespondsWithTaskContainingState(boshdirector.TaskProcessing),
+					mockbosh.Task(taskID).RespondsWithTaskContainingState(boshdirector.TaskError),
+				)
+			})
+
+			It("returns the finished task", func() {
+				Expect(waitErr).NotTo(HaveOccurred())
+				Expect(finishedTask.State).To(Equal(boshdirector.TaskError))
+			})
+		})
+
+		Context("when the task cannot be retrieved", func() {
+			BeforeEach(func() {
+				director.VerifyAndMock(
+					mockbosh.Task(taskID).RespondsInternalServerErrorWith("because reasons"),
+				)
+			})
+
+			It("returns an error", func() {
+				Expect(waitErr).To(MatchError(ContainSubstring("expected status 200, was 500")))
+			})
+		})
 	})
 
 	Context("getting task output", func() {
@@ -112,4 +164,47 @@ var _ = Describe("get task", func() {
 			})
 		})
 	})
+
+	Context("streaming task output", func() {
+		var (
+			outputType string
+			streamed   *bytes.Buffer
+			streamErr  error
+		)
+
+		BeforeEach(func() {
+			outputType = boshdirector.TaskOutputTypeDebug
+			streamed = new(bytes.Buffer)
+		})
+
+		JustBeforeEach(func() {
+			streamErr = c.StreamTaskOutput(taskID, outputType, streamed, logger)
+		})
+
+		Context("when bosh returns the output", func() {
+			BeforeEach(func() {
+				director.VerifyAndMock(
+					mockbosh.TaskOutputOfType(taskID, "debug").RespondsOKWith("D, [2017-01-01] some debug output\n"),
+				)
+			})
+
+			It("writes the raw output", func() {
+				Expect(streamErr).NotTo(HaveOccurred())
+				Expect(streamed.String()).To(Equal("D, [2017-01-01] some debug output\n"))
+			})
+		})
+
+		Context("when bosh fails to fetch the output", func() {
+			BeforeEach(func() {
+				director.VerifyAndMock(
+					mockbosh.TaskOutputOfType(taskID, "debug").RespondsInternalServerErrorWith("because reasons"),
+				)
+			})
+
+			It("returns an error without writing any output", func() {
+				Expect(streamErr).To(MatchError(ContainSubstring("expected status 200, was 500.")))
+				Expect(streamed.Len()).To(BeZero())
+			})
+		})
+	})
 })
@@ -0,0 +1,87 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package boshdirector
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+)
+
+type Releases []Release
+
+type Release struct {
+	Name            string           `json:"name"`
+	ReleaseVersions []ReleaseVersion `json:"release_versions"`
+}
+
+type ReleaseVersion struct {
+	Version string `json:"version"`
+}
+
+type Stemcells []Stemcell
+
+type Stemcell struct {
+	Name            string `json:"name"`
+	OperatingSystem string `json:"operating_system"`
+	Version         string `json:"version"`
+}
+
+// Contains reports whether the version of the release is uploaded, any version satisfying latest
+func (r Releases) Contains(name, version string) bool {
+	for _, release := range r {
+		if release.Name != name {
+			continue
+		}
+		for _, releaseVersion := range release.ReleaseVersions {
+			if version == "latest" || releaseVersion.Version == version {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ContainsOS reports whether a stemcell of the version is uploaded for the OS, any version satisfying latest
+func (s Stemcells) ContainsOS(os, version string) bool {
+	for _, stemcell := range s {
+		if stemcell.OperatingSystem == os && (version == "latest" || stemcell.Version == version) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsName is ContainsOS for stemcells referenced by their full name
+func (s Stemcells) ContainsName(name, version string) bool {
+	for _, stemcell := range s {
+		if stemcell.Name == name && (version == "latest" || stemcell.Version == version) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) GetReleases(logger *log.Logger) (Releases, error) {
+	logger.Println("getting releases from bosh")
+
+	var releases Releases
+	if err := c.getDataCheckingForErrors(fmt.Sprintf("%s/releases", c.url), http.StatusOK, &releases, logger); err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
+
+func (c *Client) GetStemcells(logger *log.Logger) (Stemcells, error) {
+	logger.Println("getting stemcells from bosh")
+
+	var stemcells Stemcells
+	if err := c.getDataCheckingForErrors(fmt.Sprintf("%s/stemcells", c.url), http.StatusOK, &stemcells, logger); err != nil {
+		return nil, err
+	}
+	return stemcells, nil
+}
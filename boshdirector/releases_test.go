@@ -0,0 +1,87 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package boshdirector_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/mockhttp/mockbosh"
+)
+
+var _ = Describe("releases and stemcells", func() {
+	It("gets the uploaded releases", func() {
+		director.VerifyAndMock(
+			mockbosh.Releases().RespondsOKWith(`[{"name": "redis", "release_versions": [{"version": "1", "currently_deployed": true}, {"version": "2"}]}]`),
+		)
+
+		releases, err := c.GetReleases(logger)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(releases).To(Equal(boshdirector.Releases{
+			{Name: "redis", ReleaseVersions: []boshdirector.ReleaseVersion{{Version: "1"}, {Version: "2"}}},
+		}))
+	})
+
+	It("returns an error when getting releases fails", func() {
+		director.VerifyAndMock(
+			mockbosh.Releases().RespondsInternalServerErrorWith("because reasons"),
+		)
+
+		_, err := c.GetReleases(logger)
+
+		Expect(err).To(MatchError(ContainSubstring("expected status 200, was 500")))
+	})
+
+	It("gets the uploaded stemcells", func() {
+		director.VerifyAndMock(
+			mockbosh.Stemcells().RespondsOKWith(`[{"name": "bosh-warden-boshlite-ubuntu-trusty-go_agent", "operating_system": "ubuntu-trusty", "version": "3312.7", "cid": "some-cid"}]`),
+		)
+
+		stemcells, err := c.GetStemcells(logger)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stemcells).To(Equal(boshdirector.Stemcells{
+			{Name: "bosh-warden-boshlite-ubuntu-trusty-go_agent", OperatingSystem: "ubuntu-trusty", Version: "3312.7"},
+		}))
+	})
+
+	It("returns an error when getting stemcells fails", func() {
+		director.VerifyAndMock(
+			mockbosh.Stemcells().RespondsInternalServerErrorWith("because reasons"),
+		)
+
+		_, err := c.GetStemcells(logger)
+
+		Expect(err).To(MatchError(ContainSubstring("expected status 200, was 500")))
+	})
+
+	Describe("matching", func() {
+		releases := boshdirector.Releases{
+			{Name: "redis", ReleaseVersions: []boshdirector.ReleaseVersion{{Version: "1"}, {Version: "2"}}},
+		}
+		stemcells := boshdirector.Stemcells{
+			{Name: "bosh-warden-boshlite-ubuntu-trusty-go_agent", OperatingSystem: "ubuntu-trusty", Version: "3312.7"},
+		}
+
+		It("finds uploaded release versions", func() {
+			Expect(releases.Contains("redis", "2")).To(BeTrue())
+			Expect(releases.Contains("redis", "latest")).To(BeTrue())
+			Expect(releases.Contains("redis", "3")).To(BeFalse())
+			Expect(releases.Contains("syslog", "latest")).To(BeFalse())
+		})
+
+		It("finds uploaded stemcells by OS or name", func() {
+			Expect(stemcells.ContainsOS("ubuntu-trusty", "3312.7")).To(BeTrue())
+			Expect(stemcells.ContainsOS("ubuntu-trusty", "latest")).To(BeTrue())
+			Expect(stemcells.ContainsOS("ubuntu-trusty", "3312.8")).To(BeFalse())
+			Expect(stemcells.ContainsOS("ubuntu-xenial", "latest")).To(BeFalse())
+			Expect(stemcells.ContainsName("bosh-warden-boshlite-ubuntu-trusty-go_agent", "3312.7")).To(BeTrue())
+			Expect(stemcells.ContainsName("ubuntu-trusty", "3312.7")).To(BeFalse())
+		})
+	})
+})
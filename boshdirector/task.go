@@ -14,6 +14,8 @@ type BoshTask struct {
 	Description string
 	Result      string
 	ContextID   string `json:"context_id,omitempty"`
+	Deployment  string `json:"deployment,omitempty"`
+	Timestamp   int64  `json:"timestamp,omitempty"`
 }
 
 type TaskStateType int
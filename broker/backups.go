@@ -0,0 +1,169 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/pborman/uuid"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/task"
+)
+
+type Backup struct {
+	TaskID    int
+	State     string
+	Timestamp int64
+	ContextID string
+}
+
+func (b *Broker) Backup(ctx context.Context, instanceID string, logger *log.Logger) (OperationData, error) {
+	return b.runBackupErrand(instanceID, OperationTypeBackup, logger)
+}
+
+func (b *Broker) Restore(ctx context.Context, instanceID string, logger *log.Logger) (OperationData, error) {
+	return b.runBackupErrand(instanceID, OperationTypeRestore, logger)
+}
+
+// Backups lists the runs of the plan's backup errand that the director still has tasks for
+func (b *Broker) Backups(instanceID string, logger *log.Logger) ([]Backup, error) {
+	instance, err := b.cfClient.GetInstanceState(instanceID, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := b.planWithErrandFor(instance.PlanID, OperationTypeBackup)
+	if err != nil {
+		return nil, err
+	}
+
+	deploymentName := b.deploymentName(instanceID)
+	tasks, err := b.boshClient.GetTasks(deploymentName, logger)
+	if err != nil {
+		logger.Printf("error getting tasks for deployment %s: %s", deploymentName, err)
+		return nil, err
+	}
+
+	backups := []Backup{}
+	errandDescription := fmt.Sprintf("run errand %s from deployment %s", plan.BackupErrand(), deploymentName)
+	for _, boshTask := range tasks {
+		if boshTask.Description != errandDescription {
+			continue
+		}
+
+		state, err := b.errandTaskState(deploymentName, boshTask, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		backups = append(backups, Backup{
+			TaskID:    boshTask.ID,
+			State:     state,
+			Timestamp: boshTask.Timestamp,
+			ContextID: boshTask.ContextID,
+		})
+	}
+
+	return backups, nil
+}
+
+func (b *Broker) runBackupErrand(instanceID string, operationType OperationType, logger *log.Logger) (OperationData, error) {
+	b.deploymentLock.Lock()
+	defer b.deploymentLock.Unlock()
+
+	instance, err := b.cfClient.GetInstanceState(instanceID, logger)
+	if err != nil {
+		return OperationData{}, err
+	}
+
+	if instance.OperationInProgress {
+		return OperationData{}, NewOperationInProgressError(fmt.Errorf("cloud controller: operation in progress for instance %s", instanceID))
+	}
+
+	plan, err := b.planWithErrandFor(instance.PlanID, operationType)
+	if err != nil {
+		return OperationData{}, err
+	}
+
+	deploymentName := b.deploymentName(instanceID)
+	_, found, err := b.boshClient.GetDeployment(deploymentName, logger)
+	if err != nil {
+		return OperationData{}, fmt.Errorf("error getting deployment %s: %s", deploymentName, err)
+	}
+	if !found {
+		return OperationData{}, task.NewDeploymentNotFoundError(fmt.Errorf("bosh deployment '%s' not found", deploymentName))
+	}
+
+	tasks, err := b.boshClient.GetTasks(deploymentName, logger)
+	if err != nil {
+		return OperationData{}, fmt.Errorf("error getting tasks for deployment %s: %s", deploymentName, err)
+	}
+	if incompleteTasks := tasks.IncompleteTasks(); len(incompleteTasks) > 0 {
+		return OperationData{}, NewOperationInProgressError(
+			fmt.Errorf("deployment %s is still in progress: tasks %s", deploymentName, incompleteTasks.ToLog()),
+		)
+	}
+
+	errand := backupErrandFor(plan, operationType)
+	contextID := uuid.New()
+
+	logger.Printf("running %s errand %s for instance %s\n", operationType, errand, instanceID)
+	taskID, err := b.boshClient.RunErrand(deploymentName, errand, contextID, logger)
+	if err != nil {
+		logger.Printf("error running %s errand %s for instance %s: %s", operationType, errand, instanceID, err)
+		return OperationData{}, err
+	}
+
+	return OperationData{
+		BoshTaskID:    taskID,
+		BoshContextID: contextID,
+		OperationType: operationType,
+	}, nil
+}
+
+func (b *Broker) planWithErrandFor(planID string, operationType OperationType) (config.Plan, error) {
+	plan, found := b.serviceOffering.Load().FindPlanByID(planID)
+	if !found {
+		return config.Plan{}, fmt.Errorf("plan %s not found", planID)
+	}
+
+	if backupErrandFor(plan, operationType) == "" {
+		return config.Plan{}, NewBackupNotConfiguredError(fmt.Errorf("plan %s has no %s errand configured", plan.Name, operationType))
+	}
+
+	return plan, nil
+}
+
+func backupErrandFor(plan config.Plan, operationType OperationType) string {
+	if operationType == OperationTypeRestore {
+		return plan.RestoreErrand()
+	}
+	return plan.BackupErrand()
+}
+
+// errandTaskState accounts for bosh reporting errands that exited non-zero as done
+func (b *Broker) errandTaskState(deploymentName string, boshTask boshdirector.BoshTask, logger *log.Logger) (string, error) {
+	if boshTask.State != boshdirector.TaskDone || boshTask.ContextID == "" {
+		return boshTask.State, nil
+	}
+
+	contextTasks, err := b.boshClient.GetNormalisedTasksByContext(deploymentName, boshTask.ContextID, logger)
+	if err != nil {
+		logger.Printf("error getting tasks with context %s for deployment %s: %s", boshTask.ContextID, deploymentName, err)
+		return "", err
+	}
+
+	for _, contextTask := range contextTasks {
+		if contextTask.ID == boshTask.ID {
+			return contextTask.State, nil
+		}
+	}
+	return boshTask.State, nil
+}
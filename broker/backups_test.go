@@ -0,0 +1,195 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/cf"
+	"github.com/pivotal-cf/on-demand-service-broker/task"
+)
+
+var _ = Describe("Backups", func() {
+	const instanceID = "some-instance"
+
+	BeforeEach(func() {
+		cfClient.GetInstanceStateReturns(cf.InstanceState{PlanID: existingPlanID}, nil)
+		boshClient.GetDeploymentReturns([]byte("name: "+deploymentName(instanceID)), true, nil)
+		boshClient.GetTasksReturns(boshdirector.BoshTasks{{State: boshdirector.TaskDone}}, nil)
+		boshClient.RunErrandReturns(42, nil)
+	})
+
+	Describe("backing up and restoring", func() {
+		var (
+			operationType broker.OperationType
+			operationData broker.OperationData
+			runErr        error
+		)
+
+		BeforeEach(func() {
+			operationType = broker.OperationTypeBackup
+		})
+
+		JustBeforeEach(func() {
+			logger := loggerFactory.NewWithRequestID()
+			if operationType == broker.OperationTypeRestore {
+				operationData, runErr = b.Restore(context.Background(), instanceID, logger)
+			} else {
+				operationData, runErr = b.Backup(context.Background(), instanceID, logger)
+			}
+		})
+
+		It("runs the plan's backup errand with a context", func() {
+			Expect(runErr).NotTo(HaveOccurred())
+			Expect(boshClient.RunErrandCallCount()).To(Equal(1))
+			actualDeploymentName, actualErrand, actualContextID, _ := boshClient.RunErrandArgsForCall(0)
+			Expect(actualDeploymentName).To(Equal(deploymentName(instanceID)))
+			Expect(actualErrand).To(Equal("backup-data"))
+			Expect(actualContextID).NotTo(BeEmpty())
+
+			Expect(operationData).To(Equal(broker.OperationData{
+				BoshTaskID:    42,
+				BoshContextID: actualContextID,
+				OperationType: broker.OperationTypeBackup,
+			}))
+		})
+
+		Context("when restoring", func() {
+			BeforeEach(func() {
+				operationType = broker.OperationTypeRestore
+			})
+
+			It("runs the plan's restore errand", func() {
+				Expect(runErr).NotTo(HaveOccurred())
+				_, actualErrand, _, _ := boshClient.RunErrandArgsForCall(0)
+				Expect(actualErrand).To(Equal("restore-data"))
+				Expect(operationData.OperationType).To(Equal(broker.OperationTypeRestore))
+			})
+		})
+
+		Context("when the plan has no backup errand", func() {
+			BeforeEach(func() {
+				cfClient.GetInstanceStateReturns(cf.InstanceState{PlanID: secondPlanID}, nil)
+			})
+
+			It("returns a BackupNotConfiguredError", func() {
+				Expect(runErr).To(BeAssignableToTypeOf(broker.BackupNotConfiguredError{}))
+				Expect(boshClient.RunErrandCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the instance is not found in Cloud Foundry", func() {
+			BeforeEach(func() {
+				cfClient.GetInstanceStateReturns(cf.InstanceState{}, cf.NewResourceNotFoundError("not found"))
+			})
+
+			It("returns the error", func() {
+				Expect(runErr).To(BeAssignableToTypeOf(cf.ResourceNotFoundError{}))
+			})
+		})
+
+		Context("when Cloud Foundry has an operation in progress", func() {
+			BeforeEach(func() {
+				cfClient.GetInstanceStateReturns(cf.InstanceState{PlanID: existingPlanID, OperationInProgress: true}, nil)
+			})
+
+			It("returns an OperationInProgressError", func() {
+				Expect(runErr).To(BeAssignableToTypeOf(broker.OperationInProgressError{}))
+			})
+		})
+
+		Context("when the deployment does not exist", func() {
+			BeforeEach(func() {
+				boshClient.GetDeploymentReturns(nil, false, nil)
+			})
+
+			It("returns a DeploymentNotFoundError", func() {
+				Expect(runErr).To(BeAssignableToTypeOf(task.DeploymentNotFoundError{}))
+				Expect(boshClient.RunErrandCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when a bosh task is in progress for the deployment", func() {
+			BeforeEach(func() {
+				boshClient.GetTasksReturns(boshdirector.BoshTasks{{State: boshdirector.TaskProcessing}}, nil)
+			})
+
+			It("returns an OperationInProgressError", func() {
+				Expect(runErr).To(BeAssignableToTypeOf(broker.OperationInProgressError{}))
+				Expect(boshClient.RunErrandCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when running the errand fails", func() {
+			BeforeEach(func() {
+				boshClient.RunErrandReturns(0, errors.New("director error"))
+			})
+
+			It("returns the error", func() {
+				Expect(runErr).To(MatchError("director error"))
+			})
+		})
+	})
+
+	Describe("listing backups", func() {
+		var (
+			backups    []broker.Backup
+			backupsErr error
+		)
+
+		BeforeEach(func() {
+			backupErrand := "run errand backup-data from deployment " + deploymentName(instanceID)
+			boshClient.GetTasksReturns(boshdirector.BoshTasks{
+				{ID: 3, State: boshdirector.TaskProcessing, Description: backupErrand, ContextID: "ctx-3", Timestamp: 300},
+				{ID: 2, State: boshdirector.TaskDone, Description: "create deployment", Timestamp: 200},
+				{ID: 1, State: boshdirector.TaskDone, Description: backupErrand, ContextID: "ctx-1", Timestamp: 100},
+			}, nil)
+			boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{{ID: 1, State: boshdirector.TaskError}}, nil)
+		})
+
+		JustBeforeEach(func() {
+			backups, backupsErr = b.Backups(instanceID, loggerFactory.NewWithRequestID())
+		})
+
+		It("returns the backup errand runs with their errand state", func() {
+			Expect(backupsErr).NotTo(HaveOccurred())
+			Expect(backups).To(Equal([]broker.Backup{
+				{TaskID: 3, State: boshdirector.TaskProcessing, Timestamp: 300, ContextID: "ctx-3"},
+				{TaskID: 1, State: boshdirector.TaskError, Timestamp: 100, ContextID: "ctx-1"},
+			}))
+
+			Expect(boshClient.GetNormalisedTasksByContextCallCount()).To(Equal(1))
+			_, actualContextID, _ := boshClient.GetNormalisedTasksByContextArgsForCall(0)
+			Expect(actualContextID).To(Equal("ctx-1"))
+		})
+
+		Context("when the plan has no backup errand", func() {
+			BeforeEach(func() {
+				cfClient.GetInstanceStateReturns(cf.InstanceState{PlanID: secondPlanID}, nil)
+			})
+
+			It("returns a BackupNotConfiguredError", func() {
+				Expect(backupsErr).To(BeAssignableToTypeOf(broker.BackupNotConfiguredError{}))
+			})
+		})
+
+		Context("when getting tasks fails", func() {
+			BeforeEach(func() {
+				boshClient.GetTasksReturns(nil, errors.New("director error"))
+			})
+
+			It("returns the error", func() {
+				Expect(backupsErr).To(MatchError("director error"))
+			})
+		})
+	})
+})
@@ -23,7 +23,7 @@ func (b *Broker) Bind(
 	details brokerapi.BindDetails,
 ) (brokerapi.Binding, error) {
 	requestID := uuid.New()
-	ctx = brokercontext.New(ctx, string(OperationTypeBind), requestID, b.serviceOffering.Name, instanceID)
+	ctx = brokercontext.New(ctx, string(OperationTypeBind), requestID, b.serviceOffering.Load().Name, instanceID)
 	logger := b.loggerFactory.NewWithContext(ctx)
 
 	errs := func(err DisplayableError) (brokerapi.Binding, error) {
@@ -50,7 +50,7 @@ func (b *Broker) Bind(
 		return errs(NewGenericError(ctx, fmt.Errorf("converting to map %s", err)))
 	}
 
-	binding, err := b.adapterClient.CreateBinding(bindingID, vms, manifest, mappedParams, logger)
+	binding, err := b.adapterClient.CreateBinding(ctx, bindingID, vms, manifest, mappedParams, logger)
 	if err != nil {
 		logger.Printf("creating binding: %v\n", err)
 	}
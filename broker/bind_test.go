@@ -11,11 +11,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pivotal-cf/brokerapi"
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
 	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
@@ -71,7 +73,7 @@ var _ = Describe("Bind", func() {
 
 	It("creates the binding using the bosh topology and admin credentials", func() {
 		Expect(serviceAdapter.CreateBindingCallCount()).To(Equal(1))
-		passedBindingID, passedVms, passedManifest, passedRequestParameters, _ := serviceAdapter.CreateBindingArgsForCall(0)
+		_, passedBindingID, passedVms, passedManifest, passedRequestParameters, _ := serviceAdapter.CreateBindingArgsForCall(0)
 		Expect(passedBindingID).To(Equal(bindingID))
 		Expect(passedVms).To(Equal(boshVms))
 		Expect(passedManifest).To(Equal(actualManifest))
@@ -279,6 +281,30 @@ var _ = Describe("Bind", func() {
 			})
 		})
 
+		Context("with a structured error", func() {
+			BeforeEach(func() {
+				serviceAdapter.CreateBindingReturns(sdk.Binding{}, serviceadapter.StructuredError{UserMessage: "bindings are disabled for this plan"})
+			})
+
+			It("returns the user message as a bad request", func() {
+				Expect(bindErr).To(Equal(brokerapi.NewFailureResponse(
+					errors.New("bindings are disabled for this plan"),
+					http.StatusBadRequest,
+					"service-adapter-error",
+				)))
+			})
+		})
+
+		Context("with a timeout error", func() {
+			BeforeEach(func() {
+				serviceAdapter.CreateBindingReturns(sdk.Binding{}, serviceadapter.NewTimeoutError("adapter timed out"))
+			})
+
+			It("returns the timeout message for the user", func() {
+				Expect(bindErr).To(MatchError(broker.AdapterTimeoutMessage))
+			})
+		})
+
 		Context("with a binding already exists error", func() {
 			BeforeEach(func() {
 				serviceAdapter.CreateBindingReturns(sdk.Binding{}, serviceadapter.BindingAlreadyExistsError{})
@@ -7,16 +7,19 @@
 package broker
 
 import (
+	"context"
+	"io"
 	"log"
-	"strings"
 	"sync"
+	"time"
 
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 	"github.com/pivotal-cf/on-demand-service-broker/cf"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
-	"github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
 )
 
 type Broker struct {
@@ -26,7 +29,13 @@ type Broker struct {
 	deployer       Deployer
 	deploymentLock *sync.Mutex
 
-	serviceOffering config.ServiceOffering
+	serviceOffering    *config.ServiceOfferingStore
+	serviceDeployment  config.ServiceDeployment
+	deploymentNames    *deploymentNames
+	maxUpgradeDeferral time.Duration
+
+	healthLock *sync.Mutex
+	healthErr  error
 
 	loggerFactory *loggerfactory.LoggerFactory
 }
@@ -36,9 +45,16 @@ func New(
 	cfClient CloudFoundryClient,
 	serviceAdapter ServiceAdapterClient,
 	deployer Deployer,
-	serviceOffering config.ServiceOffering,
+	serviceOffering *config.ServiceOfferingStore,
+	serviceDeployment config.ServiceDeployment,
+	deploymentNameTemplate string,
+	maxUpgradeDeferral time.Duration,
 	loggerFactory *loggerfactory.LoggerFactory,
 ) (*Broker, error) {
+	names, err := newDeploymentNames(deploymentNameTemplate, serviceOffering.Load())
+	if err != nil {
+		return nil, err
+	}
 
 	b := &Broker{
 		boshClient:     boshClient,
@@ -47,7 +63,12 @@ func New(
 		deployer:       deployer,
 		deploymentLock: &sync.Mutex{},
 
-		serviceOffering: serviceOffering,
+		serviceOffering:    serviceOffering,
+		serviceDeployment:  serviceDeployment,
+		deploymentNames:    names,
+		maxUpgradeDeferral: maxUpgradeDeferral,
+
+		healthLock: &sync.Mutex{},
 
 		loggerFactory: loggerFactory,
 	}
@@ -56,6 +77,10 @@ func New(
 		return nil, err
 	}
 
+	if err := b.findLegacyDeployments(loggerFactory.New()); err != nil {
+		return nil, err
+	}
+
 	return b, nil
 }
 
@@ -66,6 +91,16 @@ const (
 	OperationTypeDelete  = OperationType("delete")
 	OperationTypeBind    = OperationType("bind")
 	OperationTypeUnbind  = OperationType("unbind")
+
+	OperationTypeRecreate = OperationType("recreate")
+	OperationTypeRestart  = OperationType("restart")
+	OperationTypeStop     = OperationType("stop")
+	OperationTypeStart    = OperationType("start")
+
+	OperationTypeBackup  = OperationType("backup")
+	OperationTypeRestore = OperationType("restore")
+
+	OperationTypeRollback = OperationType("rollback")
 )
 
 type OperationType string
@@ -78,42 +113,43 @@ type OperationData struct {
 	PostDeployErrandName string `json:",omitempty"`
 }
 
-const InstancePrefix = "service-instance_"
-
-func deploymentName(instanceID string) string {
-	return InstancePrefix + instanceID
-}
-
-func instanceID(deploymentName string) string {
-	return strings.TrimPrefix(deploymentName, InstancePrefix)
-}
-
 //TODO SF only need to return manifest from Create
 //go:generate counterfeiter -o fakes/fake_deployer.go . Deployer
 type Deployer interface {
-	Create(deploymentName, planID string, requestParams map[string]interface{}, boshContextID string, logger *log.Logger) (int, []byte, error)
-	Update(deploymentName, planID string, requestParams map[string]interface{}, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error)
-	Upgrade(deploymentName, planID string, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error)
+	Create(ctx context.Context, deploymentName, planID string, requestParams map[string]interface{}, boshContextID string, logger *log.Logger) (int, []byte, error)
+	Update(ctx context.Context, deploymentName, planID string, requestParams map[string]interface{}, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error)
+	Upgrade(ctx context.Context, deploymentName, planID string, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error)
+	ChangeJobState(deploymentName string, state boshdirector.JobState, logger *log.Logger) (int, error)
+	Rollback(deploymentName, boshContextID string, logger *log.Logger) (int, []byte, error)
 }
 
 //go:generate counterfeiter -o fakes/fake_service_adapter_client.go . ServiceAdapterClient
 type ServiceAdapterClient interface {
-	CreateBinding(bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, logger *log.Logger) (serviceadapter.Binding, error)
-	DeleteBinding(bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, logger *log.Logger) error
-	GenerateDashboardUrl(instanceID string, plan serviceadapter.Plan, manifest []byte, logger *log.Logger) (string, error)
+	CreateBinding(ctx context.Context, bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, logger *log.Logger) (sdk.Binding, error)
+	DeleteBinding(ctx context.Context, bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, logger *log.Logger) error
+	GenerateDashboardUrl(ctx context.Context, instanceID string, plan sdk.Plan, manifest []byte, logger *log.Logger) (string, error)
+	Capabilities(ctx context.Context, logger *log.Logger) (serviceadapter.Capabilities, error)
+	ValidateParams(ctx context.Context, plan sdk.Plan, previousPlan *sdk.Plan, requestParams map[string]interface{}, logger *log.Logger) error
+	InstanceStatus(ctx context.Context, instanceID string, deploymentTopology bosh.BoshVMs, manifest []byte, logger *log.Logger) (serviceadapter.InstanceStatus, error)
 }
 
 //go:generate counterfeiter -o fakes/fake_bosh_client.go . BoshClient
 type BoshClient interface {
 	GetTask(taskID int, logger *log.Logger) (boshdirector.BoshTask, error)
+	WaitForTask(taskID int, logger *log.Logger) (boshdirector.BoshTask, error)
+	StreamTaskOutput(taskID int, outputType string, writer io.Writer, logger *log.Logger) error
 	GetTasks(deploymentName string, logger *log.Logger) (boshdirector.BoshTasks, error)
 	GetNormalisedTasksByContext(deploymentName, contextID string, logger *log.Logger) (boshdirector.BoshTasks, error)
 	VMs(deploymentName string, logger *log.Logger) (bosh.BoshVMs, error)
 	GetDeployment(name string, logger *log.Logger) ([]byte, bool, error)
 	GetDeployments(logger *log.Logger) ([]boshdirector.Deployment, error)
 	DeleteDeployment(name, contextID string, logger *log.Logger) (int, error)
+	GetConfig(configType, name string, logger *log.Logger) ([]byte, bool, error)
+	DeleteConfig(configType, name string, logger *log.Logger) error
 	GetDirectorVersion(logger *log.Logger) (boshdirector.Version, error)
 	RunErrand(deploymentName, errandName, contextID string, logger *log.Logger) (int, error)
+	GetReleases(logger *log.Logger) (boshdirector.Releases, error)
+	GetStemcells(logger *log.Logger) (boshdirector.Stemcells, error)
 }
 
 //go:generate counterfeiter -o fakes/fake_cloud_foundry_client.go . CloudFoundryClient
@@ -123,4 +159,6 @@ type CloudFoundryClient interface {
 	CountInstancesOfServiceOffering(serviceOfferingID string, logger *log.Logger) (instanceCountByPlanID map[string]int, err error)
 	GetInstanceState(serviceInstanceGUID string, logger *log.Logger) (cf.InstanceState, error)
 	GetInstancesOfServiceOffering(serviceOfferingID string, logger *log.Logger) ([]string, error)
+	GetServiceInstancesOfServiceOffering(serviceOfferingID string, logger *log.Logger) ([]cf.ServiceInstance, error)
+	GetOrganizationGUIDOfSpace(spaceGUID string, logger *log.Logger) (string, error)
 }
@@ -7,20 +7,27 @@
 package broker
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 
 	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
+	"github.com/pivotal-cf/on-demand-service-broker/task"
 	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
+	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
 )
 
 func (b *Broker) getDeploymentInfo(instanceID string, logger *log.Logger) (bosh.BoshVMs, []byte, error) {
-	vms, err := b.boshClient.VMs(deploymentName(instanceID), logger)
+	vms, err := b.boshClient.VMs(b.deploymentName(instanceID), logger)
 	if err != nil {
 		return nil, nil, err
 	}
-	manifest, found, err := b.boshClient.GetDeployment(deploymentName(instanceID), logger)
+	manifest, found, err := b.boshClient.GetDeployment(b.deploymentName(instanceID), logger)
 	if !found {
 		return nil, nil, fmt.Errorf("manifest not found for deployment: %s", instanceID)
 	}
@@ -61,3 +68,47 @@ func convertToMap(object interface{}) (map[string]interface{}, error) {
 	}
 	return genericMap, nil
 }
+
+// validateReservedParams checks the arbitrary parameters the broker interprets itself
+func validateReservedParams(requestParams map[string]interface{}) DisplayableError {
+	if _, _, err := task.MaintenanceWindowFromParams(requestParams); err != nil {
+		return invalidParamsError(err)
+	}
+	if _, _, err := task.UpgradePolicyFromParams(requestParams); err != nil {
+		return invalidParamsError(err)
+	}
+	return NilError
+}
+
+// validateParamsWithAdapter lets the adapter reject the request before quotas are checked or
+// anything is deployed
+func (b *Broker) validateParamsWithAdapter(ctx context.Context, serviceOffering config.ServiceOffering, plan config.Plan, previousPlanID *string, requestParams map[string]interface{}, logger *log.Logger) DisplayableError {
+	var previousPlan *sdk.Plan
+	if previousPlanID != nil {
+		if p, found := serviceOffering.FindPlanByID(*previousPlanID); found {
+			adapterPlan := p.AdapterPlan(serviceOffering.GlobalProperties)
+			previousPlan = &adapterPlan
+		}
+	}
+
+	err := b.adapterClient.ValidateParams(ctx, plan.AdapterPlan(serviceOffering.GlobalProperties), previousPlan, requestParams, logger)
+	switch err := err.(type) {
+	case nil, serviceadapter.NotImplementedError:
+		return NilError
+	case serviceadapter.StructuredError:
+		return NewDisplayableError(structuredAdapterError(err), err)
+	case serviceadapter.UnknownFailureError:
+		if err.Error() == "" {
+			return NewGenericError(ctx, err)
+		}
+		return invalidParamsError(err)
+	case serviceadapter.TimeoutError:
+		return NewDisplayableError(errors.New(AdapterTimeoutMessage), err)
+	default:
+		return NewGenericError(ctx, err)
+	}
+}
+
+func invalidParamsError(err error) DisplayableError {
+	return NewDisplayableError(brokerapi.NewFailureResponse(err, http.StatusBadRequest, "validating-parameters"), err)
+}
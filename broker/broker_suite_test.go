@@ -11,6 +11,7 @@ import (
 	"io"
 	"log"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -37,16 +38,19 @@ const (
 )
 
 var (
-	b                   *broker.Broker
-	brokerCreationErr   error
-	boshClient          *fakes.FakeBoshClient
-	boshDirectorVersion boshdirector.Version
-	cfClient            *fakes.FakeCloudFoundryClient
-	serviceAdapter      *fakes.FakeServiceAdapterClient
-	fakeDeployer        *fakes.FakeDeployer
-	serviceCatalog      config.ServiceOffering
-	logBuffer           *bytes.Buffer
-	loggerFactory       *loggerfactory.LoggerFactory
+	b                      *broker.Broker
+	brokerCreationErr      error
+	boshClient             *fakes.FakeBoshClient
+	boshDirectorVersion    boshdirector.Version
+	cfClient               *fakes.FakeCloudFoundryClient
+	serviceAdapter         *fakes.FakeServiceAdapterClient
+	fakeDeployer           *fakes.FakeDeployer
+	serviceCatalog         config.ServiceOffering
+	serviceDeployment      config.ServiceDeployment
+	deploymentNameTemplate string
+	maxUpgradeDeferral     time.Duration
+	logBuffer              *bytes.Buffer
+	loggerFactory          *loggerfactory.LoggerFactory
 
 	existingPlanServiceInstanceLimit    = 3
 	serviceOfferingServiceInstanceLimit = 5
@@ -66,6 +70,10 @@ var (
 		Properties: serviceadapter.Properties{
 			"super": "no",
 		},
+		BackupErrands: &config.BackupErrands{
+			Backup:  "backup-data",
+			Restore: "restore-data",
+		},
 		InstanceGroups: []serviceadapter.InstanceGroup{
 			{
 				Name:               existingPlanInstanceGroupName,
@@ -145,6 +153,19 @@ var _ = BeforeEach(func() {
 		},
 	}
 
+	serviceDeployment = config.ServiceDeployment{
+		Releases: serviceadapter.ServiceReleases{{Name: "redis", Version: "9", Jobs: []string{"redis-server"}}},
+		Stemcell: serviceadapter.Stemcell{OS: "ubuntu-trusty", Version: "3312"},
+	}
+	boshClient.GetReleasesReturns(boshdirector.Releases{
+		{Name: "redis", ReleaseVersions: []boshdirector.ReleaseVersion{{Version: "9"}}},
+	}, nil)
+	boshClient.GetStemcellsReturns(boshdirector.Stemcells{
+		{Name: "bosh-warden-boshlite-ubuntu-trusty-go_agent", OperatingSystem: "ubuntu-trusty", Version: "3312"},
+	}, nil)
+
+	deploymentNameTemplate = ""
+	maxUpgradeDeferral = 0
 	logBuffer = new(bytes.Buffer)
 	loggerFactory = loggerfactory.New(io.MultiWriter(GinkgoWriter, logBuffer), "broker-unit-tests", log.LstdFlags)
 })
@@ -155,7 +176,10 @@ var _ = JustBeforeEach(func() {
 		cfClient,
 		serviceAdapter,
 		fakeDeployer,
-		serviceCatalog,
+		config.NewServiceOfferingStore(serviceCatalog),
+		serviceDeployment,
+		deploymentNameTemplate,
+		maxUpgradeDeferral,
 		loggerFactory,
 	)
 })
@@ -13,8 +13,10 @@ import (
 )
 
 func (b *Broker) Services(_ context.Context) []brokerapi.Service {
+	serviceOffering := b.serviceOffering.Load()
+
 	servicePlans := []brokerapi.ServicePlan{}
-	for _, plan := range b.serviceOffering.Plans {
+	for _, plan := range serviceOffering.Plans {
 		planCosts := []brokerapi.ServicePlanCost{}
 		for _, cost := range plan.Metadata.Costs {
 			planCosts = append(planCosts, brokerapi.ServicePlanCost{Amount: cost.Amount, Unit: cost.Unit})
@@ -36,33 +38,33 @@ func (b *Broker) Services(_ context.Context) []brokerapi.Service {
 	}
 
 	var dashboardClient *brokerapi.ServiceDashboardClient
-	if b.serviceOffering.DashboardClient != nil {
+	if serviceOffering.DashboardClient != nil {
 		dashboardClient = &brokerapi.ServiceDashboardClient{
-			ID:          b.serviceOffering.DashboardClient.ID,
-			Secret:      b.serviceOffering.DashboardClient.Secret,
-			RedirectURI: b.serviceOffering.DashboardClient.RedirectUri,
+			ID:          serviceOffering.DashboardClient.ID,
+			Secret:      serviceOffering.DashboardClient.Secret,
+			RedirectURI: serviceOffering.DashboardClient.RedirectUri,
 		}
 	}
 
 	return []brokerapi.Service{
 		{
-			ID:            b.serviceOffering.ID,
-			Name:          b.serviceOffering.Name,
-			Description:   b.serviceOffering.Description,
-			Bindable:      b.serviceOffering.Bindable,
-			PlanUpdatable: b.serviceOffering.PlanUpdatable,
+			ID:            serviceOffering.ID,
+			Name:          serviceOffering.Name,
+			Description:   serviceOffering.Description,
+			Bindable:      serviceOffering.Bindable,
+			PlanUpdatable: serviceOffering.PlanUpdatable,
 			Plans:         servicePlans,
 			Metadata: &brokerapi.ServiceMetadata{
-				DisplayName:         b.serviceOffering.Metadata.DisplayName,
-				ImageUrl:            b.serviceOffering.Metadata.ImageURL,
-				LongDescription:     b.serviceOffering.Metadata.LongDescription,
-				ProviderDisplayName: b.serviceOffering.Metadata.ProviderDisplayName,
-				DocumentationUrl:    b.serviceOffering.Metadata.DocumentationURL,
-				SupportUrl:          b.serviceOffering.Metadata.SupportURL,
+				DisplayName:         serviceOffering.Metadata.DisplayName,
+				ImageUrl:            serviceOffering.Metadata.ImageURL,
+				LongDescription:     serviceOffering.Metadata.LongDescription,
+				ProviderDisplayName: serviceOffering.Metadata.ProviderDisplayName,
+				DocumentationUrl:    serviceOffering.Metadata.DocumentationURL,
+				SupportUrl:          serviceOffering.Metadata.SupportURL,
 			},
 			DashboardClient: dashboardClient,
-			Requires:        requiredPermissions(b.serviceOffering.Requires),
-			Tags:            b.serviceOffering.Tags,
+			Requires:        requiredPermissions(serviceOffering.Requires),
+			Tags:            serviceOffering.Tags,
 		},
 	}
 }
@@ -0,0 +1,61 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/task"
+)
+
+var jobStatesForOperations = map[OperationType]boshdirector.JobState{
+	OperationTypeRecreate: boshdirector.JobStateRecreate,
+	OperationTypeRestart:  boshdirector.JobStateRestart,
+	OperationTypeStop:     boshdirector.JobStateStopped,
+	OperationTypeStart:    boshdirector.JobStateStarted,
+}
+
+func (b *Broker) ChangeInstanceState(ctx context.Context, instanceID string, operationType OperationType, logger *log.Logger) (OperationData, error) {
+	state, found := jobStatesForOperations[operationType]
+	if !found {
+		return OperationData{}, fmt.Errorf("unsupported instance operation %s", operationType)
+	}
+
+	b.deploymentLock.Lock()
+	defer b.deploymentLock.Unlock()
+
+	instance, err := b.cfClient.GetInstanceState(instanceID, logger)
+	if err != nil {
+		return OperationData{}, err
+	}
+
+	if instance.OperationInProgress {
+		return OperationData{}, NewOperationInProgressError(fmt.Errorf("cloud controller: operation in progress for instance %s", instanceID))
+	}
+
+	logger.Printf("performing %s on instance %s", operationType, instanceID)
+
+	taskID, err := b.deployer.ChangeJobState(b.deploymentName(instanceID), state, logger)
+	if err != nil {
+		logger.Printf("error performing %s on instance %s: %s", operationType, instanceID, err)
+
+		switch err := err.(type) {
+		case task.TaskInProgressError:
+			return OperationData{}, NewOperationInProgressError(err)
+		default:
+			return OperationData{}, err
+		}
+	}
+
+	return OperationData{
+		BoshTaskID:    taskID,
+		OperationType: operationType,
+	}, nil
+}
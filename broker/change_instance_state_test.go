@@ -0,0 +1,122 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/cf"
+	"github.com/pivotal-cf/on-demand-service-broker/task"
+)
+
+var _ = Describe("ChangeInstanceState", func() {
+	const boshTaskID = 654
+
+	var (
+		instanceID    = "some-instance"
+		operationType broker.OperationType
+		logger        *log.Logger
+
+		operationData broker.OperationData
+		changeErr     error
+	)
+
+	BeforeEach(func() {
+		operationType = broker.OperationTypeRecreate
+		cfClient.GetInstanceStateReturns(cf.InstanceState{PlanID: existingPlanID}, nil)
+		fakeDeployer.ChangeJobStateReturns(boshTaskID, nil)
+	})
+
+	JustBeforeEach(func() {
+		logger = loggerFactory.NewWithRequestID()
+		operationData, changeErr = b.ChangeInstanceState(context.Background(), instanceID, operationType, logger)
+	})
+
+	It("changes the job state of the instance's deployment", func() {
+		Expect(fakeDeployer.ChangeJobStateCallCount()).To(Equal(1))
+		actualDeploymentName, actualState, _ := fakeDeployer.ChangeJobStateArgsForCall(0)
+		Expect(actualDeploymentName).To(Equal(deploymentName(instanceID)))
+		Expect(actualState).To(Equal(boshdirector.JobStateRecreate))
+	})
+
+	It("returns operation data for polling the task", func() {
+		Expect(changeErr).NotTo(HaveOccurred())
+		Expect(operationData).To(Equal(broker.OperationData{
+			BoshTaskID:    boshTaskID,
+			OperationType: broker.OperationTypeRecreate,
+		}))
+	})
+
+	Context("when stopping the instance", func() {
+		BeforeEach(func() {
+			operationType = broker.OperationTypeStop
+		})
+
+		It("stops the jobs", func() {
+			_, actualState, _ := fakeDeployer.ChangeJobStateArgsForCall(0)
+			Expect(actualState).To(Equal(boshdirector.JobStateStopped))
+		})
+	})
+
+	Context("when the operation is not supported", func() {
+		BeforeEach(func() {
+			operationType = broker.OperationTypeUpgrade
+		})
+
+		It("returns an error without changing the job state", func() {
+			Expect(changeErr).To(MatchError("unsupported instance operation upgrade"))
+			Expect(fakeDeployer.ChangeJobStateCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when there is an operation in progress on the instance in Cloud Foundry", func() {
+		BeforeEach(func() {
+			cfClient.GetInstanceStateReturns(cf.InstanceState{PlanID: existingPlanID, OperationInProgress: true}, nil)
+		})
+
+		It("returns an OperationInProgressError", func() {
+			Expect(changeErr).To(BeAssignableToTypeOf(broker.OperationInProgressError{}))
+			Expect(fakeDeployer.ChangeJobStateCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when a BOSH task is in progress for the deployment", func() {
+		BeforeEach(func() {
+			fakeDeployer.ChangeJobStateReturns(0, task.TaskInProgressError{Message: "task in progress"})
+		})
+
+		It("returns an OperationInProgressError", func() {
+			Expect(changeErr).To(BeAssignableToTypeOf(broker.OperationInProgressError{}))
+		})
+	})
+
+	Context("when the instance cannot be found in Cloud Foundry", func() {
+		BeforeEach(func() {
+			cfClient.GetInstanceStateReturns(cf.InstanceState{}, cf.NewResourceNotFoundError("not found"))
+		})
+
+		It("returns the error", func() {
+			Expect(changeErr).To(BeAssignableToTypeOf(cf.ResourceNotFoundError{}))
+		})
+	})
+
+	Context("when changing the job state fails", func() {
+		BeforeEach(func() {
+			fakeDeployer.ChangeJobStateReturns(0, errors.New("director says no"))
+		})
+
+		It("returns the error", func() {
+			Expect(changeErr).To(MatchError("director says no"))
+		})
+	})
+})
@@ -9,5 +9,5 @@ package broker
 import "log"
 
 func (b *Broker) CountInstancesOfPlans(logger *log.Logger) (map[string]int, error) {
-	return b.cfClient.CountInstancesOfServiceOffering(b.serviceOffering.ID, logger)
+	return b.cfClient.CountInstancesOfServiceOffering(b.serviceOffering.Load().ID, logger)
 }
@@ -0,0 +1,239 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/pborman/uuid"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/cf"
+)
+
+type OrphanDeletionOptions struct {
+	DryRun             bool
+	MinAge             time.Duration
+	RunPreDeleteErrand bool
+	PlanID             string
+}
+
+type OrphanDeletionResult struct {
+	DeploymentName  string `json:"deployment_name"`
+	Deleted         bool   `json:"deleted"`
+	DryRun          bool   `json:"dry_run"`
+	SkippedReason   string `json:"skipped_reason,omitempty"`
+	PreDeleteErrand string `json:"pre_delete_errand,omitempty"`
+	ErrandTaskID    int    `json:"errand_task_id,omitempty"`
+	DeleteTaskID    int    `json:"delete_task_id,omitempty"`
+}
+
+func (b *Broker) DeleteOrphanDeployment(deploymentName string, options OrphanDeletionOptions, logger *log.Logger) (OrphanDeletionResult, error) {
+	result := OrphanDeletionResult{DeploymentName: deploymentName, DryRun: options.DryRun}
+
+	b.deploymentLock.Lock()
+	tasks, err := b.assertOrphanIdle(deploymentName, logger)
+	b.deploymentLock.Unlock()
+	if err != nil {
+		return result, err
+	}
+
+	if options.MinAge > 0 {
+		age, known := deploymentAge(tasks)
+		switch {
+		case !known:
+			result.SkippedReason = fmt.Sprintf("its age is unknown, as the director has no tasks for it, and a minimum age of %s is required", options.MinAge)
+		case age < options.MinAge:
+			result.SkippedReason = fmt.Sprintf("last changed %s ago, which is less than the minimum age of %s", age, options.MinAge)
+		}
+		if result.SkippedReason != "" {
+			logger.Printf("not deleting orphan deployment %s: %s\n", deploymentName, result.SkippedReason)
+			return result, nil
+		}
+	}
+
+	if options.RunPreDeleteErrand {
+		result.PreDeleteErrand, err = b.orphanPreDeleteErrand(options.PlanID)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	if options.DryRun {
+		logger.Printf("dry run: would delete orphan deployment %s\n", deploymentName)
+		return result, nil
+	}
+
+	// the errand can run for a long time, so other deployments are not locked out while it runs. Its
+	// task makes concurrent deletions of this deployment fail as in progress, and the orphan is checked
+	// again before deleting it.
+	if result.PreDeleteErrand != "" {
+		result.ErrandTaskID, err = b.runOrphanPreDeleteErrand(deploymentName, result.PreDeleteErrand, logger)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	result.DeleteTaskID, err = b.startOrphanDeletion(deploymentName, result.PreDeleteErrand != "", logger)
+	if err != nil {
+		return result, err
+	}
+	result.Deleted = true
+
+	logger.Printf("Bosh task id for deleting orphan deployment %s was %d\n", deploymentName, result.DeleteTaskID)
+
+	// the deletion is waited for without holding the lock, and the deployment's configs are only
+	// deleted once it has succeeded, so that a deployment surviving a failed deletion keeps them
+	b.deleteConfigsOnceDeleted(deploymentName, result.DeleteTaskID, logger)
+
+	return result, nil
+}
+
+func (b *Broker) startOrphanDeletion(deploymentName string, recheck bool, logger *log.Logger) (int, error) {
+	b.deploymentLock.Lock()
+	defer b.deploymentLock.Unlock()
+
+	if recheck {
+		if _, err := b.assertOrphanIdle(deploymentName, logger); err != nil {
+			return 0, err
+		}
+	}
+
+	logger.Printf("deleting orphan deployment %s\n", deploymentName)
+	taskID, err := b.boshClient.DeleteDeployment(deploymentName, "", logger)
+	if err != nil {
+		return 0, fmt.Errorf("error deleting deployment %s: %s", deploymentName, err)
+	}
+	return taskID, nil
+}
+
+func (b *Broker) deleteConfigsOnceDeleted(deploymentName string, taskID int, logger *log.Logger) {
+	task, err := b.boshClient.WaitForTask(taskID, logger)
+	if err != nil {
+		logger.Printf("warning: error waiting for deletion task %d of deployment %s, keeping its configs: %s\n", taskID, deploymentName, err)
+		return
+	}
+	if task.StateType() != boshdirector.TaskComplete {
+		logger.Printf("warning: deletion task %d of deployment %s did not succeed, keeping its configs\n", taskID, deploymentName)
+		return
+	}
+
+	deleteDeploymentConfigs(b.boshClient, deploymentName, logger)
+}
+
+// assertOrphanIdle checks the deployment is an orphan of this broker with no task in progress, and
+// returns its tasks
+func (b *Broker) assertOrphanIdle(deploymentName string, logger *log.Logger) (boshdirector.BoshTasks, error) {
+	instanceID, isInstance := b.instanceID(deploymentName)
+	if !isInstance {
+		return nil, NewNotAnOrphanError(fmt.Errorf("deployment %s is not a service instance deployment", deploymentName))
+	}
+
+	if err := b.assertInstanceDoesNotExist(instanceID, logger); err != nil {
+		return nil, err
+	}
+
+	_, found, err := b.boshClient.GetDeployment(deploymentName, logger)
+	if err != nil {
+		return nil, fmt.Errorf("error getting deployment %s: %s", deploymentName, err)
+	}
+	if !found {
+		return nil, NewDeploymentNotFoundError(fmt.Errorf("bosh deployment %s not found", deploymentName))
+	}
+
+	tasks, err := b.boshClient.GetTasks(deploymentName, logger)
+	if err != nil {
+		return nil, fmt.Errorf("error getting tasks for deployment %s: %s", deploymentName, err)
+	}
+	if incompleteTasks := tasks.IncompleteTasks(); len(incompleteTasks) > 0 {
+		return nil, NewOperationInProgressError(
+			fmt.Errorf("deployment %s is still in progress: tasks %s", deploymentName, incompleteTasks.ToLog()),
+		)
+	}
+
+	return tasks, nil
+}
+
+// assertInstanceDoesNotExist asks Cloud Controller again, as the deployment may have been
+// listed as an orphan before its service instance was created
+func (b *Broker) assertInstanceDoesNotExist(instanceID string, logger *log.Logger) error {
+	_, err := b.cfClient.GetInstanceState(instanceID, logger)
+	switch err.(type) {
+	case cf.ResourceNotFoundError:
+		return nil
+	case nil:
+		return NewNotAnOrphanError(fmt.Errorf("service instance %s exists in Cloud Foundry", instanceID))
+	default:
+		return fmt.Errorf("error checking for service instance %s in Cloud Foundry: %s", instanceID, err)
+	}
+}
+
+// deploymentAge is the time since the most recent task against the deployment
+func deploymentAge(tasks boshdirector.BoshTasks) (time.Duration, bool) {
+	var latest int64
+	for _, task := range tasks {
+		if task.Timestamp > latest {
+			latest = task.Timestamp
+		}
+	}
+
+	if latest == 0 {
+		return 0, false
+	}
+	return time.Since(time.Unix(latest, 0)), true
+}
+
+// orphanPreDeleteErrand cannot look up the plan in Cloud Controller, so without a plan ID
+// it only uses an errand when every plan that has one agrees on it
+func (b *Broker) orphanPreDeleteErrand(planID string) (string, error) {
+	if planID != "" {
+		plan, found := b.serviceOffering.Load().FindPlanByID(planID)
+		if !found {
+			return "", fmt.Errorf("plan %s not found", planID)
+		}
+		return plan.PreDeleteErrand(), nil
+	}
+
+	var errand string
+	for _, plan := range b.serviceOffering.Load().Plans {
+		planErrand := plan.PreDeleteErrand()
+		if planErrand == "" {
+			continue
+		}
+		if errand != "" && errand != planErrand {
+			return "", fmt.Errorf("plans have different pre-delete errands, a plan ID is required to choose one")
+		}
+		errand = planErrand
+	}
+	return errand, nil
+}
+
+func (b *Broker) runOrphanPreDeleteErrand(deploymentName, errand string, logger *log.Logger) (int, error) {
+	logger.Printf("running pre-delete errand %s for orphan deployment %s\n", errand, deploymentName)
+
+	contextID := uuid.New()
+	taskID, err := b.boshClient.RunErrand(deploymentName, errand, contextID, logger)
+	if err != nil {
+		return 0, fmt.Errorf("error running pre-delete errand %s for deployment %s: %s", errand, deploymentName, err)
+	}
+
+	if _, err := b.boshClient.WaitForTask(taskID, logger); err != nil {
+		return taskID, fmt.Errorf("error waiting for pre-delete errand task %d: %s", taskID, err)
+	}
+
+	// the director reports failed errands as done, so check the normalised state
+	errandTasks, err := b.boshClient.GetNormalisedTasksByContext(deploymentName, contextID, logger)
+	if err != nil {
+		return taskID, fmt.Errorf("error getting pre-delete errand task %d: %s", taskID, err)
+	}
+	if len(errandTasks.DoneTasks()) != len(errandTasks) {
+		return taskID, fmt.Errorf("pre-delete errand %s for deployment %s failed in task %d, deployment not deleted", errand, deploymentName, taskID)
+	}
+
+	return taskID, nil
+}
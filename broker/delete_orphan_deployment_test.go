@@ -0,0 +1,366 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/cf"
+	"github.com/pivotal-cf/on-demand-service-broker/task"
+)
+
+var _ = Describe("DeleteOrphanDeployment", func() {
+	const (
+		errandTaskID = 321
+		deleteTaskID = 654
+	)
+
+	var (
+		orphanName = deploymentName("orphan-instance")
+		options    broker.OrphanDeletionOptions
+
+		result    broker.OrphanDeletionResult
+		deleteErr error
+	)
+
+	BeforeEach(func() {
+		options = broker.OrphanDeletionOptions{}
+		cfClient.GetInstanceStateReturns(cf.InstanceState{}, cf.NewResourceNotFoundError("not found"))
+		boshClient.GetDeploymentReturns([]byte("name: "+orphanName), true, nil)
+		boshClient.GetTasksReturns(boshdirector.BoshTasks{
+			{State: boshdirector.TaskDone, Timestamp: time.Now().Add(-48 * time.Hour).Unix()},
+		}, nil)
+		boshClient.RunErrandReturns(errandTaskID, nil)
+		boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{{ID: errandTaskID, State: boshdirector.TaskDone}}, nil)
+		boshClient.DeleteDeploymentReturns(deleteTaskID, nil)
+		boshClient.WaitForTaskStub = func(taskID int, _ *log.Logger) (boshdirector.BoshTask, error) {
+			return boshdirector.BoshTask{ID: taskID, State: boshdirector.TaskDone}, nil
+		}
+	})
+
+	JustBeforeEach(func() {
+		result, deleteErr = b.DeleteOrphanDeployment(orphanName, options, loggerFactory.NewWithRequestID())
+	})
+
+	It("re-verifies with Cloud Foundry that the instance does not exist", func() {
+		Expect(cfClient.GetInstanceStateCallCount()).To(Equal(1))
+		actualInstanceID, _ := cfClient.GetInstanceStateArgsForCall(0)
+		Expect(actualInstanceID).To(Equal("orphan-instance"))
+	})
+
+	It("deletes the deployment", func() {
+		Expect(deleteErr).NotTo(HaveOccurred())
+		Expect(boshClient.DeleteDeploymentCallCount()).To(Equal(1))
+		actualName, _, _ := boshClient.DeleteDeploymentArgsForCall(0)
+		Expect(actualName).To(Equal(orphanName))
+		Expect(result).To(Equal(broker.OrphanDeletionResult{
+			DeploymentName: orphanName,
+			Deleted:        true,
+			DeleteTaskID:   deleteTaskID,
+		}))
+	})
+
+	It("deletes the configs kept for the deployment once its deletion has succeeded", func() {
+		Expect(boshClient.WaitForTaskCallCount()).To(Equal(1))
+		actualTaskID, _ := boshClient.WaitForTaskArgsForCall(0)
+		Expect(actualTaskID).To(Equal(deleteTaskID))
+
+		var deletedTypes []string
+		for i := 0; i < boshClient.DeleteConfigCallCount(); i++ {
+			actualType, actualName, _ := boshClient.DeleteConfigArgsForCall(i)
+			Expect(actualName).To(Equal(orphanName))
+			deletedTypes = append(deletedTypes, actualType)
+		}
+		Expect(deletedTypes).To(ConsistOf(task.PreviousManifestConfigType, task.MaintenanceWindowConfigType, task.UpgradeDeferredSinceConfigType))
+	})
+
+	Context("when the deletion task fails", func() {
+		BeforeEach(func() {
+			boshClient.WaitForTaskReturns(boshdirector.BoshTask{ID: deleteTaskID, State: boshdirector.TaskError}, nil)
+		})
+
+		It("keeps the configs kept for the deployment", func() {
+			Expect(deleteErr).NotTo(HaveOccurred())
+			Expect(boshClient.DeleteConfigCallCount()).To(BeZero())
+			Expect(logBuffer.String()).To(ContainSubstring(
+				fmt.Sprintf("warning: deletion task %d of deployment %s did not succeed, keeping its configs", deleteTaskID, orphanName),
+			))
+		})
+	})
+
+	Context("when waiting for the deletion task fails", func() {
+		BeforeEach(func() {
+			boshClient.WaitForTaskReturns(boshdirector.BoshTask{}, errors.New("director error"))
+		})
+
+		It("keeps the configs kept for the deployment", func() {
+			Expect(deleteErr).NotTo(HaveOccurred())
+			Expect(boshClient.DeleteConfigCallCount()).To(BeZero())
+			Expect(logBuffer.String()).To(ContainSubstring(
+				fmt.Sprintf("warning: error waiting for deletion task %d of deployment %s, keeping its configs: director error", deleteTaskID, orphanName),
+			))
+		})
+	})
+
+	It("does not run an errand by default", func() {
+		Expect(boshClient.RunErrandCallCount()).To(BeZero())
+	})
+
+	Context("when the service instance exists in Cloud Foundry", func() {
+		BeforeEach(func() {
+			cfClient.GetInstanceStateReturns(cf.InstanceState{PlanID: existingPlanID}, nil)
+		})
+
+		It("refuses to delete the deployment", func() {
+			Expect(deleteErr).To(BeAssignableToTypeOf(broker.NotAnOrphanError{}))
+			Expect(deleteErr).To(MatchError("service instance orphan-instance exists in Cloud Foundry"))
+			Expect(boshClient.DeleteDeploymentCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when Cloud Foundry cannot be queried", func() {
+		BeforeEach(func() {
+			cfClient.GetInstanceStateReturns(cf.InstanceState{}, errors.New("cc unavailable"))
+		})
+
+		It("returns an error without deleting", func() {
+			Expect(deleteErr).To(MatchError(ContainSubstring("cc unavailable")))
+			Expect(boshClient.DeleteDeploymentCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the deployment is not a service instance deployment", func() {
+		BeforeEach(func() {
+			orphanName = "cf"
+		})
+
+		AfterEach(func() {
+			orphanName = deploymentName("orphan-instance")
+		})
+
+		It("refuses to delete the deployment", func() {
+			Expect(deleteErr).To(BeAssignableToTypeOf(broker.NotAnOrphanError{}))
+			Expect(cfClient.GetInstanceStateCallCount()).To(BeZero())
+			Expect(boshClient.DeleteDeploymentCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the deployment does not exist", func() {
+		BeforeEach(func() {
+			boshClient.GetDeploymentReturns(nil, false, nil)
+		})
+
+		It("returns a DeploymentNotFoundError", func() {
+			Expect(deleteErr).To(BeAssignableToTypeOf(broker.DeploymentNotFoundError{}))
+			Expect(boshClient.DeleteDeploymentCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when a task is in progress for the deployment", func() {
+		BeforeEach(func() {
+			boshClient.GetTasksReturns(boshdirector.BoshTasks{{State: boshdirector.TaskProcessing}}, nil)
+		})
+
+		It("returns an OperationInProgressError", func() {
+			Expect(deleteErr).To(BeAssignableToTypeOf(broker.OperationInProgressError{}))
+			Expect(boshClient.DeleteDeploymentCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the deployment is younger than the minimum age", func() {
+		BeforeEach(func() {
+			options.MinAge = 72 * time.Hour
+		})
+
+		It("skips the deployment", func() {
+			Expect(deleteErr).NotTo(HaveOccurred())
+			Expect(result.Deleted).To(BeFalse())
+			Expect(result.SkippedReason).To(ContainSubstring("less than the minimum age of 72h0m0s"))
+			Expect(boshClient.DeleteDeploymentCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the age of the deployment is unknown and a minimum age is set", func() {
+		BeforeEach(func() {
+			options.MinAge = 24 * time.Hour
+			boshClient.GetTasksReturns(boshdirector.BoshTasks{}, nil)
+		})
+
+		It("skips the deployment", func() {
+			Expect(deleteErr).NotTo(HaveOccurred())
+			Expect(result.Deleted).To(BeFalse())
+			Expect(result.SkippedReason).To(ContainSubstring("its age is unknown"))
+			Expect(boshClient.DeleteDeploymentCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the age of the deployment is unknown and no minimum age is set", func() {
+		BeforeEach(func() {
+			boshClient.GetTasksReturns(boshdirector.BoshTasks{}, nil)
+		})
+
+		It("deletes the deployment", func() {
+			Expect(deleteErr).NotTo(HaveOccurred())
+			Expect(result.Deleted).To(BeTrue())
+		})
+	})
+
+	Context("when the deployment is older than the minimum age", func() {
+		BeforeEach(func() {
+			options.MinAge = 24 * time.Hour
+		})
+
+		It("deletes the deployment", func() {
+			Expect(deleteErr).NotTo(HaveOccurred())
+			Expect(result.Deleted).To(BeTrue())
+		})
+	})
+
+	Context("when it is a dry run", func() {
+		BeforeEach(func() {
+			options.DryRun = true
+			options.RunPreDeleteErrand = true
+		})
+
+		It("reports what would happen without changing anything", func() {
+			Expect(deleteErr).NotTo(HaveOccurred())
+			Expect(result).To(Equal(broker.OrphanDeletionResult{
+				DeploymentName:  orphanName,
+				DryRun:          true,
+				PreDeleteErrand: "cleanup-resources",
+			}))
+			Expect(boshClient.RunErrandCallCount()).To(BeZero())
+			Expect(boshClient.DeleteDeploymentCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when running the pre-delete errand", func() {
+		BeforeEach(func() {
+			options.RunPreDeleteErrand = true
+		})
+
+		It("runs the errand and waits for it before deleting", func() {
+			Expect(deleteErr).NotTo(HaveOccurred())
+			Expect(boshClient.RunErrandCallCount()).To(Equal(1))
+			actualName, actualErrand, contextID, _ := boshClient.RunErrandArgsForCall(0)
+			Expect(actualName).To(Equal(orphanName))
+			Expect(actualErrand).To(Equal("cleanup-resources"))
+
+			actualTaskID, _ := boshClient.WaitForTaskArgsForCall(0)
+			Expect(actualTaskID).To(Equal(errandTaskID))
+
+			_, actualContextID, _ := boshClient.GetNormalisedTasksByContextArgsForCall(0)
+			Expect(actualContextID).To(Equal(contextID))
+
+			Expect(result.ErrandTaskID).To(Equal(errandTaskID))
+			Expect(result.Deleted).To(BeTrue())
+		})
+
+		Context("while the errand runs", func() {
+			var otherDeletionDone chan error
+
+			BeforeEach(func() {
+				otherDeletionDone = make(chan error, 1)
+				boshClient.WaitForTaskStub = func(taskID int, _ *log.Logger) (boshdirector.BoshTask, error) {
+					if taskID == errandTaskID {
+						go func() {
+							_, err := b.DeleteOrphanDeployment(deploymentName("other-instance"), broker.OrphanDeletionOptions{DryRun: true}, loggerFactory.NewWithRequestID())
+							otherDeletionDone <- err
+						}()
+						Eventually(otherDeletionDone).Should(Receive(BeNil()))
+					}
+					return boshdirector.BoshTask{ID: taskID, State: boshdirector.TaskDone}, nil
+				}
+			})
+
+			It("does not hold the deployment lock", func() {
+				Expect(deleteErr).NotTo(HaveOccurred())
+				Expect(result.Deleted).To(BeTrue())
+			})
+		})
+
+		It("checks again that the deployment is an idle orphan before deleting it", func() {
+			Expect(cfClient.GetInstanceStateCallCount()).To(Equal(2))
+			Expect(boshClient.GetTasksCallCount()).To(Equal(2))
+		})
+
+		Context("and a task is started for the deployment while the errand runs", func() {
+			BeforeEach(func() {
+				boshClient.GetTasksReturnsOnCall(1, boshdirector.BoshTasks{{ID: 999, State: boshdirector.TaskProcessing}}, nil)
+			})
+
+			It("does not delete the deployment", func() {
+				Expect(deleteErr).To(BeAssignableToTypeOf(broker.OperationInProgressError{}))
+				Expect(boshClient.DeleteDeploymentCallCount()).To(BeZero())
+			})
+		})
+
+		Context("and the service instance is created while the errand runs", func() {
+			BeforeEach(func() {
+				cfClient.GetInstanceStateReturnsOnCall(1, cf.InstanceState{PlanID: existingPlanID}, nil)
+			})
+
+			It("does not delete the deployment", func() {
+				Expect(deleteErr).To(BeAssignableToTypeOf(broker.NotAnOrphanError{}))
+				Expect(boshClient.DeleteDeploymentCallCount()).To(BeZero())
+			})
+		})
+
+		Context("and the errand fails", func() {
+			BeforeEach(func() {
+				boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{{ID: errandTaskID, State: boshdirector.TaskError}}, nil)
+			})
+
+			It("does not delete the deployment", func() {
+				Expect(deleteErr).To(MatchError(ContainSubstring("failed in task 321, deployment not deleted")))
+				Expect(boshClient.DeleteDeploymentCallCount()).To(BeZero())
+			})
+		})
+
+		Context("and a plan without an errand is specified", func() {
+			BeforeEach(func() {
+				options.PlanID = existingPlanID
+			})
+
+			It("deletes without running an errand", func() {
+				Expect(deleteErr).NotTo(HaveOccurred())
+				Expect(boshClient.RunErrandCallCount()).To(BeZero())
+				Expect(result.Deleted).To(BeTrue())
+			})
+		})
+
+		Context("and the specified plan does not exist", func() {
+			BeforeEach(func() {
+				options.PlanID = "not-a-plan"
+			})
+
+			It("returns an error", func() {
+				Expect(deleteErr).To(MatchError("plan not-a-plan not found"))
+				Expect(boshClient.DeleteDeploymentCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Context("when deleting the deployment fails", func() {
+		BeforeEach(func() {
+			boshClient.DeleteDeploymentReturns(0, errors.New("director error"))
+		})
+
+		It("returns an error", func() {
+			Expect(deleteErr).To(MatchError(ContainSubstring("director error")))
+			Expect(result.Deleted).To(BeFalse())
+			Expect(boshClient.DeleteConfigCallCount()).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,172 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/task"
+)
+
+const InstancePrefix = "service-instance_"
+
+const instanceIDPlaceholder = "\x00"
+
+type deploymentNameTemplateData struct {
+	ServiceName string
+	ServiceID   string
+	InstanceID  string
+}
+
+// deploymentNames maps instance IDs to deployment names and back. Names are the configured template
+// rendered around the instance ID, which must identify the service offering so that brokers sharing a
+// director can tell their deployments apart by name. Deployments created before the template was
+// configured keep their legacy service-instance_ names, and are still recognised.
+type deploymentNames struct {
+	prefix, suffix    string
+	legacyInstanceIDs map[string]bool
+}
+
+func newDeploymentNames(nameTemplate string, serviceOffering config.ServiceOffering) (*deploymentNames, error) {
+	if nameTemplate == "" {
+		return &deploymentNames{prefix: InstancePrefix, legacyInstanceIDs: map[string]bool{}}, nil
+	}
+
+	tmpl, err := template.New("deployment_name").Option("missingkey=error").Parse(nameTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deployment name template: %s", err)
+	}
+
+	rendered := new(bytes.Buffer)
+	err = tmpl.Execute(rendered, deploymentNameTemplateData{
+		ServiceName: serviceOffering.Name,
+		ServiceID:   serviceOffering.ID,
+		InstanceID:  instanceIDPlaceholder,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid deployment name template: %s", err)
+	}
+
+	parts := strings.Split(rendered.String(), instanceIDPlaceholder)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid deployment name template: must contain {{.InstanceID}} exactly once")
+	}
+	if parts[0] == "" && parts[1] == "" {
+		return nil, fmt.Errorf("invalid deployment name template: must contain more than {{.InstanceID}}")
+	}
+
+	rest := parts[0] + parts[1]
+	if !(serviceOffering.ID != "" && strings.Contains(rest, serviceOffering.ID)) &&
+		!(serviceOffering.Name != "" && strings.Contains(rest, serviceOffering.Name)) {
+		return nil, fmt.Errorf("invalid deployment name template: must contain {{.ServiceName}} or {{.ServiceID}}")
+	}
+
+	return &deploymentNames{prefix: parts[0], suffix: parts[1], legacyInstanceIDs: map[string]bool{}}, nil
+}
+
+func (n *deploymentNames) legacy() bool {
+	return n.prefix == InstancePrefix && n.suffix == ""
+}
+
+func (n *deploymentNames) deploymentName(instanceID string) string {
+	if n.legacyInstanceIDs[instanceID] {
+		return InstancePrefix + instanceID
+	}
+	return n.prefix + instanceID + n.suffix
+}
+
+func (n *deploymentNames) instanceID(deploymentName string) (string, bool) {
+	if strings.HasPrefix(deploymentName, n.prefix) &&
+		strings.HasSuffix(deploymentName, n.suffix) &&
+		len(deploymentName) > len(n.prefix)+len(n.suffix) {
+		return strings.TrimSuffix(strings.TrimPrefix(deploymentName, n.prefix), n.suffix), true
+	}
+
+	if instanceID := strings.TrimPrefix(deploymentName, InstancePrefix); n.legacyInstanceIDs[instanceID] {
+		return instanceID, true
+	}
+
+	return "", false
+}
+
+// findLegacyDeployments records instances deployed with the legacy naming scheme, so that they keep
+// being managed under their existing deployment names after a template is configured. Other brokers
+// may share the legacy prefix on the director, so only instances of this service offering in Cloud
+// Foundry, or deployments tagged with it, are recorded.
+func (b *Broker) findLegacyDeployments(logger *log.Logger) error {
+	if b.deploymentNames.legacy() {
+		logger.Println("warning: deployment names do not identify the service offering, brokers sharing the director will report each other's deployments as orphans: configure broker.deployment_name_template with {{.ServiceName}} or {{.ServiceID}}")
+		return nil
+	}
+
+	deployments, err := b.boshClient.GetDeployments(logger)
+	if err != nil {
+		return fmt.Errorf("error listing deployments with legacy names: %s", err)
+	}
+
+	rawInstanceIDs, err := b.cfClient.GetInstancesOfServiceOffering(b.serviceOffering.Load().ID, logger)
+	if err != nil {
+		return fmt.Errorf("error listing instances for deployments with legacy names: %s", err)
+	}
+
+	instanceIDs := map[string]bool{}
+	for _, instanceID := range rawInstanceIDs {
+		instanceIDs[instanceID] = true
+	}
+
+	for _, deployment := range deployments {
+		if !strings.HasPrefix(deployment.Name, InstancePrefix) || len(deployment.Name) == len(InstancePrefix) {
+			continue
+		}
+
+		instanceID := strings.TrimPrefix(deployment.Name, InstancePrefix)
+		if !instanceIDs[instanceID] {
+			tagged, err := b.taggedWithServiceOffering(deployment.Name, logger)
+			if err != nil {
+				return fmt.Errorf("error reading tags of deployment %s with legacy name: %s", deployment.Name, err)
+			}
+			if !tagged {
+				continue
+			}
+		}
+
+		b.deploymentNames.legacyInstanceIDs[instanceID] = true
+	}
+
+	if count := len(b.deploymentNames.legacyInstanceIDs); count > 0 {
+		logger.Printf("found %d deployments with legacy names, they will keep their names\n", count)
+	}
+
+	return nil
+}
+
+func (b *Broker) taggedWithServiceOffering(deploymentName string, logger *log.Logger) (bool, error) {
+	manifest, found, err := b.boshClient.GetDeployment(deploymentName, logger)
+	if err != nil || !found {
+		return false, err
+	}
+
+	serviceOfferingID, tagged, err := task.ServiceOfferingOf(manifest)
+	if err != nil {
+		return false, err
+	}
+
+	return tagged && serviceOfferingID == b.serviceOffering.Load().ID, nil
+}
+
+func (b *Broker) deploymentName(instanceID string) string {
+	return b.deploymentNames.deploymentName(instanceID)
+}
+
+func (b *Broker) instanceID(deploymentName string) (string, bool) {
+	return b.deploymentNames.instanceID(deploymentName)
+}
@@ -0,0 +1,202 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"errors"
+	"log"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+)
+
+var _ = Describe("deployment names", func() {
+	Context("when no template is configured", func() {
+		It("names deployments with the legacy prefix without listing deployments", func() {
+			Expect(brokerCreationErr).NotTo(HaveOccurred())
+			Expect(boshClient.GetDeploymentsCallCount()).To(BeZero())
+
+			b.InstanceTasks("some-instance", loggerFactory.NewWithRequestID())
+			actualName, _ := boshClient.GetTasksArgsForCall(0)
+			Expect(actualName).To(Equal("service-instance_some-instance"))
+		})
+
+		It("warns that brokers sharing the director will report each other's deployments as orphans", func() {
+			Expect(logBuffer.String()).To(ContainSubstring("warning: deployment names do not identify the service offering"))
+		})
+	})
+
+	Context("when a template is configured", func() {
+		BeforeEach(func() {
+			deploymentNameTemplate = "{{.ServiceName}}-{{.InstanceID}}"
+			boshClient.GetDeploymentsReturns([]boshdirector.Deployment{
+				{Name: "service-instance_legacy-instance"},
+				{Name: "service-instance_tagged-instance"},
+				{Name: "service-instance_other-instance"},
+				{Name: "service-instance_other-tagged-instance"},
+				{Name: "cf"},
+			}, nil)
+			cfClient.GetInstancesOfServiceOfferingReturns([]string{"legacy-instance"}, nil)
+			boshClient.GetDeploymentStub = func(name string, _ *log.Logger) ([]byte, bool, error) {
+				switch name {
+				case "service-instance_tagged-instance":
+					return []byte("tags:\n  odb-service-offering-id: " + serviceOfferingID), true, nil
+				case "service-instance_other-tagged-instance":
+					return []byte("tags:\n  odb-service-offering-id: another-service-id"), true, nil
+				default:
+					return []byte("name: " + name), true, nil
+				}
+			}
+		})
+
+		It("names new deployments with the template", func() {
+			Expect(brokerCreationErr).NotTo(HaveOccurred())
+
+			b.InstanceTasks("some-instance", loggerFactory.NewWithRequestID())
+			actualName, _ := boshClient.GetTasksArgsForCall(0)
+			Expect(actualName).To(Equal("a-cool-redis-service-some-instance"))
+		})
+
+		It("keeps the legacy names of existing deployments", func() {
+			Expect(brokerCreationErr).NotTo(HaveOccurred())
+			Expect(logBuffer.String()).To(ContainSubstring("found 2 deployments with legacy names"))
+
+			b.InstanceTasks("legacy-instance", loggerFactory.NewWithRequestID())
+			actualName, _ := boshClient.GetTasksArgsForCall(0)
+			Expect(actualName).To(Equal("service-instance_legacy-instance"))
+
+			b.InstanceTasks("tagged-instance", loggerFactory.NewWithRequestID())
+			actualName, _ = boshClient.GetTasksArgsForCall(1)
+			Expect(actualName).To(Equal("service-instance_tagged-instance"))
+		})
+
+		It("does not claim legacy names of instances of other service offerings", func() {
+			Expect(brokerCreationErr).NotTo(HaveOccurred())
+
+			b.InstanceTasks("other-instance", loggerFactory.NewWithRequestID())
+			actualName, _ := boshClient.GetTasksArgsForCall(0)
+			Expect(actualName).To(Equal("a-cool-redis-service-other-instance"))
+
+			b.InstanceTasks("other-tagged-instance", loggerFactory.NewWithRequestID())
+			actualName, _ = boshClient.GetTasksArgsForCall(1)
+			Expect(actualName).To(Equal("a-cool-redis-service-other-tagged-instance"))
+		})
+
+		It("only fetches the manifests of legacy deployments that are not in Cloud Foundry", func() {
+			Expect(brokerCreationErr).NotTo(HaveOccurred())
+			Expect(boshClient.GetDeploymentCallCount()).To(Equal(3))
+			actualServiceOfferingID, _ := cfClient.GetInstancesOfServiceOfferingArgsForCall(0)
+			Expect(actualServiceOfferingID).To(Equal(serviceOfferingID))
+		})
+
+		It("reports orphans under both naming schemes", func() {
+			Expect(brokerCreationErr).NotTo(HaveOccurred())
+			boshClient.GetDeploymentsReturns([]boshdirector.Deployment{
+				{Name: "service-instance_legacy-instance"},
+				{Name: "a-cool-redis-service-new-instance"},
+				{Name: "a-cool-redis-service-live-instance"},
+				{Name: "cf"},
+			}, nil)
+			cfClient.GetInstancesOfServiceOfferingReturns([]string{"live-instance"}, nil)
+			manifestFetchesAtStartup := boshClient.GetDeploymentCallCount()
+
+			orphans, err := b.OrphanDeployments(loggerFactory.NewWithRequestID())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(orphans).To(ConsistOf("service-instance_legacy-instance", "a-cool-redis-service-new-instance"))
+
+			By("not fetching manifests, as the names identify the service offering")
+			Expect(boshClient.GetDeploymentCallCount()).To(Equal(manifestFetchesAtStartup))
+		})
+
+		It("refuses to delete deployments named under neither scheme", func() {
+			Expect(brokerCreationErr).NotTo(HaveOccurred())
+
+			_, err := b.DeleteOrphanDeployment("cf", broker.OrphanDeletionOptions{}, loggerFactory.NewWithRequestID())
+			Expect(err).To(BeAssignableToTypeOf(broker.NotAnOrphanError{}))
+		})
+
+		It("refuses to delete legacy deployments of other service offerings", func() {
+			Expect(brokerCreationErr).NotTo(HaveOccurred())
+
+			_, err := b.DeleteOrphanDeployment("service-instance_other-instance", broker.OrphanDeletionOptions{}, loggerFactory.NewWithRequestID())
+			Expect(err).To(BeAssignableToTypeOf(broker.NotAnOrphanError{}))
+		})
+
+		Context("and listing deployments fails", func() {
+			BeforeEach(func() {
+				boshClient.GetDeploymentsReturns(nil, errors.New("director error"))
+			})
+
+			It("fails to create the broker", func() {
+				Expect(brokerCreationErr).To(MatchError("error listing deployments with legacy names: director error"))
+			})
+		})
+
+		Context("and listing instances fails", func() {
+			BeforeEach(func() {
+				cfClient.GetInstancesOfServiceOfferingReturns(nil, errors.New("cc error"))
+			})
+
+			It("fails to create the broker", func() {
+				Expect(brokerCreationErr).To(MatchError("error listing instances for deployments with legacy names: cc error"))
+			})
+		})
+
+		Context("and fetching the manifest of a legacy deployment fails", func() {
+			BeforeEach(func() {
+				boshClient.GetDeploymentStub = nil
+				boshClient.GetDeploymentReturns(nil, false, errors.New("manifest error"))
+			})
+
+			It("fails to create the broker", func() {
+				Expect(brokerCreationErr).To(MatchError("error reading tags of deployment service-instance_tagged-instance with legacy name: manifest error"))
+			})
+		})
+	})
+
+	Context("when a template that does not identify the service offering is configured", func() {
+		BeforeEach(func() {
+			deploymentNameTemplate = "odb-{{.InstanceID}}"
+		})
+
+		It("fails to create the broker", func() {
+			Expect(brokerCreationErr).To(MatchError("invalid deployment name template: must contain {{.ServiceName}} or {{.ServiceID}}"))
+		})
+	})
+
+	Context("when the template does not contain the instance ID", func() {
+		BeforeEach(func() {
+			deploymentNameTemplate = "{{.ServiceName}}"
+		})
+
+		It("fails to create the broker", func() {
+			Expect(brokerCreationErr).To(MatchError("invalid deployment name template: must contain {{.InstanceID}} exactly once"))
+		})
+	})
+
+	Context("when the template is only the instance ID", func() {
+		BeforeEach(func() {
+			deploymentNameTemplate = "{{.InstanceID}}"
+		})
+
+		It("fails to create the broker", func() {
+			Expect(brokerCreationErr).To(MatchError("invalid deployment name template: must contain more than {{.InstanceID}}"))
+		})
+	})
+
+	Context("when the template refers to an unknown field", func() {
+		BeforeEach(func() {
+			deploymentNameTemplate = "{{.Unknown}}-{{.InstanceID}}"
+		})
+
+		It("fails to create the broker", func() {
+			Expect(brokerCreationErr).To(MatchError(ContainSubstring("invalid deployment name template")))
+		})
+	})
+})
@@ -18,6 +18,7 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/task"
 )
 
 func (b *Broker) Deprovision(
@@ -30,7 +31,7 @@ func (b *Broker) Deprovision(
 	defer b.deploymentLock.Unlock()
 
 	requestID := uuid.New()
-	ctx = brokercontext.New(ctx, string(OperationTypeDelete), requestID, b.serviceOffering.Name, instanceID)
+	ctx = brokercontext.New(ctx, string(OperationTypeDelete), requestID, b.serviceOffering.Load().Name, instanceID)
 	logger := b.loggerFactory.NewWithContext(ctx)
 
 	if !asyncAllowed {
@@ -50,7 +51,7 @@ func (b *Broker) Deprovision(
 		return deprovisionErr(NewGenericError(ctx, err), logger)
 	}
 
-	plan, found := b.serviceOffering.FindPlanByID(instanceState.PlanID)
+	plan, found := b.serviceOffering.Load().FindPlanByID(instanceState.PlanID)
 	if found {
 		if errand := plan.PreDeleteErrand(); errand != "" {
 			return b.runPreDeleteErrand(ctx, instanceID, errand, logger)
@@ -61,7 +62,7 @@ func (b *Broker) Deprovision(
 }
 
 func (b *Broker) assertDeploymentExists(ctx context.Context, instanceID string, logger *log.Logger) DisplayableError {
-	_, deploymentFound, err := b.boshClient.GetDeployment(deploymentName(instanceID), logger)
+	_, deploymentFound, err := b.boshClient.GetDeployment(b.deploymentName(instanceID), logger)
 
 	switch err.(type) {
 	case boshdirector.RequestError:
@@ -69,7 +70,7 @@ func (b *Broker) assertDeploymentExists(ctx context.Context, instanceID string,
 	case error:
 		return NewGenericError(
 			ctx,
-			fmt.Errorf("error deprovisioning: cannot get deployment %s: %s", deploymentName(instanceID), err),
+			fmt.Errorf("error deprovisioning: cannot get deployment %s: %s", b.deploymentName(instanceID), err),
 		)
 	}
 
@@ -85,14 +86,14 @@ func (b *Broker) assertDeploymentExists(ctx context.Context, instanceID string,
 
 func (b *Broker) assertNoOperationsInProgress(ctx context.Context, instanceID string, logger *log.Logger) DisplayableError {
 
-	tasks, err := b.boshClient.GetTasks(deploymentName(instanceID), logger)
+	tasks, err := b.boshClient.GetTasks(b.deploymentName(instanceID), logger)
 	switch err.(type) {
 	case boshdirector.RequestError:
 		return NewBoshRequestError("delete", err)
 	case error:
 		return NewGenericError(
 			ctx,
-			fmt.Errorf("error deprovisioning: cannot get tasks for deployment %s: %s\n", deploymentName(instanceID), err),
+			fmt.Errorf("error deprovisioning: cannot get tasks for deployment %s: %s\n", b.deploymentName(instanceID), err),
 		)
 	}
 
@@ -101,7 +102,7 @@ func (b *Broker) assertNoOperationsInProgress(ctx context.Context, instanceID st
 		userError := errors.New("An operation is in progress for your service instance. Please try again later.")
 		operatorError := NewOperationInProgressError(
 			fmt.Errorf("error deprovisioning: deployment %s is still in progress: tasks %s\n",
-				deploymentName(instanceID),
+				b.deploymentName(instanceID),
 				incompleteTasks.ToLog()),
 		)
 		return NewDisplayableError(userError, operatorError)
@@ -121,7 +122,7 @@ func (b *Broker) runPreDeleteErrand(
 	boshContextID := uuid.New()
 
 	taskID, err := b.boshClient.RunErrand(
-		deploymentName(instanceID),
+		b.deploymentName(instanceID),
 		preDeleteErrand,
 		boshContextID,
 		logger,
@@ -150,7 +151,7 @@ func (b *Broker) deleteInstance(
 	logger *log.Logger,
 ) (brokerapi.DeprovisionServiceSpec, error) {
 	logger.Printf("deleting deployment for instance %s\n", instanceID)
-	taskID, err := b.boshClient.DeleteDeployment(deploymentName(instanceID), "", logger)
+	taskID, err := b.boshClient.DeleteDeployment(b.deploymentName(instanceID), "", logger)
 	switch err.(type) {
 	case boshdirector.RequestError:
 		return deprovisionErr(NewBoshRequestError("delete", err), logger)
@@ -179,6 +180,16 @@ func (b *Broker) deleteInstance(
 	}, nil
 }
 
+// deleteDeploymentConfigs removes what the broker kept in the director for a deployment, once its
+// deletion has succeeded. Failing to do so leaves a stale config behind, but does not affect the deletion.
+func deleteDeploymentConfigs(boshClient BoshClient, deploymentName string, logger *log.Logger) {
+	for _, configType := range task.DeploymentConfigTypes {
+		if err := boshClient.DeleteConfig(configType, deploymentName, logger); err != nil {
+			logger.Printf("warning: error deleting %s config of deployment %s: %s\n", configType, deploymentName, err)
+		}
+	}
+}
+
 func deprovisionErr(
 	err DisplayableError,
 	logger *log.Logger,
@@ -69,6 +69,10 @@ var _ = Describe("deprovisioning instances", func() {
 		Expect(actualInstanceID).To(Equal(deploymentName(instanceID)))
 	})
 
+	It("keeps the configs kept for the deployment until its deletion has succeeded", func() {
+		Expect(boshClient.DeleteConfigCallCount()).To(BeZero())
+	})
+
 	It("logs that it will delete the deployment with a request ID", func() {
 		Expect(logBuffer.String()).To(MatchRegexp(`\[[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\] \d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2} deleting deployment for instance`))
 	})
@@ -181,6 +185,7 @@ var _ = Describe("deprovisioning instances", func() {
 
 		It("does not call delete deployment", func() {
 			Expect(boshClient.DeleteDeploymentCallCount()).To(Equal(0))
+			Expect(boshClient.DeleteConfigCallCount()).To(Equal(0))
 		})
 
 		It("includes the operation type, task id, and context id in the operation data", func() {
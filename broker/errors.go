@@ -10,6 +10,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 
 	"github.com/pivotal-cf/brokerapi"
 	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
@@ -20,6 +21,7 @@ const (
 	GenericErrorPrefix         = "There was a problem completing your request. Please contact your operations team providing the following information:"
 	PendingChangesErrorMessage = "Service cannot be updated at this time, please try again later or contact your operator for more information"
 	OperationInProgressMessage = "An operation is in progress for your service instance. Please try again later."
+	AdapterTimeoutMessage      = "The service took too long to process your request. Please try again later or contact your operations team."
 
 	UpdateLoggerAction = ""
 )
@@ -32,6 +34,54 @@ func NewOperationInProgressError(e error) error {
 	return OperationInProgressError{e}
 }
 
+type TaskNotFoundError struct {
+	error
+}
+
+func NewTaskNotFoundError(e error) error {
+	return TaskNotFoundError{e}
+}
+
+type NotAnOrphanError struct {
+	error
+}
+
+func NewNotAnOrphanError(e error) error {
+	return NotAnOrphanError{e}
+}
+
+type DeploymentNotMissingError struct {
+	error
+}
+
+func NewDeploymentNotMissingError(e error) error {
+	return DeploymentNotMissingError{e}
+}
+
+type DeploymentNotFoundError struct {
+	error
+}
+
+func NewDeploymentNotFoundError(e error) error {
+	return DeploymentNotFoundError{e}
+}
+
+type BackupNotConfiguredError struct {
+	error
+}
+
+func NewBackupNotConfiguredError(e error) error {
+	return BackupNotConfiguredError{e}
+}
+
+type UpgradeDeferredError struct {
+	error
+}
+
+func NewUpgradeDeferredError(e error) error {
+	return UpgradeDeferredError{e}
+}
+
 var NilError = DisplayableError{nil, nil}
 
 // TODO SF Remove by logging operator messages when raising the error?
@@ -108,6 +158,10 @@ func adapterToAPIError(ctx context.Context, err error) error {
 		return brokerapi.ErrBindingDoesNotExist
 	case serviceadapter.AppGuidNotProvidedError:
 		return brokerapi.ErrAppGuidNotProvided
+	case serviceadapter.TimeoutError:
+		return errors.New(AdapterTimeoutMessage)
+	case serviceadapter.StructuredError:
+		return structuredAdapterError(err.(serviceadapter.StructuredError))
 	case serviceadapter.UnknownFailureError:
 		if err.Error() == "" {
 			//Adapter returns an unknown error with no message
@@ -119,3 +173,11 @@ func adapterToAPIError(ctx context.Context, err error) error {
 		return NewGenericError(ctx, err).ErrorForCFUser()
 	}
 }
+
+func structuredAdapterError(err serviceadapter.StructuredError) error {
+	statusCode := http.StatusBadRequest
+	if err.Retryable {
+		statusCode = http.StatusServiceUnavailable
+	}
+	return brokerapi.NewFailureResponse(errors.New(err.UserMessage), statusCode, "service-adapter-error")
+}
@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"io"
 	"log"
 	"sync"
 
@@ -25,6 +26,34 @@ type FakeBoshClient struct {
 		result1 boshdirector.BoshTask
 		result2 error
 	}
+	WaitForTaskStub        func(taskID int, logger *log.Logger) (boshdirector.BoshTask, error)
+	waitForTaskMutex       sync.RWMutex
+	waitForTaskArgsForCall []struct {
+		taskID int
+		logger *log.Logger
+	}
+	waitForTaskReturns struct {
+		result1 boshdirector.BoshTask
+		result2 error
+	}
+	waitForTaskReturnsOnCall map[int]struct {
+		result1 boshdirector.BoshTask
+		result2 error
+	}
+	StreamTaskOutputStub        func(taskID int, outputType string, writer io.Writer, logger *log.Logger) error
+	streamTaskOutputMutex       sync.RWMutex
+	streamTaskOutputArgsForCall []struct {
+		taskID     int
+		outputType string
+		writer     io.Writer
+		logger     *log.Logger
+	}
+	streamTaskOutputReturns struct {
+		result1 error
+	}
+	streamTaskOutputReturnsOnCall map[int]struct {
+		result1 error
+	}
 	GetTasksStub        func(deploymentName string, logger *log.Logger) (boshdirector.BoshTasks, error)
 	getTasksMutex       sync.RWMutex
 	getTasksArgsForCall []struct {
@@ -112,6 +141,36 @@ type FakeBoshClient struct {
 		result1 int
 		result2 error
 	}
+	GetConfigStub        func(configType, name string, logger *log.Logger) ([]byte, bool, error)
+	getConfigMutex       sync.RWMutex
+	getConfigArgsForCall []struct {
+		configType string
+		name       string
+		logger     *log.Logger
+	}
+	getConfigReturns struct {
+		result1 []byte
+		result2 bool
+		result3 error
+	}
+	getConfigReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 bool
+		result3 error
+	}
+	DeleteConfigStub        func(configType, name string, logger *log.Logger) error
+	deleteConfigMutex       sync.RWMutex
+	deleteConfigArgsForCall []struct {
+		configType string
+		name       string
+		logger     *log.Logger
+	}
+	deleteConfigReturns struct {
+		result1 error
+	}
+	deleteConfigReturnsOnCall map[int]struct {
+		result1 error
+	}
 	GetDirectorVersionStub        func(logger *log.Logger) (boshdirector.Version, error)
 	getDirectorVersionMutex       sync.RWMutex
 	getDirectorVersionArgsForCall []struct {
@@ -141,6 +200,32 @@ type FakeBoshClient struct {
 		result1 int
 		result2 error
 	}
+	GetReleasesStub        func(logger *log.Logger) (boshdirector.Releases, error)
+	getReleasesMutex       sync.RWMutex
+	getReleasesArgsForCall []struct {
+		logger *log.Logger
+	}
+	getReleasesReturns struct {
+		result1 boshdirector.Releases
+		result2 error
+	}
+	getReleasesReturnsOnCall map[int]struct {
+		result1 boshdirector.Releases
+		result2 error
+	}
+	GetStemcellsStub        func(logger *log.Logger) (boshdirector.Stemcells, error)
+	getStemcellsMutex       sync.RWMutex
+	getStemcellsArgsForCall []struct {
+		logger *log.Logger
+	}
+	getStemcellsReturns struct {
+		result1 boshdirector.Stemcells
+		result2 error
+	}
+	getStemcellsReturnsOnCall map[int]struct {
+		result1 boshdirector.Stemcells
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -197,6 +282,109 @@ func (fake *FakeBoshClient) GetTaskReturnsOnCall(i int, result1 boshdirector.Bos
 	}{result1, result2}
 }
 
+func (fake *FakeBoshClient) WaitForTask(taskID int, logger *log.Logger) (boshdirector.BoshTask, error) {
+	fake.waitForTaskMutex.Lock()
+	ret, specificReturn := fake.waitForTaskReturnsOnCall[len(fake.waitForTaskArgsForCall)]
+	fake.waitForTaskArgsForCall = append(fake.waitForTaskArgsForCall, struct {
+		taskID int
+		logger *log.Logger
+	}{taskID, logger})
+	fake.recordInvocation("WaitForTask", []interface{}{taskID, logger})
+	fake.waitForTaskMutex.Unlock()
+	if fake.WaitForTaskStub != nil {
+		return fake.WaitForTaskStub(taskID, logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.waitForTaskReturns.result1, fake.waitForTaskReturns.result2
+}
+
+func (fake *FakeBoshClient) WaitForTaskCallCount() int {
+	fake.waitForTaskMutex.RLock()
+	defer fake.waitForTaskMutex.RUnlock()
+	return len(fake.waitForTaskArgsForCall)
+}
+
+func (fake *FakeBoshClient) WaitForTaskArgsForCall(i int) (int, *log.Logger) {
+	fake.waitForTaskMutex.RLock()
+	defer fake.waitForTaskMutex.RUnlock()
+	return fake.waitForTaskArgsForCall[i].taskID, fake.waitForTaskArgsForCall[i].logger
+}
+
+func (fake *FakeBoshClient) WaitForTaskReturns(result1 boshdirector.BoshTask, result2 error) {
+	fake.WaitForTaskStub = nil
+	fake.waitForTaskReturns = struct {
+		result1 boshdirector.BoshTask
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) WaitForTaskReturnsOnCall(i int, result1 boshdirector.BoshTask, result2 error) {
+	fake.WaitForTaskStub = nil
+	if fake.waitForTaskReturnsOnCall == nil {
+		fake.waitForTaskReturnsOnCall = make(map[int]struct {
+			result1 boshdirector.BoshTask
+			result2 error
+		})
+	}
+	fake.waitForTaskReturnsOnCall[i] = struct {
+		result1 boshdirector.BoshTask
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) StreamTaskOutput(taskID int, outputType string, writer io.Writer, logger *log.Logger) error {
+	fake.streamTaskOutputMutex.Lock()
+	ret, specificReturn := fake.streamTaskOutputReturnsOnCall[len(fake.streamTaskOutputArgsForCall)]
+	fake.streamTaskOutputArgsForCall = append(fake.streamTaskOutputArgsForCall, struct {
+		taskID     int
+		outputType string
+		writer     io.Writer
+		logger     *log.Logger
+	}{taskID, outputType, writer, logger})
+	fake.recordInvocation("StreamTaskOutput", []interface{}{taskID, outputType, writer, logger})
+	fake.streamTaskOutputMutex.Unlock()
+	if fake.StreamTaskOutputStub != nil {
+		return fake.StreamTaskOutputStub(taskID, outputType, writer, logger)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.streamTaskOutputReturns.result1
+}
+
+func (fake *FakeBoshClient) StreamTaskOutputCallCount() int {
+	fake.streamTaskOutputMutex.RLock()
+	defer fake.streamTaskOutputMutex.RUnlock()
+	return len(fake.streamTaskOutputArgsForCall)
+}
+
+func (fake *FakeBoshClient) StreamTaskOutputArgsForCall(i int) (int, string, io.Writer, *log.Logger) {
+	fake.streamTaskOutputMutex.RLock()
+	defer fake.streamTaskOutputMutex.RUnlock()
+	return fake.streamTaskOutputArgsForCall[i].taskID, fake.streamTaskOutputArgsForCall[i].outputType, fake.streamTaskOutputArgsForCall[i].writer, fake.streamTaskOutputArgsForCall[i].logger
+}
+
+func (fake *FakeBoshClient) StreamTaskOutputReturns(result1 error) {
+	fake.StreamTaskOutputStub = nil
+	fake.streamTaskOutputReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBoshClient) StreamTaskOutputReturnsOnCall(i int, result1 error) {
+	fake.StreamTaskOutputStub = nil
+	if fake.streamTaskOutputReturnsOnCall == nil {
+		fake.streamTaskOutputReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.streamTaskOutputReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeBoshClient) GetTasks(deploymentName string, logger *log.Logger) (boshdirector.BoshTasks, error) {
 	fake.getTasksMutex.Lock()
 	ret, specificReturn := fake.getTasksReturnsOnCall[len(fake.getTasksArgsForCall)]
@@ -513,6 +701,112 @@ func (fake *FakeBoshClient) DeleteDeploymentReturnsOnCall(i int, result1 int, re
 	}{result1, result2}
 }
 
+func (fake *FakeBoshClient) GetConfig(configType string, name string, logger *log.Logger) ([]byte, bool, error) {
+	fake.getConfigMutex.Lock()
+	ret, specificReturn := fake.getConfigReturnsOnCall[len(fake.getConfigArgsForCall)]
+	fake.getConfigArgsForCall = append(fake.getConfigArgsForCall, struct {
+		configType string
+		name       string
+		logger     *log.Logger
+	}{configType, name, logger})
+	fake.recordInvocation("GetConfig", []interface{}{configType, name, logger})
+	fake.getConfigMutex.Unlock()
+	if fake.GetConfigStub != nil {
+		return fake.GetConfigStub(configType, name, logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.getConfigReturns.result1, fake.getConfigReturns.result2, fake.getConfigReturns.result3
+}
+
+func (fake *FakeBoshClient) GetConfigCallCount() int {
+	fake.getConfigMutex.RLock()
+	defer fake.getConfigMutex.RUnlock()
+	return len(fake.getConfigArgsForCall)
+}
+
+func (fake *FakeBoshClient) GetConfigArgsForCall(i int) (string, string, *log.Logger) {
+	fake.getConfigMutex.RLock()
+	defer fake.getConfigMutex.RUnlock()
+	return fake.getConfigArgsForCall[i].configType, fake.getConfigArgsForCall[i].name, fake.getConfigArgsForCall[i].logger
+}
+
+func (fake *FakeBoshClient) GetConfigReturns(result1 []byte, result2 bool, result3 error) {
+	fake.GetConfigStub = nil
+	fake.getConfigReturns = struct {
+		result1 []byte
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeBoshClient) GetConfigReturnsOnCall(i int, result1 []byte, result2 bool, result3 error) {
+	fake.GetConfigStub = nil
+	if fake.getConfigReturnsOnCall == nil {
+		fake.getConfigReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 bool
+			result3 error
+		})
+	}
+	fake.getConfigReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeBoshClient) DeleteConfig(configType string, name string, logger *log.Logger) error {
+	fake.deleteConfigMutex.Lock()
+	ret, specificReturn := fake.deleteConfigReturnsOnCall[len(fake.deleteConfigArgsForCall)]
+	fake.deleteConfigArgsForCall = append(fake.deleteConfigArgsForCall, struct {
+		configType string
+		name       string
+		logger     *log.Logger
+	}{configType, name, logger})
+	fake.recordInvocation("DeleteConfig", []interface{}{configType, name, logger})
+	fake.deleteConfigMutex.Unlock()
+	if fake.DeleteConfigStub != nil {
+		return fake.DeleteConfigStub(configType, name, logger)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.deleteConfigReturns.result1
+}
+
+func (fake *FakeBoshClient) DeleteConfigCallCount() int {
+	fake.deleteConfigMutex.RLock()
+	defer fake.deleteConfigMutex.RUnlock()
+	return len(fake.deleteConfigArgsForCall)
+}
+
+func (fake *FakeBoshClient) DeleteConfigArgsForCall(i int) (string, string, *log.Logger) {
+	fake.deleteConfigMutex.RLock()
+	defer fake.deleteConfigMutex.RUnlock()
+	return fake.deleteConfigArgsForCall[i].configType, fake.deleteConfigArgsForCall[i].name, fake.deleteConfigArgsForCall[i].logger
+}
+
+func (fake *FakeBoshClient) DeleteConfigReturns(result1 error) {
+	fake.DeleteConfigStub = nil
+	fake.deleteConfigReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBoshClient) DeleteConfigReturnsOnCall(i int, result1 error) {
+	fake.DeleteConfigStub = nil
+	if fake.deleteConfigReturnsOnCall == nil {
+		fake.deleteConfigReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteConfigReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeBoshClient) GetDirectorVersion(logger *log.Logger) (boshdirector.Version, error) {
 	fake.getDirectorVersionMutex.Lock()
 	ret, specificReturn := fake.getDirectorVersionReturnsOnCall[len(fake.getDirectorVersionArgsForCall)]
@@ -618,11 +912,117 @@ func (fake *FakeBoshClient) RunErrandReturnsOnCall(i int, result1 int, result2 e
 	}{result1, result2}
 }
 
+func (fake *FakeBoshClient) GetReleases(logger *log.Logger) (boshdirector.Releases, error) {
+	fake.getReleasesMutex.Lock()
+	ret, specificReturn := fake.getReleasesReturnsOnCall[len(fake.getReleasesArgsForCall)]
+	fake.getReleasesArgsForCall = append(fake.getReleasesArgsForCall, struct {
+		logger *log.Logger
+	}{logger})
+	fake.recordInvocation("GetReleases", []interface{}{logger})
+	fake.getReleasesMutex.Unlock()
+	if fake.GetReleasesStub != nil {
+		return fake.GetReleasesStub(logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getReleasesReturns.result1, fake.getReleasesReturns.result2
+}
+
+func (fake *FakeBoshClient) GetReleasesCallCount() int {
+	fake.getReleasesMutex.RLock()
+	defer fake.getReleasesMutex.RUnlock()
+	return len(fake.getReleasesArgsForCall)
+}
+
+func (fake *FakeBoshClient) GetReleasesArgsForCall(i int) *log.Logger {
+	fake.getReleasesMutex.RLock()
+	defer fake.getReleasesMutex.RUnlock()
+	return fake.getReleasesArgsForCall[i].logger
+}
+
+func (fake *FakeBoshClient) GetReleasesReturns(result1 boshdirector.Releases, result2 error) {
+	fake.GetReleasesStub = nil
+	fake.getReleasesReturns = struct {
+		result1 boshdirector.Releases
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) GetReleasesReturnsOnCall(i int, result1 boshdirector.Releases, result2 error) {
+	fake.GetReleasesStub = nil
+	if fake.getReleasesReturnsOnCall == nil {
+		fake.getReleasesReturnsOnCall = make(map[int]struct {
+			result1 boshdirector.Releases
+			result2 error
+		})
+	}
+	fake.getReleasesReturnsOnCall[i] = struct {
+		result1 boshdirector.Releases
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) GetStemcells(logger *log.Logger) (boshdirector.Stemcells, error) {
+	fake.getStemcellsMutex.Lock()
+	ret, specificReturn := fake.getStemcellsReturnsOnCall[len(fake.getStemcellsArgsForCall)]
+	fake.getStemcellsArgsForCall = append(fake.getStemcellsArgsForCall, struct {
+		logger *log.Logger
+	}{logger})
+	fake.recordInvocation("GetStemcells", []interface{}{logger})
+	fake.getStemcellsMutex.Unlock()
+	if fake.GetStemcellsStub != nil {
+		return fake.GetStemcellsStub(logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getStemcellsReturns.result1, fake.getStemcellsReturns.result2
+}
+
+func (fake *FakeBoshClient) GetStemcellsCallCount() int {
+	fake.getStemcellsMutex.RLock()
+	defer fake.getStemcellsMutex.RUnlock()
+	return len(fake.getStemcellsArgsForCall)
+}
+
+func (fake *FakeBoshClient) GetStemcellsArgsForCall(i int) *log.Logger {
+	fake.getStemcellsMutex.RLock()
+	defer fake.getStemcellsMutex.RUnlock()
+	return fake.getStemcellsArgsForCall[i].logger
+}
+
+func (fake *FakeBoshClient) GetStemcellsReturns(result1 boshdirector.Stemcells, result2 error) {
+	fake.GetStemcellsStub = nil
+	fake.getStemcellsReturns = struct {
+		result1 boshdirector.Stemcells
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) GetStemcellsReturnsOnCall(i int, result1 boshdirector.Stemcells, result2 error) {
+	fake.GetStemcellsStub = nil
+	if fake.getStemcellsReturnsOnCall == nil {
+		fake.getStemcellsReturnsOnCall = make(map[int]struct {
+			result1 boshdirector.Stemcells
+			result2 error
+		})
+	}
+	fake.getStemcellsReturnsOnCall[i] = struct {
+		result1 boshdirector.Stemcells
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBoshClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.getTaskMutex.RLock()
 	defer fake.getTaskMutex.RUnlock()
+	fake.waitForTaskMutex.RLock()
+	defer fake.waitForTaskMutex.RUnlock()
+	fake.streamTaskOutputMutex.RLock()
+	defer fake.streamTaskOutputMutex.RUnlock()
 	fake.getTasksMutex.RLock()
 	defer fake.getTasksMutex.RUnlock()
 	fake.getNormalisedTasksByContextMutex.RLock()
@@ -635,10 +1035,18 @@ func (fake *FakeBoshClient) Invocations() map[string][][]interface{} {
 	defer fake.getDeploymentsMutex.RUnlock()
 	fake.deleteDeploymentMutex.RLock()
 	defer fake.deleteDeploymentMutex.RUnlock()
+	fake.getConfigMutex.RLock()
+	defer fake.getConfigMutex.RUnlock()
+	fake.deleteConfigMutex.RLock()
+	defer fake.deleteConfigMutex.RUnlock()
 	fake.getDirectorVersionMutex.RLock()
 	defer fake.getDirectorVersionMutex.RUnlock()
 	fake.runErrandMutex.RLock()
 	defer fake.runErrandMutex.RUnlock()
+	fake.getReleasesMutex.RLock()
+	defer fake.getReleasesMutex.RUnlock()
+	fake.getStemcellsMutex.RLock()
+	defer fake.getStemcellsMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -80,6 +80,34 @@ type FakeCloudFoundryClient struct {
 		result1 []string
 		result2 error
 	}
+	GetServiceInstancesOfServiceOfferingStub        func(serviceOfferingID string, logger *log.Logger) ([]cf.ServiceInstance, error)
+	getServiceInstancesOfServiceOfferingMutex       sync.RWMutex
+	getServiceInstancesOfServiceOfferingArgsForCall []struct {
+		serviceOfferingID string
+		logger            *log.Logger
+	}
+	getServiceInstancesOfServiceOfferingReturns struct {
+		result1 []cf.ServiceInstance
+		result2 error
+	}
+	getServiceInstancesOfServiceOfferingReturnsOnCall map[int]struct {
+		result1 []cf.ServiceInstance
+		result2 error
+	}
+	GetOrganizationGUIDOfSpaceStub        func(spaceGUID string, logger *log.Logger) (string, error)
+	getOrganizationGUIDOfSpaceMutex       sync.RWMutex
+	getOrganizationGUIDOfSpaceArgsForCall []struct {
+		spaceGUID string
+		logger    *log.Logger
+	}
+	getOrganizationGUIDOfSpaceReturns struct {
+		result1 string
+		result2 error
+	}
+	getOrganizationGUIDOfSpaceReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -344,6 +372,110 @@ func (fake *FakeCloudFoundryClient) GetInstancesOfServiceOfferingReturnsOnCall(i
 	}{result1, result2}
 }
 
+func (fake *FakeCloudFoundryClient) GetServiceInstancesOfServiceOffering(serviceOfferingID string, logger *log.Logger) ([]cf.ServiceInstance, error) {
+	fake.getServiceInstancesOfServiceOfferingMutex.Lock()
+	ret, specificReturn := fake.getServiceInstancesOfServiceOfferingReturnsOnCall[len(fake.getServiceInstancesOfServiceOfferingArgsForCall)]
+	fake.getServiceInstancesOfServiceOfferingArgsForCall = append(fake.getServiceInstancesOfServiceOfferingArgsForCall, struct {
+		serviceOfferingID string
+		logger            *log.Logger
+	}{serviceOfferingID, logger})
+	fake.recordInvocation("GetServiceInstancesOfServiceOffering", []interface{}{serviceOfferingID, logger})
+	fake.getServiceInstancesOfServiceOfferingMutex.Unlock()
+	if fake.GetServiceInstancesOfServiceOfferingStub != nil {
+		return fake.GetServiceInstancesOfServiceOfferingStub(serviceOfferingID, logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getServiceInstancesOfServiceOfferingReturns.result1, fake.getServiceInstancesOfServiceOfferingReturns.result2
+}
+
+func (fake *FakeCloudFoundryClient) GetServiceInstancesOfServiceOfferingCallCount() int {
+	fake.getServiceInstancesOfServiceOfferingMutex.RLock()
+	defer fake.getServiceInstancesOfServiceOfferingMutex.RUnlock()
+	return len(fake.getServiceInstancesOfServiceOfferingArgsForCall)
+}
+
+func (fake *FakeCloudFoundryClient) GetServiceInstancesOfServiceOfferingArgsForCall(i int) (string, *log.Logger) {
+	fake.getServiceInstancesOfServiceOfferingMutex.RLock()
+	defer fake.getServiceInstancesOfServiceOfferingMutex.RUnlock()
+	return fake.getServiceInstancesOfServiceOfferingArgsForCall[i].serviceOfferingID, fake.getServiceInstancesOfServiceOfferingArgsForCall[i].logger
+}
+
+func (fake *FakeCloudFoundryClient) GetServiceInstancesOfServiceOfferingReturns(result1 []cf.ServiceInstance, result2 error) {
+	fake.GetServiceInstancesOfServiceOfferingStub = nil
+	fake.getServiceInstancesOfServiceOfferingReturns = struct {
+		result1 []cf.ServiceInstance
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCloudFoundryClient) GetServiceInstancesOfServiceOfferingReturnsOnCall(i int, result1 []cf.ServiceInstance, result2 error) {
+	fake.GetServiceInstancesOfServiceOfferingStub = nil
+	if fake.getServiceInstancesOfServiceOfferingReturnsOnCall == nil {
+		fake.getServiceInstancesOfServiceOfferingReturnsOnCall = make(map[int]struct {
+			result1 []cf.ServiceInstance
+			result2 error
+		})
+	}
+	fake.getServiceInstancesOfServiceOfferingReturnsOnCall[i] = struct {
+		result1 []cf.ServiceInstance
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCloudFoundryClient) GetOrganizationGUIDOfSpace(spaceGUID string, logger *log.Logger) (string, error) {
+	fake.getOrganizationGUIDOfSpaceMutex.Lock()
+	ret, specificReturn := fake.getOrganizationGUIDOfSpaceReturnsOnCall[len(fake.getOrganizationGUIDOfSpaceArgsForCall)]
+	fake.getOrganizationGUIDOfSpaceArgsForCall = append(fake.getOrganizationGUIDOfSpaceArgsForCall, struct {
+		spaceGUID string
+		logger    *log.Logger
+	}{spaceGUID, logger})
+	fake.recordInvocation("GetOrganizationGUIDOfSpace", []interface{}{spaceGUID, logger})
+	fake.getOrganizationGUIDOfSpaceMutex.Unlock()
+	if fake.GetOrganizationGUIDOfSpaceStub != nil {
+		return fake.GetOrganizationGUIDOfSpaceStub(spaceGUID, logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getOrganizationGUIDOfSpaceReturns.result1, fake.getOrganizationGUIDOfSpaceReturns.result2
+}
+
+func (fake *FakeCloudFoundryClient) GetOrganizationGUIDOfSpaceCallCount() int {
+	fake.getOrganizationGUIDOfSpaceMutex.RLock()
+	defer fake.getOrganizationGUIDOfSpaceMutex.RUnlock()
+	return len(fake.getOrganizationGUIDOfSpaceArgsForCall)
+}
+
+func (fake *FakeCloudFoundryClient) GetOrganizationGUIDOfSpaceArgsForCall(i int) (string, *log.Logger) {
+	fake.getOrganizationGUIDOfSpaceMutex.RLock()
+	defer fake.getOrganizationGUIDOfSpaceMutex.RUnlock()
+	return fake.getOrganizationGUIDOfSpaceArgsForCall[i].spaceGUID, fake.getOrganizationGUIDOfSpaceArgsForCall[i].logger
+}
+
+func (fake *FakeCloudFoundryClient) GetOrganizationGUIDOfSpaceReturns(result1 string, result2 error) {
+	fake.GetOrganizationGUIDOfSpaceStub = nil
+	fake.getOrganizationGUIDOfSpaceReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCloudFoundryClient) GetOrganizationGUIDOfSpaceReturnsOnCall(i int, result1 string, result2 error) {
+	fake.GetOrganizationGUIDOfSpaceStub = nil
+	if fake.getOrganizationGUIDOfSpaceReturnsOnCall == nil {
+		fake.getOrganizationGUIDOfSpaceReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.getOrganizationGUIDOfSpaceReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeCloudFoundryClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -357,6 +489,10 @@ func (fake *FakeCloudFoundryClient) Invocations() map[string][][]interface{} {
 	defer fake.getInstanceStateMutex.RUnlock()
 	fake.getInstancesOfServiceOfferingMutex.RLock()
 	defer fake.getInstancesOfServiceOfferingMutex.RUnlock()
+	fake.getServiceInstancesOfServiceOfferingMutex.RLock()
+	defer fake.getServiceInstancesOfServiceOfferingMutex.RUnlock()
+	fake.getOrganizationGUIDOfSpaceMutex.RLock()
+	defer fake.getOrganizationGUIDOfSpaceMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -2,16 +2,19 @@
 package fakes
 
 import (
+	"context"
 	"log"
 	"sync"
 
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
 )
 
 type FakeDeployer struct {
-	CreateStub        func(deploymentName, planID string, requestParams map[string]interface{}, boshContextID string, logger *log.Logger) (int, []byte, error)
+	CreateStub        func(ctx context.Context, deploymentName, planID string, requestParams map[string]interface{}, boshContextID string, logger *log.Logger) (int, []byte, error)
 	createMutex       sync.RWMutex
 	createArgsForCall []struct {
+		ctx            context.Context
 		deploymentName string
 		planID         string
 		requestParams  map[string]interface{}
@@ -28,9 +31,10 @@ type FakeDeployer struct {
 		result2 []byte
 		result3 error
 	}
-	UpdateStub        func(deploymentName, planID string, requestParams map[string]interface{}, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error)
+	UpdateStub        func(ctx context.Context, deploymentName, planID string, requestParams map[string]interface{}, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error)
 	updateMutex       sync.RWMutex
 	updateArgsForCall []struct {
+		ctx            context.Context
 		deploymentName string
 		planID         string
 		requestParams  map[string]interface{}
@@ -48,9 +52,10 @@ type FakeDeployer struct {
 		result2 []byte
 		result3 error
 	}
-	UpgradeStub        func(deploymentName, planID string, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error)
+	UpgradeStub        func(ctx context.Context, deploymentName, planID string, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error)
 	upgradeMutex       sync.RWMutex
 	upgradeArgsForCall []struct {
+		ctx            context.Context
 		deploymentName string
 		planID         string
 		previousPlanID *string
@@ -67,24 +72,57 @@ type FakeDeployer struct {
 		result2 []byte
 		result3 error
 	}
+	ChangeJobStateStub        func(deploymentName string, state boshdirector.JobState, logger *log.Logger) (int, error)
+	changeJobStateMutex       sync.RWMutex
+	changeJobStateArgsForCall []struct {
+		deploymentName string
+		state          boshdirector.JobState
+		logger         *log.Logger
+	}
+	changeJobStateReturns struct {
+		result1 int
+		result2 error
+	}
+	changeJobStateReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	RollbackStub        func(deploymentName, boshContextID string, logger *log.Logger) (int, []byte, error)
+	rollbackMutex       sync.RWMutex
+	rollbackArgsForCall []struct {
+		deploymentName string
+		boshContextID  string
+		logger         *log.Logger
+	}
+	rollbackReturns struct {
+		result1 int
+		result2 []byte
+		result3 error
+	}
+	rollbackReturnsOnCall map[int]struct {
+		result1 int
+		result2 []byte
+		result3 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeDeployer) Create(deploymentName string, planID string, requestParams map[string]interface{}, boshContextID string, logger *log.Logger) (int, []byte, error) {
+func (fake *FakeDeployer) Create(ctx context.Context, deploymentName string, planID string, requestParams map[string]interface{}, boshContextID string, logger *log.Logger) (int, []byte, error) {
 	fake.createMutex.Lock()
 	ret, specificReturn := fake.createReturnsOnCall[len(fake.createArgsForCall)]
 	fake.createArgsForCall = append(fake.createArgsForCall, struct {
+		ctx            context.Context
 		deploymentName string
 		planID         string
 		requestParams  map[string]interface{}
 		boshContextID  string
 		logger         *log.Logger
-	}{deploymentName, planID, requestParams, boshContextID, logger})
-	fake.recordInvocation("Create", []interface{}{deploymentName, planID, requestParams, boshContextID, logger})
+	}{ctx, deploymentName, planID, requestParams, boshContextID, logger})
+	fake.recordInvocation("Create", []interface{}{ctx, deploymentName, planID, requestParams, boshContextID, logger})
 	fake.createMutex.Unlock()
 	if fake.CreateStub != nil {
-		return fake.CreateStub(deploymentName, planID, requestParams, boshContextID, logger)
+		return fake.CreateStub(ctx, deploymentName, planID, requestParams, boshContextID, logger)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
@@ -98,10 +136,10 @@ func (fake *FakeDeployer) CreateCallCount() int {
 	return len(fake.createArgsForCall)
 }
 
-func (fake *FakeDeployer) CreateArgsForCall(i int) (string, string, map[string]interface{}, string, *log.Logger) {
+func (fake *FakeDeployer) CreateArgsForCall(i int) (context.Context, string, string, map[string]interface{}, string, *log.Logger) {
 	fake.createMutex.RLock()
 	defer fake.createMutex.RUnlock()
-	return fake.createArgsForCall[i].deploymentName, fake.createArgsForCall[i].planID, fake.createArgsForCall[i].requestParams, fake.createArgsForCall[i].boshContextID, fake.createArgsForCall[i].logger
+	return fake.createArgsForCall[i].ctx, fake.createArgsForCall[i].deploymentName, fake.createArgsForCall[i].planID, fake.createArgsForCall[i].requestParams, fake.createArgsForCall[i].boshContextID, fake.createArgsForCall[i].logger
 }
 
 func (fake *FakeDeployer) CreateReturns(result1 int, result2 []byte, result3 error) {
@@ -129,21 +167,22 @@ func (fake *FakeDeployer) CreateReturnsOnCall(i int, result1 int, result2 []byte
 	}{result1, result2, result3}
 }
 
-func (fake *FakeDeployer) Update(deploymentName string, planID string, requestParams map[string]interface{}, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error) {
+func (fake *FakeDeployer) Update(ctx context.Context, deploymentName string, planID string, requestParams map[string]interface{}, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error) {
 	fake.updateMutex.Lock()
 	ret, specificReturn := fake.updateReturnsOnCall[len(fake.updateArgsForCall)]
 	fake.updateArgsForCall = append(fake.updateArgsForCall, struct {
+		ctx            context.Context
 		deploymentName string
 		planID         string
 		requestParams  map[string]interface{}
 		previousPlanID *string
 		boshContextID  string
 		logger         *log.Logger
-	}{deploymentName, planID, requestParams, previousPlanID, boshContextID, logger})
-	fake.recordInvocation("Update", []interface{}{deploymentName, planID, requestParams, previousPlanID, boshContextID, logger})
+	}{ctx, deploymentName, planID, requestParams, previousPlanID, boshContextID, logger})
+	fake.recordInvocation("Update", []interface{}{ctx, deploymentName, planID, requestParams, previousPlanID, boshContextID, logger})
 	fake.updateMutex.Unlock()
 	if fake.UpdateStub != nil {
-		return fake.UpdateStub(deploymentName, planID, requestParams, previousPlanID, boshContextID, logger)
+		return fake.UpdateStub(ctx, deploymentName, planID, requestParams, previousPlanID, boshContextID, logger)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
@@ -157,10 +196,10 @@ func (fake *FakeDeployer) UpdateCallCount() int {
 	return len(fake.updateArgsForCall)
 }
 
-func (fake *FakeDeployer) UpdateArgsForCall(i int) (string, string, map[string]interface{}, *string, string, *log.Logger) {
+func (fake *FakeDeployer) UpdateArgsForCall(i int) (context.Context, string, string, map[string]interface{}, *string, string, *log.Logger) {
 	fake.updateMutex.RLock()
 	defer fake.updateMutex.RUnlock()
-	return fake.updateArgsForCall[i].deploymentName, fake.updateArgsForCall[i].planID, fake.updateArgsForCall[i].requestParams, fake.updateArgsForCall[i].previousPlanID, fake.updateArgsForCall[i].boshContextID, fake.updateArgsForCall[i].logger
+	return fake.updateArgsForCall[i].ctx, fake.updateArgsForCall[i].deploymentName, fake.updateArgsForCall[i].planID, fake.updateArgsForCall[i].requestParams, fake.updateArgsForCall[i].previousPlanID, fake.updateArgsForCall[i].boshContextID, fake.updateArgsForCall[i].logger
 }
 
 func (fake *FakeDeployer) UpdateReturns(result1 int, result2 []byte, result3 error) {
@@ -188,20 +227,21 @@ func (fake *FakeDeployer) UpdateReturnsOnCall(i int, result1 int, result2 []byte
 	}{result1, result2, result3}
 }
 
-func (fake *FakeDeployer) Upgrade(deploymentName string, planID string, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error) {
+func (fake *FakeDeployer) Upgrade(ctx context.Context, deploymentName string, planID string, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error) {
 	fake.upgradeMutex.Lock()
 	ret, specificReturn := fake.upgradeReturnsOnCall[len(fake.upgradeArgsForCall)]
 	fake.upgradeArgsForCall = append(fake.upgradeArgsForCall, struct {
+		ctx            context.Context
 		deploymentName string
 		planID         string
 		previousPlanID *string
 		boshContextID  string
 		logger         *log.Logger
-	}{deploymentName, planID, previousPlanID, boshContextID, logger})
-	fake.recordInvocation("Upgrade", []interface{}{deploymentName, planID, previousPlanID, boshContextID, logger})
+	}{ctx, deploymentName, planID, previousPlanID, boshContextID, logger})
+	fake.recordInvocation("Upgrade", []interface{}{ctx, deploymentName, planID, previousPlanID, boshContextID, logger})
 	fake.upgradeMutex.Unlock()
 	if fake.UpgradeStub != nil {
-		return fake.UpgradeStub(deploymentName, planID, previousPlanID, boshContextID, logger)
+		return fake.UpgradeStub(ctx, deploymentName, planID, previousPlanID, boshContextID, logger)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
@@ -215,10 +255,10 @@ func (fake *FakeDeployer) UpgradeCallCount() int {
 	return len(fake.upgradeArgsForCall)
 }
 
-func (fake *FakeDeployer) UpgradeArgsForCall(i int) (string, string, *string, string, *log.Logger) {
+func (fake *FakeDeployer) UpgradeArgsForCall(i int) (context.Context, string, string, *string, string, *log.Logger) {
 	fake.upgradeMutex.RLock()
 	defer fake.upgradeMutex.RUnlock()
-	return fake.upgradeArgsForCall[i].deploymentName, fake.upgradeArgsForCall[i].planID, fake.upgradeArgsForCall[i].previousPlanID, fake.upgradeArgsForCall[i].boshContextID, fake.upgradeArgsForCall[i].logger
+	return fake.upgradeArgsForCall[i].ctx, fake.upgradeArgsForCall[i].deploymentName, fake.upgradeArgsForCall[i].planID, fake.upgradeArgsForCall[i].previousPlanID, fake.upgradeArgsForCall[i].boshContextID, fake.upgradeArgsForCall[i].logger
 }
 
 func (fake *FakeDeployer) UpgradeReturns(result1 int, result2 []byte, result3 error) {
@@ -246,6 +286,115 @@ func (fake *FakeDeployer) UpgradeReturnsOnCall(i int, result1 int, result2 []byt
 	}{result1, result2, result3}
 }
 
+func (fake *FakeDeployer) ChangeJobState(deploymentName string, state boshdirector.JobState, logger *log.Logger) (int, error) {
+	fake.changeJobStateMutex.Lock()
+	ret, specificReturn := fake.changeJobStateReturnsOnCall[len(fake.changeJobStateArgsForCall)]
+	fake.changeJobStateArgsForCall = append(fake.changeJobStateArgsForCall, struct {
+		deploymentName string
+		state          boshdirector.JobState
+		logger         *log.Logger
+	}{deploymentName, state, logger})
+	fake.recordInvocation("ChangeJobState", []interface{}{deploymentName, state, logger})
+	fake.changeJobStateMutex.Unlock()
+	if fake.ChangeJobStateStub != nil {
+		return fake.ChangeJobStateStub(deploymentName, state, logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.changeJobStateReturns.result1, fake.changeJobStateReturns.result2
+}
+
+func (fake *FakeDeployer) ChangeJobStateCallCount() int {
+	fake.changeJobStateMutex.RLock()
+	defer fake.changeJobStateMutex.RUnlock()
+	return len(fake.changeJobStateArgsForCall)
+}
+
+func (fake *FakeDeployer) ChangeJobStateArgsForCall(i int) (string, boshdirector.JobState, *log.Logger) {
+	fake.changeJobStateMutex.RLock()
+	defer fake.changeJobStateMutex.RUnlock()
+	return fake.changeJobStateArgsForCall[i].deploymentName, fake.changeJobStateArgsForCall[i].state, fake.changeJobStateArgsForCall[i].logger
+}
+
+func (fake *FakeDeployer) ChangeJobStateReturns(result1 int, result2 error) {
+	fake.ChangeJobStateStub = nil
+	fake.changeJobStateReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDeployer) ChangeJobStateReturnsOnCall(i int, result1 int, result2 error) {
+	fake.ChangeJobStateStub = nil
+	if fake.changeJobStateReturnsOnCall == nil {
+		fake.changeJobStateReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.changeJobStateReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDeployer) Rollback(deploymentName string, boshContextID string, logger *log.Logger) (int, []byte, error) {
+	fake.rollbackMutex.Lock()
+	ret, specificReturn := fake.rollbackReturnsOnCall[len(fake.rollbackArgsForCall)]
+	fake.rollbackArgsForCall = append(fake.rollbackArgsForCall, struct {
+		deploymentName string
+		boshContextID  string
+		logger         *log.Logger
+	}{deploymentName, boshContextID, logger})
+	fake.recordInvocation("Rollback", []interface{}{deploymentName, boshContextID, logger})
+	fake.rollbackMutex.Unlock()
+	if fake.RollbackStub != nil {
+		return fake.RollbackStub(deploymentName, boshContextID, logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.rollbackReturns.result1, fake.rollbackReturns.result2, fake.rollbackReturns.result3
+}
+
+func (fake *FakeDeployer) RollbackCallCount() int {
+	fake.rollbackMutex.RLock()
+	defer fake.rollbackMutex.RUnlock()
+	return len(fake.rollbackArgsForCall)
+}
+
+func (fake *FakeDeployer) RollbackArgsForCall(i int) (string, string, *log.Logger) {
+	fake.rollbackMutex.RLock()
+	defer fake.rollbackMutex.RUnlock()
+	return fake.rollbackArgsForCall[i].deploymentName, fake.rollbackArgsForCall[i].boshContextID, fake.rollbackArgsForCall[i].logger
+}
+
+func (fake *FakeDeployer) RollbackReturns(result1 int, result2 []byte, result3 error) {
+	fake.RollbackStub = nil
+	fake.rollbackReturns = struct {
+		result1 int
+		result2 []byte
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeDeployer) RollbackReturnsOnCall(i int, result1 int, result2 []byte, result3 error) {
+	fake.RollbackStub = nil
+	if fake.rollbackReturnsOnCall == nil {
+		fake.rollbackReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 []byte
+			result3 error
+		})
+	}
+	fake.rollbackReturnsOnCall[i] = struct {
+		result1 int
+		result2 []byte
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeDeployer) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -255,6 +404,10 @@ func (fake *FakeDeployer) Invocations() map[string][][]interface{} {
 	defer fake.updateMutex.RUnlock()
 	fake.upgradeMutex.RLock()
 	defer fake.upgradeMutex.RUnlock()
+	fake.changeJobStateMutex.RLock()
+	defer fake.changeJobStateMutex.RUnlock()
+	fake.rollbackMutex.RLock()
+	defer fake.rollbackMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -2,18 +2,21 @@
 package fakes
 
 import (
+	"context"
 	"log"
 	"sync"
 
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
-	"github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
 )
 
 type FakeServiceAdapterClient struct {
-	CreateBindingStub        func(bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, logger *log.Logger) (serviceadapter.Binding, error)
+	CreateBindingStub        func(ctx context.Context, bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, logger *log.Logger) (sdk.Binding, error)
 	createBindingMutex       sync.RWMutex
 	createBindingArgsForCall []struct {
+		ctx                context.Context
 		bindingID          string
 		deploymentTopology bosh.BoshVMs
 		manifest           []byte
@@ -21,16 +24,17 @@ type FakeServiceAdapterClient struct {
 		logger             *log.Logger
 	}
 	createBindingReturns struct {
-		result1 serviceadapter.Binding
+		result1 sdk.Binding
 		result2 error
 	}
 	createBindingReturnsOnCall map[int]struct {
-		result1 serviceadapter.Binding
+		result1 sdk.Binding
 		result2 error
 	}
-	DeleteBindingStub        func(bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, logger *log.Logger) error
+	DeleteBindingStub        func(ctx context.Context, bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, logger *log.Logger) error
 	deleteBindingMutex       sync.RWMutex
 	deleteBindingArgsForCall []struct {
+		ctx                context.Context
 		bindingID          string
 		deploymentTopology bosh.BoshVMs
 		manifest           []byte
@@ -43,11 +47,12 @@ type FakeServiceAdapterClient struct {
 	deleteBindingReturnsOnCall map[int]struct {
 		result1 error
 	}
-	GenerateDashboardUrlStub        func(instanceID string, plan serviceadapter.Plan, manifest []byte, logger *log.Logger) (string, error)
+	GenerateDashboardUrlStub        func(ctx context.Context, instanceID string, plan sdk.Plan, manifest []byte, logger *log.Logger) (string, error)
 	generateDashboardUrlMutex       sync.RWMutex
 	generateDashboardUrlArgsForCall []struct {
+		ctx        context.Context
 		instanceID string
-		plan       serviceadapter.Plan
+		plan       sdk.Plan
 		manifest   []byte
 		logger     *log.Logger
 	}
@@ -59,11 +64,57 @@ type FakeServiceAdapterClient struct {
 		result1 string
 		result2 error
 	}
+	CapabilitiesStub        func(ctx context.Context, logger *log.Logger) (serviceadapter.Capabilities, error)
+	capabilitiesMutex       sync.RWMutex
+	capabilitiesArgsForCall []struct {
+		ctx    context.Context
+		logger *log.Logger
+	}
+	capabilitiesReturns struct {
+		result1 serviceadapter.Capabilities
+		result2 error
+	}
+	capabilitiesReturnsOnCall map[int]struct {
+		result1 serviceadapter.Capabilities
+		result2 error
+	}
+	ValidateParamsStub        func(ctx context.Context, plan sdk.Plan, previousPlan *sdk.Plan, requestParams map[string]interface{}, logger *log.Logger) error
+	validateParamsMutex       sync.RWMutex
+	validateParamsArgsForCall []struct {
+		ctx           context.Context
+		plan          sdk.Plan
+		previousPlan  *sdk.Plan
+		requestParams map[string]interface{}
+		logger        *log.Logger
+	}
+	validateParamsReturns struct {
+		result1 error
+	}
+	validateParamsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	InstanceStatusStub        func(ctx context.Context, instanceID string, deploymentTopology bosh.BoshVMs, manifest []byte, logger *log.Logger) (serviceadapter.InstanceStatus, error)
+	instanceStatusMutex       sync.RWMutex
+	instanceStatusArgsForCall []struct {
+		ctx                context.Context
+		instanceID         string
+		deploymentTopology bosh.BoshVMs
+		manifest           []byte
+		logger             *log.Logger
+	}
+	instanceStatusReturns struct {
+		result1 serviceadapter.InstanceStatus
+		result2 error
+	}
+	instanceStatusReturnsOnCall map[int]struct {
+		result1 serviceadapter.InstanceStatus
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeServiceAdapterClient) CreateBinding(bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, logger *log.Logger) (serviceadapter.Binding, error) {
+func (fake *FakeServiceAdapterClient) CreateBinding(ctx context.Context, bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, logger *log.Logger) (sdk.Binding, error) {
 	var manifestCopy []byte
 	if manifest != nil {
 		manifestCopy = make([]byte, len(manifest))
@@ -72,16 +123,17 @@ func (fake *FakeServiceAdapterClient) CreateBinding(bindingID string, deployment
 	fake.createBindingMutex.Lock()
 	ret, specificReturn := fake.createBindingReturnsOnCall[len(fake.createBindingArgsForCall)]
 	fake.createBindingArgsForCall = append(fake.createBindingArgsForCall, struct {
+		ctx                context.Context
 		bindingID          string
 		deploymentTopology bosh.BoshVMs
 		manifest           []byte
 		requestParams      map[string]interface{}
 		logger             *log.Logger
-	}{bindingID, deploymentTopology, manifestCopy, requestParams, logger})
-	fake.recordInvocation("CreateBinding", []interface{}{bindingID, deploymentTopology, manifestCopy, requestParams, logger})
+	}{ctx, bindingID, deploymentTopology, manifestCopy, requestParams, logger})
+	fake.recordInvocation("CreateBinding", []interface{}{ctx, bindingID, deploymentTopology, manifestCopy, requestParams, logger})
 	fake.createBindingMutex.Unlock()
 	if fake.CreateBindingStub != nil {
-		return fake.CreateBindingStub(bindingID, deploymentTopology, manifest, requestParams, logger)
+		return fake.CreateBindingStub(ctx, bindingID, deploymentTopology, manifest, requestParams, logger)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -95,35 +147,35 @@ func (fake *FakeServiceAdapterClient) CreateBindingCallCount() int {
 	return len(fake.createBindingArgsForCall)
 }
 
-func (fake *FakeServiceAdapterClient) CreateBindingArgsForCall(i int) (string, bosh.BoshVMs, []byte, map[string]interface{}, *log.Logger) {
+func (fake *FakeServiceAdapterClient) CreateBindingArgsForCall(i int) (context.Context, string, bosh.BoshVMs, []byte, map[string]interface{}, *log.Logger) {
 	fake.createBindingMutex.RLock()
 	defer fake.createBindingMutex.RUnlock()
-	return fake.createBindingArgsForCall[i].bindingID, fake.createBindingArgsForCall[i].deploymentTopology, fake.createBindingArgsForCall[i].manifest, fake.createBindingArgsForCall[i].requestParams, fake.createBindingArgsForCall[i].logger
+	return fake.createBindingArgsForCall[i].ctx, fake.createBindingArgsForCall[i].bindingID, fake.createBindingArgsForCall[i].deploymentTopology, fake.createBindingArgsForCall[i].manifest, fake.createBindingArgsForCall[i].requestParams, fake.createBindingArgsForCall[i].logger
 }
 
-func (fake *FakeServiceAdapterClient) CreateBindingReturns(result1 serviceadapter.Binding, result2 error) {
+func (fake *FakeServiceAdapterClient) CreateBindingReturns(result1 sdk.Binding, result2 error) {
 	fake.CreateBindingStub = nil
 	fake.createBindingReturns = struct {
-		result1 serviceadapter.Binding
+		result1 sdk.Binding
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeServiceAdapterClient) CreateBindingReturnsOnCall(i int, result1 serviceadapter.Binding, result2 error) {
+func (fake *FakeServiceAdapterClient) CreateBindingReturnsOnCall(i int, result1 sdk.Binding, result2 error) {
 	fake.CreateBindingStub = nil
 	if fake.createBindingReturnsOnCall == nil {
 		fake.createBindingReturnsOnCall = make(map[int]struct {
-			result1 serviceadapter.Binding
+			result1 sdk.Binding
 			result2 error
 		})
 	}
 	fake.createBindingReturnsOnCall[i] = struct {
-		result1 serviceadapter.Binding
+		result1 sdk.Binding
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeServiceAdapterClient) DeleteBinding(bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, logger *log.Logger) error {
+func (fake *FakeServiceAdapterClient) DeleteBinding(ctx context.Context, bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, logger *log.Logger) error {
 	var manifestCopy []byte
 	if manifest != nil {
 		manifestCopy = make([]byte, len(manifest))
@@ -132,16 +184,17 @@ func (fake *FakeServiceAdapterClient) DeleteBinding(bindingID string, deployment
 	fake.deleteBindingMutex.Lock()
 	ret, specificReturn := fake.deleteBindingReturnsOnCall[len(fake.deleteBindingArgsForCall)]
 	fake.deleteBindingArgsForCall = append(fake.deleteBindingArgsForCall, struct {
+		ctx                context.Context
 		bindingID          string
 		deploymentTopology bosh.BoshVMs
 		manifest           []byte
 		requestParams      map[string]interface{}
 		logger             *log.Logger
-	}{bindingID, deploymentTopology, manifestCopy, requestParams, logger})
-	fake.recordInvocation("DeleteBinding", []interface{}{bindingID, deploymentTopology, manifestCopy, requestParams, logger})
+	}{ctx, bindingID, deploymentTopology, manifestCopy, requestParams, logger})
+	fake.recordInvocation("DeleteBinding", []interface{}{ctx, bindingID, deploymentTopology, manifestCopy, requestParams, logger})
 	fake.deleteBindingMutex.Unlock()
 	if fake.DeleteBindingStub != nil {
-		return fake.DeleteBindingStub(bindingID, deploymentTopology, manifest, requestParams, logger)
+		return fake.DeleteBindingStub(ctx, bindingID, deploymentTopology, manifest, requestParams, logger)
 	}
 	if specificReturn {
 		return ret.result1
@@ -155,10 +208,10 @@ func (fake *FakeServiceAdapterClient) DeleteBindingCallCount() int {
 	return len(fake.deleteBindingArgsForCall)
 }
 
-func (fake *FakeServiceAdapterClient) DeleteBindingArgsForCall(i int) (string, bosh.BoshVMs, []byte, map[string]interface{}, *log.Logger) {
+func (fake *FakeServiceAdapterClient) DeleteBindingArgsForCall(i int) (context.Context, string, bosh.BoshVMs, []byte, map[string]interface{}, *log.Logger) {
 	fake.deleteBindingMutex.RLock()
 	defer fake.deleteBindingMutex.RUnlock()
-	return fake.deleteBindingArgsForCall[i].bindingID, fake.deleteBindingArgsForCall[i].deploymentTopology, fake.deleteBindingArgsForCall[i].manifest, fake.deleteBindingArgsForCall[i].requestParams, fake.deleteBindingArgsForCall[i].logger
+	return fake.deleteBindingArgsForCall[i].ctx, fake.deleteBindingArgsForCall[i].bindingID, fake.deleteBindingArgsForCall[i].deploymentTopology, fake.deleteBindingArgsForCall[i].manifest, fake.deleteBindingArgsForCall[i].requestParams, fake.deleteBindingArgsForCall[i].logger
 }
 
 func (fake *FakeServiceAdapterClient) DeleteBindingReturns(result1 error) {
@@ -180,7 +233,7 @@ func (fake *FakeServiceAdapterClient) DeleteBindingReturnsOnCall(i int, result1
 	}{result1}
 }
 
-func (fake *FakeServiceAdapterClient) GenerateDashboardUrl(instanceID string, plan serviceadapter.Plan, manifest []byte, logger *log.Logger) (string, error) {
+func (fake *FakeServiceAdapterClient) GenerateDashboardUrl(ctx context.Context, instanceID string, plan sdk.Plan, manifest []byte, logger *log.Logger) (string, error) {
 	var manifestCopy []byte
 	if manifest != nil {
 		manifestCopy = make([]byte, len(manifest))
@@ -189,15 +242,16 @@ func (fake *FakeServiceAdapterClient) GenerateDashboardUrl(instanceID string, pl
 	fake.generateDashboardUrlMutex.Lock()
 	ret, specificReturn := fake.generateDashboardUrlReturnsOnCall[len(fake.generateDashboardUrlArgsForCall)]
 	fake.generateDashboardUrlArgsForCall = append(fake.generateDashboardUrlArgsForCall, struct {
+		ctx        context.Context
 		instanceID string
-		plan       serviceadapter.Plan
+		plan       sdk.Plan
 		manifest   []byte
 		logger     *log.Logger
-	}{instanceID, plan, manifestCopy, logger})
-	fake.recordInvocation("GenerateDashboardUrl", []interface{}{instanceID, plan, manifestCopy, logger})
+	}{ctx, instanceID, plan, manifestCopy, logger})
+	fake.recordInvocation("GenerateDashboardUrl", []interface{}{ctx, instanceID, plan, manifestCopy, logger})
 	fake.generateDashboardUrlMutex.Unlock()
 	if fake.GenerateDashboardUrlStub != nil {
-		return fake.GenerateDashboardUrlStub(instanceID, plan, manifest, logger)
+		return fake.GenerateDashboardUrlStub(ctx, instanceID, plan, manifest, logger)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -211,10 +265,10 @@ func (fake *FakeServiceAdapterClient) GenerateDashboardUrlCallCount() int {
 	return len(fake.generateDashboardUrlArgsForCall)
 }
 
-func (fake *FakeServiceAdapterClient) GenerateDashboardUrlArgsForCall(i int) (string, serviceadapter.Plan, []byte, *log.Logger) {
+func (fake *FakeServiceAdapterClient) GenerateDashboardUrlArgsForCall(i int) (context.Context, string, sdk.Plan, []byte, *log.Logger) {
 	fake.generateDashboardUrlMutex.RLock()
 	defer fake.generateDashboardUrlMutex.RUnlock()
-	return fake.generateDashboardUrlArgsForCall[i].instanceID, fake.generateDashboardUrlArgsForCall[i].plan, fake.generateDashboardUrlArgsForCall[i].manifest, fake.generateDashboardUrlArgsForCall[i].logger
+	return fake.generateDashboardUrlArgsForCall[i].ctx, fake.generateDashboardUrlArgsForCall[i].instanceID, fake.generateDashboardUrlArgsForCall[i].plan, fake.generateDashboardUrlArgsForCall[i].manifest, fake.generateDashboardUrlArgsForCall[i].logger
 }
 
 func (fake *FakeServiceAdapterClient) GenerateDashboardUrlReturns(result1 string, result2 error) {
@@ -239,6 +293,170 @@ func (fake *FakeServiceAdapterClient) GenerateDashboardUrlReturnsOnCall(i int, r
 	}{result1, result2}
 }
 
+func (fake *FakeServiceAdapterClient) Capabilities(ctx context.Context, logger *log.Logger) (serviceadapter.Capabilities, error) {
+	fake.capabilitiesMutex.Lock()
+	ret, specificReturn := fake.capabilitiesReturnsOnCall[len(fake.capabilitiesArgsForCall)]
+	fake.capabilitiesArgsForCall = append(fake.capabilitiesArgsForCall, struct {
+		ctx    context.Context
+		logger *log.Logger
+	}{ctx, logger})
+	fake.recordInvocation("Capabilities", []interface{}{ctx, logger})
+	fake.capabilitiesMutex.Unlock()
+	if fake.CapabilitiesStub != nil {
+		return fake.CapabilitiesStub(ctx, logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.capabilitiesReturns.result1, fake.capabilitiesReturns.result2
+}
+
+func (fake *FakeServiceAdapterClient) CapabilitiesCallCount() int {
+	fake.capabilitiesMutex.RLock()
+	defer fake.capabilitiesMutex.RUnlock()
+	return len(fake.capabilitiesArgsForCall)
+}
+
+func (fake *FakeServiceAdapterClient) CapabilitiesArgsForCall(i int) (context.Context, *log.Logger) {
+	fake.capabilitiesMutex.RLock()
+	defer fake.capabilitiesMutex.RUnlock()
+	return fake.capabilitiesArgsForCall[i].ctx, fake.capabilitiesArgsForCall[i].logger
+}
+
+func (fake *FakeServiceAdapterClient) CapabilitiesReturns(result1 serviceadapter.Capabilities, result2 error) {
+	fake.CapabilitiesStub = nil
+	fake.capabilitiesReturns = struct {
+		result1 serviceadapter.Capabilities
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceAdapterClient) CapabilitiesReturnsOnCall(i int, result1 serviceadapter.Capabilities, result2 error) {
+	fake.CapabilitiesStub = nil
+	if fake.capabilitiesReturnsOnCall == nil {
+		fake.capabilitiesReturnsOnCall = make(map[int]struct {
+			result1 serviceadapter.Capabilities
+			result2 error
+		})
+	}
+	fake.capabilitiesReturnsOnCall[i] = struct {
+		result1 serviceadapter.Capabilities
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceAdapterClient) ValidateParams(ctx context.Context, plan sdk.Plan, previousPlan *sdk.Plan, requestParams map[string]interface{}, logger *log.Logger) error {
+	fake.validateParamsMutex.Lock()
+	ret, specificReturn := fake.validateParamsReturnsOnCall[len(fake.validateParamsArgsForCall)]
+	fake.validateParamsArgsForCall = append(fake.validateParamsArgsForCall, struct {
+		ctx           context.Context
+		plan          sdk.Plan
+		previousPlan  *sdk.Plan
+		requestParams map[string]interface{}
+		logger        *log.Logger
+	}{ctx, plan, previousPlan, requestParams, logger})
+	fake.recordInvocation("ValidateParams", []interface{}{ctx, plan, previousPlan, requestParams, logger})
+	fake.validateParamsMutex.Unlock()
+	if fake.ValidateParamsStub != nil {
+		return fake.ValidateParamsStub(ctx, plan, previousPlan, requestParams, logger)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.validateParamsReturns.result1
+}
+
+func (fake *FakeServiceAdapterClient) ValidateParamsCallCount() int {
+	fake.validateParamsMutex.RLock()
+	defer fake.validateParamsMutex.RUnlock()
+	return len(fake.validateParamsArgsForCall)
+}
+
+func (fake *FakeServiceAdapterClient) ValidateParamsArgsForCall(i int) (context.Context, sdk.Plan, *sdk.Plan, map[string]interface{}, *log.Logger) {
+	fake.validateParamsMutex.RLock()
+	defer fake.validateParamsMutex.RUnlock()
+	return fake.validateParamsArgsForCall[i].ctx, fake.validateParamsArgsForCall[i].plan, fake.validateParamsArgsForCall[i].previousPlan, fake.validateParamsArgsForCall[i].requestParams, fake.validateParamsArgsForCall[i].logger
+}
+
+func (fake *FakeServiceAdapterClient) ValidateParamsReturns(result1 error) {
+	fake.ValidateParamsStub = nil
+	fake.validateParamsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeServiceAdapterClient) ValidateParamsReturnsOnCall(i int, result1 error) {
+	fake.ValidateParamsStub = nil
+	if fake.validateParamsReturnsOnCall == nil {
+		fake.validateParamsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.validateParamsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeServiceAdapterClient) InstanceStatus(ctx context.Context, instanceID string, deploymentTopology bosh.BoshVMs, manifest []byte, logger *log.Logger) (serviceadapter.InstanceStatus, error) {
+	var manifestCopy []byte
+	if manifest != nil {
+		manifestCopy = make([]byte, len(manifest))
+		copy(manifestCopy, manifest)
+	}
+	fake.instanceStatusMutex.Lock()
+	ret, specificReturn := fake.instanceStatusReturnsOnCall[len(fake.instanceStatusArgsForCall)]
+	fake.instanceStatusArgsForCall = append(fake.instanceStatusArgsForCall, struct {
+		ctx                context.Context
+		instanceID         string
+		deploymentTopology bosh.BoshVMs
+		manifest           []byte
+		logger             *log.Logger
+	}{ctx, instanceID, deploymentTopology, manifestCopy, logger})
+	fake.recordInvocation("InstanceStatus", []interface{}{ctx, instanceID, deploymentTopology, manifestCopy, logger})
+	fake.instanceStatusMutex.Unlock()
+	if fake.InstanceStatusStub != nil {
+		return fake.InstanceStatusStub(ctx, instanceID, deploymentTopology, manifest, logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.instanceStatusReturns.result1, fake.instanceStatusReturns.result2
+}
+
+func (fake *FakeServiceAdapterClient) InstanceStatusCallCount() int {
+	fake.instanceStatusMutex.RLock()
+	defer fake.instanceStatusMutex.RUnlock()
+	return len(fake.instanceStatusArgsForCall)
+}
+
+func (fake *FakeServiceAdapterClient) InstanceStatusArgsForCall(i int) (context.Context, string, bosh.BoshVMs, []byte, *log.Logger) {
+	fake.instanceStatusMutex.RLock()
+	defer fake.instanceStatusMutex.RUnlock()
+	return fake.instanceStatusArgsForCall[i].ctx, fake.instanceStatusArgsForCall[i].instanceID, fake.instanceStatusArgsForCall[i].deploymentTopology, fake.instanceStatusArgsForCall[i].manifest, fake.instanceStatusArgsForCall[i].logger
+}
+
+func (fake *FakeServiceAdapterClient) InstanceStatusReturns(result1 serviceadapter.InstanceStatus, result2 error) {
+	fake.InstanceStatusStub = nil
+	fake.instanceStatusReturns = struct {
+		result1 serviceadapter.InstanceStatus
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceAdapterClient) InstanceStatusReturnsOnCall(i int, result1 serviceadapter.InstanceStatus, result2 error) {
+	fake.InstanceStatusStub = nil
+	if fake.instanceStatusReturnsOnCall == nil {
+		fake.instanceStatusReturnsOnCall = make(map[int]struct {
+			result1 serviceadapter.InstanceStatus
+			result2 error
+		})
+	}
+	fake.instanceStatusReturnsOnCall[i] = struct {
+		result1 serviceadapter.InstanceStatus
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeServiceAdapterClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -248,6 +466,12 @@ func (fake *FakeServiceAdapterClient) Invocations() map[string][][]interface{} {
 	defer fake.deleteBindingMutex.RUnlock()
 	fake.generateDashboardUrlMutex.RLock()
 	defer fake.generateDashboardUrlMutex.RUnlock()
+	fake.capabilitiesMutex.RLock()
+	defer fake.capabilitiesMutex.RUnlock()
+	fake.validateParamsMutex.RLock()
+	defer fake.validateParamsMutex.RUnlock()
+	fake.instanceStatusMutex.RLock()
+	defer fake.instanceStatusMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -0,0 +1,49 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"log"
+	"time"
+)
+
+// HealthCheck re-runs the director checks made at startup, as releases and stemcells can be
+// deleted while the broker is running
+func (b *Broker) HealthCheck(logger *log.Logger) error {
+	err := b.checkServiceDeploymentUploaded(logger)
+
+	b.healthLock.Lock()
+	defer b.healthLock.Unlock()
+	b.healthErr = err
+
+	return err
+}
+
+// Health returns the result of the last health check
+func (b *Broker) Health() error {
+	b.healthLock.Lock()
+	defer b.healthLock.Unlock()
+	return b.healthErr
+}
+
+func (b *Broker) RunHealthChecks(interval time.Duration, stop <-chan struct{}) {
+	logger := b.loggerFactory.New()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := b.HealthCheck(logger); err != nil {
+				logger.Printf("health check failed: %s", err)
+			}
+		}
+	}
+}
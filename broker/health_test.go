@@ -0,0 +1,43 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+)
+
+var _ = Describe("Health", func() {
+	It("is healthy after startup", func() {
+		Expect(brokerCreationErr).NotTo(HaveOccurred())
+		Expect(b.Health()).To(Succeed())
+	})
+
+	Context("when a release is deleted from the director after startup", func() {
+		JustBeforeEach(func() {
+			boshClient.GetReleasesReturns(boshdirector.Releases{}, nil)
+		})
+
+		It("reports the missing release", func() {
+			err := b.HealthCheck(loggerFactory.NewWithRequestID())
+
+			Expect(err).To(MatchError(ContainSubstring("release redis version 9")))
+			Expect(b.Health()).To(MatchError(ContainSubstring("release redis version 9")))
+		})
+
+		It("is found by the periodic health checks", func() {
+			stop := make(chan struct{})
+			defer close(stop)
+			go b.RunHealthChecks(time.Millisecond, stop)
+
+			Eventually(b.Health).Should(MatchError(ContainSubstring("release redis version 9")))
+		})
+	})
+})
@@ -0,0 +1,86 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
+	"github.com/pivotal-cf/on-demand-service-broker/task"
+	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
+)
+
+var _ = Describe("Instance status", func() {
+	var (
+		instanceID = "some-instance"
+		logger     *log.Logger
+		vms        = bosh.BoshVMs{"redis-server": []string{"10.0.0.1"}}
+
+		status    serviceadapter.InstanceStatus
+		statusErr error
+	)
+
+	BeforeEach(func() {
+		logger = loggerFactory.NewWithRequestID()
+		boshClient.VMsReturns(vms, nil)
+		boshClient.GetDeploymentReturns([]byte("a-manifest"), true, nil)
+		serviceAdapter.InstanceStatusReturns(serviceadapter.InstanceStatus{"version": "3.2.1", "leader": "redis-server/0"}, nil)
+	})
+
+	JustBeforeEach(func() {
+		status, statusErr = b.InstanceStatus(context.Background(), instanceID, logger)
+	})
+
+	It("passes the instance's VMs and manifest to the adapter", func() {
+		Expect(serviceAdapter.InstanceStatusCallCount()).To(Equal(1))
+		_, actualInstanceID, actualVMs, actualManifest, _ := serviceAdapter.InstanceStatusArgsForCall(0)
+		Expect(actualInstanceID).To(Equal(instanceID))
+		Expect(actualVMs).To(Equal(vms))
+		Expect(actualManifest).To(Equal([]byte("a-manifest")))
+	})
+
+	It("returns the status reported by the adapter", func() {
+		Expect(statusErr).NotTo(HaveOccurred())
+		Expect(status).To(Equal(serviceadapter.InstanceStatus{"version": "3.2.1", "leader": "redis-server/0"}))
+	})
+
+	Context("when the deployment does not exist", func() {
+		BeforeEach(func() {
+			boshClient.VMsReturns(nil, boshdirector.DeploymentNotFoundError{})
+		})
+
+		It("returns a deployment not found error", func() {
+			Expect(statusErr).To(BeAssignableToTypeOf(task.DeploymentNotFoundError{}))
+			Expect(serviceAdapter.InstanceStatusCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the deployment info cannot be retrieved", func() {
+		BeforeEach(func() {
+			boshClient.VMsReturns(nil, errors.New("bosh is down"))
+		})
+
+		It("returns an error", func() {
+			Expect(statusErr).To(MatchError("error getting deployment info for instance some-instance: bosh is down"))
+		})
+	})
+
+	Context("when the adapter fails", func() {
+		BeforeEach(func() {
+			serviceAdapter.InstanceStatusReturns(nil, serviceadapter.NewNotImplementedError("not supported"))
+		})
+
+		It("returns the adapter's error", func() {
+			Expect(statusErr).To(Equal(serviceadapter.NewNotImplementedError("not supported")))
+		})
+	})
+})
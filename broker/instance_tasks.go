@@ -0,0 +1,42 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+)
+
+func (b *Broker) InstanceTasks(instanceID string, logger *log.Logger) (boshdirector.BoshTasks, error) {
+	return b.boshClient.GetTasks(b.deploymentName(instanceID), logger)
+}
+
+// InstanceTask returns a task of the instance's deployment, so that a task of another deployment
+// is not found
+func (b *Broker) InstanceTask(instanceID string, taskID int, logger *log.Logger) (boshdirector.BoshTask, error) {
+	task, err := b.boshClient.GetTask(taskID, logger)
+	switch err.(type) {
+	case nil:
+	case boshdirector.TaskNotFoundError:
+		return boshdirector.BoshTask{}, NewTaskNotFoundError(fmt.Errorf("bosh task %d not found", taskID))
+	default:
+		return boshdirector.BoshTask{}, err
+	}
+
+	if task.Deployment != b.deploymentName(instanceID) {
+		return boshdirector.BoshTask{}, NewTaskNotFoundError(fmt.Errorf("bosh task %d does not belong to instance %s", taskID, instanceID))
+	}
+
+	return task, nil
+}
+
+func (b *Broker) StreamTaskOutput(taskID int, outputType string, writer io.Writer, logger *log.Logger) error {
+	return b.boshClient.StreamTaskOutput(taskID, outputType, writer, logger)
+}
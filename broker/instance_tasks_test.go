@@ -0,0 +1,154 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+)
+
+var _ = Describe("Instance tasks", func() {
+	var (
+		instanceID = "some-instance"
+		logger     *log.Logger
+	)
+
+	BeforeEach(func() {
+		logger = loggerFactory.NewWithRequestID()
+	})
+
+	Describe("listing tasks", func() {
+		var (
+			tasks    boshdirector.BoshTasks
+			tasksErr error
+		)
+
+		JustBeforeEach(func() {
+			tasks, tasksErr = b.InstanceTasks(instanceID, logger)
+		})
+
+		Context("when bosh returns tasks for the deployment", func() {
+			BeforeEach(func() {
+				boshClient.GetTasksReturns(boshdirector.BoshTasks{{ID: 1, State: boshdirector.TaskError}}, nil)
+			})
+
+			It("gets the tasks of the instance's deployment", func() {
+				Expect(boshClient.GetTasksCallCount()).To(Equal(1))
+				actualDeploymentName, _ := boshClient.GetTasksArgsForCall(0)
+				Expect(actualDeploymentName).To(Equal(deploymentName(instanceID)))
+			})
+
+			It("returns the tasks", func() {
+				Expect(tasksErr).NotTo(HaveOccurred())
+				Expect(tasks).To(Equal(boshdirector.BoshTasks{{ID: 1, State: boshdirector.TaskError}}))
+			})
+		})
+
+		Context("when bosh fails to return tasks", func() {
+			BeforeEach(func() {
+				boshClient.GetTasksReturns(nil, errors.New("bosh is down"))
+			})
+
+			It("returns the error", func() {
+				Expect(tasksErr).To(MatchError("bosh is down"))
+			})
+		})
+	})
+
+	Describe("getting a task of an instance", func() {
+		const taskID = 123
+
+		var (
+			task    boshdirector.BoshTask
+			taskErr error
+		)
+
+		BeforeEach(func() {
+			boshClient.GetTaskReturns(boshdirector.BoshTask{ID: taskID, Deployment: deploymentName(instanceID)}, nil)
+		})
+
+		JustBeforeEach(func() {
+			task, taskErr = b.InstanceTask(instanceID, taskID, logger)
+		})
+
+		It("returns the task", func() {
+			Expect(taskErr).NotTo(HaveOccurred())
+			Expect(task.ID).To(Equal(taskID))
+			actualTaskID, _ := boshClient.GetTaskArgsForCall(0)
+			Expect(actualTaskID).To(Equal(taskID))
+		})
+
+		Context("when the task belongs to another deployment", func() {
+			BeforeEach(func() {
+				boshClient.GetTaskReturns(boshdirector.BoshTask{ID: taskID, Deployment: "some-other-deployment"}, nil)
+			})
+
+			It("returns a task not found error", func() {
+				Expect(taskErr).To(BeAssignableToTypeOf(broker.TaskNotFoundError{}))
+			})
+		})
+
+		Context("when the task does not exist", func() {
+			BeforeEach(func() {
+				boshClient.GetTaskReturns(boshdirector.BoshTask{}, boshdirector.TaskNotFoundError{})
+			})
+
+			It("returns a task not found error", func() {
+				Expect(taskErr).To(BeAssignableToTypeOf(broker.TaskNotFoundError{}))
+				Expect(taskErr).To(MatchError(fmt.Sprintf("bosh task %d not found", taskID)))
+			})
+		})
+
+		Context("when the task cannot be retrieved", func() {
+			BeforeEach(func() {
+				boshClient.GetTaskReturns(boshdirector.BoshTask{}, errors.New("bosh is down"))
+			})
+
+			It("returns the error", func() {
+				Expect(taskErr).To(MatchError("bosh is down"))
+			})
+		})
+	})
+
+	Describe("streaming task output", func() {
+		const taskID = 123
+
+		var (
+			output    *bytes.Buffer
+			streamErr error
+		)
+
+		BeforeEach(func() {
+			output = new(bytes.Buffer)
+			boshClient.StreamTaskOutputStub = func(_ int, _ string, writer io.Writer, _ *log.Logger) error {
+				_, err := writer.Write([]byte("some output"))
+				return err
+			}
+		})
+
+		JustBeforeEach(func() {
+			streamErr = b.StreamTaskOutput(taskID, boshdirector.TaskOutputTypeEvent, output, logger)
+		})
+
+		It("streams the requested output of the task", func() {
+			Expect(streamErr).NotTo(HaveOccurred())
+			Expect(boshClient.StreamTaskOutputCallCount()).To(Equal(1))
+			actualTaskID, actualOutputType, _, _ := boshClient.StreamTaskOutputArgsForCall(0)
+			Expect(actualTaskID).To(Equal(taskID))
+			Expect(actualOutputType).To(Equal(boshdirector.TaskOutputTypeEvent))
+			Expect(output.String()).To(Equal("some output"))
+		})
+	})
+})
@@ -16,7 +16,7 @@ import (
 )
 
 func (b *Broker) Instances(logger *log.Logger) ([]string, error) {
-	instanceIDs, err := b.cfClient.GetInstancesOfServiceOffering(b.serviceOffering.ID, logger)
+	instanceIDs, err := b.cfClient.GetInstancesOfServiceOffering(b.serviceOffering.Load().ID, logger)
 	if err != nil {
 		logger.Printf("error listing instances: %s", err)
 		return nil, err
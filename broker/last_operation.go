@@ -25,18 +25,48 @@ var descriptions = map[brokerapi.LastOperationState]map[OperationType]string{
 		OperationTypeUpdate:  "Instance update in progress",
 		OperationTypeUpgrade: "Instance upgrade in progress",
 		OperationTypeDelete:  "Instance deletion in progress",
+
+		OperationTypeRecreate: "Instance recreate in progress",
+		OperationTypeRestart:  "Instance restart in progress",
+		OperationTypeStop:     "Instance stop in progress",
+		OperationTypeStart:    "Instance start in progress",
+
+		OperationTypeBackup:  "Instance backup in progress",
+		OperationTypeRestore: "Instance restore in progress",
+
+		OperationTypeRollback: "Instance rollback in progress",
 	},
 	brokerapi.Succeeded: {
 		OperationTypeCreate:  "Instance provisioning completed",
 		OperationTypeUpdate:  "Instance update completed",
 		OperationTypeUpgrade: "Instance upgrade completed",
 		OperationTypeDelete:  "Instance deletion completed",
+
+		OperationTypeRecreate: "Instance recreate completed",
+		OperationTypeRestart:  "Instance restart completed",
+		OperationTypeStop:     "Instance stop completed",
+		OperationTypeStart:    "Instance start completed",
+
+		OperationTypeBackup:  "Instance backup completed",
+		OperationTypeRestore: "Instance restore completed",
+
+		OperationTypeRollback: "Instance rollback completed",
 	},
 	brokerapi.Failed: {
 		OperationTypeCreate:  "Instance provisioning failed",
 		OperationTypeUpdate:  "Instance update failed",
 		OperationTypeUpgrade: "Failed for bosh task",
 		OperationTypeDelete:  "Instance deletion failed",
+
+		OperationTypeRecreate: "Instance recreate failed",
+		OperationTypeRestart:  "Instance restart failed",
+		OperationTypeStop:     "Instance stop failed",
+		OperationTypeStart:    "Instance start failed",
+
+		OperationTypeBackup:  "Instance backup failed",
+		OperationTypeRestore: "Instance restore failed",
+
+		OperationTypeRollback: "Instance rollback failed",
 	},
 }
 
@@ -44,7 +74,7 @@ func (b *Broker) LastOperation(ctx context.Context, instanceID, operationDataRaw
 ) (brokerapi.LastOperation, error) {
 
 	requestID := uuid.New()
-	ctx = brokercontext.New(ctx, "", requestID, b.serviceOffering.Name, instanceID)
+	ctx = brokercontext.New(ctx, "", requestID, b.serviceOffering.Load().Name, instanceID)
 	logger := b.loggerFactory.NewWithContext(ctx)
 
 	errs := func(err DisplayableError) (brokerapi.LastOperation, error) {
@@ -74,13 +104,13 @@ func (b *Broker) LastOperation(ctx context.Context, instanceID, operationDataRaw
 
 	ctx = brokercontext.WithBoshTaskID(ctx, operationData.BoshTaskID)
 
-	lifeCycleRunner := NewLifeCycleRunner(b.boshClient, b.serviceOffering.Plans)
+	lifeCycleRunner := NewLifeCycleRunner(b.boshClient, b.serviceOffering.Load().Plans)
 
-	lastBoshTask, err := lifeCycleRunner.GetTask(deploymentName(instanceID), operationData, logger)
+	lastBoshTask, err := lifeCycleRunner.GetTask(b.deploymentName(instanceID), operationData, logger)
 	if err != nil {
 		return errs(NewGenericError(ctx, fmt.Errorf(
 			"error retrieving tasks from bosh, for deployment '%s': %s",
-			deploymentName(instanceID), err,
+			b.deploymentName(instanceID), err,
 		)))
 	}
 
@@ -89,6 +119,10 @@ func (b *Broker) LastOperation(ctx context.Context, instanceID, operationDataRaw
 	lastOperation := constructLastOperation(ctx, lastBoshTask, operationData, logger)
 	logLastOperation(instanceID, lastBoshTask, operationData, logger)
 
+	if operationData.OperationType == OperationTypeDelete && lastOperation.State == brokerapi.Succeeded {
+		deleteDeploymentConfigs(b.boshClient, b.deploymentName(instanceID), logger)
+	}
+
 	return lastOperation, nil
 }
 
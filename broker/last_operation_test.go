@@ -17,6 +17,7 @@ import (
 	"github.com/pivotal-cf/brokerapi"
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/task"
 )
 
 var _ = Describe("LastOperation", func() {
@@ -819,4 +820,69 @@ var _ = Describe("LastOperation", func() {
 			)
 		})
 	})
+
+	Context("when a deletion has been polled", func() {
+		var (
+			instanceID = "deleted-instance"
+			lastOpErr  error
+		)
+
+		JustBeforeEach(func() {
+			operationData, err := json.Marshal(broker.OperationData{OperationType: broker.OperationTypeDelete, BoshTaskID: 199})
+			Expect(err).NotTo(HaveOccurred())
+			_, lastOpErr = b.LastOperation(context.Background(), instanceID, string(operationData))
+		})
+
+		Context("and it succeeded", func() {
+			BeforeEach(func() {
+				boshClient.GetTaskReturns(boshdirector.BoshTask{ID: 199, State: boshdirector.TaskDone}, nil)
+			})
+
+			It("deletes the configs kept for the deployment", func() {
+				Expect(lastOpErr).NotTo(HaveOccurred())
+				var deletedTypes []string
+				for i := 0; i < boshClient.DeleteConfigCallCount(); i++ {
+					actualType, actualName, _ := boshClient.DeleteConfigArgsForCall(i)
+					Expect(actualName).To(Equal(deploymentName(instanceID)))
+					deletedTypes = append(deletedTypes, actualType)
+				}
+				Expect(deletedTypes).To(ConsistOf(task.PreviousManifestConfigType, task.MaintenanceWindowConfigType, task.UpgradeDeferredSinceConfigType))
+			})
+
+			Context("and deleting the configs fails", func() {
+				BeforeEach(func() {
+					boshClient.DeleteConfigReturns(errors.New("director error"))
+				})
+
+				It("still reports the deletion as succeeded and logs a warning", func() {
+					Expect(lastOpErr).NotTo(HaveOccurred())
+					Expect(logBuffer.String()).To(ContainSubstring(
+						fmt.Sprintf("warning: error deleting odb-previous-manifest config of deployment %s: director error", deploymentName(instanceID)),
+					))
+				})
+			})
+		})
+
+		Context("and it is still in progress", func() {
+			BeforeEach(func() {
+				boshClient.GetTaskReturns(boshdirector.BoshTask{ID: 199, State: boshdirector.TaskProcessing}, nil)
+			})
+
+			It("keeps the configs kept for the deployment", func() {
+				Expect(lastOpErr).NotTo(HaveOccurred())
+				Expect(boshClient.DeleteConfigCallCount()).To(BeZero())
+			})
+		})
+
+		Context("and it failed", func() {
+			BeforeEach(func() {
+				boshClient.GetTaskReturns(boshdirector.BoshTask{ID: 199, State: boshdirector.TaskError}, nil)
+			})
+
+			It("keeps the configs kept for the deployment", func() {
+				Expect(lastOpErr).NotTo(HaveOccurred())
+				Expect(boshClient.DeleteConfigCallCount()).To(BeZero())
+			})
+		})
+	})
 })
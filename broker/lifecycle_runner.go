@@ -38,6 +38,8 @@ func (l LifeCycleRunner) GetTask(deploymentName string, operationData OperationD
 		return l.processPostDeployment(deploymentName, operationData, logger)
 	case validPreDeleteOpType(operationData.OperationType):
 		return l.processPreDelete(deploymentName, operationData, logger)
+	case validBackupOpType(operationData.OperationType):
+		return l.processBackupErrand(deploymentName, operationData, logger)
 	default:
 		return l.boshClient.GetTask(operationData.BoshTaskID, logger)
 	}
@@ -46,13 +48,18 @@ func (l LifeCycleRunner) GetTask(deploymentName string, operationData OperationD
 func validPostDeployOpType(op OperationType) bool {
 	return op == OperationTypeCreate ||
 		op == OperationTypeUpdate ||
-		op == OperationTypeUpgrade
+		op == OperationTypeUpgrade ||
+		op == OperationTypeRollback
 }
 
 func validPreDeleteOpType(op OperationType) bool {
 	return op == OperationTypeDelete
 }
 
+func validBackupOpType(op OperationType) bool {
+	return op == OperationTypeBackup || op == OperationTypeRestore
+}
+
 func (l LifeCycleRunner) processPostDeployment(
 	deploymentName string,
 	operationData OperationData,
@@ -116,6 +123,7 @@ func (l LifeCycleRunner) processPreDelete(
 		if err != nil {
 			return boshdirector.BoshTask{}, err
 		}
+
 		return l.boshClient.GetTask(taskID, logger)
 	case 2:
 		// there must be a delete deployment and it must be the first in the task list
@@ -126,6 +134,26 @@ func (l LifeCycleRunner) processPreDelete(
 	}
 }
 
+// processBackupErrand reads the errand task by context, so that an errand exiting non-zero is reported as failed
+func (l LifeCycleRunner) processBackupErrand(
+	deploymentName string,
+	operationData OperationData,
+	logger *log.Logger,
+) (boshdirector.BoshTask, error) {
+	boshTasks, err := l.boshClient.GetNormalisedTasksByContext(deploymentName, operationData.BoshContextID, logger)
+	if err != nil {
+		return boshdirector.BoshTask{}, err
+	}
+
+	for _, task := range boshTasks {
+		if task.ID == operationData.BoshTaskID {
+			return task, nil
+		}
+	}
+
+	return boshdirector.BoshTask{}, fmt.Errorf("task %d not found for context id: %s", operationData.BoshTaskID, operationData.BoshContextID)
+}
+
 func (l LifeCycleRunner) runErrand(deploymentName, errand, contextID string, log *log.Logger) (boshdirector.BoshTask, error) {
 	taskID, err := l.boshClient.RunErrand(deploymentName, errand, contextID, log)
 	if err != nil {
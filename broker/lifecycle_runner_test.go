@@ -436,6 +436,10 @@ var _ = Describe("Lifecycle runner", func() {
 				Expect(task.State).To(Equal(boshdirector.TaskProcessing))
 			})
 
+			It("keeps the configs kept for the deployment until its deletion has succeeded", func() {
+				Expect(boshClient.DeleteConfigCallCount()).To(BeZero())
+			})
+
 			Context("and running bosh delete deployment fails", func() {
 				BeforeEach(func() {
 					boshClient.DeleteDeploymentReturns(0, errors.New("some err"))
@@ -485,4 +489,37 @@ var _ = Describe("Lifecycle runner", func() {
 			})
 		})
 	})
+
+	Describe("backup and restore errands", func() {
+		BeforeEach(func() {
+			operationData = broker.OperationData{
+				BoshTaskID:    taskErrored.ID,
+				BoshContextID: contextID,
+				OperationType: broker.OperationTypeBackup,
+			}
+			boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{taskErrored}, nil)
+		})
+
+		It("returns the errand task with its normalised state", func() {
+			task, err := deployRunner.GetTask(deploymentName, operationData, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(task).To(Equal(taskErrored))
+
+			actualDeploymentName, actualContextID, _ := boshClient.GetNormalisedTasksByContextArgsForCall(0)
+			Expect(actualDeploymentName).To(Equal(deploymentName))
+			Expect(actualContextID).To(Equal(contextID))
+			Expect(boshClient.RunErrandCallCount()).To(BeZero())
+		})
+
+		Context("when the errand task is not found for the context id", func() {
+			BeforeEach(func() {
+				boshClient.GetNormalisedTasksByContextReturns(boshdirector.BoshTasks{taskComplete}, nil)
+			})
+
+			It("returns an error", func() {
+				_, err := deployRunner.GetTask(deploymentName, operationData, logger)
+				Expect(err).To(MatchError("task 2 not found for context id: some-uuid"))
+			})
+		})
+	})
 })
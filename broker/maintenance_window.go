@@ -0,0 +1,46 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/task"
+)
+
+// MaintenanceWindow returns the window during which the instance may be upgraded: the one set by the
+// instance's parameters if any, else its plan's. Instances without either may be upgraded at any time.
+func (b *Broker) MaintenanceWindow(instanceID string, logger *log.Logger) (*config.MaintenanceWindow, error) {
+	instance, err := b.cfClient.GetInstanceState(instanceID, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, found := b.serviceOffering.Load().FindPlanByID(instance.PlanID)
+	if !found {
+		return nil, fmt.Errorf("plan %s not found", instance.PlanID)
+	}
+
+	deploymentName := b.deploymentName(instanceID)
+	content, found, err := b.boshClient.GetConfig(task.MaintenanceWindowConfigType, deploymentName, logger)
+	if err != nil {
+		logger.Printf("error getting maintenance window of deployment %s: %s", deploymentName, err)
+		return nil, err
+	}
+	if !found {
+		return plan.MaintenanceWindow, nil
+	}
+
+	instanceWindow, err := task.MaintenanceWindowOf(content)
+	if err != nil {
+		logger.Printf("error reading maintenance window of deployment %s: %s", deploymentName, err)
+		return nil, err
+	}
+	return instanceWindow, nil
+}
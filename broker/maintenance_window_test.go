@@ -0,0 +1,97 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/cf"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/task"
+)
+
+var _ = Describe("maintenance windows", func() {
+	var (
+		planWindow = &config.MaintenanceWindow{Days: []string{"sun"}, Start: "01:00", Duration: "2h"}
+
+		window    *config.MaintenanceWindow
+		windowErr error
+	)
+
+	BeforeEach(func() {
+		serviceCatalog.Plans[0].MaintenanceWindow = planWindow
+		cfClient.GetInstanceStateReturns(cf.InstanceState{PlanID: existingPlanID}, nil)
+		boshClient.GetConfigReturns(nil, false, nil)
+	})
+
+	JustBeforeEach(func() {
+		window, windowErr = b.MaintenanceWindow("some-instance", loggerFactory.NewWithRequestID())
+	})
+
+	It("returns the plan's window", func() {
+		Expect(windowErr).NotTo(HaveOccurred())
+		Expect(window).To(Equal(planWindow))
+
+		actualType, actualDeploymentName, _ := boshClient.GetConfigArgsForCall(0)
+		Expect(actualType).To(Equal(task.MaintenanceWindowConfigType))
+		Expect(actualDeploymentName).To(Equal("service-instance_some-instance"))
+	})
+
+	Context("when the instance sets its own window", func() {
+		BeforeEach(func() {
+			boshClient.GetConfigReturns([]byte(`{"start":"03:00","duration":"1h"}`), true, nil)
+		})
+
+		It("returns the instance's window", func() {
+			Expect(windowErr).NotTo(HaveOccurred())
+			Expect(window).To(Equal(&config.MaintenanceWindow{Start: "03:00", Duration: "1h"}))
+		})
+	})
+
+	Context("when neither the instance nor its plan has a window", func() {
+		BeforeEach(func() {
+			cfClient.GetInstanceStateReturns(cf.InstanceState{PlanID: secondPlanID}, nil)
+		})
+
+		It("returns no window", func() {
+			Expect(windowErr).NotTo(HaveOccurred())
+			Expect(window).To(BeNil())
+		})
+	})
+
+	Context("when the instance cannot be found", func() {
+		BeforeEach(func() {
+			cfClient.GetInstanceStateReturns(cf.InstanceState{}, cf.NewResourceNotFoundError("not found"))
+		})
+
+		It("returns the error", func() {
+			Expect(windowErr).To(BeAssignableToTypeOf(cf.ResourceNotFoundError{}))
+		})
+	})
+
+	Context("when getting the window's config fails", func() {
+		BeforeEach(func() {
+			boshClient.GetConfigReturns(nil, false, errors.New("director error"))
+		})
+
+		It("returns the error", func() {
+			Expect(windowErr).To(MatchError("director error"))
+		})
+	})
+
+	Context("when the window's config is invalid", func() {
+		BeforeEach(func() {
+			boshClient.GetConfigReturns([]byte("not json"), true, nil)
+		})
+
+		It("returns an error", func() {
+			Expect(windowErr).To(MatchError(ContainSubstring("odb-maintenance-window config is invalid")))
+		})
+	})
+})
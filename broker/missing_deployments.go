@@ -0,0 +1,141 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/pborman/uuid"
+	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/task"
+)
+
+type MissingDeployment struct {
+	InstanceID       string
+	DeploymentName   string
+	PlanID           string
+	SpaceGUID        string
+	OrganizationGUID string
+}
+
+// MissingDeployments finds service instances in Cloud Foundry whose BOSH deployment no longer exists
+func (b *Broker) MissingDeployments(logger *log.Logger) ([]MissingDeployment, error) {
+	instances, err := b.cfClient.GetServiceInstancesOfServiceOffering(b.serviceOffering.Load().ID, logger)
+	if err != nil {
+		logger.Printf("error listing instances: %s", err)
+		return nil, err
+	}
+
+	deployments, err := b.boshClient.GetDeployments(logger)
+	if err != nil {
+		logger.Printf("error getting deployments: %s", err)
+		return nil, err
+	}
+
+	deployedInstanceIDs := map[string]bool{}
+	for _, deployment := range deployments {
+		if instanceID, isInstance := b.instanceID(deployment.Name); isInstance {
+			deployedInstanceIDs[instanceID] = true
+		}
+	}
+
+	organizationsBySpace := map[string]string{}
+	missing := []MissingDeployment{}
+	for _, instance := range instances {
+		if deployedInstanceIDs[instance.GUID] {
+			continue
+		}
+
+		if instance.LastOperation.IsIncompleteCreate() || instance.LastOperation.IsIncompleteDelete() {
+			continue
+		}
+
+		orgGUID, found := organizationsBySpace[instance.SpaceGUID]
+		if !found {
+			orgGUID, err = b.cfClient.GetOrganizationGUIDOfSpace(instance.SpaceGUID, logger)
+			if err != nil {
+				logger.Printf("error getting organization of space %s: %s", instance.SpaceGUID, err)
+				return nil, err
+			}
+			organizationsBySpace[instance.SpaceGUID] = orgGUID
+		}
+
+		missing = append(missing, MissingDeployment{
+			InstanceID:       instance.GUID,
+			DeploymentName:   b.deploymentName(instance.GUID),
+			PlanID:           instance.PlanID,
+			SpaceGUID:        instance.SpaceGUID,
+			OrganizationGUID: orgGUID,
+		})
+	}
+
+	return missing, nil
+}
+
+// RecreateMissingDeployment deploys a missing instance again from the adapter with its current
+// plan. Arbitrary parameters given at provision time are not stored, so the plan defaults are used.
+func (b *Broker) RecreateMissingDeployment(ctx context.Context, instanceID string, logger *log.Logger) (OperationData, error) {
+	b.deploymentLock.Lock()
+	defer b.deploymentLock.Unlock()
+
+	missingDeployments, err := b.MissingDeployments(logger)
+	if err != nil {
+		return OperationData{}, err
+	}
+
+	var missing *MissingDeployment
+	for i := range missingDeployments {
+		if missingDeployments[i].InstanceID == instanceID {
+			missing = &missingDeployments[i]
+		}
+	}
+	if missing == nil {
+		return OperationData{}, NewDeploymentNotMissingError(
+			fmt.Errorf("service instance %s is not in Cloud Foundry or its deployment exists", instanceID),
+		)
+	}
+
+	plan, found := b.serviceOffering.Load().FindPlanByID(missing.PlanID)
+	if !found {
+		return OperationData{}, fmt.Errorf("plan %s of instance %s not found in broker config", missing.PlanID, instanceID)
+	}
+
+	requestParams, err := convertDetailsToMap(brokerapi.DetailsWithRawParameters(brokerapi.ProvisionDetails{
+		ServiceID:        b.serviceOffering.Load().ID,
+		PlanID:           plan.ID,
+		OrganizationGUID: missing.OrganizationGUID,
+		SpaceGUID:        missing.SpaceGUID,
+	}))
+	if err != nil {
+		return OperationData{}, err
+	}
+
+	var boshContextID string
+	if plan.PostDeployErrand() != "" {
+		boshContextID = uuid.New()
+	}
+
+	logger.Printf("recreating missing deployment for instance %s with plan %s\n", instanceID, plan.ID)
+	boshTaskID, _, err := b.deployer.Create(ctx, missing.DeploymentName, plan.ID, requestParams, boshContextID, logger)
+	switch err := err.(type) {
+	case nil:
+	case task.TaskInProgressError:
+		return OperationData{}, NewOperationInProgressError(err)
+	default:
+		logger.Printf("error recreating missing deployment for instance %s: %s", instanceID, err)
+		return OperationData{}, err
+	}
+
+	return OperationData{
+		BoshTaskID:           boshTaskID,
+		OperationType:        OperationTypeCreate,
+		BoshContextID:        boshContextID,
+		PostDeployErrandName: plan.PostDeployErrand(),
+	}, nil
+}
@@ -0,0 +1,260 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/cf"
+	"github.com/pivotal-cf/on-demand-service-broker/task"
+)
+
+var _ = Describe("Missing Deployments", func() {
+	var logger *log.Logger
+
+	BeforeEach(func() {
+		logger = loggerFactory.NewWithRequestID()
+		cfClient.GetServiceInstancesOfServiceOfferingReturns([]cf.ServiceInstance{
+			{GUID: "one", PlanID: existingPlanID, SpaceGUID: "space-a"},
+			{GUID: "two", PlanID: postDeployErrandPlanID, SpaceGUID: "space-a"},
+			{GUID: "three", PlanID: secondPlanID, SpaceGUID: "space-b"},
+		}, nil)
+		boshClient.GetDeploymentsReturns([]boshdirector.Deployment{{Name: "service-instance_three"}, {Name: "cf"}}, nil)
+		cfClient.GetOrganizationGUIDOfSpaceReturns("some-org", nil)
+	})
+
+	Describe("listing", func() {
+		var (
+			missing    []broker.MissingDeployment
+			missingErr error
+		)
+
+		JustBeforeEach(func() {
+			missing, missingErr = b.MissingDeployments(logger)
+		})
+
+		It("returns the instances without a deployment with their plan and organization", func() {
+			Expect(missingErr).NotTo(HaveOccurred())
+			Expect(missing).To(Equal([]broker.MissingDeployment{
+				{InstanceID: "one", DeploymentName: "service-instance_one", PlanID: existingPlanID, SpaceGUID: "space-a", OrganizationGUID: "some-org"},
+				{InstanceID: "two", DeploymentName: "service-instance_two", PlanID: postDeployErrandPlanID, SpaceGUID: "space-a", OrganizationGUID: "some-org"},
+			}))
+		})
+
+		It("looks up the organization of each space once", func() {
+			Expect(cfClient.GetOrganizationGUIDOfSpaceCallCount()).To(Equal(1))
+			actualSpaceGUID, _ := cfClient.GetOrganizationGUIDOfSpaceArgsForCall(0)
+			Expect(actualSpaceGUID).To(Equal("space-a"))
+		})
+
+		Context("when every instance has a deployment", func() {
+			BeforeEach(func() {
+				cfClient.GetServiceInstancesOfServiceOfferingReturns([]cf.ServiceInstance{{GUID: "three", PlanID: secondPlanID}}, nil)
+			})
+
+			It("returns an empty list", func() {
+				Expect(missingErr).NotTo(HaveOccurred())
+				Expect(missing).To(BeEmpty())
+			})
+		})
+
+		Context("when the create of an instance is still in progress", func() {
+			BeforeEach(func() {
+				cfClient.GetServiceInstancesOfServiceOfferingReturns([]cf.ServiceInstance{
+					{GUID: "one", PlanID: existingPlanID, SpaceGUID: "space-a", LastOperation: cf.LastOperation{Type: cf.OperationTypeCreate, State: cf.OperationStateInProgress}},
+					{GUID: "two", PlanID: postDeployErrandPlanID, SpaceGUID: "space-a"},
+				}, nil)
+			})
+
+			It("does not report it as missing", func() {
+				Expect(missingErr).NotTo(HaveOccurred())
+				Expect(missing).To(Equal([]broker.MissingDeployment{
+					{InstanceID: "two", DeploymentName: "service-instance_two", PlanID: postDeployErrandPlanID, SpaceGUID: "space-a", OrganizationGUID: "some-org"},
+				}))
+			})
+		})
+
+		Context("when the create of an instance failed", func() {
+			BeforeEach(func() {
+				cfClient.GetServiceInstancesOfServiceOfferingReturns([]cf.ServiceInstance{
+					{GUID: "one", PlanID: existingPlanID, SpaceGUID: "space-a", LastOperation: cf.LastOperation{Type: cf.OperationTypeCreate, State: cf.OperationStateFailed}},
+					{GUID: "two", PlanID: postDeployErrandPlanID, SpaceGUID: "space-a"},
+				}, nil)
+			})
+
+			It("does not report it as missing", func() {
+				Expect(missingErr).NotTo(HaveOccurred())
+				Expect(missing).To(Equal([]broker.MissingDeployment{
+					{InstanceID: "two", DeploymentName: "service-instance_two", PlanID: postDeployErrandPlanID, SpaceGUID: "space-a", OrganizationGUID: "some-org"},
+				}))
+			})
+		})
+
+		Context("when the delete of an instance still in progress", func() {
+			BeforeEach(func() {
+				cfClient.GetServiceInstancesOfServiceOfferingReturns([]cf.ServiceInstance{
+					{GUID: "one", PlanID: existingPlanID, SpaceGUID: "space-a", LastOperation: cf.LastOperation{Type: cf.OperationTypeDelete, State: cf.OperationStateInProgress}},
+					{GUID: "two", PlanID: postDeployErrandPlanID, SpaceGUID: "space-a"},
+				}, nil)
+			})
+
+			It("does not report it as missing", func() {
+				Expect(missingErr).NotTo(HaveOccurred())
+				Expect(missing).To(Equal([]broker.MissingDeployment{
+					{InstanceID: "two", DeploymentName: "service-instance_two", PlanID: postDeployErrandPlanID, SpaceGUID: "space-a", OrganizationGUID: "some-org"},
+				}))
+			})
+		})
+
+		Context("when the delete of an instance failed", func() {
+			BeforeEach(func() {
+				cfClient.GetServiceInstancesOfServiceOfferingReturns([]cf.ServiceInstance{
+					{GUID: "one", PlanID: existingPlanID, SpaceGUID: "space-a", LastOperation: cf.LastOperation{Type: cf.OperationTypeDelete, State: cf.OperationStateFailed}},
+					{GUID: "two", PlanID: postDeployErrandPlanID, SpaceGUID: "space-a"},
+				}, nil)
+			})
+
+			It("does not report it as missing", func() {
+				Expect(missingErr).NotTo(HaveOccurred())
+				Expect(missing).To(Equal([]broker.MissingDeployment{
+					{InstanceID: "two", DeploymentName: "service-instance_two", PlanID: postDeployErrandPlanID, SpaceGUID: "space-a", OrganizationGUID: "some-org"},
+				}))
+			})
+		})
+
+		Context("when an update of an instance failed", func() {
+			BeforeEach(func() {
+				cfClient.GetServiceInstancesOfServiceOfferingReturns([]cf.ServiceInstance{
+					{GUID: "one", PlanID: existingPlanID, SpaceGUID: "space-a", LastOperation: cf.LastOperation{Type: "update", State: cf.OperationStateFailed}},
+				}, nil)
+			})
+
+			It("reports it as missing", func() {
+				Expect(missingErr).NotTo(HaveOccurred())
+				Expect(missing).To(Equal([]broker.MissingDeployment{
+					{InstanceID: "one", DeploymentName: "service-instance_one", PlanID: existingPlanID, SpaceGUID: "space-a", OrganizationGUID: "some-org"},
+				}))
+			})
+		})
+
+		Context("when listing instances fails", func() {
+			BeforeEach(func() {
+				cfClient.GetServiceInstancesOfServiceOfferingReturns(nil, errors.New("cc error"))
+			})
+
+			It("returns the error", func() {
+				Expect(missingErr).To(MatchError("cc error"))
+			})
+		})
+
+		Context("when listing deployments fails", func() {
+			BeforeEach(func() {
+				boshClient.GetDeploymentsReturns(nil, errors.New("director error"))
+			})
+
+			It("returns the error", func() {
+				Expect(missingErr).To(MatchError("director error"))
+			})
+		})
+
+		Context("when the organization of a space cannot be found", func() {
+			BeforeEach(func() {
+				cfClient.GetOrganizationGUIDOfSpaceReturns("", errors.New("space error"))
+			})
+
+			It("returns the error", func() {
+				Expect(missingErr).To(MatchError("space error"))
+			})
+		})
+	})
+
+	Describe("recreating", func() {
+		var (
+			instanceID    string
+			operationData broker.OperationData
+			recreateErr   error
+		)
+
+		BeforeEach(func() {
+			instanceID = "one"
+			fakeDeployer.CreateReturns(123, []byte("manifest"), nil)
+		})
+
+		JustBeforeEach(func() {
+			operationData, recreateErr = b.RecreateMissingDeployment(context.Background(), instanceID, logger)
+		})
+
+		It("creates the deployment with the instance's current plan and organization", func() {
+			Expect(recreateErr).NotTo(HaveOccurred())
+			Expect(fakeDeployer.CreateCallCount()).To(Equal(1))
+			_, actualDeploymentName, actualPlanID, actualRequestParams, actualContextID, _ := fakeDeployer.CreateArgsForCall(0)
+			Expect(actualDeploymentName).To(Equal("service-instance_one"))
+			Expect(actualPlanID).To(Equal(existingPlanID))
+			Expect(actualRequestParams).To(HaveKeyWithValue("organization_guid", "some-org"))
+			Expect(actualRequestParams).To(HaveKeyWithValue("space_guid", "space-a"))
+			Expect(actualRequestParams).To(HaveKeyWithValue("plan_id", existingPlanID))
+			Expect(actualContextID).To(BeEmpty())
+		})
+
+		It("returns operation data for polling the create task", func() {
+			Expect(operationData).To(Equal(broker.OperationData{
+				BoshTaskID:    123,
+				OperationType: broker.OperationTypeCreate,
+			}))
+		})
+
+		Context("when the plan has a post-deploy errand", func() {
+			BeforeEach(func() {
+				instanceID = "two"
+			})
+
+			It("runs the errand after the deployment", func() {
+				_, _, _, _, actualContextID, _ := fakeDeployer.CreateArgsForCall(0)
+				Expect(actualContextID).NotTo(BeEmpty())
+				Expect(operationData.BoshContextID).To(Equal(actualContextID))
+				Expect(operationData.PostDeployErrandName).To(Equal("health-check"))
+			})
+		})
+
+		Context("when the instance's deployment is not missing", func() {
+			BeforeEach(func() {
+				instanceID = "three"
+			})
+
+			It("returns a DeploymentNotMissingError without deploying", func() {
+				Expect(recreateErr).To(BeAssignableToTypeOf(broker.DeploymentNotMissingError{}))
+				Expect(fakeDeployer.CreateCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when a task is in progress for the deployment", func() {
+			BeforeEach(func() {
+				fakeDeployer.CreateReturns(0, nil, task.TaskInProgressError{Message: "task in progress"})
+			})
+
+			It("returns an OperationInProgressError", func() {
+				Expect(recreateErr).To(BeAssignableToTypeOf(broker.OperationInProgressError{}))
+			})
+		})
+
+		Context("when the deployment fails", func() {
+			BeforeEach(func() {
+				fakeDeployer.CreateReturns(0, nil, errors.New("adapter error"))
+			})
+
+			It("returns the error", func() {
+				Expect(recreateErr).To(MatchError("adapter error"))
+			})
+		})
+	})
+})
@@ -8,9 +8,10 @@ package broker
 
 import (
 	"log"
-	"strings"
 )
 
+// OrphanDeployments tells deployments of this broker apart from others on the director by their names
+// alone, so that it does not need to fetch any manifests
 func (b *Broker) OrphanDeployments(logger *log.Logger) ([]string, error) {
 	rawInstanceIDs, err := b.Instances(logger)
 	if err != nil {
@@ -31,13 +32,12 @@ func (b *Broker) OrphanDeployments(logger *log.Logger) ([]string, error) {
 
 	var orphanDeploymentNames []string
 	for _, deployment := range deployments {
-		if !strings.HasPrefix(deployment.Name, InstancePrefix) {
+		instanceID, isInstance := b.instanceID(deployment.Name)
+		if !isInstance || instanceIDs[instanceID] {
 			continue
 		}
 
-		if !instanceIDs[instanceID(deployment.Name)] {
-			orphanDeploymentNames = append(orphanDeploymentNames, deployment.Name)
-		}
+		orphanDeploymentNames = append(orphanDeploymentNames, deployment.Name)
 	}
 
 	return orphanDeploymentNames, nil
@@ -89,6 +89,10 @@ var _ = Describe("Orphan Deployments", func() {
 			Expect(orphanDeploymentsErr).NotTo(HaveOccurred())
 			Expect(orphans).To(ConsistOf("service-instance_two"))
 		})
+
+		It("does not fetch any manifests", func() {
+			Expect(boshClient.GetDeploymentCallCount()).To(BeZero())
+		})
 	})
 
 	Context("when the getting the list of instances fails", func() {
@@ -9,6 +9,7 @@ package broker
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/pivotal-cf/brokerapi"
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 )
 
@@ -25,8 +27,10 @@ func (b *Broker) Provision(ctx context.Context, instanceID string, details broke
 	b.deploymentLock.Lock()
 	defer b.deploymentLock.Unlock()
 
+	serviceOffering := b.serviceOffering.Load()
+
 	requestID := uuid.New()
-	ctx = brokercontext.New(ctx, string(OperationTypeCreate), requestID, b.serviceOffering.Name, instanceID)
+	ctx = brokercontext.New(ctx, string(OperationTypeCreate), requestID, serviceOffering.Name, instanceID)
 	logger := b.loggerFactory.NewWithContext(ctx)
 
 	if !asyncAllowed {
@@ -41,6 +45,7 @@ func (b *Broker) Provision(ctx context.Context, instanceID string, details broke
 
 	operationData, dashboardURL, err := b.provisionInstance(
 		ctx,
+		serviceOffering,
 		instanceID,
 		details.PlanID,
 		requestParams,
@@ -69,14 +74,14 @@ func (b *Broker) Provision(ctx context.Context, instanceID string, details broke
 	}, nil
 }
 
-func (b *Broker) provisionInstance(ctx context.Context, instanceID string, planID string,
+func (b *Broker) provisionInstance(ctx context.Context, serviceOffering config.ServiceOffering, instanceID string, planID string,
 	requestParams map[string]interface{}, logger *log.Logger) (OperationData, string, error) {
 
 	errs := func(err error) (OperationData, string, error) {
 		return OperationData{}, "", err
 	}
 
-	plan, found := b.serviceOffering.FindPlanByID(planID)
+	plan, found := serviceOffering.FindPlanByID(planID)
 	if !found {
 		return errs(NewDisplayableError(
 			fmt.Errorf("plan %s not found", planID),
@@ -84,7 +89,7 @@ func (b *Broker) provisionInstance(ctx context.Context, instanceID string, planI
 		))
 	}
 
-	_, found, err := b.boshClient.GetDeployment(deploymentName(instanceID), logger)
+	_, found, err := b.boshClient.GetDeployment(b.deploymentName(instanceID), logger)
 	switch err := err.(type) {
 	case boshdirector.RequestError:
 		return errs(NewBoshRequestError("create", fmt.Errorf("could not get manifest: %s", err)))
@@ -99,10 +104,18 @@ func (b *Broker) provisionInstance(ctx context.Context, instanceID string, planI
 		))
 	}
 
+	if err := validateReservedParams(requestParams); err != NilError {
+		return errs(err)
+	}
+
+	if err := b.validateParamsWithAdapter(ctx, serviceOffering, plan, nil, requestParams, logger); err != NilError {
+		return errs(err)
+	}
+
 	var planCounts map[string]int
-	if b.serviceOffering.GlobalQuotas.ServiceInstanceLimit != nil {
+	if serviceOffering.GlobalQuotas.ServiceInstanceLimit != nil {
 		var displayableError DisplayableError
-		planCounts, displayableError = b.checkGlobalQuota(ctx, b.serviceOffering.ID, logger)
+		planCounts, displayableError = b.checkGlobalQuota(ctx, serviceOffering, logger)
 		if displayableError.Occurred() {
 			return errs(displayableError)
 		}
@@ -110,7 +123,7 @@ func (b *Broker) provisionInstance(ctx context.Context, instanceID string, planI
 
 	if plan.Quotas.ServiceInstanceLimit != nil {
 		limit := *plan.Quotas.ServiceInstanceLimit
-		planCount, displayableError := b.getPlanCount(ctx, planID, planCounts, logger)
+		planCount, displayableError := b.getPlanCount(ctx, serviceOffering.ID, planID, planCounts, logger)
 		if displayableError.Occurred() {
 			return errs(displayableError)
 		}
@@ -130,23 +143,27 @@ func (b *Broker) provisionInstance(ctx context.Context, instanceID string, planI
 		operationPostDeployErrand = plan.PostDeployErrand()
 	}
 
-	boshTaskID, manifest, err := b.deployer.Create(deploymentName(instanceID), plan.ID, requestParams, boshContextID, logger)
+	boshTaskID, manifest, err := b.deployer.Create(ctx, b.deploymentName(instanceID), plan.ID, requestParams, boshContextID, logger)
 	switch err := err.(type) {
 	case boshdirector.RequestError:
 		return errs(NewBoshRequestError("create", err))
 	case DisplayableError:
 		return errs(err)
+	case serviceadapter.TimeoutError:
+		return errs(NewDisplayableError(errors.New(AdapterTimeoutMessage), err))
 	case serviceadapter.UnknownFailureError:
 		return errs(adapterToAPIError(ctx, err))
+	case serviceadapter.StructuredError:
+		return errs(NewDisplayableError(adapterToAPIError(ctx, err), err))
 	case error:
 		return errs(NewGenericError(ctx, err))
 	}
 
 	ctx = brokercontext.WithBoshTaskID(ctx, boshTaskID)
 
-	abridgedPlan := plan.AdapterPlan(b.serviceOffering.GlobalProperties)
+	abridgedPlan := plan.AdapterPlan(serviceOffering.GlobalProperties)
 
-	dashboardUrl, err := b.adapterClient.GenerateDashboardUrl(instanceID, abridgedPlan, manifest, logger)
+	dashboardUrl, err := b.adapterClient.GenerateDashboardUrl(ctx, instanceID, abridgedPlan, manifest, logger)
 	if err != nil {
 		logger.Printf("generating dashboard: %v\n", err)
 	}
@@ -170,14 +187,14 @@ func (b *Broker) provisionInstance(ctx context.Context, instanceID string, planI
 	return operationData, dashboardUrl, DisplayableError{}
 }
 
-func (b *Broker) getPlanCount(ctx context.Context, planID string, planCounts map[string]int, logger *log.Logger) (int, DisplayableError) {
+func (b *Broker) getPlanCount(ctx context.Context, serviceOfferingID, planID string, planCounts map[string]int, logger *log.Logger) (int, DisplayableError) {
 	var planCount int
 
 	if planCounts != nil {
 		planCount = planCounts[planID]
 	} else {
 		var countErr error
-		planCount, countErr = b.cfClient.CountInstancesOfPlan(b.serviceOffering.ID, planID, logger)
+		planCount, countErr = b.cfClient.CountInstancesOfPlan(serviceOfferingID, planID, logger)
 		if countErr != nil {
 			return 0, NewGenericError(ctx, fmt.Errorf("could not count instances of plan: %s", countErr))
 		}
@@ -188,11 +205,11 @@ func (b *Broker) getPlanCount(ctx context.Context, planID string, planCounts map
 
 func (b *Broker) checkGlobalQuota(
 	ctx context.Context,
-	serviceOfferingID string,
+	serviceOffering config.ServiceOffering,
 	logger *log.Logger,
 ) (map[string]int, DisplayableError) {
 
-	planCounts, err := b.cfClient.CountInstancesOfServiceOffering(serviceOfferingID, logger)
+	planCounts, err := b.cfClient.CountInstancesOfServiceOffering(serviceOffering.ID, logger)
 	if err != nil {
 		return nil, NewGenericError(ctx, err)
 	}
@@ -202,10 +219,11 @@ func (b *Broker) checkGlobalQuota(
 		totalServiceInstances += count
 	}
 
-	if b.serviceOffering.GlobalQuotas.ServiceInstanceLimit != nil && totalServiceInstances >= *b.serviceOffering.GlobalQuotas.ServiceInstanceLimit {
+	limit := serviceOffering.GlobalQuotas.ServiceInstanceLimit
+	if limit != nil && totalServiceInstances >= *limit {
 		return nil, NewDisplayableError(
 			brokerapi.ErrServiceQuotaExceeded,
-			fmt.Errorf("service quota exceeded for service ID %s", b.serviceOffering.ID),
+			fmt.Errorf("service quota exceeded for service ID %s", serviceOffering.ID),
 		)
 	}
 
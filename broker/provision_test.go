@@ -12,12 +12,14 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/http"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pivotal-cf/brokerapi"
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
@@ -85,7 +87,7 @@ var _ = Describe("provisioning", func() {
 
 		It("invokes the deployer", func() {
 			Expect(fakeDeployer.CreateCallCount()).To(Equal(1))
-			actualDeploymentName, actualPlan, actualRequestParams, actualBoshContextID, _ := fakeDeployer.CreateArgsForCall(0)
+			_, actualDeploymentName, actualPlan, actualRequestParams, actualBoshContextID, _ := fakeDeployer.CreateArgsForCall(0)
 			Expect(actualRequestParams).To(Equal(map[string]interface{}{
 				"plan_id":           planID,
 				"parameters":        arbParams,
@@ -98,6 +100,11 @@ var _ = Describe("provisioning", func() {
 			Expect(actualBoshContextID).To(BeEmpty())
 		})
 
+		It("passes the request context to the deployer", func() {
+			actualCtx, _, _, _, _, _ := fakeDeployer.CreateArgsForCall(0)
+			Expect(brokercontext.GetInstanceID(actualCtx)).To(Equal(instanceID))
+		})
+
 		It("returns operation data with bosh task ID and operation type", func() {
 			var operationData broker.OperationData
 			Expect(json.Unmarshal([]byte(serviceSpec.OperationData), &operationData)).To(Succeed())
@@ -114,7 +121,7 @@ var _ = Describe("provisioning", func() {
 
 		It("invokes the adapter for the dashboard url, merging global and plan properties", func() {
 			Expect(serviceAdapter.GenerateDashboardUrlCallCount()).To(Equal(1))
-			instanceID, plan, boshManifest, _ := serviceAdapter.GenerateDashboardUrlArgsForCall(0)
+			_, instanceID, plan, boshManifest, _ := serviceAdapter.GenerateDashboardUrlArgsForCall(0)
 			Expect(instanceID).To(Equal(instanceID))
 			expectedProperties := sdk.Properties{"super": "no", "a_global_property": "global_value", "some_other_global_property": "other_global_value"}
 			Expect(plan).To(Equal(sdk.Plan{
@@ -263,7 +270,7 @@ var _ = Describe("provisioning", func() {
 
 		It("calls the deployer with a bosh context id", func() {
 			Expect(fakeDeployer.CreateCallCount()).To(Equal(1))
-			_, _, _, actualBoshContextID, _ := fakeDeployer.CreateArgsForCall(0)
+			_, _, _, _, actualBoshContextID, _ := fakeDeployer.CreateArgsForCall(0)
 			Expect(actualBoshContextID).NotTo(BeEmpty())
 		})
 
@@ -293,10 +300,10 @@ var _ = Describe("provisioning", func() {
 
 			It("calls the deployer with a different bosh context id", func() {
 				Expect(fakeDeployer.CreateCallCount()).To(Equal(2))
-				_, _, _, firstBoshContextID, _ := fakeDeployer.CreateArgsForCall(0)
+				_, _, _, _, firstBoshContextID, _ := fakeDeployer.CreateArgsForCall(0)
 				Expect(firstBoshContextID).NotTo(BeNil())
 
-				_, _, _, secondBoshContextID, _ := fakeDeployer.CreateArgsForCall(1)
+				_, _, _, _, secondBoshContextID, _ := fakeDeployer.CreateArgsForCall(1)
 				Expect(secondBoshContextID).NotTo(Equal(firstBoshContextID))
 			})
 		})
@@ -322,7 +329,7 @@ var _ = Describe("provisioning", func() {
 		})
 
 		It("no arbitrary params are passed to the adapter", func() {
-			_, _, actualRequestParams, _, _ := fakeDeployer.CreateArgsForCall(0)
+			_, _, _, actualRequestParams, _, _ := fakeDeployer.CreateArgsForCall(0)
 			Expect(actualRequestParams["parameters"]).To(BeNil())
 		})
 
@@ -437,6 +444,59 @@ var _ = Describe("provisioning", func() {
 		})
 	})
 
+	Context("when the deploy returns an adapter timeout error", func() {
+		BeforeEach(func() {
+			fakeDeployer.CreateReturns(0, nil, serviceadapter.NewTimeoutError("adapter timed out after 1m0s"))
+		})
+
+		It("returns the timeout message for the user", func() {
+			Expect(provisionErr).To(MatchError(broker.AdapterTimeoutMessage))
+		})
+
+		It("logs the error", func() {
+			Expect(logBuffer.String()).To(ContainSubstring("adapter timed out after 1m0s"))
+		})
+	})
+
+	Context("when the deploy returns a structured adapter error", func() {
+		BeforeEach(func() {
+			fakeDeployer.CreateReturns(0, nil, serviceadapter.StructuredError{
+				UserMessage:     "size must be small or large",
+				OperatorMessage: "invalid size 'medium'",
+				Code:            "invalid-params",
+			})
+		})
+
+		It("returns the user message as a bad request", func() {
+			Expect(provisionErr).To(Equal(brokerapi.NewFailureResponse(
+				errors.New("size must be small or large"),
+				http.StatusBadRequest,
+				"service-adapter-error",
+			)))
+		})
+
+		It("logs the operator message", func() {
+			Expect(logBuffer.String()).To(ContainSubstring("invalid-params: invalid size 'medium'"))
+		})
+	})
+
+	Context("when the deploy returns a retryable structured adapter error", func() {
+		BeforeEach(func() {
+			fakeDeployer.CreateReturns(0, nil, serviceadapter.StructuredError{
+				UserMessage: "capacity exhausted, try again later",
+				Retryable:   true,
+			})
+		})
+
+		It("returns the user message as service unavailable", func() {
+			Expect(provisionErr).To(Equal(brokerapi.NewFailureResponse(
+				errors.New("capacity exhausted, try again later"),
+				http.StatusServiceUnavailable,
+				"service-adapter-error",
+			)))
+		})
+	})
+
 	Context("when the deploy returns an adapter error with no message", func() {
 		var err = serviceadapter.NewUnknownFailureError("")
 
@@ -451,6 +511,97 @@ var _ = Describe("provisioning", func() {
 		})
 	})
 
+	Context("when the adapter validates the params", func() {
+		It("passes the plan and the params without a previous plan", func() {
+			Expect(serviceAdapter.ValidateParamsCallCount()).To(Equal(1))
+			_, plan, previousPlan, requestParams, _ := serviceAdapter.ValidateParamsArgsForCall(0)
+			Expect(plan.Properties).NotTo(BeNil())
+			Expect(previousPlan).To(BeNil())
+			Expect(requestParams).To(HaveKey("parameters"))
+		})
+	})
+
+	Context("when the adapter rejects the params with a structured error", func() {
+		BeforeEach(func() {
+			serviceAdapter.ValidateParamsReturns(serviceadapter.StructuredError{UserMessage: "size must be small or large"})
+		})
+
+		It("returns a bad request error without checking quotas or deploying", func() {
+			Expect(provisionErr).To(Equal(brokerapi.NewFailureResponse(
+				errors.New("size must be small or large"),
+				http.StatusBadRequest,
+				"service-adapter-error",
+			)))
+			Expect(cfClient.CountInstancesOfPlanCallCount()).To(BeZero())
+			Expect(fakeDeployer.CreateCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the adapter rejects the params with a message", func() {
+		BeforeEach(func() {
+			serviceAdapter.ValidateParamsReturns(serviceadapter.NewUnknownFailureError("size must be small or large"))
+		})
+
+		It("returns a bad request error", func() {
+			Expect(provisionErr).To(Equal(brokerapi.NewFailureResponse(
+				serviceadapter.NewUnknownFailureError("size must be small or large"),
+				http.StatusBadRequest,
+				"validating-parameters",
+			)))
+		})
+	})
+
+	Context("when the adapter does not support validating params", func() {
+		BeforeEach(func() {
+			serviceAdapter.ValidateParamsReturns(serviceadapter.NewNotImplementedError("not implemented"))
+		})
+
+		It("provisions the instance", func() {
+			Expect(provisionErr).NotTo(HaveOccurred())
+			Expect(fakeDeployer.CreateCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when validating the params times out", func() {
+		BeforeEach(func() {
+			serviceAdapter.ValidateParamsReturns(serviceadapter.NewTimeoutError("timed out"))
+		})
+
+		It("returns the timeout message", func() {
+			Expect(provisionErr).To(MatchError(broker.AdapterTimeoutMessage))
+		})
+	})
+
+	Context("when the maintenance window parameter is invalid", func() {
+		BeforeEach(func() {
+			jsonParams = []byte(`{"maintenance_window": {"start": "noon", "duration": "4h"}}`)
+		})
+
+		It("returns a bad request error without deploying", func() {
+			Expect(provisionErr).To(Equal(brokerapi.NewFailureResponse(
+				errors.New("parameter maintenance_window is invalid: start must be a UTC time of day in the form HH:MM, got 'noon'"),
+				http.StatusBadRequest,
+				"validating-parameters",
+			)))
+			Expect(fakeDeployer.CreateCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the upgrade policy parameter is invalid", func() {
+		BeforeEach(func() {
+			jsonParams = []byte(`{"upgrade_policy": "never"}`)
+		})
+
+		It("returns a bad request error without deploying", func() {
+			Expect(provisionErr).To(Equal(brokerapi.NewFailureResponse(
+				errors.New("parameter upgrade_policy must be one of 'auto' or 'defer'"),
+				http.StatusBadRequest,
+				"validating-parameters",
+			)))
+			Expect(fakeDeployer.CreateCallCount()).To(BeZero())
+		})
+	})
+
 	Context("when a provision of an already provisioned instance is triggered", func() {
 		BeforeEach(func() {
 			boshClient.GetDeploymentReturns([]byte(`manifest: true`), true, nil)
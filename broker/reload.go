@@ -0,0 +1,72 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+)
+
+// ReloadServiceOffering swaps in a new service offering once it has passed the startup
+// checks that depend on it
+func (b *Broker) ReloadServiceOffering(serviceOffering config.ServiceOffering, logger *log.Logger) error {
+	if err := b.checkBoshDirectorVersion(serviceOffering, logger); err != nil {
+		return errors.New("BOSH Director error: " + err.Error())
+	}
+
+	if err := b.verifyExistingInstancePlanIDsUnchanged(serviceOffering, logger); err != nil {
+		return err
+	}
+
+	b.serviceOffering.Store(serviceOffering)
+	return nil
+}
+
+type ConfigReloader struct {
+	broker         *Broker
+	configFilePath string
+
+	lock    *sync.Mutex
+	current config.Config
+}
+
+func NewConfigReloader(broker *Broker, configFilePath string, current config.Config) *ConfigReloader {
+	return &ConfigReloader{
+		broker:         broker,
+		configFilePath: configFilePath,
+		lock:           &sync.Mutex{},
+		current:        current,
+	}
+}
+
+func (r *ConfigReloader) Reload(logger *log.Logger) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	logger.Printf("reloading config from %s", r.configFilePath)
+
+	reloaded, err := config.Parse(r.configFilePath)
+	if err != nil {
+		return fmt.Errorf("error parsing config: %s", err)
+	}
+
+	if err := r.current.CheckReloadable(reloaded); err != nil {
+		return err
+	}
+
+	if err := r.broker.ReloadServiceOffering(reloaded.ServiceCatalog, logger); err != nil {
+		return err
+	}
+
+	r.current = reloaded
+	logger.Println("reloaded config")
+	return nil
+}
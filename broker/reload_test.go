@@ -0,0 +1,160 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"gopkg.in/yaml.v2"
+)
+
+var _ = Describe("Reloading the service offering", func() {
+	var (
+		logger           *log.Logger
+		reloadedCatalog  config.ServiceOffering
+		reloadErr        error
+		newDescription   = "a reloaded description"
+		existingPlanOnly config.ServiceOffering
+	)
+
+	BeforeEach(func() {
+		logger = loggerFactory.NewWithRequestID()
+		reloadedCatalog = serviceCatalog
+		reloadedCatalog.Description = newDescription
+	})
+
+	JustBeforeEach(func() {
+		Expect(brokerCreationErr).NotTo(HaveOccurred())
+		reloadErr = b.ReloadServiceOffering(reloadedCatalog, logger)
+	})
+
+	It("serves the reloaded service offering", func() {
+		Expect(reloadErr).NotTo(HaveOccurred())
+		Expect(b.Services(context.Background())[0].Description).To(Equal(newDescription))
+	})
+
+	Context("when a plan with instances is removed", func() {
+		BeforeEach(func() {
+			existingPlanOnly = serviceCatalog
+			existingPlanOnly.Plans = config.Plans{existingPlan}
+			reloadedCatalog = existingPlanOnly
+
+			cfClient.CountInstancesOfServiceOfferingReturnsOnCall(1, map[string]int{secondPlanID: 1}, nil)
+		})
+
+		It("returns an error and keeps serving the previous service offering", func() {
+			Expect(reloadErr).To(MatchError("You cannot change the plan_id of a plan that has existing service instances"))
+			Expect(b.Services(context.Background())[0].Plans).To(HaveLen(len(serviceCatalog.Plans)))
+		})
+	})
+
+	Context("when the director no longer supports a plan's lifecycle errands", func() {
+		BeforeEach(func() {
+			boshClient.GetDirectorVersionReturnsOnCall(1, boshdirector.NewVersion(257, boshdirector.SemverDirectorVersionType), nil)
+		})
+
+		It("returns an error", func() {
+			Expect(reloadErr).To(MatchError(ContainSubstring("BOSH Director error: API version is insufficient")))
+		})
+	})
+})
+
+var _ = Describe("ConfigReloader", func() {
+	var (
+		logger         *log.Logger
+		tempDir        string
+		configFilePath string
+		currentConfig  config.Config
+		reloadedConfig config.Config
+		reloader       *broker.ConfigReloader
+		reloadErr      error
+	)
+
+	writeConfig := func(conf config.Config) {
+		contents, err := yaml.Marshal(conf)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ioutil.WriteFile(configFilePath, contents, 0600)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "broker-config")
+		Expect(err).NotTo(HaveOccurred())
+		configFilePath = filepath.Join(tempDir, "broker.yml")
+
+		logger = loggerFactory.NewWithRequestID()
+		currentConfig = config.Config{
+			Broker: config.Broker{Port: 8080, Username: "username", Password: "password"},
+			Bosh: config.Bosh{
+				URL:            "https://director:25555",
+				Authentication: config.BOSHAuthentication{Basic: config.UserCredentials{Username: "admin", Password: "admin"}},
+			},
+			CF: config.CF{
+				URL: "https://api.cf",
+				Authentication: config.UAAAuthentication{
+					URL:               "https://uaa.cf",
+					ClientCredentials: config.ClientCredentials{ID: "client", Secret: "secret"},
+				},
+			},
+			ServiceAdapter:    config.ServiceAdapter{URL: "http://localhost:8081"},
+			ServiceDeployment: serviceDeployment,
+			ServiceCatalog:    serviceCatalog,
+		}
+		reloadedConfig = currentConfig
+		reloadedConfig.ServiceCatalog.Description = "a reloaded description"
+	})
+
+	JustBeforeEach(func() {
+		Expect(brokerCreationErr).NotTo(HaveOccurred())
+		writeConfig(currentConfig)
+		parsedConfig, err := config.Parse(configFilePath)
+		Expect(err).NotTo(HaveOccurred())
+
+		writeConfig(reloadedConfig)
+		reloader = broker.NewConfigReloader(b, configFilePath, parsedConfig)
+		reloadErr = reloader.Reload(logger)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tempDir)).To(Succeed())
+	})
+
+	It("swaps in the reloaded service offering", func() {
+		Expect(reloadErr).NotTo(HaveOccurred())
+		Expect(b.Services(context.Background())[0].Description).To(Equal("a reloaded description"))
+	})
+
+	Context("when the reloaded config changes the BOSH URL", func() {
+		BeforeEach(func() {
+			reloadedConfig.Bosh.URL = "https://another-director:25555"
+		})
+
+		It("rejects the reload", func() {
+			Expect(reloadErr).To(MatchError("the following settings cannot be changed without restarting the broker: bosh"))
+			Expect(b.Services(context.Background())[0].Description).To(Equal(serviceCatalog.Description))
+		})
+	})
+
+	Context("when the reloaded config is invalid", func() {
+		BeforeEach(func() {
+			reloadedConfig.Broker.Username = ""
+		})
+
+		It("rejects the reload", func() {
+			Expect(reloadErr).To(MatchError("error parsing config: broker.username can't be empty"))
+		})
+	})
+})
@@ -0,0 +1,110 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package broker_test
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/cf"
+	"github.com/pivotal-cf/on-demand-service-broker/task"
+)
+
+var _ = Describe("Rollback", func() {
+	const boshTaskID = 876
+
+	var (
+		instanceID = "some-instance"
+		logger     *log.Logger
+
+		operationData broker.OperationData
+		rollbackErr   error
+	)
+
+	BeforeEach(func() {
+		cfClient.GetInstanceStateReturns(cf.InstanceState{PlanID: existingPlanID}, nil)
+		fakeDeployer.RollbackReturns(boshTaskID, []byte("name: previous-manifest"), nil)
+	})
+
+	JustBeforeEach(func() {
+		logger = loggerFactory.NewWithRequestID()
+		operationData, rollbackErr = b.Rollback(context.Background(), instanceID, logger)
+	})
+
+	It("rolls back the instance's deployment", func() {
+		Expect(fakeDeployer.RollbackCallCount()).To(Equal(1))
+		actualDeploymentName, actualContextID, _ := fakeDeployer.RollbackArgsForCall(0)
+		Expect(actualDeploymentName).To(Equal(deploymentName(instanceID)))
+		Expect(actualContextID).To(BeEmpty())
+	})
+
+	It("returns operation data for polling the task", func() {
+		Expect(rollbackErr).NotTo(HaveOccurred())
+		Expect(operationData).To(Equal(broker.OperationData{
+			BoshTaskID:    boshTaskID,
+			OperationType: broker.OperationTypeRollback,
+		}))
+	})
+
+	Context("when the plan has a post-deploy errand", func() {
+		BeforeEach(func() {
+			cfClient.GetInstanceStateReturns(cf.InstanceState{PlanID: postDeployErrandPlanID}, nil)
+		})
+
+		It("runs the errand after the rollback", func() {
+			_, actualContextID, _ := fakeDeployer.RollbackArgsForCall(0)
+			Expect(actualContextID).NotTo(BeEmpty())
+			Expect(operationData.BoshContextID).To(Equal(actualContextID))
+			Expect(operationData.PostDeployErrandName).To(Equal("health-check"))
+		})
+	})
+
+	Context("when there is an operation in progress on the instance in Cloud Foundry", func() {
+		BeforeEach(func() {
+			cfClient.GetInstanceStateReturns(cf.InstanceState{PlanID: existingPlanID, OperationInProgress: true}, nil)
+		})
+
+		It("returns an OperationInProgressError", func() {
+			Expect(rollbackErr).To(BeAssignableToTypeOf(broker.OperationInProgressError{}))
+			Expect(fakeDeployer.RollbackCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when a BOSH task is in progress for the deployment", func() {
+		BeforeEach(func() {
+			fakeDeployer.RollbackReturns(0, nil, task.TaskInProgressError{Message: "task in progress"})
+		})
+
+		It("returns an OperationInProgressError", func() {
+			Expect(rollbackErr).To(BeAssignableToTypeOf(broker.OperationInProgressError{}))
+		})
+	})
+
+	Context("when the instance cannot be found in Cloud Foundry", func() {
+		BeforeEach(func() {
+			cfClient.GetInstanceStateReturns(cf.InstanceState{}, cf.NewResourceNotFoundError("not found"))
+		})
+
+		It("returns the error", func() {
+			Expect(rollbackErr).To(BeAssignableToTypeOf(cf.ResourceNotFoundError{}))
+		})
+	})
+
+	Context("when the releases of the previous manifest are no longer uploaded", func() {
+		BeforeEach(func() {
+			fakeDeployer.RollbackReturns(0, nil, task.NewMissingDependenciesError(errors.New("release redis/1 missing")))
+		})
+
+		It("returns the error", func() {
+			Expect(rollbackErr).To(BeAssignableToTypeOf(task.MissingDependenciesError{}))
+		})
+	})
+})
@@ -23,10 +23,11 @@ type FakeHTTPClient struct {
 		result1 *http.Response
 		result2 error
 	}
-	PatchStub        func(path string) (*http.Response, error)
+	PatchStub        func(path string, query map[string]string) (*http.Response, error)
 	patchMutex       sync.RWMutex
 	patchArgsForCall []struct {
-		path string
+		path  string
+		query map[string]string
 	}
 	patchReturns struct {
 		result1 *http.Response
@@ -36,6 +37,33 @@ type FakeHTTPClient struct {
 		result1 *http.Response
 		result2 error
 	}
+	PostStub        func(path string) (*http.Response, error)
+	postMutex       sync.RWMutex
+	postArgsForCall []struct {
+		path string
+	}
+	postReturns struct {
+		result1 *http.Response
+		result2 error
+	}
+	postReturnsOnCall map[int]struct {
+		result1 *http.Response
+		result2 error
+	}
+	DeleteStub        func(path string, query map[string]string) (*http.Response, error)
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		path  string
+		query map[string]string
+	}
+	deleteReturns struct {
+		result1 *http.Response
+		result2 error
+	}
+	deleteReturnsOnCall map[int]struct {
+		result1 *http.Response
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -92,16 +120,17 @@ func (fake *FakeHTTPClient) GetReturnsOnCall(i int, result1 *http.Response, resu
 	}{result1, result2}
 }
 
-func (fake *FakeHTTPClient) Patch(path string) (*http.Response, error) {
+func (fake *FakeHTTPClient) Patch(path string, query map[string]string) (*http.Response, error) {
 	fake.patchMutex.Lock()
 	ret, specificReturn := fake.patchReturnsOnCall[len(fake.patchArgsForCall)]
 	fake.patchArgsForCall = append(fake.patchArgsForCall, struct {
-		path string
-	}{path})
-	fake.recordInvocation("Patch", []interface{}{path})
+		path  string
+		query map[string]string
+	}{path, query})
+	fake.recordInvocation("Patch", []interface{}{path, query})
 	fake.patchMutex.Unlock()
 	if fake.PatchStub != nil {
-		return fake.PatchStub(path)
+		return fake.PatchStub(path, query)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -115,10 +144,10 @@ func (fake *FakeHTTPClient) PatchCallCount() int {
 	return len(fake.patchArgsForCall)
 }
 
-func (fake *FakeHTTPClient) PatchArgsForCall(i int) string {
+func (fake *FakeHTTPClient) PatchArgsForCall(i int) (string, map[string]string) {
 	fake.patchMutex.RLock()
 	defer fake.patchMutex.RUnlock()
-	return fake.patchArgsForCall[i].path
+	return fake.patchArgsForCall[i].path, fake.patchArgsForCall[i].query
 }
 
 func (fake *FakeHTTPClient) PatchReturns(result1 *http.Response, result2 error) {
@@ -143,6 +172,109 @@ func (fake *FakeHTTPClient) PatchReturnsOnCall(i int, result1 *http.Response, re
 	}{result1, result2}
 }
 
+func (fake *FakeHTTPClient) Post(path string) (*http.Response, error) {
+	fake.postMutex.Lock()
+	ret, specificReturn := fake.postReturnsOnCall[len(fake.postArgsForCall)]
+	fake.postArgsForCall = append(fake.postArgsForCall, struct {
+		path string
+	}{path})
+	fake.recordInvocation("Post", []interface{}{path})
+	fake.postMutex.Unlock()
+	if fake.PostStub != nil {
+		return fake.PostStub(path)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.postReturns.result1, fake.postReturns.result2
+}
+
+func (fake *FakeHTTPClient) PostCallCount() int {
+	fake.postMutex.RLock()
+	defer fake.postMutex.RUnlock()
+	return len(fake.postArgsForCall)
+}
+
+func (fake *FakeHTTPClient) PostArgsForCall(i int) string {
+	fake.postMutex.RLock()
+	defer fake.postMutex.RUnlock()
+	return fake.postArgsForCall[i].path
+}
+
+func (fake *FakeHTTPClient) PostReturns(result1 *http.Response, result2 error) {
+	fake.PostStub = nil
+	fake.postReturns = struct {
+		result1 *http.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeHTTPClient) PostReturnsOnCall(i int, result1 *http.Response, result2 error) {
+	fake.PostStub = nil
+	if fake.postReturnsOnCall == nil {
+		fake.postReturnsOnCall = make(map[int]struct {
+			result1 *http.Response
+			result2 error
+		})
+	}
+	fake.postReturnsOnCall[i] = struct {
+		result1 *http.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeHTTPClient) Delete(path string, query map[string]string) (*http.Response, error) {
+	fake.deleteMutex.Lock()
+	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		path  string
+		query map[string]string
+	}{path, query})
+	fake.recordInvocation("Delete", []interface{}{path, query})
+	fake.deleteMutex.Unlock()
+	if fake.DeleteStub != nil {
+		return fake.DeleteStub(path, query)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.deleteReturns.result1, fake.deleteReturns.result2
+}
+
+func (fake *FakeHTTPClient) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *FakeHTTPClient) DeleteArgsForCall(i int) (string, map[string]string) {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return fake.deleteArgsForCall[i].path, fake.deleteArgsForCall[i].query
+}
+
+func (fake *FakeHTTPClient) DeleteReturns(result1 *http.Response, result2 error) {
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 *http.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeHTTPClient) DeleteReturnsOnCall(i int, result1 *http.Response, result2 error) {
+	fake.DeleteStub = nil
+	if fake.deleteReturnsOnCall == nil {
+		fake.deleteReturnsOnCall = make(map[int]struct {
+			result1 *http.Response
+			result2 error
+		})
+	}
+	fake.deleteReturnsOnCall[i] = struct {
+		result1 *http.Response
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeHTTPClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -150,6 +282,10 @@ func (fake *FakeHTTPClient) Invocations() map[string][][]interface{} {
 	defer fake.getMutex.RUnlock()
 	fake.patchMutex.RLock()
 	defer fake.patchMutex.RUnlock()
+	fake.postMutex.RLock()
+	defer fake.postMutex.RUnlock()
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -14,6 +14,7 @@ import (
 
 	"github.com/pivotal-cf/brokerapi"
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/mgmtapi"
 )
 
@@ -29,6 +30,22 @@ const (
 	OperationInProgress UpgradeOperationType = iota
 	InstanceNotFound    UpgradeOperationType = iota
 	OrphanDeployment    UpgradeOperationType = iota
+	UpgradeDeferred     UpgradeOperationType = iota
+)
+
+type BackupOperation struct {
+	Type BackupOperationType
+	Data broker.OperationData
+}
+
+type BackupOperationType int
+
+const (
+	BackupAccepted BackupOperationType = iota
+	BackupOperationInProgress
+	BackupInstanceNotFound
+	BackupOrphanDeployment
+	BackupNotConfigured
 )
 
 type ResponseConverter struct{}
@@ -49,6 +66,8 @@ func (r ResponseConverter) UpgradeOperationFrom(response *http.Response) (Upgrad
 		return UpgradeOperation{Type: OrphanDeployment}, nil
 	case http.StatusConflict:
 		return UpgradeOperation{Type: OperationInProgress}, nil
+	case http.StatusUnprocessableEntity:
+		return UpgradeOperation{Type: UpgradeDeferred}, nil
 	case http.StatusInternalServerError:
 		var errorResponse brokerapi.ErrorResponse
 		body, _ := ioutil.ReadAll(response.Body)
@@ -69,6 +88,30 @@ func (r ResponseConverter) UpgradeOperationFrom(response *http.Response) (Upgrad
 	}
 }
 
+func (r ResponseConverter) BackupOperationFrom(response *http.Response) (BackupOperation, error) {
+	defer response.Body.Close()
+
+	body, _ := ioutil.ReadAll(response.Body)
+	switch response.StatusCode {
+	case http.StatusAccepted:
+		var operationData broker.OperationData
+		if err := json.Unmarshal(body, &operationData); err != nil {
+			return BackupOperation{}, fmt.Errorf("cannot parse backup response: %s", err)
+		}
+		return BackupOperation{Type: BackupAccepted, Data: operationData}, nil
+	case http.StatusNotFound:
+		return BackupOperation{Type: BackupInstanceNotFound}, nil
+	case http.StatusGone:
+		return BackupOperation{Type: BackupOrphanDeployment}, nil
+	case http.StatusConflict:
+		return BackupOperation{Type: BackupOperationInProgress}, nil
+	case http.StatusUnprocessableEntity:
+		return BackupOperation{Type: BackupNotConfigured}, nil
+	default:
+		return BackupOperation{}, unexpectedStatusError(response.StatusCode, body)
+	}
+}
+
 func (r ResponseConverter) ListInstancesFrom(response *http.Response) ([]string, error) {
 	var instances []mgmtapi.Instance
 	err := decodeBodyInto(response, &instances)
@@ -79,6 +122,23 @@ func (r ResponseConverter) ListInstancesFrom(response *http.Response) ([]string,
 	return instanceIDsIn(instances), nil
 }
 
+// MaintenanceWindowFrom treats instances that no longer exist as having no window, as upgrading them
+// reports that they were deleted
+func (r ResponseConverter) MaintenanceWindowFrom(response *http.Response) (*config.MaintenanceWindow, error) {
+	if response.StatusCode == http.StatusNotFound {
+		response.Body.Close()
+		return nil, nil
+	}
+
+	var window mgmtapi.InstanceMaintenanceWindow
+	err := decodeBodyInto(response, &window)
+	if err != nil {
+		return nil, err
+	}
+
+	return window.MaintenanceWindow, nil
+}
+
 func (r ResponseConverter) LastOperationFrom(response *http.Response) (brokerapi.LastOperation, error) {
 	var lastOperation brokerapi.LastOperation
 	err := decodeBodyInto(response, &lastOperation)
@@ -99,6 +159,54 @@ func (r ResponseConverter) OrphanDeploymentsFrom(response *http.Response) ([]mgm
 	return orphans, nil
 }
 
+func (r ResponseConverter) MissingDeploymentsFrom(response *http.Response) ([]mgmtapi.MissingDeployment, error) {
+	var missing []mgmtapi.MissingDeployment
+	err := decodeBodyInto(response, &missing)
+	if err != nil {
+		return nil, err
+	}
+
+	return missing, nil
+}
+
+func (r ResponseConverter) RecreateOperationFrom(response *http.Response) (broker.OperationData, error) {
+	defer response.Body.Close()
+
+	body, _ := ioutil.ReadAll(response.Body)
+	if response.StatusCode != http.StatusAccepted {
+		return broker.OperationData{}, unexpectedStatusError(response.StatusCode, body)
+	}
+
+	var operationData broker.OperationData
+	if err := json.Unmarshal(body, &operationData); err != nil {
+		return broker.OperationData{}, fmt.Errorf("cannot parse recreate response: %s", err)
+	}
+	return operationData, nil
+}
+
+func (r ResponseConverter) OrphanDeletionResultFrom(response *http.Response) (broker.OrphanDeletionResult, error) {
+	defer response.Body.Close()
+
+	body, _ := ioutil.ReadAll(response.Body)
+	if response.StatusCode != http.StatusOK {
+		return broker.OrphanDeletionResult{}, unexpectedStatusError(response.StatusCode, body)
+	}
+
+	var result broker.OrphanDeletionResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return broker.OrphanDeletionResult{}, fmt.Errorf("cannot parse orphan deletion response: %s", err)
+	}
+	return result, nil
+}
+
+func unexpectedStatusError(statusCode int, body []byte) error {
+	var errorResponse brokerapi.ErrorResponse
+	if err := json.Unmarshal(body, &errorResponse); err != nil || errorResponse.Description == "" {
+		return fmt.Errorf("unexpected status code: %d. body: %s", statusCode, string(body))
+	}
+	return fmt.Errorf("unexpected status code: %d. description: %s", statusCode, errorResponse.Description)
+}
+
 func decodeBodyInto(response *http.Response, contents interface{}) error {
 	defer response.Body.Close()
 
@@ -184,6 +184,20 @@ var _ = Describe("Response Converter", func() {
 			})
 		})
 
+		Context("when the instance's upgrade policy defers upgrades", func() {
+			It("returns an upgrade deferred result", func() {
+				response := http.Response{
+					StatusCode: http.StatusUnprocessableEntity,
+					Body:       asBody(""),
+				}
+
+				result, err := converter.UpgradeOperationFrom(&response)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Type).To(Equal(services.UpgradeDeferred))
+			})
+		})
+
 		Context("when the upgrade response is internal server error", func() {
 			It("returns the error description", func() {
 				response := http.Response{
@@ -10,16 +10,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/pivotal-cf/brokerapi"
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/mgmtapi"
 )
 
 //go:generate counterfeiter -o fakes/fake_http_client.go . HTTPClient
 type HTTPClient interface {
 	Get(path string, query map[string]string) (*http.Response, error)
-	Patch(path string) (*http.Response, error)
+	Patch(path string, query map[string]string) (*http.Response, error)
+	Post(path string) (*http.Response, error)
+	Delete(path string, query map[string]string) (*http.Response, error)
 }
 
 type BrokerServices struct {
@@ -42,14 +46,35 @@ func (b *BrokerServices) Instances() ([]string, error) {
 	return b.converter.ListInstancesFrom(response)
 }
 
-func (b *BrokerServices) UpgradeInstance(instanceGUID string) (UpgradeOperation, error) {
-	response, err := b.client.Patch(fmt.Sprintf("/mgmt/service_instances/%s", instanceGUID))
+func (b *BrokerServices) UpgradeInstance(instanceGUID string, options broker.UpgradeOptions) (UpgradeOperation, error) {
+	var query map[string]string
+	if options.OverrideUpgradePolicy {
+		query = map[string]string{"override_upgrade_policy": "true"}
+	}
+
+	response, err := b.client.Patch(fmt.Sprintf("/mgmt/service_instances/%s", instanceGUID), query)
 	if err != nil {
 		return UpgradeOperation{}, err
 	}
 	return b.converter.UpgradeOperationFrom(response)
 }
 
+func (b *BrokerServices) BackupInstance(instanceGUID string) (BackupOperation, error) {
+	response, err := b.client.Post(fmt.Sprintf("/mgmt/service_instances/%s/backups", instanceGUID))
+	if err != nil {
+		return BackupOperation{}, err
+	}
+	return b.converter.BackupOperationFrom(response)
+}
+
+func (b *BrokerServices) MaintenanceWindow(instanceGUID string) (*config.MaintenanceWindow, error) {
+	response, err := b.client.Get(fmt.Sprintf("/mgmt/service_instances/%s/maintenance_window", instanceGUID), nil)
+	if err != nil {
+		return nil, err
+	}
+	return b.converter.MaintenanceWindowFrom(response)
+}
+
 func (b *BrokerServices) LastOperation(instanceGUID string, operationData broker.OperationData) (brokerapi.LastOperation, error) {
 	asJSON, err := json.Marshal(operationData)
 	if err != nil {
@@ -72,3 +97,39 @@ func (b *BrokerServices) OrphanDeployments() ([]mgmtapi.Deployment, error) {
 
 	return b.converter.OrphanDeploymentsFrom(response)
 }
+
+func (b *BrokerServices) DeleteOrphanDeployment(deploymentName string, options broker.OrphanDeletionOptions) (broker.OrphanDeletionResult, error) {
+	query := map[string]string{
+		"dry_run":               strconv.FormatBool(options.DryRun),
+		"run_pre_delete_errand": strconv.FormatBool(options.RunPreDeleteErrand),
+		"min_age":               options.MinAge.String(),
+	}
+	if options.PlanID != "" {
+		query["plan_id"] = options.PlanID
+	}
+
+	response, err := b.client.Delete(fmt.Sprintf("/mgmt/orphan_deployments/%s", deploymentName), query)
+	if err != nil {
+		return broker.OrphanDeletionResult{}, err
+	}
+
+	return b.converter.OrphanDeletionResultFrom(response)
+}
+
+func (b *BrokerServices) MissingDeployments() ([]mgmtapi.MissingDeployment, error) {
+	response, err := b.client.Get("/mgmt/missing_deployments", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.converter.MissingDeploymentsFrom(response)
+}
+
+func (b *BrokerServices) RecreateMissingDeployment(instanceGUID string) (broker.OperationData, error) {
+	response, err := b.client.Post(fmt.Sprintf("/mgmt/missing_deployments/%s", instanceGUID))
+	if err != nil {
+		return broker.OperationData{}, err
+	}
+
+	return b.converter.RecreateOperationFrom(response)
+}
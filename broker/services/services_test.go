@@ -11,13 +11,16 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	"github.com/pivotal-cf/brokerapi"
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
 	"github.com/pivotal-cf/on-demand-service-broker/broker/services/fakes"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/mgmtapi"
 )
 
@@ -71,19 +74,30 @@ var _ = Describe("Broker Services", func() {
 		It("returns an upgrade operation", func() {
 			client.PatchReturns(response(http.StatusNotFound, ""), nil)
 
-			upgradeOperation, err := brokerServices.UpgradeInstance(serviceInstanceGUID)
+			upgradeOperation, err := brokerServices.UpgradeInstance(serviceInstanceGUID, broker.UpgradeOptions{})
 
 			Expect(err).NotTo(HaveOccurred())
-			actualPath := client.PatchArgsForCall(0)
+			actualPath, actualQuery := client.PatchArgsForCall(0)
 			Expect(actualPath).To(Equal("/mgmt/service_instances/" + serviceInstanceGUID))
+			Expect(actualQuery).To(BeNil())
 			Expect(upgradeOperation.Type).To(Equal(services.InstanceNotFound))
 		})
 
+		It("overrides the upgrade policy of the instance", func() {
+			client.PatchReturns(response(http.StatusAccepted, "{}"), nil)
+
+			_, err := brokerServices.UpgradeInstance(serviceInstanceGUID, broker.UpgradeOptions{OverrideUpgradePolicy: true})
+
+			Expect(err).NotTo(HaveOccurred())
+			_, actualQuery := client.PatchArgsForCall(0)
+			Expect(actualQuery).To(Equal(map[string]string{"override_upgrade_policy": "true"}))
+		})
+
 		Context("when the request fails", func() {
 			It("returns an error", func() {
 				client.PatchReturns(nil, errors.New("connection error"))
 
-				_, err := brokerServices.UpgradeInstance(serviceInstanceGUID)
+				_, err := brokerServices.UpgradeInstance(serviceInstanceGUID, broker.UpgradeOptions{})
 
 				Expect(err).To(HaveOccurred())
 			})
@@ -93,7 +107,7 @@ var _ = Describe("Broker Services", func() {
 			It("returns an error", func() {
 				client.PatchReturns(response(http.StatusInternalServerError, "error upgrading instance"), nil)
 
-				_, err := brokerServices.UpgradeInstance(serviceInstanceGUID)
+				_, err := brokerServices.UpgradeInstance(serviceInstanceGUID, broker.UpgradeOptions{})
 
 				Expect(err).To(HaveOccurred())
 			})
@@ -180,6 +194,195 @@ var _ = Describe("Broker Services", func() {
 			})
 		})
 	})
+
+	Describe("MissingDeployments", func() {
+		It("returns a list of missing deployments", func() {
+			client.GetReturns(response(http.StatusOK, `[{"service_instance_id":"one","deployment_name":"service-instance_one","plan_id":"plan","organization_guid":"org","space_guid":"space"}]`), nil)
+
+			missing, err := brokerServices.MissingDeployments()
+
+			Expect(err).NotTo(HaveOccurred())
+			actualPath, _ := client.GetArgsForCall(0)
+			Expect(actualPath).To(Equal("/mgmt/missing_deployments"))
+			Expect(missing).To(ConsistOf(mgmtapi.MissingDeployment{
+				InstanceID:       "one",
+				DeploymentName:   "service-instance_one",
+				PlanID:           "plan",
+				OrganizationGUID: "org",
+				SpaceGUID:        "space",
+			}))
+		})
+
+		Context("when the request fails", func() {
+			It("returns an error", func() {
+				client.GetReturns(nil, errors.New("connection error"))
+
+				_, err := brokerServices.MissingDeployments()
+
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("MaintenanceWindow", func() {
+		It("returns the maintenance window of the instance", func() {
+			client.GetReturns(response(http.StatusOK, `{"maintenance_window": {"days": ["sat"], "start": "02:00", "duration": "4h"}}`), nil)
+
+			window, err := brokerServices.MaintenanceWindow(serviceInstanceGUID)
+
+			Expect(err).NotTo(HaveOccurred())
+			actualPath, _ := client.GetArgsForCall(0)
+			Expect(actualPath).To(Equal("/mgmt/service_instances/my-service-instance/maintenance_window"))
+			Expect(window).To(Equal(&config.MaintenanceWindow{Days: []string{"sat"}, Start: "02:00", Duration: "4h"}))
+		})
+
+		It("returns no window when the instance has none", func() {
+			client.GetReturns(response(http.StatusOK, `{"maintenance_window": null}`), nil)
+
+			window, err := brokerServices.MaintenanceWindow(serviceInstanceGUID)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(window).To(BeNil())
+		})
+
+		It("returns no window when the instance cannot be found", func() {
+			client.GetReturns(response(http.StatusNotFound, ""), nil)
+
+			window, err := brokerServices.MaintenanceWindow(serviceInstanceGUID)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(window).To(BeNil())
+		})
+
+		Context("when the broker fails", func() {
+			It("returns an error", func() {
+				client.GetReturns(response(http.StatusInternalServerError, ""), nil)
+
+				_, err := brokerServices.MaintenanceWindow(serviceInstanceGUID)
+
+				Expect(err).To(MatchError(ContainSubstring("HTTP response status")))
+			})
+		})
+	})
+
+	Describe("BackupInstance", func() {
+		It("returns the operation data of the backup errand", func() {
+			client.PostReturns(response(http.StatusAccepted, `{"BoshTaskID":7,"BoshContextID":"ctx","OperationType":"backup"}`), nil)
+
+			operation, err := brokerServices.BackupInstance(serviceInstanceGUID)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(client.PostArgsForCall(0)).To(Equal("/mgmt/service_instances/my-service-instance/backups"))
+			Expect(operation).To(Equal(services.BackupOperation{
+				Type: services.BackupAccepted,
+				Data: broker.OperationData{BoshTaskID: 7, BoshContextID: "ctx", OperationType: broker.OperationTypeBackup},
+			}))
+		})
+
+		DescribeTable("reporting why a backup did not start",
+			func(statusCode int, expectedType services.BackupOperationType) {
+				client.PostReturns(response(statusCode, ""), nil)
+
+				operation, err := brokerServices.BackupInstance(serviceInstanceGUID)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(operation.Type).To(Equal(expectedType))
+			},
+			Entry("instance deleted", http.StatusNotFound, services.BackupInstanceNotFound),
+			Entry("deployment missing", http.StatusGone, services.BackupOrphanDeployment),
+			Entry("operation in progress", http.StatusConflict, services.BackupOperationInProgress),
+			Entry("no backup errand", http.StatusUnprocessableEntity, services.BackupNotConfigured),
+		)
+
+		Context("when the broker fails", func() {
+			It("returns an error with the description", func() {
+				client.PostReturns(response(http.StatusInternalServerError, `{"description":"errand error"}`), nil)
+
+				_, err := brokerServices.BackupInstance(serviceInstanceGUID)
+
+				Expect(err).To(MatchError("unexpected status code: 500. description: errand error"))
+			})
+		})
+
+		Context("when the request fails", func() {
+			It("returns an error", func() {
+				client.PostReturns(nil, errors.New("connection error"))
+
+				_, err := brokerServices.BackupInstance(serviceInstanceGUID)
+
+				Expect(err).To(MatchError("connection error"))
+			})
+		})
+	})
+
+	Describe("RecreateMissingDeployment", func() {
+		It("returns the operation data of the create task", func() {
+			client.PostReturns(response(http.StatusAccepted, `{"BoshTaskID":7,"OperationType":"create"}`), nil)
+
+			operationData, err := brokerServices.RecreateMissingDeployment("one")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(client.PostArgsForCall(0)).To(Equal("/mgmt/missing_deployments/one"))
+			Expect(operationData).To(Equal(broker.OperationData{BoshTaskID: 7, OperationType: broker.OperationTypeCreate}))
+		})
+
+		Context("when the deployment is not missing", func() {
+			It("returns an error with the description", func() {
+				client.PostReturns(response(http.StatusNotFound, `{"description":"not missing"}`), nil)
+
+				_, err := brokerServices.RecreateMissingDeployment("one")
+
+				Expect(err).To(MatchError("unexpected status code: 404. description: not missing"))
+			})
+		})
+	})
+
+	Describe("DeleteOrphanDeployment", func() {
+		It("deletes the deployment with the given options", func() {
+			client.DeleteReturns(response(http.StatusOK, `{"deployment_name":"service-instance_one","deleted":true,"delete_task_id":7}`), nil)
+
+			result, err := brokerServices.DeleteOrphanDeployment("service-instance_one", broker.OrphanDeletionOptions{
+				RunPreDeleteErrand: true,
+				MinAge:             time.Hour,
+				PlanID:             "some-plan",
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			actualPath, actualQuery := client.DeleteArgsForCall(0)
+			Expect(actualPath).To(Equal("/mgmt/orphan_deployments/service-instance_one"))
+			Expect(actualQuery).To(Equal(map[string]string{
+				"dry_run":               "false",
+				"run_pre_delete_errand": "true",
+				"min_age":               "1h0m0s",
+				"plan_id":               "some-plan",
+			}))
+			Expect(result).To(Equal(broker.OrphanDeletionResult{
+				DeploymentName: "service-instance_one",
+				Deleted:        true,
+				DeleteTaskID:   7,
+			}))
+		})
+
+		Context("when the broker refuses to delete the deployment", func() {
+			It("returns an error with the description", func() {
+				client.DeleteReturns(response(http.StatusConflict, `{"description":"service instance one exists in Cloud Foundry"}`), nil)
+
+				_, err := brokerServices.DeleteOrphanDeployment("service-instance_one", broker.OrphanDeletionOptions{})
+
+				Expect(err).To(MatchError("unexpected status code: 409. description: service instance one exists in Cloud Foundry"))
+			})
+		})
+
+		Context("when the request fails", func() {
+			It("returns an error", func() {
+				client.DeleteReturns(nil, errors.New("connection error"))
+
+				_, err := brokerServices.DeleteOrphanDeployment("service-instance_one", broker.OrphanDeletionOptions{})
+
+				Expect(err).To(MatchError("connection error"))
+			})
+		})
+	})
 })
 
 func response(statusCode int, body string) *http.Response {
@@ -7,6 +7,7 @@
 package broker
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -14,29 +15,40 @@ import (
 
 	"github.com/coreos/go-semver/semver"
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 )
 
 func (b *Broker) startupChecks() error {
 	logger := b.loggerFactory.New()
+	serviceOffering := b.serviceOffering.Load()
 
-	if err := b.checkAPIVersions(logger); err != nil {
+	if err := b.checkAPIVersions(serviceOffering, logger); err != nil {
 		return err
 	}
 
-	if err := b.verifyExistingInstancePlanIDsUnchanged(logger); err != nil {
+	if err := b.checkServiceDeploymentUploaded(logger); err != nil {
+		return err
+	}
+
+	if err := b.checkAdapterCapabilities(logger); err != nil {
+		return err
+	}
+
+	if err := b.verifyExistingInstancePlanIDsUnchanged(serviceOffering, logger); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (b *Broker) verifyExistingInstancePlanIDsUnchanged(logger *log.Logger) error {
-	instanceCountByPlanID, err := b.cfClient.CountInstancesOfServiceOffering(b.serviceOffering.ID, logger)
+func (b *Broker) verifyExistingInstancePlanIDsUnchanged(serviceOffering config.ServiceOffering, logger *log.Logger) error {
+	instanceCountByPlanID, err := b.cfClient.CountInstancesOfServiceOffering(serviceOffering.ID, logger)
 	if err != nil {
 		return err
 	}
 
 	for planID, count := range instanceCountByPlanID {
-		_, found := b.serviceOffering.Plans.FindByID(planID)
+		_, found := serviceOffering.Plans.FindByID(planID)
 
 		if !found && count > 0 {
 			return fmt.Errorf("You cannot change the plan_id of a plan that has existing service instances")
@@ -46,13 +58,13 @@ func (b *Broker) verifyExistingInstancePlanIDsUnchanged(logger *log.Logger) erro
 	return nil
 }
 
-func (b *Broker) checkAPIVersions(logger *log.Logger) error {
+func (b *Broker) checkAPIVersions(serviceOffering config.ServiceOffering, logger *log.Logger) error {
 	var apiErrorMessages []string
 
 	if err := b.checkCFAPIVersion(logger); err != nil {
 		apiErrorMessages = append(apiErrorMessages, "CF API error: "+err.Error())
 	}
-	if err := b.checkBoshDirectorVersion(logger); err != nil {
+	if err := b.checkBoshDirectorVersion(serviceOffering, logger); err != nil {
 		apiErrorMessages = append(apiErrorMessages, "BOSH Director error: "+err.Error())
 	}
 
@@ -80,7 +92,7 @@ func (b *Broker) checkCFAPIVersion(logger *log.Logger) error {
 	return nil
 }
 
-func (b *Broker) checkBoshDirectorVersion(logger *log.Logger) error {
+func (b *Broker) checkBoshDirectorVersion(serviceOffering config.ServiceOffering, logger *log.Logger) error {
 	directorVersion, err := b.boshClient.GetDirectorVersion(logger)
 	if err != nil {
 		return fmt.Errorf("%s. ODB requires BOSH v257+.", err)
@@ -90,10 +102,69 @@ func (b *Broker) checkBoshDirectorVersion(logger *log.Logger) error {
 		return errors.New("API version is insufficient, ODB requires BOSH v257+.")
 	}
 
-	if b.serviceOffering.HasLifecycleErrands() && !directorVersion.SupportsLifecycleErrands() {
+	if serviceOffering.HasLifecycleErrands() && !directorVersion.SupportsLifecycleErrands() {
 		errMsg := fmt.Sprintf("API version is insufficient, one or more plans are configured with lifecycle_errands which require BOSH v%d+.", boshdirector.MinimumMajorSemverDirectorVersionForLifecycleErrands)
 		return errors.New(errMsg)
 	}
 
 	return nil
 }
+
+// checkServiceDeploymentUploaded catches releases and stemcells the operator forgot to upload,
+// which would otherwise only surface as director errors on provision
+func (b *Broker) checkServiceDeploymentUploaded(logger *log.Logger) error {
+	releases := b.serviceDeployment.Releases
+	stemcells := b.serviceDeployment.AllStemcells()
+	if len(releases) == 0 && len(stemcells) == 0 {
+		return nil
+	}
+
+	uploadedReleases, err := b.boshClient.GetReleases(logger)
+	if err != nil {
+		return fmt.Errorf("BOSH Director error: error getting releases: %s", err)
+	}
+
+	uploadedStemcells, err := b.boshClient.GetStemcells(logger)
+	if err != nil {
+		return fmt.Errorf("BOSH Director error: error getting stemcells: %s", err)
+	}
+
+	var missing []string
+	for _, release := range releases {
+		if !uploadedReleases.Contains(release.Name, release.Version) {
+			missing = append(missing, fmt.Sprintf("release %s version %s", release.Name, release.Version))
+		}
+	}
+	for _, stemcell := range stemcells {
+		if !uploadedStemcells.ContainsOS(stemcell.OS, stemcell.Version) {
+			missing = append(missing, fmt.Sprintf("stemcell %s version %s", stemcell.OS, stemcell.Version))
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf(
+			"BOSH Director error: service_deployment references %s, which must be uploaded to the director",
+			strings.Join(missing, ", "),
+		)
+	}
+	return nil
+}
+
+func (b *Broker) checkAdapterCapabilities(logger *log.Logger) error {
+	capabilities, err := b.adapterClient.Capabilities(context.Background(), logger)
+	if err != nil {
+		return fmt.Errorf("Service adapter error: error getting capabilities: %s", err)
+	}
+
+	var missing []string
+	for _, subcommand := range serviceadapter.MandatorySubcommands {
+		if !capabilities.SupportsSubcommand(subcommand) {
+			missing = append(missing, subcommand)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("Service adapter error: the service adapter does not support %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
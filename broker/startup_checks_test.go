@@ -14,7 +14,8 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
-	"github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
+	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
 )
 
 var _ = Describe("Initializing the broker", func() {
@@ -167,7 +168,7 @@ var _ = Describe("Initializing the broker", func() {
 						PostDeploy: "",
 						PreDelete:  "",
 					},
-					InstanceGroups: []serviceadapter.InstanceGroup{},
+					InstanceGroups: []sdk.InstanceGroup{},
 				}
 
 				serviceCatalog.Plans = config.Plans{
@@ -270,4 +271,117 @@ var _ = Describe("Initializing the broker", func() {
 			})
 		})
 	})
+
+	Describe("check service deployment releases and stemcell", func() {
+		Context("when they are uploaded to the director", func() {
+			It("returns no error", func() {
+				Expect(brokerCreationErr).NotTo(HaveOccurred())
+				Expect(boshClient.GetReleasesCallCount()).To(Equal(1))
+				Expect(boshClient.GetStemcellsCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when a release version and the stemcell are not uploaded", func() {
+			BeforeEach(func() {
+				serviceDeployment.Releases = append(serviceDeployment.Releases, sdk.ServiceRelease{Name: "syslog", Version: "11"})
+				boshClient.GetStemcellsReturns(boshdirector.Stemcells{{OperatingSystem: "ubuntu-trusty", Version: "3311"}}, nil)
+			})
+
+			It("returns an error naming them", func() {
+				Expect(brokerCreationErr).To(MatchError(
+					"BOSH Director error: service_deployment references release syslog version 11, stemcell ubuntu-trusty version 3312, which must be uploaded to the director",
+				))
+			})
+		})
+
+		Context("when one of several aliased stemcells is not uploaded", func() {
+			BeforeEach(func() {
+				serviceDeployment.Stemcell = sdk.Stemcell{}
+				serviceDeployment.Stemcells = []config.Stemcell{
+					{Alias: "linux", OS: "ubuntu-trusty", Version: "3312"},
+					{Alias: "windows", OS: "windows2012R2", Version: "1200.3"},
+				}
+			})
+
+			It("returns an error naming it", func() {
+				Expect(brokerCreationErr).To(MatchError(
+					"BOSH Director error: service_deployment references stemcell windows2012R2 version 1200.3, which must be uploaded to the director",
+				))
+			})
+		})
+
+		Context("when no service deployment is configured", func() {
+			BeforeEach(func() {
+				serviceDeployment = config.ServiceDeployment{}
+			})
+
+			It("does not query the director", func() {
+				Expect(brokerCreationErr).NotTo(HaveOccurred())
+				Expect(boshClient.GetReleasesCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when getting the releases fails", func() {
+			BeforeEach(func() {
+				boshClient.GetReleasesReturns(nil, errors.New("director says no"))
+			})
+
+			It("returns an error", func() {
+				Expect(brokerCreationErr).To(MatchError("BOSH Director error: error getting releases: director says no"))
+			})
+		})
+
+		Context("when getting the stemcells fails", func() {
+			BeforeEach(func() {
+				boshClient.GetStemcellsReturns(nil, errors.New("director says no"))
+			})
+
+			It("returns an error", func() {
+				Expect(brokerCreationErr).To(MatchError("BOSH Director error: error getting stemcells: director says no"))
+			})
+		})
+	})
+
+	Describe("check service adapter capabilities", func() {
+		Context("when the adapter does not report its subcommands", func() {
+			It("returns no error", func() {
+				Expect(brokerCreationErr).NotTo(HaveOccurred())
+				Expect(serviceAdapter.CapabilitiesCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when the adapter supports the mandatory subcommands", func() {
+			BeforeEach(func() {
+				serviceAdapter.CapabilitiesReturns(serviceadapter.Capabilities{
+					Subcommands: []string{"generate-manifest", "create-binding", "delete-binding"},
+				}, nil)
+			})
+
+			It("returns no error", func() {
+				Expect(brokerCreationErr).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("when the adapter does not support a mandatory subcommand", func() {
+			BeforeEach(func() {
+				serviceAdapter.CapabilitiesReturns(serviceadapter.Capabilities{
+					Subcommands: []string{"generate-manifest", "dashboard-url"},
+				}, nil)
+			})
+
+			It("returns an error naming the missing subcommands", func() {
+				Expect(brokerCreationErr).To(MatchError("Service adapter error: the service adapter does not support create-binding, delete-binding"))
+			})
+		})
+
+		Context("when getting the capabilities fails", func() {
+			BeforeEach(func() {
+				serviceAdapter.CapabilitiesReturns(serviceadapter.Capabilities{}, errors.New("adapter timed out"))
+			})
+
+			It("returns an error", func() {
+				Expect(brokerCreationErr).To(MatchError("Service adapter error: error getting capabilities: adapter timed out"))
+			})
+		})
+	})
 })
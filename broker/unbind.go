@@ -24,7 +24,7 @@ func (b *Broker) Unbind(
 ) error {
 
 	requestID := uuid.New()
-	ctx = brokercontext.New(ctx, string(OperationTypeUnbind), requestID, b.serviceOffering.Name, instanceID)
+	ctx = brokercontext.New(ctx, string(OperationTypeUnbind), requestID, b.serviceOffering.Load().Name, instanceID)
 	logger := b.loggerFactory.NewWithContext(ctx)
 
 	errs := func(err DisplayableError) error {
@@ -48,7 +48,7 @@ func (b *Broker) Unbind(
 	}
 
 	logger.Printf("service adapter will delete binding with ID %s for instance %s\n", bindingID, instanceID)
-	err = b.adapterClient.DeleteBinding(bindingID, vms, manifest, requestParams, logger)
+	err = b.adapterClient.DeleteBinding(ctx, bindingID, vms, manifest, requestParams, logger)
 
 	if err != nil {
 		logger.Printf("delete binding: %v\n", err)
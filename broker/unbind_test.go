@@ -50,7 +50,7 @@ var _ = Describe("Unbind", func() {
 
 	It("destroys the binding using the bosh topology and admin credentials", func() {
 		Expect(serviceAdapter.DeleteBindingCallCount()).To(Equal(1))
-		passedBindingID, passedVms, passedManifest, passedRequestParams, _ := serviceAdapter.DeleteBindingArgsForCall(0)
+		_, passedBindingID, passedVms, passedManifest, passedRequestParams, _ := serviceAdapter.DeleteBindingArgsForCall(0)
 		Expect(passedBindingID).To(Equal(bindingID))
 		Expect(passedVms).To(Equal(boshVms))
 		Expect(passedManifest).To(Equal(actualManifest))
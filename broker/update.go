@@ -29,8 +29,10 @@ func (b *Broker) Update(
 	b.deploymentLock.Lock()
 	defer b.deploymentLock.Unlock()
 
+	serviceOffering := b.serviceOffering.Load()
+
 	requestID := uuid.New()
-	ctx = brokercontext.New(ctx, string(OperationTypeUpdate), requestID, b.serviceOffering.Name, instanceID)
+	ctx = brokercontext.New(ctx, string(OperationTypeUpdate), requestID, serviceOffering.Name, instanceID)
 	logger := b.loggerFactory.NewWithContext(ctx)
 
 	if !asyncAllowed {
@@ -42,7 +44,7 @@ func (b *Broker) Update(
 		return brokerapi.UpdateServiceSpec{IsAsync: true}, err.ErrorForCFUser()
 	}
 
-	plan, found := b.serviceOffering.FindPlanByID(details.PlanID)
+	plan, found := serviceOffering.FindPlanByID(details.PlanID)
 	if !found {
 		message := fmt.Sprintf("Plan %s not found", details.PlanID)
 		logger.Println(message)
@@ -62,6 +64,14 @@ func (b *Broker) Update(
 		return errs(NewGenericError(ctx, err))
 	}
 
+	if err := validateReservedParams(detailsMap); err != NilError {
+		return errs(err)
+	}
+
+	if err := b.validateParamsWithAdapter(ctx, serviceOffering, plan, &details.PreviousValues.PlanID, detailsMap, logger); err != NilError {
+		return errs(err)
+	}
+
 	var boshContextID string
 	var operationPostDeployErrandName string
 	if plan.PostDeployErrand() != "" {
@@ -70,7 +80,8 @@ func (b *Broker) Update(
 	}
 
 	boshTaskID, _, err := b.deployer.Update(
-		deploymentName(instanceID),
+		ctx,
+		b.deploymentName(instanceID),
 		details.PlanID,
 		detailsMap,
 		&details.PreviousValues.PlanID,
@@ -91,7 +102,7 @@ func (b *Broker) Update(
 		return brokerapi.UpdateServiceSpec{IsAsync: true}, errors.New(OperationInProgressMessage)
 	case task.PlanNotFoundError:
 		return brokerapi.UpdateServiceSpec{IsAsync: true}, err
-	case serviceadapter.UnknownFailureError:
+	case serviceadapter.UnknownFailureError, serviceadapter.TimeoutError, serviceadapter.StructuredError:
 		return brokerapi.UpdateServiceSpec{IsAsync: true}, adapterToAPIError(ctx, err)
 	case error:
 		return errs(NewGenericError(ctx, fmt.Errorf("error deploying instance: %s", err)))
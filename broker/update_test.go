@@ -90,7 +90,7 @@ var _ = Describe("Update", func() {
 
 				It("calls the deployer without a bosh context id", func() {
 					Expect(fakeDeployer.UpdateCallCount()).To(Equal(1))
-					_, _, _, _, actualBoshContextID, _ := fakeDeployer.UpdateArgsForCall(0)
+					_, _, _, _, _, actualBoshContextID, _ := fakeDeployer.UpdateArgsForCall(0)
 					Expect(actualBoshContextID).To(BeEmpty())
 				})
 
@@ -165,7 +165,7 @@ var _ = Describe("Update", func() {
 
 				It("calls the deployer with a bosh context id", func() {
 					Expect(fakeDeployer.UpdateCallCount()).To(Equal(1))
-					_, _, _, _, actualBoshContextID, _ := fakeDeployer.UpdateArgsForCall(0)
+					_, _, _, _, _, actualBoshContextID, _ := fakeDeployer.UpdateArgsForCall(0)
 					Expect(actualBoshContextID).NotTo(BeEmpty())
 				})
 			})
@@ -277,7 +277,7 @@ var _ = Describe("Update", func() {
 
 					It("calls the deployer with a bosh context id", func() {
 						Expect(fakeDeployer.UpdateCallCount()).To(Equal(1))
-						_, _, _, _, actualBoshContextID, _ := fakeDeployer.UpdateArgsForCall(0)
+						_, _, _, _, _, actualBoshContextID, _ := fakeDeployer.UpdateArgsForCall(0)
 						Expect(actualBoshContextID).NotTo(BeEmpty())
 					})
 				})
@@ -383,6 +383,45 @@ var _ = Describe("Update", func() {
 			})
 		})
 
+		Context("when the adapter client fails with a structured error", func() {
+			BeforeEach(func() {
+				fakeDeployer.UpdateReturns(boshTaskID, nil, serviceadapter.StructuredError{UserMessage: "cannot shrink disk"})
+			})
+
+			It("returns the user message as a bad request", func() {
+				Expect(updateError).To(Equal(brokerapi.NewFailureResponse(
+					errors.New("cannot shrink disk"),
+					http.StatusBadRequest,
+					"service-adapter-error",
+				)))
+			})
+		})
+
+		Context("when the adapter validates the params", func() {
+			It("passes the new plan, the previous plan and the params", func() {
+				Expect(serviceAdapter.ValidateParamsCallCount()).To(Equal(1))
+				_, plan, previousPlan, requestParams, _ := serviceAdapter.ValidateParamsArgsForCall(0)
+				Expect(plan).To(Equal(existingPlan.AdapterPlan(serviceCatalog.GlobalProperties)))
+				Expect(*previousPlan).To(Equal(secondPlan.AdapterPlan(serviceCatalog.GlobalProperties)))
+				Expect(requestParams).To(HaveKeyWithValue("parameters", arbitraryParams))
+			})
+		})
+
+		Context("when the adapter rejects the params", func() {
+			BeforeEach(func() {
+				serviceAdapter.ValidateParamsReturns(serviceadapter.StructuredError{UserMessage: "cannot shrink disk"})
+			})
+
+			It("returns the user message as a bad request without deploying", func() {
+				Expect(updateError).To(Equal(brokerapi.NewFailureResponse(
+					errors.New("cannot shrink disk"),
+					http.StatusBadRequest,
+					"service-adapter-error",
+				)))
+				Expect(fakeDeployer.UpdateCallCount()).To(BeZero())
+			})
+		})
+
 		Context("when bosh is blocked", func() {
 			BeforeEach(func() {
 				fakeDeployer.UpdateReturns(boshTaskID, nil, task.TaskInProgressError{})
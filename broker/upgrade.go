@@ -10,13 +10,18 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/pborman/uuid"
 	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 	"github.com/pivotal-cf/on-demand-service-broker/task"
 )
 
-func (b *Broker) Upgrade(ctx context.Context, instanceID string, logger *log.Logger) (OperationData, error) {
+type UpgradeOptions struct {
+	OverrideUpgradePolicy bool
+}
+
+func (b *Broker) Upgrade(ctx context.Context, instanceID string, options UpgradeOptions, logger *log.Logger) (OperationData, error) {
 	b.deploymentLock.Lock()
 	defer b.deploymentLock.Unlock()
 
@@ -31,12 +36,18 @@ func (b *Broker) Upgrade(ctx context.Context, instanceID string, logger *log.Log
 
 	logger.Printf("upgrading instance %s", instanceID)
 
-	plan, found := b.serviceOffering.FindPlanByID(instance.PlanID)
+	plan, found := b.serviceOffering.Load().FindPlanByID(instance.PlanID)
 	if !found {
 		logger.Printf("error: finding plan ID %s", instance.PlanID)
 		return OperationData{}, fmt.Errorf("plan %s not found", instance.PlanID)
 	}
 
+	if !options.OverrideUpgradePolicy {
+		if err := b.assertUpgradeNotDeferred(instanceID, logger); err != nil {
+			return OperationData{}, err
+		}
+	}
+
 	var boshContextID string
 	var operationPostDeployErrand string
 	if plan.LifecycleErrands != nil {
@@ -45,7 +56,8 @@ func (b *Broker) Upgrade(ctx context.Context, instanceID string, logger *log.Log
 	}
 
 	taskID, _, err := b.deployer.Upgrade(
-		deploymentName(instanceID),
+		ctx,
+		b.deploymentName(instanceID),
 		instance.PlanID,
 		&instance.PlanID,
 		boshContextID,
@@ -58,7 +70,7 @@ func (b *Broker) Upgrade(ctx context.Context, instanceID string, logger *log.Log
 		switch err := err.(type) {
 		case DisplayableError:
 			return OperationData{}, err.ErrorForCFUser()
-		case serviceadapter.UnknownFailureError:
+		case serviceadapter.UnknownFailureError, serviceadapter.TimeoutError, serviceadapter.StructuredError:
 			return OperationData{}, adapterToAPIError(ctx, err)
 		case task.TaskInProgressError:
 			return OperationData{}, NewOperationInProgressError(err)
@@ -74,3 +86,29 @@ func (b *Broker) Upgrade(ctx context.Context, instanceID string, logger *log.Log
 		OperationType:        OperationTypeUpgrade,
 	}, nil
 }
+
+// assertUpgradeNotDeferred honours developers deferring upgrades, for at most the max upgrade deferral
+func (b *Broker) assertUpgradeNotDeferred(instanceID string, logger *log.Logger) error {
+	deploymentName := b.deploymentName(instanceID)
+	content, deferred, err := b.boshClient.GetConfig(task.UpgradeDeferredSinceConfigType, deploymentName, logger)
+	if err != nil {
+		logger.Printf("error getting upgrade deferral of deployment %s: %s", deploymentName, err)
+		return err
+	}
+	if !deferred {
+		return nil
+	}
+
+	since, err := task.UpgradeDeferralOf(content)
+	if err != nil {
+		logger.Printf("error reading upgrade deferral of deployment %s: %s", deploymentName, err)
+		return err
+	}
+
+	if b.maxUpgradeDeferral > 0 && time.Since(since) > b.maxUpgradeDeferral {
+		logger.Printf("upgrades of instance %s deferred since %s, longer than the max upgrade deferral of %s: forcing upgrade", instanceID, since.Format(time.RFC3339), b.maxUpgradeDeferral)
+		return nil
+	}
+
+	return NewUpgradeDeferredError(fmt.Errorf("upgrades of instance %s deferred by its upgrade policy since %s", instanceID, since.Format(time.RFC3339)))
+}
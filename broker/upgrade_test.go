@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -28,6 +29,7 @@ var _ = Describe("Upgrade", func() {
 		logger                   *log.Logger
 		expectedPreviousManifest []byte
 		boshTaskID               int
+		upgradeOptions           broker.UpgradeOptions
 		redeployErr              error
 	)
 
@@ -36,11 +38,12 @@ var _ = Describe("Upgrade", func() {
 		serviceDeploymentName = deploymentName(instanceID)
 		expectedPreviousManifest = []byte("old-manifest-fetched-from-bosh")
 		boshTaskID = 876
+		upgradeOptions = broker.UpgradeOptions{}
 	})
 
 	JustBeforeEach(func() {
 		logger = loggerFactory.NewWithRequestID()
-		upgradeOperationData, redeployErr = b.Upgrade(context.Background(), instanceID, logger)
+		upgradeOperationData, redeployErr = b.Upgrade(context.Background(), instanceID, upgradeOptions, logger)
 	})
 
 	Context("when the deployment goes well", func() {
@@ -57,7 +60,7 @@ var _ = Describe("Upgrade", func() {
 			Expect(fakeDeployer.CreateCallCount()).To(Equal(0))
 			Expect(fakeDeployer.UpgradeCallCount()).To(Equal(1))
 			Expect(fakeDeployer.UpdateCallCount()).To(Equal(0))
-			actualDeploymentName, actualPlanID, actualPreviousPlanID, actualBoshContextID, _ := fakeDeployer.UpgradeArgsForCall(0)
+			_, actualDeploymentName, actualPlanID, actualPreviousPlanID, actualBoshContextID, _ := fakeDeployer.UpgradeArgsForCall(0)
 			Expect(actualPlanID).To(Equal(existingPlanID))
 			Expect(actualDeploymentName).To(Equal(broker.InstancePrefix + instanceID))
 			oldPlanIDCopy := existingPlanID
@@ -114,7 +117,7 @@ var _ = Describe("Upgrade", func() {
 			})
 
 			It("deploys with a context id", func() {
-				_, _, _, contextID, _ := fakeDeployer.UpgradeArgsForCall(0)
+				_, _, _, _, contextID, _ := fakeDeployer.UpgradeArgsForCall(0)
 				Expect(contextID).NotTo(BeEmpty())
 				Expect(upgradeOperationData.BoshContextID).NotTo(BeEmpty())
 				Expect(upgradeOperationData).To(Equal(
@@ -196,4 +199,69 @@ var _ = Describe("Upgrade", func() {
 			Expect(redeployErr).To(BeAssignableToTypeOf(broker.OperationInProgressError{}))
 		})
 	})
+
+	Context("when the developer has deferred upgrades", func() {
+		BeforeEach(func() {
+			cfClient.GetInstanceStateReturns(cf.InstanceState{PlanID: existingPlanID}, nil)
+			fakeDeployer.UpgradeReturns(boshTaskID, []byte("new-manifest-fetched-from-adapter"), nil)
+			deferredSince := time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
+			boshClient.GetConfigReturns([]byte(deferredSince), true, nil)
+		})
+
+		It("returns an UpgradeDeferredError without upgrading", func() {
+			Expect(redeployErr).To(BeAssignableToTypeOf(broker.UpgradeDeferredError{}))
+			Expect(redeployErr).To(MatchError(ContainSubstring("upgrades of instance some-instance deferred by its upgrade policy since")))
+			Expect(fakeDeployer.UpgradeCallCount()).To(BeZero())
+
+			actualType, actualName, _ := boshClient.GetConfigArgsForCall(0)
+			Expect(actualType).To(Equal(task.UpgradeDeferredSinceConfigType))
+			Expect(actualName).To(Equal(serviceDeploymentName))
+		})
+
+		Context("and the operator overrides the upgrade policy", func() {
+			BeforeEach(func() {
+				upgradeOptions = broker.UpgradeOptions{OverrideUpgradePolicy: true}
+			})
+
+			It("upgrades the instance", func() {
+				Expect(redeployErr).NotTo(HaveOccurred())
+				Expect(fakeDeployer.UpgradeCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("and the deferral is within the max upgrade deferral", func() {
+			BeforeEach(func() {
+				maxUpgradeDeferral = 72 * time.Hour
+			})
+
+			It("returns an UpgradeDeferredError without upgrading", func() {
+				Expect(redeployErr).To(BeAssignableToTypeOf(broker.UpgradeDeferredError{}))
+				Expect(fakeDeployer.UpgradeCallCount()).To(BeZero())
+			})
+		})
+
+		Context("and the deferral is older than the max upgrade deferral", func() {
+			BeforeEach(func() {
+				maxUpgradeDeferral = 24 * time.Hour
+			})
+
+			It("forces the upgrade", func() {
+				Expect(redeployErr).NotTo(HaveOccurred())
+				Expect(fakeDeployer.UpgradeCallCount()).To(Equal(1))
+				Expect(logBuffer.String()).To(ContainSubstring("longer than the max upgrade deferral of 24h0m0s: forcing upgrade"))
+			})
+		})
+	})
+
+	Context("when getting the upgrade deferral fails", func() {
+		BeforeEach(func() {
+			cfClient.GetInstanceStateReturns(cf.InstanceState{PlanID: existingPlanID}, nil)
+			boshClient.GetConfigReturns(nil, false, errors.New("director error"))
+		})
+
+		It("returns the error without upgrading", func() {
+			Expect(redeployErr).To(MatchError("director error"))
+			Expect(fakeDeployer.UpgradeCallCount()).To(BeZero())
+		})
+	})
 })
@@ -94,12 +94,25 @@ func (c Client) CountInstancesOfPlan(serviceID, servicePlanID string, logger *lo
 }
 
 func (c Client) GetInstancesOfServiceOffering(serviceOfferingID string, logger *log.Logger) ([]string, error) {
-	plans, err := c.getPlansForServiceID(serviceOfferingID, logger)
+	serviceInstances, err := c.GetServiceInstancesOfServiceOffering(serviceOfferingID, logger)
 	if err != nil {
 		return nil, err
 	}
 
 	var instances []string
+	for _, instance := range serviceInstances {
+		instances = append(instances, instance.GUID)
+	}
+	return instances, nil
+}
+
+func (c Client) GetServiceInstancesOfServiceOffering(serviceOfferingID string, logger *log.Logger) ([]ServiceInstance, error) {
+	plans, err := c.getPlansForServiceID(serviceOfferingID, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var instances []ServiceInstance
 	for _, plan := range plans {
 		path := fmt.Sprintf(
 			"/v2/service_plans/%s/service_instances?results-per-page=%d",
@@ -117,7 +130,12 @@ func (c Client) GetInstancesOfServiceOffering(serviceOfferingID string, logger *
 				return nil, err
 			}
 			for _, instance := range serviceInstancesResp.ServiceInstances {
-				instances = append(instances, instance.Metadata.GUID)
+				instances = append(instances, ServiceInstance{
+					GUID:          instance.Metadata.GUID,
+					PlanID:        plan.ServicePlanEntity.UniqueID,
+					SpaceGUID:     instance.Entity.SpaceGUID,
+					LastOperation: instance.Entity.LastOperation,
+				})
 			}
 			path = serviceInstancesResp.NextPath
 		}
@@ -125,6 +143,15 @@ func (c Client) GetInstancesOfServiceOffering(serviceOfferingID string, logger *
 	return instances, nil
 }
 
+func (c Client) GetOrganizationGUIDOfSpace(spaceGUID string, logger *log.Logger) (string, error) {
+	var space spaceResource
+	err := c.get(fmt.Sprintf("%s/v2/spaces/%s", c.url, spaceGUID), &space, logger)
+	if err != nil {
+		return "", err
+	}
+	return space.Entity.OrganizationGUID, nil
+}
+
 func (c Client) GetBindingsForInstance(instanceGUID string, logger *log.Logger) ([]Binding, error) {
 	path := fmt.Sprintf(
 		"/v2/service_instances/%s/service_bindings?results-per-page=%d",
@@ -821,6 +821,59 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Describe("GetServiceInstancesOfServiceOffering", func() {
+		It("returns the instances with their plans and spaces", func() {
+			offeringID := "8F3E8998-5FD0-4F32-924A-5478DC390A5F"
+
+			server.VerifyAndMock(
+				mockcfapi.ListServiceOfferings().WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_services_response.json")),
+				mockcfapi.ListServicePlans("34c08156-5b5d-4cc1-9af1-29cda9ec056f").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_plans_response.json")),
+				mockcfapi.ListServiceInstances("ff717e7c-afd5-4d0a-bafe-16c7eff546ec").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_for_plan_1_response.json")),
+				mockcfapi.ListServiceInstances("2777ad05-8114-4169-8188-2ef5f39e0c6b").WithAuthorizationHeader(cfAuthorizationHeader).RespondsOKWith(fixture("list_service_instances_for_plan_2_response.json")),
+			)
+
+			client, err := cf.New(server.URL, authHeaderBuilder, nil, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			instances, err := client.GetServiceInstancesOfServiceOffering(offeringID, testLogger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(instances).To(ConsistOf(
+				cf.ServiceInstance{GUID: "520f8566-b727-4c67-8be8-d9285645e936", PlanID: "11789210-D743-4C65-9D38-C80B29F4D9C8", SpaceGUID: "a157c861-92bb-4f57-9108-f791260f66ab", LastOperation: cf.LastOperation{Type: "create", State: "succeeded"}},
+				cf.ServiceInstance{GUID: "f897f40d-0b2d-474a-a5c9-98426a2cb4b8", PlanID: "22789210-D743-4C65-9D38-C80B29F4D9C8", SpaceGUID: "a157c861-92bb-4f57-9108-f791260f66ab", LastOperation: cf.LastOperation{Type: "update", State: "succeeded"}},
+				cf.ServiceInstance{GUID: "2f759033-04a4-426b-bccd-01722036c152", PlanID: "22789210-D743-4C65-9D38-C80B29F4D9C8", SpaceGUID: "a157c861-92bb-4f57-9108-f791260f66ab", LastOperation: cf.LastOperation{Type: "create", State: "succeeded"}},
+			))
+		})
+	})
+
+	Describe("GetOrganizationGUIDOfSpace", func() {
+		It("returns the organization of the space", func() {
+			server.VerifyAndMock(
+				mockcfapi.GetSpace("some-space-guid").RespondsWithOrganization("some-org-guid"),
+			)
+
+			client, err := cf.New(server.URL, authHeaderBuilder, nil, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			orgGUID, err := client.GetOrganizationGUIDOfSpace("some-space-guid", testLogger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(orgGUID).To(Equal("some-org-guid"))
+		})
+
+		Context("when the space cannot be retrieved", func() {
+			It("returns an error", func() {
+				server.VerifyAndMock(
+					mockcfapi.GetSpace("some-space-guid").RespondsNotFoundWith(`{"description": "The app space could not be found: some-space-guid"}`),
+				)
+
+				client, err := cf.New(server.URL, authHeaderBuilder, nil, true)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = client.GetOrganizationGUIDOfSpace("some-space-guid", testLogger)
+				Expect(err).To(BeAssignableToTypeOf(cf.ResourceNotFoundError{}))
+			})
+		})
+	})
+
 	Describe("GetBindingsForInstance", func() {
 		const serviceInstanceGUID = "92d707ce-c06c-421a-a1d2-ed1e750af650"
 
@@ -11,6 +11,7 @@ import "time"
 const (
 	defaultPerPage = 100
 
+	OperationTypeCreate OperationType = "create"
 	OperationTypeDelete OperationType = "delete"
 
 	OperationStateFailed     OperationState = "failed"
@@ -94,8 +95,21 @@ func (o LastOperation) IsDelete() bool {
 	return o.Type == OperationTypeDelete
 }
 
+func (o LastOperation) IsIncompleteCreate() bool {
+	return o.Type == OperationTypeCreate && o.isIncomplete()
+}
+
+func (o LastOperation) IsIncompleteDelete() bool {
+	return o.IsDelete() && o.isIncomplete()
+}
+
+func (o LastOperation) isIncomplete() bool {
+	return o.State == OperationStateInProgress || o.State == OperationStateFailed
+}
+
 type serviceInstanceEntity struct {
 	ServicePlanURL string        `json:"service_plan_url"`
+	SpaceGUID      string        `json:"space_guid"`
 	LastOperation  LastOperation `json:"last_operation"`
 }
 
@@ -112,6 +126,21 @@ func (i Instance) OperationFailed() bool {
 	return i.LastOperation.State == OperationStateFailed
 }
 
+type ServiceInstance struct {
+	GUID          string
+	PlanID        string
+	SpaceGUID     string
+	LastOperation LastOperation
+}
+
+type spaceResource struct {
+	Entity spaceEntity `json:"entity"`
+}
+
+type spaceEntity struct {
+	OrganizationGUID string `json:"organization_guid"`
+}
+
 type InstanceState struct {
 	PlanID              string
 	OperationInProgress bool
@@ -0,0 +1,92 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/craigfurman/herottp"
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/network"
+)
+
+const FailedToDeleteOrphansExitCode = 1
+
+type deletionReport struct {
+	broker.OrphanDeletionResult
+	Error string `json:"error,omitempty"`
+}
+
+func main() {
+	loggerFactory := loggerfactory.New(os.Stderr, "delete-orphan-deployments", loggerfactory.Flags)
+	logger := loggerFactory.New()
+
+	brokerUsername := flag.String("brokerUsername", "", "username for the broker")
+	brokerPassword := flag.String("brokerPassword", "", "password for the broker")
+	brokerURL := flag.String("brokerUrl", "", "url of the broker")
+	dryRun := flag.Bool("dryRun", false, "report which orphan deployments would be deleted without deleting them")
+	minAge := flag.Duration("minAge", 0, "only delete orphan deployments with no BOSH tasks more recent than this, e.g. 24h; orphans without any BOSH tasks are skipped")
+	runPreDeleteErrand := flag.Bool("runPreDeleteErrand", false, "run the plan's pre-delete errand before deleting each deployment")
+	planID := flag.String("planId", "", "plan whose pre-delete errand to run, required when plans have different pre-delete errands")
+	requestTimeout := flag.Duration("requestTimeout", 30*time.Minute, "timeout for each deletion request, which includes running the pre-delete errand and waiting for the deletion")
+	flag.Parse()
+
+	listClient := network.NewBasicAuthHTTPClient(network.NewDefaultHTTPClient(), *brokerUsername, *brokerPassword, *brokerURL)
+	orphans, err := services.NewBrokerServices(listClient).OrphanDeployments()
+	if err != nil {
+		logger.Fatalf("error retrieving orphan deployments: %s", err)
+	}
+
+	deleteClient := network.NewBasicAuthHTTPClient(
+		herottp.New(herottp.Config{Timeout: *requestTimeout}),
+		*brokerUsername,
+		*brokerPassword,
+		*brokerURL,
+	)
+	brokerServices := services.NewBrokerServices(deleteClient)
+
+	options := broker.OrphanDeletionOptions{
+		DryRun:             *dryRun,
+		MinAge:             *minAge,
+		RunPreDeleteErrand: *runPreDeleteErrand,
+		PlanID:             *planID,
+	}
+
+	reports := []deletionReport{}
+	failures := 0
+	for _, orphan := range orphans {
+		logger.Printf("deleting orphan deployment %s\n", orphan.Name)
+
+		result, err := brokerServices.DeleteOrphanDeployment(orphan.Name, options)
+		report := deletionReport{OrphanDeletionResult: result}
+		report.DeploymentName = orphan.Name
+		if err != nil {
+			logger.Printf("error deleting orphan deployment %s: %s\n", orphan.Name, err)
+			report.Error = err.Error()
+			failures++
+		}
+		reports = append(reports, report)
+	}
+
+	rawJSON, err := json.Marshal(reports)
+	if err != nil {
+		logger.Fatalf("error marshalling deletion report: %s", err)
+	}
+
+	fmt.Fprint(os.Stdout, string(rawJSON))
+
+	if failures > 0 {
+		logger.Printf("failed to delete %d of %d orphan deployments\n", failures, len(orphans))
+		os.Exit(FailedToDeleteOrphansExitCode)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
+	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/mgmtapi"
+	"github.com/pivotal-cf/on-demand-service-broker/network"
+)
+
+const (
+	MissingDeploymentsDetectedMessage  = "Service instances detected in Cloud Foundry with no corresponding BOSH deployment. Re-creating a deployment uses the instance's current plan, but arbitrary parameters from provisioning are not restored and any data is lost."
+	MissingDeploymentsDetectedExitCode = 10
+	FailedToRecreateExitCode           = 1
+)
+
+type recreationReport struct {
+	mgmtapi.MissingDeployment
+	BoshTaskID int    `json:"bosh_task_id,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func main() {
+	loggerFactory := loggerfactory.New(os.Stderr, "missing-deployments", loggerfactory.Flags)
+	logger := loggerFactory.New()
+
+	brokerUsername := flag.String("brokerUsername", "", "username for the broker")
+	brokerPassword := flag.String("brokerPassword", "", "password for the broker")
+	brokerURL := flag.String("brokerUrl", "", "url of the broker")
+	recreate := flag.Bool("recreate", false, "re-create each missing deployment with the instance's current plan")
+	flag.Parse()
+
+	httpClient := network.NewDefaultHTTPClient()
+	basicAuthClient := network.NewBasicAuthHTTPClient(httpClient, *brokerUsername, *brokerPassword, *brokerURL)
+	brokerServices := services.NewBrokerServices(basicAuthClient)
+
+	missing, err := brokerServices.MissingDeployments()
+	if err != nil {
+		logger.Fatalf("error retrieving missing deployments: %s", err)
+	}
+
+	if !*recreate {
+		printJSON(missing, logger)
+
+		if len(missing) > 0 {
+			logger.Println(MissingDeploymentsDetectedMessage)
+			os.Exit(MissingDeploymentsDetectedExitCode)
+		}
+		return
+	}
+
+	reports := []recreationReport{}
+	failures := 0
+	for _, deployment := range missing {
+		logger.Printf("re-creating deployment %s for instance %s\n", deployment.DeploymentName, deployment.InstanceID)
+
+		report := recreationReport{MissingDeployment: deployment}
+		operationData, err := brokerServices.RecreateMissingDeployment(deployment.InstanceID)
+		if err != nil {
+			logger.Printf("error re-creating deployment for instance %s: %s\n", deployment.InstanceID, err)
+			report.Error = err.Error()
+			failures++
+		} else {
+			report.BoshTaskID = operationData.BoshTaskID
+		}
+		reports = append(reports, report)
+	}
+
+	printJSON(reports, logger)
+
+	if failures > 0 {
+		logger.Printf("failed to re-create %d of %d missing deployments\n", failures, len(missing))
+		os.Exit(FailedToRecreateExitCode)
+	}
+}
+
+func printJSON(obj interface{}, logger *log.Logger) {
+	rawJSON, err := json.Marshal(obj)
+	if err != nil {
+		logger.Fatalf("error marshalling missing deployments: %s", err)
+	}
+
+	fmt.Fprint(os.Stdout, string(rawJSON))
+}
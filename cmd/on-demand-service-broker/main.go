@@ -7,11 +7,14 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"code.cloudfoundry.org/lager"
 	"github.com/gorilla/mux"
@@ -46,10 +49,10 @@ func main() {
 	if err != nil {
 		logger.Fatalf("error parsing config: %s", err)
 	}
-	startBroker(conf, logger, loggerFactory)
+	startBroker(*configFilePath, conf, logger, loggerFactory)
 }
 
-func startBroker(conf config.Config, logger *log.Logger, loggerFactory *loggerfactory.LoggerFactory) {
+func startBroker(configFilePath string, conf config.Config, logger *log.Logger, loggerFactory *loggerfactory.LoggerFactory) {
 	var (
 		boshAuthenticator boshdirector.AuthHeaderBuilder
 		err               error
@@ -79,6 +82,20 @@ func startBroker(conf config.Config, logger *log.Logger, loggerFactory *loggerfa
 		logger.Fatalf("error creating bosh client: %s", err)
 	}
 
+	for _, team := range conf.Bosh.Teams {
+		teamAuthenticator, err := authorizationheader.NewClientTokenAuthHeaderBuilder(
+			boshAuthConfig.UAA.UAAURL,
+			team.ID,
+			team.Secret,
+			conf.Broker.DisableSSLCertVerification,
+			[]byte(conf.Bosh.TrustedCert),
+		)
+		if err != nil {
+			logger.Fatalf("error creating BOSH authorization header builder for team %s: %s", team.Name, err)
+		}
+		boshClient.RegisterTeam(team.Name, teamAuthenticator)
+	}
+
 	cfAuthenticator, err := conf.CF.NewAuthHeaderBuilder(conf.Broker.DisableSSLCertVerification)
 	if err != nil {
 		logger.Fatalf("error creating CF authorization header builder: %s", err)
@@ -97,23 +114,53 @@ func startBroker(conf config.Config, logger *log.Logger, loggerFactory *loggerfa
 	serviceAdapter := &serviceadapter.Client{
 		ExternalBinPath: conf.ServiceAdapter.Path,
 		CommandRunner:   serviceadapter.NewCommandRunner(),
+		Timeouts:        conf.ServiceAdapter.TimeoutDurations(),
+	}
+	if conf.ServiceAdapter.URL != "" {
+		serviceAdapter.ExternalBinPath = conf.ServiceAdapter.URL
+		serviceAdapter.CommandRunner, err = serviceadapter.NewHTTPCommandRunner(conf.ServiceAdapter.URL)
+		if err != nil {
+			logger.Fatalf("error creating service adapter client: %s", err)
+		}
+	}
+	if err := serviceAdapter.UseInputMode(context.Background(), conf.ServiceAdapter.InputMode, logger); err != nil {
+		logger.Fatalf("error negotiating service adapter input mode: %s", err)
 	}
 
+	serviceOffering := config.NewServiceOfferingStore(conf.ServiceCatalog)
+
 	manifestGenerator := task.NewManifestGenerator(
 		serviceAdapter,
-		conf.ServiceCatalog,
-		conf.ServiceDeployment.Stemcell,
-		conf.ServiceDeployment.Releases,
+		serviceOffering,
+		conf.ServiceDeployment,
+		conf.Broker.TagDeployments,
 	)
 
-	deploymentManager := task.NewDeployer(boshClient, manifestGenerator)
+	deploymentManager := task.NewDeployer(boshClient, manifestGenerator, conf.Broker.StrictPendingChangesDetection(), conf.Bosh)
 
-	onDemandBroker, err := broker.New(boshClient, cfClient, serviceAdapter, deploymentManager, conf.ServiceCatalog, loggerFactory)
+	onDemandBroker, err := broker.New(
+		boshClient,
+		cfClient,
+		serviceAdapter,
+		deploymentManager,
+		serviceOffering,
+		conf.ServiceDeployment,
+		conf.Broker.DeploymentNameTemplate,
+		conf.Broker.MaxUpgradeDeferralDuration(),
+		loggerFactory,
+	)
 
 	if err != nil {
 		logger.Fatalf("error starting broker: %s", err)
 	}
 
+	if interval := conf.Broker.HealthCheckIntervalDuration(); interval > 0 {
+		go onDemandBroker.RunHealthChecks(interval, nil)
+	}
+
+	configReloader := broker.NewConfigReloader(onDemandBroker, configFilePath, conf)
+	go reloadConfigOnSIGHUP(configReloader, loggerFactory)
+
 	if conf.Broker.StartUpBanner {
 		fmt.Println(`
                   .//\
@@ -131,7 +178,7 @@ func startBroker(conf config.Config, logger *log.Logger, loggerFactory *loggerfa
 	}
 
 	brokerRouter := mux.NewRouter()
-	mgmtapi.AttachRoutes(brokerRouter, onDemandBroker, conf.ServiceCatalog, loggerFactory)
+	mgmtapi.AttachRoutes(brokerRouter, onDemandBroker, configReloader, serviceOffering, loggerFactory)
 	brokerapi.AttachRoutes(brokerRouter, onDemandBroker, lager.NewLogger("on-demand-service-broker"))
 	authProtectedBrokerAPI := apiauth.NewWrapper(conf.Broker.Username, conf.Broker.Password).Wrap(brokerRouter)
 
@@ -141,3 +188,15 @@ func startBroker(conf config.Config, logger *log.Logger, loggerFactory *loggerfa
 	server.UseHandler(authProtectedBrokerAPI)
 	server.Run(fmt.Sprintf("0.0.0.0:%d", conf.Broker.Port))
 }
+
+func reloadConfigOnSIGHUP(configReloader *broker.ConfigReloader, loggerFactory *loggerfactory.LoggerFactory) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	for range sighup {
+		logger := loggerFactory.New()
+		if err := configReloader.Reload(logger); err != nil {
+			logger.Printf("error reloading config: %s", err)
+		}
+	}
+}
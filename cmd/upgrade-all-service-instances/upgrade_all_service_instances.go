@@ -10,6 +10,7 @@ import (
 	"flag"
 	"os"
 
+	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
 	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
 	"github.com/pivotal-cf/on-demand-service-broker/network"
@@ -24,6 +25,7 @@ func main() {
 	brokerPassword := flag.String("brokerPassword", "", "password for the broker")
 	brokerUrl := flag.String("brokerUrl", "", "url of the broker")
 	pollingInterval := flag.Int("pollingInterval", 0, "interval for checking the upgrade in seconds")
+	overrideUpgradePolicy := flag.Bool("overrideUpgradePolicy", false, "upgrade instances whose upgrade policy defers upgrades")
 	flag.Parse()
 
 	if *brokerUsername == "" || *brokerPassword == "" || *brokerUrl == "" {
@@ -38,7 +40,13 @@ func main() {
 	basicAuthClient := network.NewBasicAuthHTTPClient(httpClient, *brokerUsername, *brokerPassword, *brokerUrl)
 	brokerServices := services.NewBrokerServices(basicAuthClient)
 	listener := upgrader.NewLoggingListener(logger)
-	upgradeTool := upgrader.New(brokerServices, *pollingInterval, listener)
+	upgradeTool := upgrader.New(
+		brokerServices,
+		*pollingInterval,
+		broker.UpgradeOptions{OverrideUpgradePolicy: *overrideUpgradePolicy},
+		listener,
+		upgrader.SystemClock{},
+	)
 
 	err := upgradeTool.Upgrade()
 	if err != nil {
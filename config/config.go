@@ -11,8 +11,10 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"net/url"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/pivotal-cf/on-demand-service-broker/authorizationheader"
 	"github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
@@ -26,6 +28,7 @@ type Config struct {
 	ServiceAdapter    ServiceAdapter    `yaml:"service_adapter"`
 	ServiceDeployment ServiceDeployment `yaml:"service_deployment"`
 	ServiceCatalog    ServiceOffering   `yaml:"service_catalog"`
+	CredentialsFile   string            `yaml:"credentials_file,omitempty"`
 }
 
 func (c Config) Validate() error {
@@ -41,14 +44,24 @@ func (c Config) Validate() error {
 		return err
 	}
 
-	if err := checkIsExecutableFile(c.ServiceAdapter.Path); err != nil {
-		return fmt.Errorf("checking for executable service adapter file: %s", err)
+	if c.ServiceAdapter.URL == "" {
+		if err := checkIsExecutableFile(c.ServiceAdapter.Path); err != nil {
+			return fmt.Errorf("checking for executable service adapter file: %s", err)
+		}
+	}
+
+	if err := c.ServiceAdapter.Validate(); err != nil {
+		return err
 	}
 
 	if err := c.ServiceDeployment.Validate(); err != nil {
 		return err
 	}
 
+	if err := c.ServiceCatalog.Plans.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -56,8 +69,36 @@ type Broker struct {
 	Port                       int
 	Username                   string
 	Password                   string
-	DisableSSLCertVerification bool `yaml:"disable_ssl_cert_verification"`
-	StartUpBanner              bool `yaml:"startup_banner"`
+	DisableSSLCertVerification bool   `yaml:"disable_ssl_cert_verification"`
+	StartUpBanner              bool   `yaml:"startup_banner"`
+	PendingChangesDetection    string `yaml:"pending_changes_detection"`
+	TagDeployments             bool   `yaml:"tag_deployments"`
+	DeploymentNameTemplate     string `yaml:"deployment_name_template"`
+	MaxUpgradeDeferral         string `yaml:"max_upgrade_deferral"`
+	HealthCheckInterval        string `yaml:"health_check_interval"`
+}
+
+const (
+	PendingChangesDetectionManifest = "manifest"
+	PendingChangesDetectionStrict   = "strict"
+)
+
+func (b Broker) StrictPendingChangesDetection() bool {
+	return b.PendingChangesDetection == PendingChangesDetectionStrict
+}
+
+// MaxUpgradeDeferralDuration is how long developers may defer upgrades of their instances for,
+// zero meaning indefinitely
+func (b Broker) MaxUpgradeDeferralDuration() time.Duration {
+	duration, _ := time.ParseDuration(b.MaxUpgradeDeferral)
+	return duration
+}
+
+// HealthCheckIntervalDuration is how often the broker re-runs its director health checks,
+// zero meaning only at startup
+func (b Broker) HealthCheckIntervalDuration() time.Duration {
+	duration, _ := time.ParseDuration(b.HealthCheckInterval)
+	return duration
 }
 
 func (b Broker) Validate() error {
@@ -70,13 +111,42 @@ func (b Broker) Validate() error {
 	if b.Password == "" {
 		return errors.New("broker.password can't be empty")
 	}
+	switch b.PendingChangesDetection {
+	case "", PendingChangesDetectionManifest, PendingChangesDetectionStrict:
+	default:
+		return fmt.Errorf(
+			"broker.pending_changes_detection must be one of '%s' or '%s', got '%s'",
+			PendingChangesDetectionManifest,
+			PendingChangesDetectionStrict,
+			b.PendingChangesDetection,
+		)
+	}
+	if b.MaxUpgradeDeferral != "" {
+		if duration, err := time.ParseDuration(b.MaxUpgradeDeferral); err != nil || duration < 0 {
+			return fmt.Errorf("broker.max_upgrade_deferral must be a positive duration, got '%s'", b.MaxUpgradeDeferral)
+		}
+	}
+	if b.HealthCheckInterval != "" {
+		if duration, err := time.ParseDuration(b.HealthCheckInterval); err != nil || duration <= 0 {
+			return fmt.Errorf("broker.health_check_interval must be a positive duration, got '%s'", b.HealthCheckInterval)
+		}
+	}
 
 	return nil
 }
 
 type ServiceDeployment struct {
-	Releases serviceadapter.ServiceReleases
-	Stemcell serviceadapter.Stemcell
+	Releases  serviceadapter.ServiceReleases
+	Stemcell  serviceadapter.Stemcell
+	Stemcells []Stemcell
+}
+
+// Stemcell is one of several stemcells a service deployment can use. Instance groups
+// refer to it by alias, e.g. to mix Linux and Windows VMs in one deployment.
+type Stemcell struct {
+	Alias   string `yaml:"alias"`
+	OS      string `yaml:"os"`
+	Version string `yaml:"version"`
 }
 
 func (s ServiceDeployment) Validate() error {
@@ -86,13 +156,53 @@ func (s ServiceDeployment) Validate() error {
 		}
 	}
 
-	if err := assertVersion(s.Stemcell.Version); err != nil {
-		return err
+	if len(s.Stemcells) == 0 {
+		return assertVersion(s.Stemcell.Version)
+	}
+
+	if s.Stemcell != (serviceadapter.Stemcell{}) {
+		return errors.New("service_deployment.stemcell and service_deployment.stemcells are mutually exclusive")
+	}
+
+	aliases := map[string]bool{}
+	for i, stemcell := range s.Stemcells {
+		if stemcell.Alias == "" || stemcell.OS == "" || stemcell.Version == "" {
+			return fmt.Errorf("service_deployment.stemcells[%d] must specify alias, os and version", i)
+		}
+		if aliases[stemcell.Alias] {
+			return fmt.Errorf("service_deployment.stemcells alias '%s' is used more than once", stemcell.Alias)
+		}
+		aliases[stemcell.Alias] = true
+
+		if err := assertVersion(stemcell.Version); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// AllStemcells returns the configured stemcells, treating the single-stemcell form
+// as one stemcell without an alias.
+func (s ServiceDeployment) AllStemcells() []Stemcell {
+	if len(s.Stemcells) > 0 {
+		return s.Stemcells
+	}
+	if s.Stemcell == (serviceadapter.Stemcell{}) {
+		return nil
+	}
+	return []Stemcell{{OS: s.Stemcell.OS, Version: s.Stemcell.Version}}
+}
+
+// DefaultStemcell is the stemcell passed to adapters that only understand a single
+// stemcell: the configured one, or the first of several.
+func (s ServiceDeployment) DefaultStemcell() serviceadapter.Stemcell {
+	if len(s.Stemcells) > 0 {
+		return serviceadapter.Stemcell{OS: s.Stemcells[0].OS, Version: s.Stemcells[0].Version}
+	}
+	return s.Stemcell
+}
+
 func assertVersion(version string) error {
 	if strings.HasSuffix(version, "latest") {
 		return errors.New("You must configure the exact release and stemcell versions in broker.service_deployment. ODB requires exact versions to detect pending changes as part of the 'cf update-service' workflow. For example, latest and 3112.latest are not supported.")
@@ -104,6 +214,42 @@ type Bosh struct {
 	URL            string
 	TrustedCert    string `yaml:"root_ca_cert"`
 	Authentication BOSHAuthentication
+	Teams          []BOSHTeam `yaml:"teams"`
+}
+
+// BOSHTeam is a director team that service deployments are created as. The team's
+// UAA client must have the bosh.teams.<name>.admin scope.
+type BOSHTeam struct {
+	Name   string   `yaml:"name"`
+	ID     string   `yaml:"client_id"`
+	Secret string   `yaml:"client_secret"`
+	Plans  []string `yaml:"plans"`
+	Orgs   []string `yaml:"orgs"`
+}
+
+// TeamFor returns the team that a deployment for the given plan and org should be
+// created as. Org mappings take precedence over plan mappings.
+func (b Bosh) TeamFor(planID, orgGUID string) string {
+	for _, team := range b.Teams {
+		if contains(team.Orgs, orgGUID) {
+			return team.Name
+		}
+	}
+	for _, team := range b.Teams {
+		if contains(team.Plans, planID) {
+			return team.Name
+		}
+	}
+	return ""
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
 
 type BOSHAuthentication struct {
@@ -177,7 +323,31 @@ func (b Bosh) Validate() error {
 	if b.URL == "" {
 		return fmt.Errorf("Must specify bosh url")
 	}
-	return b.Authentication.Validate()
+	if err := b.Authentication.Validate(); err != nil {
+		return err
+	}
+	if len(b.Teams) > 0 && !b.Authentication.UAA.IsSet() {
+		return fmt.Errorf("BOSH teams require UAA authentication for BOSH")
+	}
+	for _, team := range b.Teams {
+		if err := team.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t BOSHTeam) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("Must specify a name for each BOSH team")
+	}
+	if t.ID == "" || t.Secret == "" {
+		return fmt.Errorf("Must specify client_id and client_secret for BOSH team %s", t.Name)
+	}
+	if len(t.Plans) == 0 && len(t.Orgs) == 0 {
+		return fmt.Errorf("Must specify plans or orgs for BOSH team %s", t.Name)
+	}
+	return nil
 }
 
 func (cf CF) Validate() error {
@@ -240,6 +410,69 @@ func (a BOSHAuthentication) Validate() error {
 
 type ServiceAdapter struct {
 	Path string
+	// URL of a long-running adapter to call over HTTP instead of executing Path, either
+	// unix:///path/to/socket or http://localhost:<port>.
+	URL string `yaml:"url,omitempty"`
+	// Timeouts bounds how long each adapter subcommand may run for, keyed by subcommand
+	// or "default". Subcommands without a timeout are bounded by the built-in default of 10m.
+	Timeouts map[string]string `yaml:"timeouts,omitempty"`
+	// InputMode is how inputs are passed to the adapter, "argv" (default) or "stdin" for
+	// a JSON envelope. stdin is only used if the adapter reports supporting it.
+	InputMode string `yaml:"input_mode,omitempty"`
+}
+
+var (
+	adapterTimeoutKeys = []string{"default", "generate-manifest", "create-binding", "delete-binding", "dashboard-url", "capabilities", "validate-params", "instance-status"}
+	adapterInputModes  = []string{"argv", "stdin"}
+)
+
+func (s ServiceAdapter) Validate() error {
+	if s.URL != "" {
+		if s.Path != "" {
+			return errors.New("service_adapter.path and service_adapter.url are mutually exclusive")
+		}
+		if !isLocalAdapterURL(s.URL) {
+			return fmt.Errorf("service_adapter.url must be a unix socket or a localhost http URL, got '%s'", s.URL)
+		}
+	}
+
+	if s.InputMode != "" && !contains(adapterInputModes, s.InputMode) {
+		return fmt.Errorf("service_adapter.input_mode must be one of %s, got '%s'", strings.Join(adapterInputModes, ", "), s.InputMode)
+	}
+
+	for key, timeout := range s.Timeouts {
+		if !contains(adapterTimeoutKeys, key) {
+			return fmt.Errorf("service_adapter.timeouts.%s is not a service adapter subcommand, expected one of %s", key, strings.Join(adapterTimeoutKeys, ", "))
+		}
+		if duration, err := time.ParseDuration(timeout); err != nil || duration <= 0 {
+			return fmt.Errorf("service_adapter.timeouts.%s must be a positive duration, got '%s'", key, timeout)
+		}
+	}
+	return nil
+}
+
+func isLocalAdapterURL(adapterURL string) bool {
+	u, err := url.Parse(adapterURL)
+	if err != nil {
+		return false
+	}
+
+	switch u.Scheme {
+	case "unix":
+		return u.Host == "" && u.Path != ""
+	case "http":
+		return contains([]string{"localhost", "127.0.0.1", "::1"}, u.Hostname()) && u.Port() != ""
+	default:
+		return false
+	}
+}
+
+func (s ServiceAdapter) TimeoutDurations() map[string]time.Duration {
+	durations := map[string]time.Duration{}
+	for key, timeout := range s.Timeouts {
+		durations[key], _ = time.ParseDuration(timeout)
+	}
+	return durations
 }
 
 func Parse(configFilePath string) (Config, error) {
@@ -253,6 +486,14 @@ func Parse(configFilePath string) (Config, error) {
 		return Config{}, err
 	}
 
+	if err := config.mergeCredentialsFile(); err != nil {
+		return Config{}, err
+	}
+
+	if err := config.resolveSecrets(); err != nil {
+		return Config{}, err
+	}
+
 	if err := config.Validate(); err != nil {
 		return Config{}, err
 	}
@@ -308,6 +549,18 @@ func (s ServiceOffering) HasLifecycleErrands() bool {
 
 type Plans []Plan
 
+func (p Plans) Validate() error {
+	for _, plan := range p {
+		if plan.MaintenanceWindow == nil {
+			continue
+		}
+		if err := plan.MaintenanceWindow.Validate(); err != nil {
+			return fmt.Errorf("plan %s has an invalid maintenance_window: %s", plan.Name, err)
+		}
+	}
+	return nil
+}
+
 func (p Plans) FindByID(id string) (Plan, bool) {
 	for _, plan := range p {
 		if plan.ID == id {
@@ -318,17 +571,19 @@ func (p Plans) FindByID(id string) (Plan, bool) {
 }
 
 type Plan struct {
-	ID               string `yaml:"plan_id"`
-	Name             string
-	Free             *bool
-	Bindable         *bool
-	Description      string
-	Metadata         PlanMetadata
-	Quotas           Quotas `yaml:"quotas,omitempty"`
-	Properties       serviceadapter.Properties
-	InstanceGroups   []serviceadapter.InstanceGroup `yaml:"instance_groups,omitempty"`
-	Update           *serviceadapter.Update         `yaml:"update,omitempty"`
-	LifecycleErrands *LifecycleErrands              `yaml:"lifecycle_errands,omitempty"`
+	ID                string `yaml:"plan_id"`
+	Name              string
+	Free              *bool
+	Bindable          *bool
+	Description       string
+	Metadata          PlanMetadata
+	Quotas            Quotas `yaml:"quotas,omitempty"`
+	Properties        serviceadapter.Properties
+	InstanceGroups    []serviceadapter.InstanceGroup `yaml:"instance_groups,omitempty"`
+	Update            *serviceadapter.Update         `yaml:"update,omitempty"`
+	LifecycleErrands  *LifecycleErrands              `yaml:"lifecycle_errands,omitempty"`
+	BackupErrands     *BackupErrands                 `yaml:"backup_errands,omitempty"`
+	MaintenanceWindow *MaintenanceWindow             `yaml:"maintenance_window,omitempty"`
 }
 
 func (p Plan) AdapterPlan(globalProperties serviceadapter.Properties) serviceadapter.Plan {
@@ -371,6 +626,27 @@ type LifecycleErrands struct {
 	PreDelete  string `yaml:"pre_delete"`
 }
 
+func (p Plan) BackupErrand() string {
+	if p.BackupErrands == nil {
+		return ""
+	}
+
+	return p.BackupErrands.Backup
+}
+
+func (p Plan) RestoreErrand() string {
+	if p.BackupErrands == nil {
+		return ""
+	}
+
+	return p.BackupErrands.Restore
+}
+
+type BackupErrands struct {
+	Backup  string `yaml:"backup"`
+	Restore string `yaml:"restore"`
+}
+
 type PlanMetadata struct {
 	DisplayName string     `yaml:"display_name"`
 	Bullets     []string   `yaml:"bullets,omitempty"`
@@ -11,6 +11,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -126,6 +127,10 @@ var _ = Describe("Config", func() {
 								LifecycleErrands: &config.LifecycleErrands{
 									PostDeploy: "health-check",
 								},
+								BackupErrands: &config.BackupErrands{
+									Backup:  "backup-data",
+									Restore: "restore-data",
+								},
 								InstanceGroups: []serviceadapter.InstanceGroup{
 									{
 										Name:               "redis-server",
@@ -208,6 +213,194 @@ var _ = Describe("Config", func() {
 			})
 		})
 
+		Context("when BOSH teams are configured", func() {
+			BeforeEach(func() {
+				configFileName = "bosh_teams_config.yml"
+			})
+
+			It("returns a config object with the teams", func() {
+				Expect(parseErr).NotTo(HaveOccurred())
+				Expect(conf.Bosh.Teams).To(Equal([]config.BOSHTeam{
+					{Name: "plan-team", ID: "plan-team-client", Secret: "plan-team-secret", Plans: []string{"some-plan-id"}},
+					{Name: "org-team", ID: "org-team-client", Secret: "org-team-secret", Orgs: []string{"some-org-guid"}},
+				}))
+			})
+
+			It("maps orgs to teams ahead of plans", func() {
+				Expect(conf.Bosh.TeamFor("some-plan-id", "some-org-guid")).To(Equal("org-team"))
+				Expect(conf.Bosh.TeamFor("some-plan-id", "other-org-guid")).To(Equal("plan-team"))
+				Expect(conf.Bosh.TeamFor("other-plan-id", "other-org-guid")).To(BeEmpty())
+			})
+		})
+
+		Context("when a BOSH team has no plans or orgs", func() {
+			BeforeEach(func() {
+				configFileName = "bosh_team_without_mapping_config.yml"
+			})
+
+			It("returns an error", func() {
+				Expect(parseErr).To(MatchError("Must specify plans or orgs for BOSH team some-team"))
+			})
+		})
+
+		Context("when BOSH teams are configured with basic BOSH authentication", func() {
+			BeforeEach(func() {
+				configFileName = "bosh_teams_basic_auth_config.yml"
+			})
+
+			It("returns an error", func() {
+				Expect(parseErr).To(MatchError("BOSH teams require UAA authentication for BOSH"))
+			})
+		})
+
+		Context("when strict pending changes detection is configured", func() {
+			BeforeEach(func() {
+				configFileName = "strict_pending_changes_config.yml"
+			})
+
+			It("returns a config object with strict pending changes detection", func() {
+				Expect(parseErr).NotTo(HaveOccurred())
+				Expect(conf.Broker.StrictPendingChangesDetection()).To(BeTrue())
+			})
+		})
+
+		Context("when the configuration contains an unknown pending changes detection mode", func() {
+			BeforeEach(func() {
+				configFileName = "bad_pending_changes_config.yml"
+			})
+
+			It("returns an error", func() {
+				Expect(parseErr).To(MatchError("broker.pending_changes_detection must be one of 'manifest' or 'strict', got 'sometimes'"))
+			})
+		})
+
+		Context("when a max upgrade deferral is configured", func() {
+			BeforeEach(func() {
+				configFileName = "max_upgrade_deferral_config.yml"
+			})
+
+			It("returns a config object with the max upgrade deferral", func() {
+				Expect(parseErr).NotTo(HaveOccurred())
+				Expect(conf.Broker.MaxUpgradeDeferralDuration()).To(Equal(720 * time.Hour))
+			})
+		})
+
+		Context("when the configuration contains an invalid max upgrade deferral", func() {
+			BeforeEach(func() {
+				configFileName = "bad_max_upgrade_deferral_config.yml"
+			})
+
+			It("returns an error", func() {
+				Expect(parseErr).To(MatchError("broker.max_upgrade_deferral must be a positive duration, got 'a month'"))
+			})
+		})
+
+		Context("when a health check interval is configured", func() {
+			BeforeEach(func() {
+				configFileName = "health_check_interval_config.yml"
+			})
+
+			It("returns a config object with the health check interval", func() {
+				Expect(parseErr).NotTo(HaveOccurred())
+				Expect(conf.Broker.HealthCheckIntervalDuration()).To(Equal(5 * time.Minute))
+			})
+		})
+
+		Context("when the configuration contains an invalid health check interval", func() {
+			BeforeEach(func() {
+				configFileName = "bad_health_check_interval_config.yml"
+			})
+
+			It("returns an error", func() {
+				Expect(parseErr).To(MatchError("broker.health_check_interval must be a positive duration, got '-5m'"))
+			})
+		})
+
+		Context("when service adapter timeouts are configured", func() {
+			BeforeEach(func() {
+				configFileName = "service_adapter_timeouts_config.yml"
+			})
+
+			It("returns a config object with the timeouts", func() {
+				Expect(parseErr).NotTo(HaveOccurred())
+				Expect(conf.ServiceAdapter.TimeoutDurations()).To(Equal(map[string]time.Duration{
+					"default":           30 * time.Second,
+					"generate-manifest": 2 * time.Minute,
+				}))
+			})
+		})
+
+		Context("when the configuration contains an invalid service adapter timeout", func() {
+			BeforeEach(func() {
+				configFileName = "bad_service_adapter_timeout_config.yml"
+			})
+
+			It("returns an error", func() {
+				Expect(parseErr).To(MatchError("service_adapter.timeouts.generate-manifest must be a positive duration, got 'soon'"))
+			})
+		})
+
+		Context("when the configuration contains a timeout for an unknown service adapter subcommand", func() {
+			BeforeEach(func() {
+				configFileName = "unknown_service_adapter_timeout_config.yml"
+			})
+
+			It("returns an error", func() {
+				Expect(parseErr).To(MatchError(ContainSubstring("service_adapter.timeouts.generate-manifests is not a service adapter subcommand")))
+			})
+		})
+
+		Context("when the service adapter input mode is not supported", func() {
+			BeforeEach(func() {
+				configFileName = "bad_service_adapter_input_mode_config.yml"
+			})
+
+			It("returns an error", func() {
+				Expect(parseErr).To(MatchError("service_adapter.input_mode must be one of argv, stdin, got 'tempfile'"))
+			})
+		})
+
+		Context("when the service adapter is configured with a URL", func() {
+			BeforeEach(func() {
+				configFileName = "http_service_adapter_config.yml"
+			})
+
+			It("does not require an executable path", func() {
+				Expect(parseErr).NotTo(HaveOccurred())
+				Expect(conf.ServiceAdapter.URL).To(Equal("unix:///var/vcap/sys/run/adapter/adapter.sock"))
+			})
+		})
+
+		Context("when the service adapter is configured with both a path and a URL", func() {
+			BeforeEach(func() {
+				configFileName = "service_adapter_with_path_and_url.yml"
+			})
+
+			It("returns an error", func() {
+				Expect(parseErr).To(MatchError("service_adapter.path and service_adapter.url are mutually exclusive"))
+			})
+		})
+
+		Context("when the service adapter URL is not local", func() {
+			BeforeEach(func() {
+				configFileName = "remote_service_adapter_url_config.yml"
+			})
+
+			It("returns an error", func() {
+				Expect(parseErr).To(MatchError("service_adapter.url must be a unix socket or a localhost http URL, got 'http://adapter.example.com:8081'"))
+			})
+		})
+
+		Context("when a plan has an invalid maintenance window", func() {
+			BeforeEach(func() {
+				configFileName = "bad_maintenance_window_config.yml"
+			})
+
+			It("returns an error", func() {
+				Expect(parseErr).To(MatchError("plan some-dedicated-name has an invalid maintenance_window: unknown day 'someday', must be one of sun, mon, tue, wed, thu, fri or sat"))
+			})
+		})
+
 		Context("when the configuration contains a non-executable service adapter path", func() {
 			BeforeEach(func() {
 				configFileName = "config_with_non_executable_adapter_path.yml"
@@ -412,6 +605,54 @@ var _ = Describe("Config", func() {
 					Expect(parseErr).To(MatchError(ContainSubstring(latestFailureMessage)))
 				})
 			})
+
+			Context("when multiple aliased stemcells are configured", func() {
+				BeforeEach(func() {
+					configFileName = "multiple_stemcells_config.yml"
+				})
+
+				It("parses the stemcells", func() {
+					Expect(parseErr).NotTo(HaveOccurred())
+					Expect(conf.ServiceDeployment.Stemcells).To(Equal([]config.Stemcell{
+						{Alias: "linux", OS: "ubuntu-trusty", Version: "1234"},
+						{Alias: "windows", OS: "windows2012R2", Version: "1200.3"},
+					}))
+				})
+
+				It("defaults to the first stemcell for adapters expecting a single stemcell", func() {
+					Expect(conf.ServiceDeployment.DefaultStemcell()).To(Equal(serviceadapter.Stemcell{OS: "ubuntu-trusty", Version: "1234"}))
+				})
+			})
+
+			Context("when an aliased stemcell version is n.latest", func() {
+				BeforeEach(func() {
+					configFileName = "service_deployment_with_latest_aliased_stemcell.yml"
+				})
+
+				It("returns an error", func() {
+					Expect(parseErr).To(MatchError(ContainSubstring(latestFailureMessage)))
+				})
+			})
+
+			Context("when both stemcell and stemcells are configured", func() {
+				BeforeEach(func() {
+					configFileName = "service_deployment_with_both_stemcell_forms.yml"
+				})
+
+				It("returns an error", func() {
+					Expect(parseErr).To(MatchError(ContainSubstring("service_deployment.stemcell and service_deployment.stemcells are mutually exclusive")))
+				})
+			})
+
+			Context("when a stemcell alias is used more than once", func() {
+				BeforeEach(func() {
+					configFileName = "service_deployment_with_duplicate_stemcell_alias.yml"
+				})
+
+				It("returns an error", func() {
+					Expect(parseErr).To(MatchError(ContainSubstring("service_deployment.stemcells alias 'linux' is used more than once")))
+				})
+			})
 		})
 	})
 
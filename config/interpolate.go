@@ -0,0 +1,123 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const fileReferencePrefix = "file://"
+
+// envVarReference matches ${NAME}, and $${NAME} for a literal ${NAME}
+var envVarReference = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// mergeCredentialsFile decodes the credentials file, if the config names one, over the
+// config so that values it sets take precedence
+func (c *Config) mergeCredentialsFile() error {
+	if c.CredentialsFile == "" {
+		return nil
+	}
+
+	path, err := resolveEnvVars(c.CredentialsFile)
+	if err != nil {
+		return err
+	}
+
+	credentialsBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading credentials_file: %s", err)
+	}
+
+	if err := yaml.Unmarshal(credentialsBytes, c); err != nil {
+		return fmt.Errorf("error parsing credentials_file %s: %s", path, err)
+	}
+	return nil
+}
+
+// resolveSecrets resolves ${ENV_VAR} and file:// references in the credential and
+// certificate fields, so secrets need not be kept in the config file itself. Other fields,
+// such as plan properties passed to the adapter, are left as written.
+func (c *Config) resolveSecrets() error {
+	for _, field := range c.secretFields() {
+		resolved, err := resolveSecret(*field)
+		if err != nil {
+			return err
+		}
+		*field = resolved
+	}
+	return nil
+}
+
+func (c *Config) secretFields() []*string {
+	fields := []*string{
+		&c.Broker.Username,
+		&c.Broker.Password,
+		&c.Bosh.TrustedCert,
+		&c.Bosh.Authentication.Basic.Username,
+		&c.Bosh.Authentication.Basic.Password,
+		&c.Bosh.Authentication.UAA.ID,
+		&c.Bosh.Authentication.UAA.Secret,
+		&c.CF.TrustedCert,
+		&c.CF.Authentication.ClientCredentials.ID,
+		&c.CF.Authentication.ClientCredentials.Secret,
+		&c.CF.Authentication.UserCredentials.Username,
+		&c.CF.Authentication.UserCredentials.Password,
+	}
+	for i := range c.Bosh.Teams {
+		fields = append(fields, &c.Bosh.Teams[i].ID, &c.Bosh.Teams[i].Secret)
+	}
+	if c.ServiceCatalog.DashboardClient != nil {
+		fields = append(fields, &c.ServiceCatalog.DashboardClient.Secret)
+	}
+	return fields
+}
+
+func resolveSecret(value string) (string, error) {
+	resolved, err := resolveEnvVars(value)
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.HasPrefix(resolved, fileReferencePrefix) {
+		return resolved, nil
+	}
+
+	path := strings.TrimPrefix(resolved, fileReferencePrefix)
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading secret from %s: %s", resolved, err)
+	}
+	return strings.TrimRight(string(contents), "\r\n"), nil
+}
+
+func resolveEnvVars(value string) (string, error) {
+	var unsetVar string
+
+	resolved := envVarReference.ReplaceAllStringFunc(value, func(reference string) string {
+		if strings.HasPrefix(reference, "$$") {
+			return reference[1:]
+		}
+
+		name := envVarReference.FindStringSubmatch(reference)[1]
+		envValue, set := os.LookupEnv(name)
+		if !set && unsetVar == "" {
+			unsetVar = name
+		}
+		return envValue
+	})
+
+	if unsetVar != "" {
+		return "", fmt.Errorf("config references environment variable %s, which is not set", unsetVar)
+	}
+	return resolved, nil
+}
@@ -0,0 +1,113 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+)
+
+var _ = Describe("Config interpolation", func() {
+	var (
+		configFileName string
+		conf           config.Config
+		parseErr       error
+	)
+
+	BeforeEach(func() {
+		os.Setenv("BROKER_PASSWORD", "password-from-env")
+		os.Setenv("DASHBOARD_SECRET_SUFFIX", "from-env")
+		os.Setenv("BOSH_PASSWORD", "bosh-password-from-env")
+	})
+
+	AfterEach(func() {
+		os.Unsetenv("BROKER_PASSWORD")
+		os.Unsetenv("DASHBOARD_SECRET_SUFFIX")
+		os.Unsetenv("BOSH_PASSWORD")
+	})
+
+	JustBeforeEach(func() {
+		cwd, err := os.Getwd()
+		Expect(err).ToNot(HaveOccurred())
+		conf, parseErr = config.Parse(filepath.Join(cwd, "test_assets", configFileName))
+	})
+
+	Context("when the config references environment variables and files", func() {
+		BeforeEach(func() {
+			configFileName = "interpolated_config.yml"
+		})
+
+		It("resolves environment variable references", func() {
+			Expect(parseErr).NotTo(HaveOccurred())
+			Expect(conf.Broker.Password).To(Equal("password-from-env"))
+			Expect(conf.ServiceCatalog.DashboardClient.Secret).To(Equal("dashboard-from-env"))
+		})
+
+		It("resolves file references without the trailing newline", func() {
+			Expect(conf.CF.Authentication.UserCredentials.Password).To(Equal("cf-password-from-file"))
+		})
+
+		It("leaves escaped references as literals", func() {
+			Expect(conf.CF.Authentication.UserCredentials.Username).To(Equal("some-cf-${username}"))
+		})
+
+		It("leaves references outside credential fields as written", func() {
+			Expect(conf.ServiceCatalog.GlobalProperties).To(Equal(serviceadapter.Properties{
+				"global_foo":      "global_bar",
+				"global_template": "${NOT_INTERPOLATED}",
+			}))
+		})
+
+		It("preserves release and stemcell versions exactly", func() {
+			Expect(conf.ServiceDeployment.Releases[0].Version).To(Equal("1.0"))
+			Expect(conf.ServiceDeployment.Stemcell.Version).To(Equal("3312.10"))
+		})
+	})
+
+	Context("when the config names a credentials file", func() {
+		BeforeEach(func() {
+			configFileName = "config_with_credentials_file.yml"
+		})
+
+		It("merges in the credentials", func() {
+			Expect(parseErr).NotTo(HaveOccurred())
+			Expect(conf.Broker.Password).To(Equal("password-from-credentials"))
+			Expect(conf.Broker.Username).To(Equal("username"))
+			Expect(conf.ServiceCatalog.DashboardClient.Secret).To(Equal("dashboard-secret-from-credentials"))
+			Expect(conf.ServiceCatalog.DashboardClient.ID).To(Equal("client-id-1"))
+		})
+
+		It("resolves references in the credentials", func() {
+			Expect(conf.Bosh.Authentication.Basic.Password).To(Equal("bosh-password-from-env"))
+		})
+	})
+
+	Context("when the config references an unset environment variable", func() {
+		BeforeEach(func() {
+			configFileName = "config_with_unset_env_var.yml"
+		})
+
+		It("returns an error", func() {
+			Expect(parseErr).To(MatchError("config references environment variable UNSET_BROKER_PASSWORD, which is not set"))
+		})
+	})
+
+	Context("when the config references a file that does not exist", func() {
+		BeforeEach(func() {
+			configFileName = "config_with_missing_secret_file.yml"
+		})
+
+		It("returns an error", func() {
+			Expect(parseErr).To(MatchError(ContainSubstring("error reading secret from file://test_assets/missing_password")))
+		})
+	})
+})
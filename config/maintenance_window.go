@@ -0,0 +1,103 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaintenanceWindow is a recurring period, in UTC, during which an instance may be upgraded.
+// A window without days recurs daily.
+type MaintenanceWindow struct {
+	Days     []string `yaml:"days,omitempty" json:"days,omitempty"`
+	Start    string   `yaml:"start" json:"start"`
+	Duration string   `yaml:"duration" json:"duration"`
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+const maxMaintenanceWindowDuration = 24 * time.Hour
+
+func (w MaintenanceWindow) Validate() error {
+	for _, day := range w.Days {
+		if _, found := weekdays[strings.ToLower(day)]; !found {
+			return fmt.Errorf("unknown day '%s', must be one of sun, mon, tue, wed, thu, fri or sat", day)
+		}
+	}
+
+	if _, err := w.startTime(); err != nil {
+		return fmt.Errorf("start must be a UTC time of day in the form HH:MM, got '%s'", w.Start)
+	}
+
+	duration, err := time.ParseDuration(w.Duration)
+	if err != nil || duration <= 0 || duration > maxMaintenanceWindowDuration {
+		return fmt.Errorf("duration must be a positive duration of at most 24h, got '%s'", w.Duration)
+	}
+
+	return nil
+}
+
+// Contains reports whether t falls within an occurrence of the window. Windows may span midnight,
+// in which case they belong to the day they start on.
+func (w MaintenanceWindow) Contains(t time.Time) bool {
+	start, err := w.startTime()
+	if err != nil {
+		return false
+	}
+	duration, err := time.ParseDuration(w.Duration)
+	if err != nil {
+		return false
+	}
+
+	t = t.UTC()
+	for _, daysAgo := range []int{0, 1} {
+		day := t.AddDate(0, 0, -daysAgo)
+		if !w.recursOn(day.Weekday()) {
+			continue
+		}
+
+		opens := time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, time.UTC)
+		if !t.Before(opens) && t.Before(opens.Add(duration)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w MaintenanceWindow) startTime() (time.Time, error) {
+	return time.Parse("15:04", w.Start)
+}
+
+func (w MaintenanceWindow) recursOn(weekday time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, day := range w.Days {
+		if weekdays[strings.ToLower(day)] == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+func (w MaintenanceWindow) String() string {
+	days := "daily"
+	if len(w.Days) > 0 {
+		days = strings.Join(w.Days, ",")
+	}
+	return fmt.Sprintf("%s %s UTC for %s", days, w.Start, w.Duration)
+}
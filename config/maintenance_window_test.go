@@ -0,0 +1,55 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package config_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+)
+
+var _ = Describe("MaintenanceWindow", func() {
+	// 2017-06-03 is a Saturday
+	saturday := func(hour, minute int) time.Time {
+		return time.Date(2017, 6, 3, hour, minute, 0, 0, time.UTC)
+	}
+
+	DescribeTable("Contains",
+		func(window config.MaintenanceWindow, t time.Time, expected bool) {
+			Expect(window.Contains(t)).To(Equal(expected))
+		},
+		Entry("inside a daily window", config.MaintenanceWindow{Start: "02:00", Duration: "4h"}, saturday(3, 0), true),
+		Entry("at the opening of a window", config.MaintenanceWindow{Start: "02:00", Duration: "4h"}, saturday(2, 0), true),
+		Entry("at the close of a window", config.MaintenanceWindow{Start: "02:00", Duration: "4h"}, saturday(6, 0), false),
+		Entry("before a daily window", config.MaintenanceWindow{Start: "02:00", Duration: "4h"}, saturday(1, 59), false),
+		Entry("inside a window on a listed day", config.MaintenanceWindow{Days: []string{"sat", "sun"}, Start: "02:00", Duration: "4h"}, saturday(3, 0), true),
+		Entry("inside the hours of a window on an unlisted day", config.MaintenanceWindow{Days: []string{"mon"}, Start: "02:00", Duration: "4h"}, saturday(3, 0), false),
+		Entry("after midnight in a window opening the day before", config.MaintenanceWindow{Days: []string{"fri"}, Start: "22:00", Duration: "4h"}, saturday(1, 0), true),
+		Entry("after midnight in a window opening on an unlisted day", config.MaintenanceWindow{Days: []string{"sat"}, Start: "22:00", Duration: "4h"}, saturday(1, 0), false),
+		Entry("a time in another timezone", config.MaintenanceWindow{Start: "02:00", Duration: "1h"}, saturday(4, 30).In(time.FixedZone("UTC+2", 2*60*60)), false),
+		Entry("days in upper case", config.MaintenanceWindow{Days: []string{"SAT"}, Start: "02:00", Duration: "4h"}, saturday(3, 0), true),
+	)
+
+	DescribeTable("Validate",
+		func(window config.MaintenanceWindow, expectedErr string) {
+			err := window.Validate()
+			if expectedErr == "" {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(expectedErr))
+			}
+		},
+		Entry("a valid window", config.MaintenanceWindow{Days: []string{"sat"}, Start: "02:00", Duration: "4h"}, ""),
+		Entry("an unknown day", config.MaintenanceWindow{Days: []string{"caturday"}, Start: "02:00", Duration: "4h"}, "unknown day 'caturday', must be one of sun, mon, tue, wed, thu, fri or sat"),
+		Entry("a malformed start", config.MaintenanceWindow{Start: "2am", Duration: "4h"}, "start must be a UTC time of day in the form HH:MM, got '2am'"),
+		Entry("a malformed duration", config.MaintenanceWindow{Start: "02:00", Duration: "four hours"}, "duration must be a positive duration of at most 24h, got 'four hours'"),
+		Entry("a duration longer than a day", config.MaintenanceWindow{Start: "02:00", Duration: "25h"}, "duration must be a positive duration of at most 24h, got '25h'"),
+	)
+})
@@ -0,0 +1,66 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ServiceOfferingStore holds the service offering shared by the broker, the management API and
+// manifest generation, so that a config reload swaps it for all of them at once
+type ServiceOfferingStore struct {
+	lock     *sync.RWMutex
+	offering ServiceOffering
+}
+
+func NewServiceOfferingStore(offering ServiceOffering) *ServiceOfferingStore {
+	return &ServiceOfferingStore{lock: &sync.RWMutex{}, offering: offering}
+}
+
+func (s *ServiceOfferingStore) Load() ServiceOffering {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.offering
+}
+
+func (s *ServiceOfferingStore) Store(offering ServiceOffering) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.offering = offering
+}
+
+// CheckReloadable rejects a reloaded config that changes anything other than the service
+// catalog, or that changes the catalog's ID or name, as those are only read at startup
+func (c Config) CheckReloadable(reloaded Config) error {
+	var changed []string
+
+	sections := []struct {
+		name              string
+		current, reloaded interface{}
+	}{
+		{"broker", c.Broker, reloaded.Broker},
+		{"bosh", c.Bosh, reloaded.Bosh},
+		{"cf", c.CF, reloaded.CF},
+		{"service_adapter", c.ServiceAdapter, reloaded.ServiceAdapter},
+		{"service_deployment", c.ServiceDeployment, reloaded.ServiceDeployment},
+		{"service_catalog.id", c.ServiceCatalog.ID, reloaded.ServiceCatalog.ID},
+		{"service_catalog.service_name", c.ServiceCatalog.Name, reloaded.ServiceCatalog.Name},
+	}
+	for _, section := range sections {
+		if !reflect.DeepEqual(section.current, section.reloaded) {
+			changed = append(changed, section.name)
+		}
+	}
+
+	if len(changed) > 0 {
+		return fmt.Errorf("the following settings cannot be changed without restarting the broker: %s", strings.Join(changed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+)
+
+var _ = Describe("Reloading config", func() {
+	var current, reloaded config.Config
+
+	BeforeEach(func() {
+		cwd, err := os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		current, err = config.Parse(filepath.Join(cwd, "test_assets", "good_config.yml"))
+		Expect(err).NotTo(HaveOccurred())
+		reloaded, err = config.Parse(filepath.Join(cwd, "test_assets", "good_config.yml"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("accepts changes to plans, quotas and global properties", func() {
+		limit := 42
+		reloaded.ServiceCatalog.Plans[0].Description = "a new description"
+		reloaded.ServiceCatalog.Plans[0].Quotas.ServiceInstanceLimit = &limit
+		reloaded.ServiceCatalog.GlobalQuotas.ServiceInstanceLimit = &limit
+		reloaded.ServiceCatalog.GlobalProperties = serviceadapter.Properties{"new": "property"}
+
+		Expect(current.CheckReloadable(reloaded)).To(Succeed())
+	})
+
+	It("rejects changes to settings outside the service catalog", func() {
+		reloaded.Bosh.URL = "https://another-director:25555"
+		reloaded.Broker.Port = current.Broker.Port + 1
+
+		Expect(current.CheckReloadable(reloaded)).To(MatchError("the following settings cannot be changed without restarting the broker: broker, bosh"))
+	})
+
+	It("rejects changes to the service offering's ID and name", func() {
+		reloaded.ServiceCatalog.ID = "another-id"
+		reloaded.ServiceCatalog.Name = "another-name"
+
+		Expect(current.CheckReloadable(reloaded)).To(MatchError("the following settings cannot be changed without restarting the broker: service_catalog.id, service_catalog.service_name"))
+	})
+})
+
+var _ = Describe("ServiceOfferingStore", func() {
+	It("returns the most recently stored service offering", func() {
+		store := config.NewServiceOfferingStore(config.ServiceOffering{ID: "an-id", Description: "old"})
+		Expect(store.Load().Description).To(Equal("old"))
+
+		store.Store(config.ServiceOffering{ID: "an-id", Description: "new"})
+		Expect(store.Load().Description).To(Equal("new"))
+	})
+})
@@ -0,0 +1,115 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package backup_all_service_instances_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
+	"github.com/pivotal-cf/on-demand-service-broker/integration_tests/helpers"
+	"github.com/pivotal-cf/on-demand-service-broker/mockhttp"
+	"github.com/pivotal-cf/on-demand-service-broker/mockhttp/mockbroker"
+)
+
+var _ = Describe("running the tool to back up all service instances", func() {
+	const (
+		brokerUsername = "broker username"
+		brokerPassword = "broker password"
+	)
+
+	var (
+		odb         *mockhttp.Server
+		validParams []string
+	)
+
+	BeforeEach(func() {
+		odb = mockbroker.New()
+		odb.ExpectedBasicAuth(brokerUsername, brokerPassword)
+		validParams = []string{
+			"-brokerUsername", brokerUsername,
+			"-brokerPassword", brokerPassword,
+			"-brokerUrl", odb.URL,
+			"-pollingInterval", "1",
+		}
+	})
+
+	AfterEach(func() {
+		odb.VerifyMocks()
+		odb.Close()
+	})
+
+	Context("when all backups succeed", func() {
+		It("exits successfully with a summary", func() {
+			operationData := `{"BoshTaskID":1,"BoshContextID":"some-context","OperationType":"backup"}`
+			odb.VerifyAndMock(
+				mockbroker.ListInstances().RespondsOKWith(`[{"instance_id": "one"}, {"instance_id": "two"}]`),
+				mockbroker.BackupInstance("one").RespondsAcceptedWith(operationData),
+				mockbroker.LastOperation("one", operationData).RespondWithOperationInProgress(),
+				mockbroker.LastOperation("one", operationData).RespondWithOperationSucceeded(),
+				mockbroker.BackupInstance("two").RespondsUnprocessableEntityWith(`{"description": "plan small has no backup errand configured"}`),
+			)
+
+			runningTool := helpers.StartBinaryWithParams(binaryPath, validParams)
+
+			Eventually(runningTool, 5*time.Second).Should(gexec.Exit(0))
+			Expect(runningTool).To(gbytes.Say("Result: Service Instance one backup success"))
+			Expect(runningTool).To(gbytes.Say("Result: plan has no backup errand"))
+			Expect(runningTool).To(gbytes.Say("Number of successful backups: 1"))
+			Expect(runningTool).To(gbytes.Say("Number of instances without a backup errand: 1"))
+		})
+	})
+
+	Context("when a backup fails", func() {
+		It("backs up the other instances and exits non-zero", func() {
+			operationData := `{"BoshTaskID":1,"BoshContextID":"some-context","OperationType":"backup"}`
+			odb.VerifyAndMock(
+				mockbroker.ListInstances().RespondsOKWith(`[{"instance_id": "one"}, {"instance_id": "two"}]`),
+				mockbroker.BackupInstance("one").RespondsInternalServerErrorWith(`{"description": "errand error"}`),
+				mockbroker.BackupInstance("two").RespondsAcceptedWith(operationData),
+				mockbroker.LastOperation("two", operationData).RespondWithOperationSucceeded(),
+			)
+
+			runningTool := helpers.StartBinaryWithParams(binaryPath, validParams)
+
+			Eventually(runningTool, 5*time.Second).Should(gexec.Exit(1))
+			Expect(runningTool).To(gbytes.Say("Number of successful backups: 1"))
+			Expect(runningTool).To(gbytes.Say("backup failed for service instances: one"))
+		})
+	})
+
+	Context("when listing instances fails", func() {
+		It("exits non-zero with the error message", func() {
+			odb.VerifyAndMock(
+				mockbroker.ListInstances().RespondsUnauthorizedWith(""),
+			)
+
+			runningTool := helpers.StartBinaryWithParams(binaryPath, validParams)
+
+			Eventually(runningTool).Should(gexec.Exit(1))
+			Expect(runningTool).To(gbytes.Say("error listing service instances: HTTP response status: 401 Unauthorized"))
+		})
+	})
+
+	Context("when the tool is misconfigured", func() {
+		It("fails without broker credentials", func() {
+			runningTool := helpers.StartBinaryWithParams(binaryPath, []string{"-brokerUrl", odb.URL, "-pollingInterval", "1"})
+
+			Eventually(runningTool).Should(gexec.Exit(1))
+			Expect(runningTool).To(gbytes.Say("the brokerUsername, brokerPassword and brokerUrl are required to function"))
+		})
+
+		It("fails with pollingInterval of zero", func() {
+			runningTool := helpers.StartBinaryWithParams(binaryPath, []string{"-brokerUsername", brokerUsername, "-brokerPassword", brokerPassword, "-brokerUrl", odb.URL, "-pollingInterval", "0"})
+
+			Eventually(runningTool).Should(gexec.Exit(1))
+			Expect(runningTool).To(gbytes.Say("the pollingInterval must be greater than zero"))
+		})
+	})
+})
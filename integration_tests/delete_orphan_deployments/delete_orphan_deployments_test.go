@@ -0,0 +1,99 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package delete_orphan_deployments_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
+	"github.com/pivotal-cf/on-demand-service-broker/integration_tests/helpers"
+	"github.com/pivotal-cf/on-demand-service-broker/mockhttp"
+	"github.com/pivotal-cf/on-demand-service-broker/mockhttp/mockbroker"
+)
+
+const (
+	brokerUsername = "broker-username"
+	brokerPassword = "broker-password"
+)
+
+var _ = Describe("Delete Orphan Deployments", func() {
+	var (
+		odb    *mockhttp.Server
+		params []string
+	)
+
+	BeforeEach(func() {
+		odb = mockbroker.New()
+		odb.ExpectedBasicAuth(brokerUsername, brokerPassword)
+		params = []string{
+			"-brokerUsername", brokerUsername,
+			"-brokerPassword", brokerPassword,
+			"-brokerUrl", odb.URL,
+		}
+	})
+
+	AfterEach(func() {
+		odb.VerifyMocks()
+		odb.Close()
+	})
+
+	It("succeeds with an empty report when there are no orphan deployments", func() {
+		odb.AppendMocks(mockbroker.OrphanDeployments().RespondsOKWith("[]"))
+
+		session := helpers.StartBinaryWithParams(binaryPath, params)
+
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(string(session.Out.Contents())).To(Equal("[]"))
+	})
+
+	It("deletes each orphan deployment and reports the results", func() {
+		odb.AppendMocks(
+			mockbroker.OrphanDeployments().RespondsOKWith(`[{"deployment_name":"service-instance_one"},{"deployment_name":"service-instance_two"}]`),
+			mockbroker.DeleteOrphanDeployment("service-instance_one", "dry_run=false&min_age=24h0m0s&run_pre_delete_errand=true").
+				RespondsOKWith(`{"deployment_name":"service-instance_one","deleted":true,"dry_run":false,"delete_task_id":1}`),
+			mockbroker.DeleteOrphanDeployment("service-instance_two", "dry_run=false&min_age=24h0m0s&run_pre_delete_errand=true").
+				RespondsOKWith(`{"deployment_name":"service-instance_two","deleted":false,"dry_run":false,"skipped_reason":"too young"}`),
+		)
+
+		session := helpers.StartBinaryWithParams(binaryPath, append(params, "-minAge", "24h", "-runPreDeleteErrand"))
+
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(session.Out.Contents()).To(MatchJSON(`[
+			{"deployment_name":"service-instance_one","deleted":true,"dry_run":false,"delete_task_id":1},
+			{"deployment_name":"service-instance_two","deleted":false,"dry_run":false,"skipped_reason":"too young"}
+		]`))
+	})
+
+	It("fails after attempting every deployment when a deletion fails", func() {
+		odb.AppendMocks(
+			mockbroker.OrphanDeployments().RespondsOKWith(`[{"deployment_name":"service-instance_one"},{"deployment_name":"service-instance_two"}]`),
+			mockbroker.DeleteOrphanDeployment("service-instance_one", "dry_run=true&min_age=0s&run_pre_delete_errand=false").
+				RespondsConflictWith(`{"description":"service instance one exists in Cloud Foundry"}`),
+			mockbroker.DeleteOrphanDeployment("service-instance_two", "dry_run=true&min_age=0s&run_pre_delete_errand=false").
+				RespondsOKWith(`{"deployment_name":"service-instance_two","deleted":false,"dry_run":true}`),
+		)
+
+		session := helpers.StartBinaryWithParams(binaryPath, append(params, "-dryRun"))
+
+		Eventually(session).Should(gexec.Exit(1))
+		Expect(session.Out.Contents()).To(MatchJSON(`[
+			{"deployment_name":"service-instance_one","deleted":false,"dry_run":false,"error":"unexpected status code: 409. description: service instance one exists in Cloud Foundry"},
+			{"deployment_name":"service-instance_two","deleted":false,"dry_run":true}
+		]`))
+		Expect(session.Err).To(gbytes.Say("failed to delete 1 of 2 orphan deployments"))
+	})
+
+	It("fails when orphan deployments cannot be listed", func() {
+		odb.AppendMocks(mockbroker.OrphanDeployments().RespondsInternalServerErrorWith("error message"))
+
+		session := helpers.StartBinaryWithParams(binaryPath, params)
+
+		Eventually(session).Should(gexec.Exit(1))
+		Expect(session.Err).To(gbytes.Say("error retrieving orphan deployments"))
+	})
+})
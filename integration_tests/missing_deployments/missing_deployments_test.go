@@ -0,0 +1,94 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package missing_deployments_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
+	"github.com/pivotal-cf/on-demand-service-broker/integration_tests/helpers"
+	"github.com/pivotal-cf/on-demand-service-broker/mockhttp"
+	"github.com/pivotal-cf/on-demand-service-broker/mockhttp/mockbroker"
+)
+
+const (
+	brokerUsername = "broker-username"
+	brokerPassword = "broker-password"
+
+	listOfMissingDeployments = `[
+		{"service_instance_id":"one","deployment_name":"service-instance_one","plan_id":"plan","organization_guid":"org","space_guid":"space"},
+		{"service_instance_id":"two","deployment_name":"service-instance_two","plan_id":"plan","organization_guid":"org","space_guid":"space"}
+	]`
+)
+
+var _ = Describe("Missing Deployments", func() {
+	var (
+		odb    *mockhttp.Server
+		params []string
+	)
+
+	BeforeEach(func() {
+		odb = mockbroker.New()
+		odb.ExpectedBasicAuth(brokerUsername, brokerPassword)
+		params = []string{
+			"-brokerUsername", brokerUsername,
+			"-brokerPassword", brokerPassword,
+			"-brokerUrl", odb.URL,
+		}
+	})
+
+	AfterEach(func() {
+		odb.VerifyMocks()
+		odb.Close()
+	})
+
+	It("succeeds when no missing deployments are detected", func() {
+		odb.AppendMocks(mockbroker.MissingDeployments().RespondsOKWith("[]"))
+
+		session := helpers.StartBinaryWithParams(binaryPath, params)
+
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(string(session.Out.Contents())).To(Equal("[]"))
+	})
+
+	It("fails with exit code 10 when missing deployments are detected", func() {
+		odb.AppendMocks(mockbroker.MissingDeployments().RespondsOKWith(listOfMissingDeployments))
+
+		session := helpers.StartBinaryWithParams(binaryPath, params)
+
+		Eventually(session).Should(gexec.Exit(10))
+		Expect(session.Out.Contents()).To(MatchJSON(listOfMissingDeployments))
+		Expect(session.Err).To(gbytes.Say("Service instances detected in Cloud Foundry with no corresponding BOSH deployment"))
+	})
+
+	It("re-creates each missing deployment and reports the results", func() {
+		odb.AppendMocks(
+			mockbroker.MissingDeployments().RespondsOKWith(listOfMissingDeployments),
+			mockbroker.RecreateMissingDeployment("one").RespondsAcceptedWith(`{"BoshTaskID":11,"OperationType":"create"}`),
+			mockbroker.RecreateMissingDeployment("two").RespondsInternalServerErrorWith(`{"description":"adapter error"}`),
+		)
+
+		session := helpers.StartBinaryWithParams(binaryPath, append(params, "-recreate"))
+
+		Eventually(session).Should(gexec.Exit(1))
+		Expect(session.Out.Contents()).To(MatchJSON(`[
+			{"service_instance_id":"one","deployment_name":"service-instance_one","plan_id":"plan","organization_guid":"org","space_guid":"space","bosh_task_id":11},
+			{"service_instance_id":"two","deployment_name":"service-instance_two","plan_id":"plan","organization_guid":"org","space_guid":"space","error":"unexpected status code: 500. description: adapter error"}
+		]`))
+		Expect(session.Err).To(gbytes.Say("failed to re-create 1 of 2 missing deployments"))
+	})
+
+	It("fails when missing deployments cannot be listed", func() {
+		odb.AppendMocks(mockbroker.MissingDeployments().RespondsInternalServerErrorWith("error message"))
+
+		session := helpers.StartBinaryWithParams(binaryPath, params)
+
+		Eventually(session).Should(gexec.Exit(1))
+		Expect(session.Err).To(gbytes.Say("error retrieving missing deployments"))
+	})
+})
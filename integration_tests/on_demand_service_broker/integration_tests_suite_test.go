@@ -25,6 +25,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gexec"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/integration_tests/on_demand_service_broker/mock"
@@ -182,10 +183,30 @@ func startBrokerWithPassingStartupChecks(
 	)
 	boshDirector.VerifyAndMock(
 		mockbosh.Info().RespondsWithSufficientVersionForLifecycleErrands(),
+		mockReleasesUploaded(conf),
+		mockStemcellsUploaded(conf),
 	)
 	return startBroker(conf)
 }
 
+func mockReleasesUploaded(conf config.Config) *mockhttp.Handler {
+	releases := boshdirector.Releases{}
+	for _, release := range conf.ServiceDeployment.Releases {
+		releases = append(releases, boshdirector.Release{
+			Name:            release.Name,
+			ReleaseVersions: []boshdirector.ReleaseVersion{{Version: release.Version}},
+		})
+	}
+	return mockbosh.Releases().RespondsOKWithJSON(releases)
+}
+
+func mockStemcellsUploaded(conf config.Config) *mockhttp.Handler {
+	stemcell := conf.ServiceDeployment.Stemcell
+	return mockbosh.Stemcells().RespondsOKWithJSON(boshdirector.Stemcells{
+		{Name: "bosh-stemcell", OperatingSystem: stemcell.OS, Version: stemcell.Version},
+	})
+}
+
 func startBroker(conf config.Config) *gexec.Session {
 	session := startBrokerWithoutPortCheck(conf)
 	Eventually(dialBroker).Should(BeTrue())
@@ -24,6 +24,7 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/mockhttp/mockbosh"
 	"github.com/pivotal-cf/on-demand-service-broker/mockhttp/mockcfapi"
 	"github.com/pivotal-cf/on-demand-service-broker/mockuaa"
+	"github.com/pivotal-cf/on-demand-service-broker/task"
 )
 
 var _ = Describe("last operation", func() {
@@ -752,6 +753,9 @@ var _ = Describe("last operation", func() {
 						RespondsOKWithJSON(boshdirector.BoshTasks{anotherTaskDone, taskDone}),
 					mockbosh.TaskOutput(anotherTaskDone.ID).RespondsOKWith(""),
 					mockbosh.TaskOutput(taskDone.ID).RespondsOKWith(""),
+					mockbosh.DeleteConfig(task.PreviousManifestConfigType, deploymentName(instanceID)).RespondsNoContent(),
+					mockbosh.DeleteConfig(task.MaintenanceWindowConfigType, deploymentName(instanceID)).RespondsNoContent(),
+					mockbosh.DeleteConfig(task.UpgradeDeferredSinceConfigType, deploymentName(instanceID)).RespondsNoContent(),
 				)
 			})
 
@@ -1056,6 +1060,27 @@ var _ = Describe("last operation", func() {
 				Eventually(runningBroker).Should(gbytes.Say(regexpString))
 			})
 		})
+
+		Context("when the task is done", func() {
+			BeforeEach(func() {
+				boshDirector.VerifyAndMock(
+					mockbosh.Task(boshTaskID).RespondsWithTaskContainingState(boshdirector.TaskDone),
+					mockbosh.DeleteConfig(task.PreviousManifestConfigType, deploymentName(instanceID)).RespondsNoContent(),
+					mockbosh.DeleteConfig(task.MaintenanceWindowConfigType, deploymentName(instanceID)).RespondsNoContent(),
+					mockbosh.DeleteConfig(task.UpgradeDeferredSinceConfigType, deploymentName(instanceID)).RespondsNoContent(),
+				)
+			})
+
+			It("deletes the configs kept for the deployment", func() {
+				Expect(lastOperationResponse.StatusCode).To(Equal(http.StatusOK))
+				Expect(rawResponse).To(MatchJSON(toJSONString(
+					map[string]interface{}{
+						"state":       brokerapi.Succeeded,
+						"description": "Instance deletion completed",
+					},
+				)))
+			})
+		})
 	})
 
 	Context("when updating", func() {
@@ -24,6 +24,7 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/mockhttp/mockbosh"
 	"github.com/pivotal-cf/on-demand-service-broker/mockhttp/mockcfapi"
 	"github.com/pivotal-cf/on-demand-service-broker/mockuaa"
+	"github.com/pivotal-cf/on-demand-service-broker/task"
 	"github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
 )
 
@@ -115,7 +116,9 @@ var _ = Describe("Management API", func() {
 				)
 
 				boshDirector.VerifyAndMock(
-					mockbosh.Deployments().RespondsOKWith(`[{"name":"service-instance_123abc"}]`),
+					mockbosh.Deployments().RespondsOKWith(`[{"name":"service-instance_123abc"},{"name":"service-instance_other-broker"}]`),
+					mockbosh.GetDeployment("service-instance_123abc").RespondsWithRawManifest([]byte("name: service-instance_123abc")),
+					mockbosh.GetDeployment("service-instance_other-broker").RespondsWithRawManifest([]byte("tags:\n  odb-service-offering-id: another-service-id")),
 				)
 
 				orphanRequest, err := http.NewRequest("GET", fmt.Sprintf("http://localhost:%d/mgmt/orphan_deployments", brokerPort), nil)
@@ -123,7 +126,7 @@ var _ = Describe("Management API", func() {
 
 				orphanResponse := responseFrom(basicAuthBrokerRequest(orphanRequest), http.StatusOK)
 
-				By("responding with a JSON list of one orphan deployment")
+				By("responding with a JSON list of the orphan deployment not owned by another broker")
 				defer orphanResponse.Body.Close()
 				Expect(ioutil.ReadAll(orphanResponse.Body)).To(MatchJSON(`[{"deployment_name": "service-instance_123abc"}]`))
 			})
@@ -151,6 +154,7 @@ var _ = Describe("Management API", func() {
 
 					boshDirector.VerifyAndMock(
 						mockbosh.Deployments().RespondsOKWith(`[{"name":"service-instance_123abc"},{"name":"service-instance_one"},{"name":"service-instance_two"}]`),
+						mockbosh.GetDeployment("service-instance_123abc").RespondsWithRawManifest([]byte("name: service-instance_123abc")),
 					)
 
 					orphanRequest, err := http.NewRequest("GET", fmt.Sprintf("http://localhost:%d/mgmt/orphan_deployments", brokerPort), nil)
@@ -407,8 +411,10 @@ var _ = Describe("Management API", func() {
 				)
 
 				boshDirector.VerifyAndMock(
+					mockbosh.GetDeployment("service-instance_instance-id").RespondsWithRawManifest([]byte(rawManifestWithDeploymentName(instanceID))),
 					mockbosh.Tasks("service-instance_instance-id").RespondsWithNoTasks(),
 					mockbosh.GetDeployment("service-instance_instance-id").RespondsWithRawManifest([]byte(rawManifestWithDeploymentName(instanceID))),
+					mockbosh.UpdateConfig(task.PreviousManifestConfigType, "service-instance_instance-id", []byte(rawManifestWithDeploymentName(instanceID))).RespondsCreated(),
 					mockbosh.Deploy().RedirectsToTask(upgradingTaskID),
 				)
 
@@ -449,6 +455,7 @@ var _ = Describe("Management API", func() {
 				)
 
 				boshDirector.VerifyAndMock(
+					mockbosh.GetDeployment("service-instance_instance-id").RespondsNotFoundWith("{}"),
 					mockbosh.Tasks("service-instance_instance-id").RespondsWithNoTasks(), // TODO: Check this is the response for a deleted deployment
 					mockbosh.GetDeployment("service-instance_instance-id").RespondsNotFoundWith("{}"),
 				)
@@ -482,6 +489,7 @@ var _ = Describe("Management API", func() {
 				)
 
 				boshDirector.VerifyAndMock(
+					mockbosh.GetDeployment("service-instance_instance-id").RespondsWithRawManifest([]byte(rawManifestWithDeploymentName(instanceID))),
 					mockbosh.Tasks("service-instance_instance-id").RespondsWithATaskContainingState("processing", ""),
 				)
 
@@ -515,6 +523,71 @@ var _ = Describe("Management API", func() {
 			})
 		})
 	})
+
+	Describe("rollback instance", func() {
+		const (
+			rollbackTaskID = 124
+			planGUID       = "my-plan"
+		)
+
+		previousManifest := []byte(`name: service-instance_instance-id
+releases:
+- name: some-release
+  version: "1"
+stemcells:
+- alias: trusty
+  os: ubuntu-trusty
+  version: "3312"
+`)
+
+		It("redeploys the manifest retained by the last upgrade", func() {
+			cfAPI.VerifyAndMock(
+				mockcfapi.GetServiceInstance(instanceID).RespondsWithPlanURL(planGUID, mockcfapi.Update, mockcfapi.Succeeded),
+				mockcfapi.GetServicePlan(planGUID).RespondsOKWith(getServicePlanResponse(dedicatedPlanID)),
+			)
+
+			boshDirector.VerifyAndMock(
+				mockbosh.Tasks("service-instance_instance-id").RespondsWithNoTasks(),
+				mockbosh.GetDeployment("service-instance_instance-id").RespondsWithRawManifest([]byte(rawManifestWithDeploymentName(instanceID))),
+				mockbosh.Config(task.PreviousManifestConfigType, "service-instance_instance-id").RespondsWithContent(previousManifest),
+				mockbosh.Releases().RespondsOKWith(`[{"name": "some-release", "release_versions": [{"version": "1"}]}]`),
+				mockbosh.Stemcells().RespondsOKWith(`[{"name": "some-stemcell", "operating_system": "ubuntu-trusty", "version": "3312"}]`),
+				mockbosh.Deploy().WithRawManifest(previousManifest).RedirectsToTask(rollbackTaskID),
+			)
+
+			rollbackReq, err := http.NewRequest("POST", fmt.Sprintf("http://localhost:%d/mgmt/service_instances/%s/rollback", brokerPort, instanceID), nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			rollbackResp := responseFrom(basicAuthBrokerRequest(rollbackReq), http.StatusAccepted)
+
+			Expect(decodeOperationDataFromResponseBody(rollbackResp.Body)).To(Equal(broker.OperationData{
+				OperationType: broker.OperationTypeRollback,
+				BoshTaskID:    rollbackTaskID,
+			}))
+		})
+
+		It("refuses when a release of the previous manifest is no longer uploaded", func() {
+			cfAPI.VerifyAndMock(
+				mockcfapi.GetServiceInstance(instanceID).RespondsWithPlanURL(planGUID, mockcfapi.Update, mockcfapi.Succeeded),
+				mockcfapi.GetServicePlan(planGUID).RespondsOKWith(getServicePlanResponse(dedicatedPlanID)),
+			)
+
+			boshDirector.VerifyAndMock(
+				mockbosh.Tasks("service-instance_instance-id").RespondsWithNoTasks(),
+				mockbosh.GetDeployment("service-instance_instance-id").RespondsWithRawManifest([]byte(rawManifestWithDeploymentName(instanceID))),
+				mockbosh.Config(task.PreviousManifestConfigType, "service-instance_instance-id").RespondsWithContent(previousManifest),
+				mockbosh.Releases().RespondsOKWith(`[{"name": "some-release", "release_versions": [{"version": "2"}]}]`),
+				mockbosh.Stemcells().RespondsOKWith(`[{"name": "some-stemcell", "operating_system": "ubuntu-trusty", "version": "3312"}]`),
+			)
+
+			rollbackReq, err := http.NewRequest("POST", fmt.Sprintf("http://localhost:%d/mgmt/service_instances/%s/rollback", brokerPort, instanceID), nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			rollbackResp := responseFrom(basicAuthBrokerRequest(rollbackReq), http.StatusUnprocessableEntity)
+
+			Expect(ioutil.ReadAll(rollbackResp.Body)).To(ContainSubstring("release some-release/1"))
+		})
+	})
 })
 
 func responseFrom(req *http.Request, expectedStatusCode int) *http.Response {
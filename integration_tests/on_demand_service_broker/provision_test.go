@@ -20,6 +20,7 @@ import (
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/mockhttp"
+	"github.com/pivotal-cf/on-demand-service-broker/mockhttp/mockadapter"
 	"github.com/pivotal-cf/on-demand-service-broker/mockhttp/mockbosh"
 	"github.com/pivotal-cf/on-demand-service-broker/mockhttp/mockcfapi"
 	"github.com/pivotal-cf/on-demand-service-broker/mockuaa"
@@ -377,6 +378,75 @@ var _ = Describe("provision service instance", func() {
 		})
 	})
 
+	Context("when the service adapter is served over HTTP", func() {
+		var httpAdapter *mockhttp.Server
+
+		BeforeEach(func() {
+			planID = highMemoryPlanID
+			httpAdapter = mockadapter.New()
+			conf.ServiceAdapter = config.ServiceAdapter{URL: httpAdapter.URL}
+			httpAdapter.VerifyAndMock(
+				mockadapter.Capabilities().Succeeds(`{"subcommands": ["generate-manifest", "create-binding", "delete-binding"]}`),
+			)
+			runningBroker = startBrokerWithPassingStartupChecks(conf, cfAPI, boshDirector)
+
+			httpAdapter.AppendMocks(
+				mockadapter.GenerateManifest().Succeeds(string(toYaml(manifestForFirstDeployment))),
+			)
+			boshDirector.VerifyAndMock(
+				mockbosh.GetDeployment(deploymentName(instanceID)).RespondsNotFoundWith(""),
+				mockbosh.Tasks(deploymentName(instanceID)).RespondsWithNoTasks(),
+				mockbosh.Deploy().WithManifest(manifestForFirstDeployment).WithoutContextID().RedirectsToTask(taskID),
+			)
+
+			provisionResponse = provisionInstance(instanceID, planID, arbitraryParams)
+		})
+
+		AfterEach(func() {
+			httpAdapter.VerifyMocks()
+			httpAdapter.Close()
+		})
+
+		It("responds with 202", func() {
+			Expect(provisionResponse.StatusCode).To(Equal(http.StatusAccepted))
+		})
+	})
+
+	Context("when a service adapter served over HTTP rejects the params", func() {
+		var httpAdapter *mockhttp.Server
+
+		BeforeEach(func() {
+			planID = highMemoryPlanID
+			httpAdapter = mockadapter.New()
+			conf.ServiceAdapter = config.ServiceAdapter{URL: httpAdapter.URL}
+			httpAdapter.VerifyAndMock(
+				mockadapter.Capabilities().Succeeds(`{"subcommands": ["generate-manifest", "create-binding", "delete-binding", "validate-params"]}`),
+			)
+			runningBroker = startBrokerWithPassingStartupChecks(conf, cfAPI, boshDirector)
+
+			httpAdapter.AppendMocks(
+				mockadapter.ValidateParams().RespondsWith(`{"error": {"user_message": "size must be small or large"}}`, "", serviceadapter.ErrorExitCode),
+			)
+			boshDirector.VerifyAndMock(
+				mockbosh.GetDeployment(deploymentName(instanceID)).RespondsNotFoundWith(""),
+			)
+
+			provisionResponse = provisionInstance(instanceID, planID, map[string]interface{}{"size": "medium"})
+		})
+
+		AfterEach(func() {
+			httpAdapter.VerifyMocks()
+			httpAdapter.Close()
+		})
+
+		It("responds with 400 and the adapter's message", func() {
+			Expect(provisionResponse.StatusCode).To(Equal(http.StatusBadRequest))
+			body, err := ioutil.ReadAll(provisionResponse.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(body).To(MatchJSON(`{"description": "size must be small or large"}`))
+		})
+	})
+
 	Context("when the plan has a post-deploy errand", func() {
 		BeforeEach(func() {
 			planID = "post-deploy-errand-id"
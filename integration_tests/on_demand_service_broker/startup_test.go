@@ -7,7 +7,9 @@
 package integration_tests
 
 import (
+	"fmt"
 	"os/exec"
+	"regexp"
 
 	"time"
 
@@ -130,6 +132,8 @@ var _ = Describe("Startup", func() {
 				)
 				boshDirector.VerifyAndMock(
 					mockbosh.Info().RespondsWithSufficientStemcellVersionForODB(),
+					mockReleasesUploaded(conf),
+					mockStemcellsUploaded(conf),
 				)
 
 				runningBroker = startBroker(conf)
@@ -174,6 +178,8 @@ var _ = Describe("Startup", func() {
 				)
 				boshDirector.VerifyAndMock(
 					mockbosh.Info().RespondsWithSufficientStemcellVersionForODB(),
+					mockReleasesUploaded(conf),
+					mockStemcellsUploaded(conf),
 				)
 
 				runningBroker = startBroker(conf)
@@ -245,6 +251,8 @@ var _ = Describe("Startup", func() {
 				)
 				boshDirector.VerifyAndMock(
 					mockbosh.Info().RespondsWithSufficientStemcellVersionForODB(),
+					mockReleasesUploaded(conf),
+					mockStemcellsUploaded(conf),
 				)
 				conf.ServiceCatalog.Plans = []config.Plan{}
 
@@ -289,6 +297,28 @@ var _ = Describe("Startup", func() {
 				Expect(runningBroker.ExitCode()).ToNot(Equal(0))
 			})
 		})
+
+		Context("when a service release is not uploaded to the director", func() {
+			It("fails to start", func() {
+				cfAPI.VerifyAndMock(
+					mockcfapi.GetInfo().RespondsWithSufficientAPIVersion(),
+				)
+				boshDirector.VerifyAndMock(
+					mockbosh.Info().RespondsWithSufficientStemcellVersionForODB(),
+					mockbosh.Releases().RespondsOKWith(`[]`),
+					mockStemcellsUploaded(conf),
+				)
+
+				runningBroker = startBrokerWithoutPortCheck(conf)
+
+				Eventually(runningBroker.Out).Should(gbytes.Say(regexp.QuoteMeta(fmt.Sprintf(
+					"error starting broker: BOSH Director error: service_deployment references release %s version %s, which must be uploaded to the director",
+					serviceReleaseName, serviceReleaseVersion,
+				))))
+				Eventually(runningBroker).Should(gexec.Exit())
+				Expect(runningBroker.ExitCode()).ToNot(Equal(0))
+			})
+		})
 	})
 
 	Describe("BOSH Director API version", func() {
@@ -332,6 +362,10 @@ var _ = Describe("Startup", func() {
 
 			Context("and no lifecycle errands configured", func() {
 				BeforeEach(func() {
+					boshDirector.AppendMocks(
+						mockReleasesUploaded(conf),
+						mockStemcellsUploaded(conf),
+					)
 					cfAPI.VerifyAndMock(
 						mockcfapi.GetInfo().RespondsWithSufficientAPIVersion(),
 						mockcfapi.ListServiceOfferings().RespondsWithNoServiceOfferings(),
@@ -388,6 +422,8 @@ var _ = Describe("Startup", func() {
 				BeforeEach(func() {
 					boshDirector.VerifyAndMock(
 						mockbosh.Info().RespondsWithSufficientSemverVersionForODB(),
+						mockReleasesUploaded(conf),
+						mockStemcellsUploaded(conf),
 					)
 					cfAPI.VerifyAndMock(
 						mockcfapi.GetInfo().RespondsWithSufficientAPIVersion(),
@@ -445,6 +481,8 @@ var _ = Describe("Startup", func() {
 			BeforeEach(func() {
 				boshDirector.VerifyAndMock(
 					mockbosh.Info().RespondsWithSufficientVersionForLifecycleErrands(),
+					mockReleasesUploaded(conf),
+					mockStemcellsUploaded(conf),
 				)
 				cfAPI.VerifyAndMock(
 					mockcfapi.GetInfo().RespondsWithSufficientAPIVersion(),
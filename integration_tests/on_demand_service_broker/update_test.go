@@ -426,8 +426,8 @@ var _ = Describe("updating a service instance", func() {
 			var generatedManifest = bosh.BoshManifest{
 				Name: deploymentName(instanceID),
 				Releases: []bosh.Release{{
-					Name:    "foo",
-					Version: "1.0",
+					Name:    serviceReleaseName,
+					Version: serviceReleaseVersion,
 				}},
 				Stemcells:      []bosh.Stemcell{},
 				InstanceGroups: []bosh.InstanceGroup{},
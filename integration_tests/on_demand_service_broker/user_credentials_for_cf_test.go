@@ -56,6 +56,10 @@ var _ = Describe("UAA user credentials for CF", func() {
 				Password: cfPassword,
 			},
 		}
+		boshDirector.AppendMocks(
+			mockReleasesUploaded(conf),
+			mockStemcellsUploaded(conf),
+		)
 
 		runningBroker = startBroker(conf)
 	})
@@ -8,6 +8,7 @@ package upgrade_all_service_instances_test
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo"
@@ -52,6 +53,7 @@ var _ = Describe("running the tool to upgrade all service instances", func() {
 			instanceID := "service-instance-id"
 			odb.VerifyAndMock(
 				mockbroker.ListInstances().RespondsOKWith(fmt.Sprintf(`[{"instance_id": "%s"}]`, instanceID)),
+				mockbroker.MaintenanceWindow(instanceID).RespondsOKWith(`{"maintenance_window": null}`),
 				mockbroker.UpgradeInstance(instanceID).RespondsAcceptedWith(operationData),
 				mockbroker.LastOperation(instanceID, operationData).RespondWithOperationInProgress(),
 				mockbroker.LastOperation(instanceID, operationData).RespondWithOperationSucceeded(),
@@ -65,6 +67,27 @@ var _ = Describe("running the tool to upgrade all service instances", func() {
 		})
 	})
 
+	Context("when a service instance is outside its maintenance window", func() {
+		It("defers the instance to the next run", func() {
+			operationData := `{"BoshTaskID":1,"OperationType":"upgrade"}`
+			closedDay := strings.ToLower(time.Now().UTC().AddDate(0, 0, 3).Weekday().String()[:3])
+			odb.VerifyAndMock(
+				mockbroker.ListInstances().RespondsOKWith(`[{"instance_id": "deferred-instance"}, {"instance_id": "unrestricted-instance"}]`),
+				mockbroker.MaintenanceWindow("deferred-instance").RespondsOKWith(fmt.Sprintf(`{"maintenance_window": {"days": ["%s"], "start": "12:00", "duration": "1h"}}`, closedDay)),
+				mockbroker.MaintenanceWindow("unrestricted-instance").RespondsOKWith(`{"maintenance_window": null}`),
+				mockbroker.UpgradeInstance("unrestricted-instance").RespondsAcceptedWith(operationData),
+				mockbroker.LastOperation("unrestricted-instance", operationData).RespondWithOperationSucceeded(),
+			)
+
+			runningTool := helpers.StartBinaryWithParams(binaryPath, validParams)
+
+			Eventually(runningTool, 5*time.Second).Should(gexec.Exit(0))
+			Expect(runningTool).To(gbytes.Say("Result: deferred, outside maintenance window"))
+			Expect(runningTool).To(gbytes.Say("Number of successful upgrades: 1"))
+			Expect(runningTool).To(gbytes.Say("Number of deferred instances: 1"))
+		})
+	})
+
 	Context("when the upgrade errors", func() {
 		It("exits non-zero with the error message", func() {
 			odb.VerifyAndMock(
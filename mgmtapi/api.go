@@ -13,21 +13,26 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/pborman/uuid"
 	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/brokercontext"
 	"github.com/pivotal-cf/on-demand-service-broker/cf"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 	"github.com/pivotal-cf/on-demand-service-broker/task"
 )
 
 type api struct {
 	manageableBroker ManageableBroker
-	serviceOffering  config.ServiceOffering
+	configReloader   ConfigReloader
+	serviceOffering  *config.ServiceOfferingStore
 	loggerFactory    *loggerfactory.LoggerFactory
 }
 
@@ -35,8 +40,28 @@ type api struct {
 type ManageableBroker interface {
 	Instances(logger *log.Logger) ([]string, error)
 	OrphanDeployments(logger *log.Logger) ([]string, error)
-	Upgrade(ctx context.Context, instanceID string, logger *log.Logger) (broker.OperationData, error)
+	DeleteOrphanDeployment(deploymentName string, options broker.OrphanDeletionOptions, logger *log.Logger) (broker.OrphanDeletionResult, error)
+	MissingDeployments(logger *log.Logger) ([]broker.MissingDeployment, error)
+	RecreateMissingDeployment(ctx context.Context, instanceID string, logger *log.Logger) (broker.OperationData, error)
+	Upgrade(ctx context.Context, instanceID string, options broker.UpgradeOptions, logger *log.Logger) (broker.OperationData, error)
+	Rollback(ctx context.Context, instanceID string, logger *log.Logger) (broker.OperationData, error)
 	CountInstancesOfPlans(logger *log.Logger) (map[string]int, error)
+	ChangeInstanceState(ctx context.Context, instanceID string, operationType broker.OperationType, logger *log.Logger) (broker.OperationData, error)
+	InstanceTasks(instanceID string, logger *log.Logger) (boshdirector.BoshTasks, error)
+	InstanceTask(instanceID string, taskID int, logger *log.Logger) (boshdirector.BoshTask, error)
+	StreamTaskOutput(taskID int, outputType string, writer io.Writer, logger *log.Logger) error
+	Backup(ctx context.Context, instanceID string, logger *log.Logger) (broker.OperationData, error)
+	Restore(ctx context.Context, instanceID string, logger *log.Logger) (broker.OperationData, error)
+	Backups(instanceID string, logger *log.Logger) ([]broker.Backup, error)
+	MaintenanceWindow(instanceID string, logger *log.Logger) (*config.MaintenanceWindow, error)
+	Health() error
+	AdapterCapabilities(logger *log.Logger) (serviceadapter.Capabilities, error)
+	InstanceStatus(ctx context.Context, instanceID string, logger *log.Logger) (serviceadapter.InstanceStatus, error)
+}
+
+//go:generate counterfeiter -o fakes/fake_config_reloader.go . ConfigReloader
+type ConfigReloader interface {
+	Reload(logger *log.Logger) error
 }
 
 type Instance struct {
@@ -47,18 +72,76 @@ type Deployment struct {
 	Name string `json:"deployment_name"`
 }
 
+type MissingDeployment struct {
+	InstanceID       string `json:"service_instance_id"`
+	DeploymentName   string `json:"deployment_name"`
+	PlanID           string `json:"plan_id"`
+	PlanName         string `json:"plan_name,omitempty"`
+	OrganizationGUID string `json:"organization_guid"`
+	SpaceGUID        string `json:"space_guid"`
+}
+
+type Task struct {
+	ID          int    `json:"id"`
+	State       string `json:"state"`
+	Description string `json:"description"`
+	Result      string `json:"result"`
+	ContextID   string `json:"context_id,omitempty"`
+}
+
+type Backup struct {
+	TaskID    int    `json:"bosh_task_id"`
+	State     string `json:"state"`
+	Timestamp int64  `json:"timestamp"`
+	ContextID string `json:"context_id,omitempty"`
+}
+
+type InstanceMaintenanceWindow struct {
+	MaintenanceWindow *config.MaintenanceWindow `json:"maintenance_window"`
+}
+
+type Health struct {
+	Healthy     bool   `json:"healthy"`
+	Description string `json:"description,omitempty"`
+}
+
+type ServiceAdapter struct {
+	Subcommands []string `json:"subcommands"`
+	SDKVersion  string   `json:"sdk_version"`
+	Features    []string `json:"features"`
+	InputModes  []string `json:"input_modes"`
+}
+
 type Metric struct {
 	Key   string  `json:"key"`
 	Value float64 `json:"value"`
 	Unit  string  `json:"unit"`
 }
 
-func AttachRoutes(r *mux.Router, manageableBroker ManageableBroker, serviceOffering config.ServiceOffering, loggerFactory *loggerfactory.LoggerFactory) {
-	a := &api{manageableBroker: manageableBroker, serviceOffering: serviceOffering, loggerFactory: loggerFactory}
+func AttachRoutes(r *mux.Router, manageableBroker ManageableBroker, configReloader ConfigReloader, serviceOffering *config.ServiceOfferingStore, loggerFactory *loggerfactory.LoggerFactory) {
+	a := &api{manageableBroker: manageableBroker, configReloader: configReloader, serviceOffering: serviceOffering, loggerFactory: loggerFactory}
 	r.HandleFunc("/mgmt/service_instances", a.listAllInstances).Methods("GET")
 	r.HandleFunc("/mgmt/service_instances/{instance_id}", a.upgradeInstance).Methods("PATCH")
+	r.HandleFunc("/mgmt/service_instances/{instance_id}/rollback", a.rollbackInstance).Methods("POST")
+	r.HandleFunc("/mgmt/service_instances/{instance_id}/recreate", a.changeInstanceState(broker.OperationTypeRecreate)).Methods("POST")
+	r.HandleFunc("/mgmt/service_instances/{instance_id}/restart", a.changeInstanceState(broker.OperationTypeRestart)).Methods("POST")
+	r.HandleFunc("/mgmt/service_instances/{instance_id}/stop", a.changeInstanceState(broker.OperationTypeStop)).Methods("POST")
+	r.HandleFunc("/mgmt/service_instances/{instance_id}/start", a.changeInstanceState(broker.OperationTypeStart)).Methods("POST")
+	r.HandleFunc("/mgmt/service_instances/{instance_id}/backups", a.runBackupErrand(broker.OperationTypeBackup)).Methods("POST")
+	r.HandleFunc("/mgmt/service_instances/{instance_id}/backups", a.listBackups).Methods("GET")
+	r.HandleFunc("/mgmt/service_instances/{instance_id}/restore", a.runBackupErrand(broker.OperationTypeRestore)).Methods("POST")
+	r.HandleFunc("/mgmt/service_instances/{instance_id}/maintenance_window", a.getMaintenanceWindow).Methods("GET")
+	r.HandleFunc("/mgmt/service_instances/{instance_id}/status", a.getInstanceStatus).Methods("GET")
+	r.HandleFunc("/mgmt/service_instances/{instance_id}/tasks", a.listInstanceTasks).Methods("GET")
+	r.HandleFunc("/mgmt/service_instances/{instance_id}/tasks/{task_id}/output", a.streamInstanceTaskOutput).Methods("GET")
 	r.HandleFunc("/mgmt/metrics", a.metrics).Methods("GET")
+	r.HandleFunc("/mgmt/health", a.health).Methods("GET")
+	r.HandleFunc("/mgmt/service_adapter", a.serviceAdapter).Methods("GET")
+	r.HandleFunc("/mgmt/reload_config", a.reloadConfig).Methods("POST")
 	r.HandleFunc("/mgmt/orphan_deployments", a.listOrphanDeployments).Methods("GET")
+	r.HandleFunc("/mgmt/orphan_deployments/{deployment_name}", a.deleteOrphanDeployment).Methods("DELETE")
+	r.HandleFunc("/mgmt/missing_deployments", a.listMissingDeployments).Methods("GET")
+	r.HandleFunc("/mgmt/missing_deployments/{instance_id}", a.recreateMissingDeployment).Methods("POST")
 }
 
 func (a *api) listOrphanDeployments(w http.ResponseWriter, r *http.Request) {
@@ -79,6 +162,114 @@ func (a *api) listOrphanDeployments(w http.ResponseWriter, r *http.Request) {
 	a.writeJson(w, orphanDeployments, logger)
 }
 
+func (a *api) deleteOrphanDeployment(w http.ResponseWriter, r *http.Request) {
+	deploymentName := mux.Vars(r)["deployment_name"]
+	logger := a.loggerFactory.NewWithRequestID()
+
+	options, err := orphanDeletionOptionsFrom(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		a.writeJson(w, brokerapi.ErrorResponse{Description: err.Error()}, logger)
+		return
+	}
+
+	result, err := a.manageableBroker.DeleteOrphanDeployment(deploymentName, options, logger)
+
+	switch err.(type) {
+	case nil:
+		a.writeJson(w, result, logger)
+	case broker.DeploymentNotFoundError:
+		w.WriteHeader(http.StatusNotFound)
+	case broker.NotAnOrphanError, broker.OperationInProgressError:
+		w.WriteHeader(http.StatusConflict)
+		a.writeJson(w, brokerapi.ErrorResponse{Description: err.Error()}, logger)
+	case error:
+		logger.Printf("error occurred deleting orphan deployment %s: %s", deploymentName, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		a.writeJson(w, brokerapi.ErrorResponse{Description: err.Error()}, logger)
+	}
+}
+
+func orphanDeletionOptionsFrom(r *http.Request) (broker.OrphanDeletionOptions, error) {
+	query := r.URL.Query()
+	options := broker.OrphanDeletionOptions{PlanID: query.Get("plan_id")}
+
+	var err error
+	if value := query.Get("dry_run"); value != "" {
+		if options.DryRun, err = strconv.ParseBool(value); err != nil {
+			return options, fmt.Errorf("invalid dry_run %s, must be true or false", value)
+		}
+	}
+	if value := query.Get("run_pre_delete_errand"); value != "" {
+		if options.RunPreDeleteErrand, err = strconv.ParseBool(value); err != nil {
+			return options, fmt.Errorf("invalid run_pre_delete_errand %s, must be true or false", value)
+		}
+	}
+	if value := query.Get("min_age"); value != "" {
+		if options.MinAge, err = time.ParseDuration(value); err != nil {
+			return options, fmt.Errorf("invalid min_age %s, must be a duration such as 24h", value)
+		}
+	}
+
+	return options, nil
+}
+
+func (a *api) listMissingDeployments(w http.ResponseWriter, r *http.Request) {
+	logger := a.loggerFactory.NewWithRequestID()
+
+	missing, err := a.manageableBroker.MissingDeployments(logger)
+	if err != nil {
+		logger.Printf("error occurred querying missing deployments: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	missingDeployments := []MissingDeployment{}
+	for _, deployment := range missing {
+		planName := ""
+		if plan, err := a.getPlan(deployment.PlanID); err == nil {
+			planName = plan.Name
+		}
+
+		missingDeployments = append(missingDeployments, MissingDeployment{
+			InstanceID:       deployment.InstanceID,
+			DeploymentName:   deployment.DeploymentName,
+			PlanID:           deployment.PlanID,
+			PlanName:         planName,
+			OrganizationGUID: deployment.OrganizationGUID,
+			SpaceGUID:        deployment.SpaceGUID,
+		})
+	}
+
+	a.writeJson(w, missingDeployments, logger)
+}
+
+func (a *api) recreateMissingDeployment(w http.ResponseWriter, r *http.Request) {
+	instanceID := mux.Vars(r)["instance_id"]
+
+	requestID := uuid.New()
+	ctx := brokercontext.New(r.Context(), string(broker.OperationTypeCreate), requestID, a.serviceOffering.Load().Name, instanceID)
+
+	logger := a.loggerFactory.NewWithContext(ctx)
+
+	operationData, err := a.manageableBroker.RecreateMissingDeployment(ctx, instanceID, logger)
+
+	switch err.(type) {
+	case nil:
+		w.WriteHeader(http.StatusAccepted)
+		a.writeJson(w, operationData, logger)
+	case broker.DeploymentNotMissingError:
+		w.WriteHeader(http.StatusNotFound)
+		a.writeJson(w, brokerapi.ErrorResponse{Description: err.Error()}, logger)
+	case broker.OperationInProgressError:
+		w.WriteHeader(http.StatusConflict)
+	case error:
+		logger.Printf("error occurred recreating missing deployment of instance %s: %s", instanceID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		a.writeJson(w, brokerapi.ErrorResponse{Description: err.Error()}, logger)
+	}
+}
+
 func (a *api) listAllInstances(w http.ResponseWriter, r *http.Request) {
 	logger := a.loggerFactory.NewWithRequestID()
 
@@ -102,11 +293,18 @@ func (a *api) upgradeInstance(w http.ResponseWriter, r *http.Request) {
 	instanceID := vars["instance_id"]
 
 	requestID := uuid.New()
-	ctx := brokercontext.New(r.Context(), string(broker.OperationTypeUpgrade), requestID, a.serviceOffering.Name, instanceID)
+	ctx := brokercontext.New(r.Context(), string(broker.OperationTypeUpgrade), requestID, a.serviceOffering.Load().Name, instanceID)
 
 	logger := a.loggerFactory.NewWithContext(ctx)
 
-	operationData, err := a.manageableBroker.Upgrade(ctx, instanceID, logger)
+	options, err := upgradeOptionsFrom(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		a.writeJson(w, brokerapi.ErrorResponse{Description: err.Error()}, logger)
+		return
+	}
+
+	operationData, err := a.manageableBroker.Upgrade(ctx, instanceID, options, logger)
 
 	switch err.(type) {
 	case nil:
@@ -118,6 +316,9 @@ func (a *api) upgradeInstance(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusGone)
 	case broker.OperationInProgressError:
 		w.WriteHeader(http.StatusConflict)
+	case broker.UpgradeDeferredError:
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		a.writeJson(w, brokerapi.ErrorResponse{Description: err.Error()}, logger)
 	case error:
 		logger.Printf("error occurred upgrading instance %s: %s", instanceID, err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -125,20 +326,340 @@ func (a *api) upgradeInstance(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func upgradeOptionsFrom(r *http.Request) (broker.UpgradeOptions, error) {
+	var options broker.UpgradeOptions
+
+	var err error
+	if value := r.URL.Query().Get("override_upgrade_policy"); value != "" {
+		if options.OverrideUpgradePolicy, err = strconv.ParseBool(value); err != nil {
+			return options, fmt.Errorf("invalid override_upgrade_policy %s, must be true or false", value)
+		}
+	}
+
+	return options, nil
+}
+
+func (a *api) rollbackInstance(w http.ResponseWriter, r *http.Request) {
+	instanceID := mux.Vars(r)["instance_id"]
+
+	requestID := uuid.New()
+	ctx := brokercontext.New(r.Context(), string(broker.OperationTypeRollback), requestID, a.serviceOffering.Load().Name, instanceID)
+
+	logger := a.loggerFactory.NewWithContext(ctx)
+
+	operationData, err := a.manageableBroker.Rollback(ctx, instanceID, logger)
+
+	switch err.(type) {
+	case nil:
+		w.WriteHeader(http.StatusAccepted)
+		a.writeJson(w, operationData, logger)
+	case cf.ResourceNotFoundError:
+		w.WriteHeader(http.StatusNotFound)
+	case task.DeploymentNotFoundError:
+		w.WriteHeader(http.StatusGone)
+	case broker.OperationInProgressError:
+		w.WriteHeader(http.StatusConflict)
+	case task.NoPreviousManifestError, task.MissingDependenciesError:
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		a.writeJson(w, brokerapi.ErrorResponse{Description: err.Error()}, logger)
+	case error:
+		logger.Printf("error occurred rolling back instance %s: %s", instanceID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		a.writeJson(w, brokerapi.ErrorResponse{Description: err.Error()}, logger)
+	}
+}
+
+func (a *api) changeInstanceState(operationType broker.OperationType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		instanceID := vars["instance_id"]
+
+		requestID := uuid.New()
+		ctx := brokercontext.New(r.Context(), string(operationType), requestID, a.serviceOffering.Load().Name, instanceID)
+
+		logger := a.loggerFactory.NewWithContext(ctx)
+
+		operationData, err := a.manageableBroker.ChangeInstanceState(ctx, instanceID, operationType, logger)
+
+		switch err.(type) {
+		case nil:
+			w.WriteHeader(http.StatusAccepted)
+			a.writeJson(w, operationData, logger)
+		case cf.ResourceNotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		case task.DeploymentNotFoundError:
+			w.WriteHeader(http.StatusGone)
+		case broker.OperationInProgressError:
+			w.WriteHeader(http.StatusConflict)
+		case error:
+			logger.Printf("error occurred performing %s on instance %s: %s", operationType, instanceID, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			a.writeJson(w, brokerapi.ErrorResponse{Description: err.Error()}, logger)
+		}
+	}
+}
+
+func (a *api) runBackupErrand(operationType broker.OperationType) http.HandlerFunc {
+	run := a.manageableBroker.Backup
+	if operationType == broker.OperationTypeRestore {
+		run = a.manageableBroker.Restore
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		instanceID := mux.Vars(r)["instance_id"]
+
+		requestID := uuid.New()
+		ctx := brokercontext.New(r.Context(), string(operationType), requestID, a.serviceOffering.Load().Name, instanceID)
+
+		logger := a.loggerFactory.NewWithContext(ctx)
+
+		operationData, err := run(ctx, instanceID, logger)
+
+		switch err.(type) {
+		case nil:
+			w.WriteHeader(http.StatusAccepted)
+			a.writeJson(w, operationData, logger)
+		case cf.ResourceNotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		case task.DeploymentNotFoundError:
+			w.WriteHeader(http.StatusGone)
+		case broker.OperationInProgressError:
+			w.WriteHeader(http.StatusConflict)
+		case broker.BackupNotConfiguredError:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			a.writeJson(w, brokerapi.ErrorResponse{Description: err.Error()}, logger)
+		case error:
+			logger.Printf("error occurred performing %s on instance %s: %s", operationType, instanceID, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			a.writeJson(w, brokerapi.ErrorResponse{Description: err.Error()}, logger)
+		}
+	}
+}
+
+func (a *api) listBackups(w http.ResponseWriter, r *http.Request) {
+	instanceID := mux.Vars(r)["instance_id"]
+	logger := a.loggerFactory.NewWithRequestID()
+
+	brokerBackups, err := a.manageableBroker.Backups(instanceID, logger)
+
+	switch err.(type) {
+	case nil:
+	case cf.ResourceNotFoundError:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	case broker.BackupNotConfiguredError:
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		a.writeJson(w, brokerapi.ErrorResponse{Description: err.Error()}, logger)
+		return
+	case error:
+		logger.Printf("error occurred querying backups of instance %s: %s", instanceID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	backups := []Backup{}
+	for _, backup := range brokerBackups {
+		backups = append(backups, Backup{
+			TaskID:    backup.TaskID,
+			State:     backup.State,
+			Timestamp: backup.Timestamp,
+			ContextID: backup.ContextID,
+		})
+	}
+
+	a.writeJson(w, backups, logger)
+}
+
+func (a *api) getMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	instanceID := mux.Vars(r)["instance_id"]
+	logger := a.loggerFactory.NewWithRequestID()
+
+	window, err := a.manageableBroker.MaintenanceWindow(instanceID, logger)
+
+	switch err.(type) {
+	case nil:
+	case cf.ResourceNotFoundError:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	case error:
+		logger.Printf("error occurred querying maintenance window of instance %s: %s", instanceID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	a.writeJson(w, InstanceMaintenanceWindow{MaintenanceWindow: window}, logger)
+}
+
+func (a *api) getInstanceStatus(w http.ResponseWriter, r *http.Request) {
+	instanceID := mux.Vars(r)["instance_id"]
+	logger := a.loggerFactory.NewWithRequestID()
+
+	status, err := a.manageableBroker.InstanceStatus(r.Context(), instanceID, logger)
+
+	switch err.(type) {
+	case nil:
+	case task.DeploymentNotFoundError:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	case serviceadapter.NotImplementedError:
+		w.WriteHeader(http.StatusNotImplemented)
+		a.writeJson(w, brokerapi.ErrorResponse{Description: err.Error()}, logger)
+		return
+	case error:
+		logger.Printf("error occurred querying status of instance %s: %s", instanceID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	a.writeJson(w, status, logger)
+}
+
+func (a *api) listInstanceTasks(w http.ResponseWriter, r *http.Request) {
+	instanceID := mux.Vars(r)["instance_id"]
+	logger := a.loggerFactory.NewWithRequestID()
+
+	boshTasks, err := a.manageableBroker.InstanceTasks(instanceID, logger)
+	if err != nil {
+		logger.Printf("error occurred querying tasks of instance %s: %s", instanceID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	tasks := []Task{}
+	for _, boshTask := range boshTasks {
+		tasks = append(tasks, Task{
+			ID:          boshTask.ID,
+			State:       boshTask.State,
+			Description: boshTask.Description,
+			Result:      boshTask.Result,
+			ContextID:   boshTask.ContextID,
+		})
+	}
+
+	a.writeJson(w, tasks, logger)
+}
+
+var taskOutputTypes = map[string]bool{
+	boshdirector.TaskOutputTypeEvent:  true,
+	boshdirector.TaskOutputTypeResult: true,
+	boshdirector.TaskOutputTypeDebug:  true,
+}
+
+func (a *api) streamInstanceTaskOutput(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	instanceID := vars["instance_id"]
+	logger := a.loggerFactory.NewWithRequestID()
+
+	taskID, err := strconv.Atoi(vars["task_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		a.writeJson(w, brokerapi.ErrorResponse{Description: fmt.Sprintf("invalid task ID %s", vars["task_id"])}, logger)
+		return
+	}
+
+	outputType := r.URL.Query().Get("type")
+	if outputType == "" {
+		outputType = boshdirector.TaskOutputTypeEvent
+	}
+	if !taskOutputTypes[outputType] {
+		w.WriteHeader(http.StatusBadRequest)
+		a.writeJson(w, brokerapi.ErrorResponse{Description: fmt.Sprintf("invalid output type %s, must be one of event, result or debug", outputType)}, logger)
+		return
+	}
+
+	_, err = a.manageableBroker.InstanceTask(instanceID, taskID, logger)
+	switch err.(type) {
+	case nil:
+	case broker.TaskNotFoundError:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	default:
+		logger.Printf("error occurred getting task %d of instance %s: %s", taskID, instanceID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// the status is sent before the output, so failures while streaming can only be reported in the body
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+
+	if err := a.manageableBroker.StreamTaskOutput(taskID, outputType, flushingWriter{w}, logger); err != nil {
+		logger.Printf("error occurred streaming output of task %d for instance %s: %s", taskID, instanceID, err)
+		fmt.Fprintf(w, "\nerror streaming output of task %d, the output is incomplete\n", taskID)
+	}
+}
+
+// flushingWriter sends task output to the client as it arrives from the director
+type flushingWriter struct {
+	http.ResponseWriter
+}
+
+func (f flushingWriter) Write(p []byte) (int, error) {
+	n, err := f.ResponseWriter.Write(p)
+	if flusher, ok := f.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
+
+func (a *api) health(w http.ResponseWriter, r *http.Request) {
+	logger := a.loggerFactory.NewWithRequestID()
+
+	if err := a.manageableBroker.Health(); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		a.writeJson(w, Health{Healthy: false, Description: err.Error()}, logger)
+		return
+	}
+
+	a.writeJson(w, Health{Healthy: true}, logger)
+}
+
+func (a *api) reloadConfig(w http.ResponseWriter, r *http.Request) {
+	logger := a.loggerFactory.NewWithRequestID()
+
+	if err := a.configReloader.Reload(logger); err != nil {
+		logger.Printf("error reloading config: %s", err)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		a.writeJson(w, brokerapi.ErrorResponse{Description: err.Error()}, logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *api) serviceAdapter(w http.ResponseWriter, r *http.Request) {
+	logger := a.loggerFactory.NewWithRequestID()
+
+	capabilities, err := a.manageableBroker.AdapterCapabilities(logger)
+	if err != nil {
+		logger.Printf("error getting service adapter capabilities: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	a.writeJson(w, ServiceAdapter{
+		Subcommands: capabilities.Subcommands,
+		SDKVersion:  capabilities.SDKVersion,
+		Features:    capabilities.Features,
+		InputModes:  capabilities.InputModes,
+	}, logger)
+}
+
 func (a *api) metrics(w http.ResponseWriter, r *http.Request) {
 	logger := a.loggerFactory.NewWithRequestID()
+	serviceOffering := a.serviceOffering.Load()
 
 	brokerMetrics := []Metric{}
 	instanceCountsByPlan, err := a.manageableBroker.CountInstancesOfPlans(logger)
 
 	if err != nil {
-		logger.Printf("error getting instance count for service offering %s: %s", a.serviceOffering.Name, err)
+		logger.Printf("error getting instance count for service offering %s: %s", serviceOffering.Name, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	if len(instanceCountsByPlan) == 0 {
-		logger.Printf("service %s not registered with Cloud Foundry", a.serviceOffering.Name)
+		logger.Printf("service %s not registered with Cloud Foundry", serviceOffering.Name)
 		w.WriteHeader(http.StatusServiceUnavailable)
 		return
 	}
@@ -154,7 +675,7 @@ func (a *api) metrics(w http.ResponseWriter, r *http.Request) {
 		}
 
 		countMetric := Metric{
-			Key:   fmt.Sprintf("/on-demand-broker/%s/%s/total_instances", a.serviceOffering.Name, plan.Name),
+			Key:   fmt.Sprintf("/on-demand-broker/%s/%s/total_instances", serviceOffering.Name, plan.Name),
 			Unit:  "count",
 			Value: float64(instanceCount),
 		}
@@ -163,7 +684,7 @@ func (a *api) metrics(w http.ResponseWriter, r *http.Request) {
 		if plan.Quotas.ServiceInstanceLimit != nil {
 			limit := *plan.Quotas.ServiceInstanceLimit
 			quotaMetric := Metric{
-				Key:   fmt.Sprintf("/on-demand-broker/%s/%s/quota_remaining", a.serviceOffering.Name, plan.Name),
+				Key:   fmt.Sprintf("/on-demand-broker/%s/%s/quota_remaining", serviceOffering.Name, plan.Name),
 				Unit:  "count",
 				Value: float64(limit - instanceCount),
 			}
@@ -174,16 +695,16 @@ func (a *api) metrics(w http.ResponseWriter, r *http.Request) {
 	}
 
 	totalCountMetric := Metric{
-		Key:   fmt.Sprintf("/on-demand-broker/%s/total_instances", a.serviceOffering.Name),
+		Key:   fmt.Sprintf("/on-demand-broker/%s/total_instances", serviceOffering.Name),
 		Unit:  "count",
 		Value: float64(totalInstances),
 	}
 	brokerMetrics = append(brokerMetrics, totalCountMetric)
 
-	if a.serviceOffering.GlobalQuotas.ServiceInstanceLimit != nil {
-		limit := *a.serviceOffering.GlobalQuotas.ServiceInstanceLimit
+	if serviceOffering.GlobalQuotas.ServiceInstanceLimit != nil {
+		limit := *serviceOffering.GlobalQuotas.ServiceInstanceLimit
 		quotaMetric := Metric{
-			Key:   fmt.Sprintf("/on-demand-broker/%s/quota_remaining", a.serviceOffering.Name),
+			Key:   fmt.Sprintf("/on-demand-broker/%s/quota_remaining", serviceOffering.Name),
 			Unit:  "count",
 			Value: float64(limit - totalInstances),
 		}
@@ -200,7 +721,7 @@ func (a *api) writeJson(w io.Writer, obj interface{}, logger *log.Logger) {
 }
 
 func (a *api) getPlan(planID string) (config.Plan, error) {
-	for _, plan := range a.serviceOffering.Plans {
+	for _, plan := range a.serviceOffering.Load().Plans {
 		if plan.ID == planID {
 			return plan, nil
 		}
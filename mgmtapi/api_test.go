@@ -15,17 +15,23 @@ import (
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
 	"github.com/gorilla/mux"
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/brokerapi"
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/cf"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
 	"github.com/pivotal-cf/on-demand-service-broker/mgmtapi"
 	"github.com/pivotal-cf/on-demand-service-broker/mgmtapi/fake_manageable_broker"
+	"github.com/pivotal-cf/on-demand-service-broker/mgmtapi/fakes"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 	"github.com/pivotal-cf/on-demand-service-broker/task"
 )
 
@@ -33,6 +39,7 @@ var _ = Describe("Management API", func() {
 	var (
 		server           *httptest.Server
 		manageableBroker *fake_manageable_broker.FakeManageableBroker
+		configReloader   *fakes.FakeConfigReloader
 		logs             *gbytes.Buffer
 		loggerFactory    *loggerfactory.LoggerFactory
 		serviceOffering  config.ServiceOffering
@@ -47,11 +54,12 @@ var _ = Describe("Management API", func() {
 		logs = gbytes.NewBuffer()
 		loggerFactory = loggerfactory.New(io.MultiWriter(GinkgoWriter, logs), "mgmtapi-unit-tests", log.LstdFlags)
 		manageableBroker = new(fake_manageable_broker.FakeManageableBroker)
+		configReloader = new(fakes.FakeConfigReloader)
 	})
 
 	JustBeforeEach(func() {
 		router := mux.NewRouter()
-		mgmtapi.AttachRoutes(router, manageableBroker, serviceOffering, loggerFactory)
+		mgmtapi.AttachRoutes(router, manageableBroker, configReloader, config.NewServiceOfferingStore(serviceOffering), loggerFactory)
 		server = httptest.NewServer(router)
 	})
 
@@ -116,94 +124,871 @@ var _ = Describe("Management API", func() {
 		var (
 			instanceID = "283974"
 			taskID     = 54321
+			query      string
 
 			upgradeResp *http.Response
 		)
 
+		BeforeEach(func() {
+			query = ""
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			upgradeResp, err = Patch(fmt.Sprintf("%s/mgmt/service_instances/"+instanceID+query, server.URL))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when it succeeds", func() {
+			contextID := "some-context-id"
+			planID := "some-plan-id"
+
+			BeforeEach(func() {
+				manageableBroker.UpgradeReturns(broker.OperationData{
+					BoshTaskID:    taskID,
+					BoshContextID: contextID,
+					PlanID:        planID,
+					OperationType: broker.OperationTypeUpgrade,
+				}, nil)
+			})
+
+			It("upgrades the instance using the broker", func() {
+				Expect(manageableBroker.UpgradeCallCount()).To(Equal(1))
+				_, actualInstanceID, actualOptions, _ := manageableBroker.UpgradeArgsForCall(0)
+				Expect(actualInstanceID).To(Equal(instanceID))
+				Expect(actualOptions).To(Equal(broker.UpgradeOptions{}))
+			})
+
+			It("responds with HTTP 202", func() {
+				Expect(upgradeResp.StatusCode).To(Equal(http.StatusAccepted))
+			})
+
+			It("responds with operation data", func() {
+				var upgradeRespBody broker.OperationData
+				Expect(json.NewDecoder(upgradeResp.Body).Decode(&upgradeRespBody)).To(Succeed())
+				Expect(upgradeRespBody.BoshTaskID).To(Equal(taskID))
+				Expect(upgradeRespBody.BoshContextID).To(Equal(contextID))
+				Expect(upgradeRespBody.PlanID).To(Equal(planID))
+				Expect(upgradeRespBody.OperationType).To(Equal(broker.OperationTypeUpgrade))
+			})
+		})
+
+		Context("when the operator overrides the upgrade policy", func() {
+			BeforeEach(func() {
+				query = "?override_upgrade_policy=true"
+			})
+
+			It("passes the override to the broker", func() {
+				_, _, actualOptions, _ := manageableBroker.UpgradeArgsForCall(0)
+				Expect(actualOptions).To(Equal(broker.UpgradeOptions{OverrideUpgradePolicy: true}))
+			})
+		})
+
+		Context("when the upgrade policy override is invalid", func() {
+			BeforeEach(func() {
+				query = "?override_upgrade_policy=maybe"
+			})
+
+			It("responds with HTTP 400 without upgrading", func() {
+				Expect(upgradeResp.StatusCode).To(Equal(http.StatusBadRequest))
+				Expect(ioutil.ReadAll(upgradeResp.Body)).To(MatchJSON(`{"description": "invalid override_upgrade_policy maybe, must be true or false"}`))
+				Expect(manageableBroker.UpgradeCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the developer has deferred upgrades", func() {
+			BeforeEach(func() {
+				manageableBroker.UpgradeReturns(broker.OperationData{}, broker.NewUpgradeDeferredError(errors.New("upgrades deferred")))
+			})
+
+			It("responds with HTTP 422 and the reason", func() {
+				Expect(upgradeResp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(ioutil.ReadAll(upgradeResp.Body)).To(MatchJSON(`{"description": "upgrades deferred"}`))
+			})
+		})
+
+		Context("when the CF service instance is not found", func() {
+			BeforeEach(func() {
+				manageableBroker.UpgradeReturns(broker.OperationData{}, cf.ResourceNotFoundError{})
+			})
+
+			It("responds with HTTP 404 Not Found", func() {
+				Expect(upgradeResp.StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("when the bosh deployment is not found", func() {
+			BeforeEach(func() {
+				manageableBroker.UpgradeReturns(broker.OperationData{}, task.NewDeploymentNotFoundError(errors.New("error finding deployment")))
+			})
+
+			It("responds with HTTP 410 Gone", func() {
+				Expect(upgradeResp.StatusCode).To(Equal(http.StatusGone))
+			})
+		})
+
+		Context("when there is an operation in progress", func() {
+			BeforeEach(func() {
+				manageableBroker.UpgradeReturns(broker.OperationData{}, broker.NewOperationInProgressError(errors.New("operation in progress error")))
+			})
+
+			It("responds with HTTP 409 Conflict", func() {
+				Expect(upgradeResp.StatusCode).To(Equal(http.StatusConflict))
+			})
+		})
+
+		Context("when it fails", func() {
+			BeforeEach(func() {
+				manageableBroker.UpgradeReturns(broker.OperationData{}, errors.New("upgrade error"))
+			})
+
+			It("responds with HTTP 500", func() {
+				Expect(upgradeResp.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+
+			It("includes the upgrade error in the response", func() {
+				Expect(ioutil.ReadAll(upgradeResp.Body)).To(MatchJSON(`{"description": "upgrade error"}`))
+			})
+
+			It("logs the error", func() {
+				Eventually(logs).Should(gbytes.Say(fmt.Sprintf("error occurred upgrading instance %s: upgrade error", instanceID)))
+			})
+		})
+	})
+
+	Describe("changing the state of an instance", func() {
+		var (
+			instanceID = "283974"
+			taskID     = 54321
+			operation  string
+
+			changeResp *http.Response
+		)
+
+		BeforeEach(func() {
+			operation = "recreate"
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			changeResp, err = http.Post(fmt.Sprintf("%s/mgmt/service_instances/%s/%s", server.URL, instanceID, operation), "application/json", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when it succeeds", func() {
+			BeforeEach(func() {
+				manageableBroker.ChangeInstanceStateReturns(broker.OperationData{
+					BoshTaskID:    taskID,
+					OperationType: broker.OperationTypeRecreate,
+				}, nil)
+			})
+
+			It("recreates the instance using the broker", func() {
+				Expect(manageableBroker.ChangeInstanceStateCallCount()).To(Equal(1))
+				_, actualInstanceID, actualOperationType, _ := manageableBroker.ChangeInstanceStateArgsForCall(0)
+				Expect(actualInstanceID).To(Equal(instanceID))
+				Expect(actualOperationType).To(Equal(broker.OperationTypeRecreate))
+			})
+
+			It("responds with HTTP 202", func() {
+				Expect(changeResp.StatusCode).To(Equal(http.StatusAccepted))
+			})
+
+			It("responds with operation data", func() {
+				var changeRespBody broker.OperationData
+				Expect(json.NewDecoder(changeResp.Body).Decode(&changeRespBody)).To(Succeed())
+				Expect(changeRespBody.BoshTaskID).To(Equal(taskID))
+				Expect(changeRespBody.OperationType).To(Equal(broker.OperationTypeRecreate))
+			})
+		})
+
+		DescribeTable("supported operations",
+			func(path string, expectedOperationType broker.OperationType) {
+				resp, err := http.Post(fmt.Sprintf("%s/mgmt/service_instances/%s/%s", server.URL, instanceID, path), "application/json", nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+
+				_, _, actualOperationType, _ := manageableBroker.ChangeInstanceStateArgsForCall(1)
+				Expect(actualOperationType).To(Equal(expectedOperationType))
+			},
+			Entry("restart", "restart", broker.OperationTypeRestart),
+			Entry("stop", "stop", broker.OperationTypeStop),
+			Entry("start", "start", broker.OperationTypeStart),
+		)
+
+		Context("when the CF service instance is not found", func() {
+			BeforeEach(func() {
+				manageableBroker.ChangeInstanceStateReturns(broker.OperationData{}, cf.ResourceNotFoundError{})
+			})
+
+			It("responds with HTTP 404 Not Found", func() {
+				Expect(changeResp.StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("when the bosh deployment is not found", func() {
+			BeforeEach(func() {
+				manageableBroker.ChangeInstanceStateReturns(broker.OperationData{}, task.NewDeploymentNotFoundError(errors.New("error finding deployment")))
+			})
+
+			It("responds with HTTP 410 Gone", func() {
+				Expect(changeResp.StatusCode).To(Equal(http.StatusGone))
+			})
+		})
+
+		Context("when there is an operation in progress", func() {
+			BeforeEach(func() {
+				manageableBroker.ChangeInstanceStateReturns(broker.OperationData{}, broker.NewOperationInProgressError(errors.New("operation in progress error")))
+			})
+
+			It("responds with HTTP 409 Conflict", func() {
+				Expect(changeResp.StatusCode).To(Equal(http.StatusConflict))
+			})
+		})
+
+		Context("when it fails", func() {
+			BeforeEach(func() {
+				manageableBroker.ChangeInstanceStateReturns(broker.OperationData{}, errors.New("recreate error"))
+			})
+
+			It("responds with HTTP 500", func() {
+				Expect(changeResp.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+
+			It("includes the error in the response", func() {
+				Expect(ioutil.ReadAll(changeResp.Body)).To(MatchJSON(`{"description": "recreate error"}`))
+			})
+
+			It("logs the error", func() {
+				Eventually(logs).Should(gbytes.Say(fmt.Sprintf("error occurred performing recreate on instance %s: recreate error", instanceID)))
+			})
+		})
+	})
+
+	Describe("backing up an instance", func() {
+		var (
+			instanceID = "283974"
+			backupResp *http.Response
+		)
+
+		JustBeforeEach(func() {
+			var err error
+			backupResp, err = http.Post(fmt.Sprintf("%s/mgmt/service_instances/%s/backups", server.URL, instanceID), "application/json", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when it succeeds", func() {
+			BeforeEach(func() {
+				manageableBroker.BackupReturns(broker.OperationData{
+					BoshTaskID:    54321,
+					BoshContextID: "some-context",
+					OperationType: broker.OperationTypeBackup,
+				}, nil)
+			})
+
+			It("backs up the instance using the broker", func() {
+				Expect(manageableBroker.BackupCallCount()).To(Equal(1))
+				_, actualInstanceID, _ := manageableBroker.BackupArgsForCall(0)
+				Expect(actualInstanceID).To(Equal(instanceID))
+			})
+
+			It("responds with HTTP 202 and operation data", func() {
+				Expect(backupResp.StatusCode).To(Equal(http.StatusAccepted))
+				Expect(ioutil.ReadAll(backupResp.Body)).To(MatchJSON(`{"BoshTaskID": 54321, "BoshContextID": "some-context", "OperationType": "backup"}`))
+			})
+		})
+
+		Context("when the plan has no backup errand", func() {
+			BeforeEach(func() {
+				manageableBroker.BackupReturns(broker.OperationData{}, broker.NewBackupNotConfiguredError(errors.New("plan some-plan has no backup errand configured")))
+			})
+
+			It("responds with HTTP 422 and the error", func() {
+				Expect(backupResp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(ioutil.ReadAll(backupResp.Body)).To(MatchJSON(`{"description": "plan some-plan has no backup errand configured"}`))
+			})
+		})
+
+		Context("when the CF service instance is not found", func() {
+			BeforeEach(func() {
+				manageableBroker.BackupReturns(broker.OperationData{}, cf.ResourceNotFoundError{})
+			})
+
+			It("responds with HTTP 404 Not Found", func() {
+				Expect(backupResp.StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("when the bosh deployment is not found", func() {
+			BeforeEach(func() {
+				manageableBroker.BackupReturns(broker.OperationData{}, task.NewDeploymentNotFoundError(errors.New("error finding deployment")))
+			})
+
+			It("responds with HTTP 410 Gone", func() {
+				Expect(backupResp.StatusCode).To(Equal(http.StatusGone))
+			})
+		})
+
+		Context("when there is an operation in progress", func() {
+			BeforeEach(func() {
+				manageableBroker.BackupReturns(broker.OperationData{}, broker.NewOperationInProgressError(errors.New("operation in progress error")))
+			})
+
+			It("responds with HTTP 409 Conflict", func() {
+				Expect(backupResp.StatusCode).To(Equal(http.StatusConflict))
+			})
+		})
+
+		Context("when it fails", func() {
+			BeforeEach(func() {
+				manageableBroker.BackupReturns(broker.OperationData{}, errors.New("errand error"))
+			})
+
+			It("responds with HTTP 500 and logs the error", func() {
+				Expect(backupResp.StatusCode).To(Equal(http.StatusInternalServerError))
+				Expect(ioutil.ReadAll(backupResp.Body)).To(MatchJSON(`{"description": "errand error"}`))
+				Eventually(logs).Should(gbytes.Say(fmt.Sprintf("error occurred performing backup on instance %s: errand error", instanceID)))
+			})
+		})
+	})
+
+	Describe("restoring an instance", func() {
+		var restoreResp *http.Response
+
+		BeforeEach(func() {
+			manageableBroker.RestoreReturns(broker.OperationData{BoshTaskID: 54321, OperationType: broker.OperationTypeRestore}, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			restoreResp, err = http.Post(fmt.Sprintf("%s/mgmt/service_instances/283974/restore", server.URL), "application/json", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("restores the instance using the broker", func() {
+			Expect(restoreResp.StatusCode).To(Equal(http.StatusAccepted))
+			Expect(manageableBroker.RestoreCallCount()).To(Equal(1))
+			Expect(manageableBroker.BackupCallCount()).To(BeZero())
+			_, actualInstanceID, _ := manageableBroker.RestoreArgsForCall(0)
+			Expect(actualInstanceID).To(Equal("283974"))
+		})
+
+		Context("when the plan has no restore errand", func() {
+			BeforeEach(func() {
+				manageableBroker.RestoreReturns(broker.OperationData{}, broker.NewBackupNotConfiguredError(errors.New("no restore errand")))
+			})
+
+			It("responds with HTTP 422", func() {
+				Expect(restoreResp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+			})
+		})
+	})
+
+	Describe("rolling back an instance", func() {
+		const instanceID = "283974"
+
+		var rollbackResp *http.Response
+
+		BeforeEach(func() {
+			manageableBroker.RollbackReturns(broker.OperationData{BoshTaskID: 54321, OperationType: broker.OperationTypeRollback}, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			rollbackResp, err = http.Post(fmt.Sprintf("%s/mgmt/service_instances/%s/rollback", server.URL, instanceID), "application/json", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("rolls back the instance using the broker", func() {
+			Expect(manageableBroker.RollbackCallCount()).To(Equal(1))
+			_, actualInstanceID, _ := manageableBroker.RollbackArgsForCall(0)
+			Expect(actualInstanceID).To(Equal(instanceID))
+		})
+
+		It("responds with HTTP 202 and operation data", func() {
+			Expect(rollbackResp.StatusCode).To(Equal(http.StatusAccepted))
+			Expect(ioutil.ReadAll(rollbackResp.Body)).To(MatchJSON(`{"BoshTaskID": 54321, "OperationType": "rollback"}`))
+		})
+
+		Context("when no previous manifest was retained", func() {
+			BeforeEach(func() {
+				manageableBroker.RollbackReturns(broker.OperationData{}, task.NewNoPreviousManifestError(errors.New("no previous manifest retained for deployment some-deployment")))
+			})
+
+			It("responds with HTTP 422 and the error", func() {
+				Expect(rollbackResp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(ioutil.ReadAll(rollbackResp.Body)).To(MatchJSON(`{"description": "no previous manifest retained for deployment some-deployment"}`))
+			})
+		})
+
+		Context("when releases or stemcells of the previous manifest are no longer uploaded", func() {
+			BeforeEach(func() {
+				manageableBroker.RollbackReturns(broker.OperationData{}, task.NewMissingDependenciesError(errors.New("release redis/1 missing")))
+			})
+
+			It("responds with HTTP 422 and the error", func() {
+				Expect(rollbackResp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(ioutil.ReadAll(rollbackResp.Body)).To(MatchJSON(`{"description": "release redis/1 missing"}`))
+			})
+		})
+
+		Context("when the CF service instance is not found", func() {
+			BeforeEach(func() {
+				manageableBroker.RollbackReturns(broker.OperationData{}, cf.ResourceNotFoundError{})
+			})
+
+			It("responds with HTTP 404 Not Found", func() {
+				Expect(rollbackResp.StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("when the bosh deployment is not found", func() {
+			BeforeEach(func() {
+				manageableBroker.RollbackReturns(broker.OperationData{}, task.NewDeploymentNotFoundError(errors.New("error finding deployment")))
+			})
+
+			It("responds with HTTP 410 Gone", func() {
+				Expect(rollbackResp.StatusCode).To(Equal(http.StatusGone))
+			})
+		})
+
+		Context("when there is an operation in progress", func() {
+			BeforeEach(func() {
+				manageableBroker.RollbackReturns(broker.OperationData{}, broker.NewOperationInProgressError(errors.New("operation in progress error")))
+			})
+
+			It("responds with HTTP 409 Conflict", func() {
+				Expect(rollbackResp.StatusCode).To(Equal(http.StatusConflict))
+			})
+		})
+
+		Context("when it fails", func() {
+			BeforeEach(func() {
+				manageableBroker.RollbackReturns(broker.OperationData{}, errors.New("director error"))
+			})
+
+			It("responds with HTTP 500 and logs the error", func() {
+				Expect(rollbackResp.StatusCode).To(Equal(http.StatusInternalServerError))
+				Expect(ioutil.ReadAll(rollbackResp.Body)).To(MatchJSON(`{"description": "director error"}`))
+				Eventually(logs).Should(gbytes.Say(fmt.Sprintf("error occurred rolling back instance %s: director error", instanceID)))
+			})
+		})
+	})
+
+	Describe("health", func() {
+		var healthResp *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+			healthResp, err = http.Get(fmt.Sprintf("%s/mgmt/health", server.URL))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("responds with HTTP 200 when the broker is healthy", func() {
+			Expect(healthResp.StatusCode).To(Equal(http.StatusOK))
+			Expect(ioutil.ReadAll(healthResp.Body)).To(MatchJSON(`{"healthy": true}`))
+		})
+
+		Context("when the last health check failed", func() {
+			BeforeEach(func() {
+				manageableBroker.HealthReturns(errors.New("release redis version 9 missing"))
+			})
+
+			It("responds with HTTP 503 and the failure", func() {
+				Expect(healthResp.StatusCode).To(Equal(http.StatusServiceUnavailable))
+				Expect(ioutil.ReadAll(healthResp.Body)).To(MatchJSON(`{"healthy": false, "description": "release redis version 9 missing"}`))
+			})
+		})
+	})
+
+	Describe("reloading config", func() {
+		var reloadResp *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+			reloadResp, err = http.Post(fmt.Sprintf("%s/mgmt/reload_config", server.URL), "application/json", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("reloads the config and responds with HTTP 204", func() {
+			Expect(configReloader.ReloadCallCount()).To(Equal(1))
+			Expect(reloadResp.StatusCode).To(Equal(http.StatusNoContent))
+		})
+
+		Context("when the reload is rejected", func() {
+			BeforeEach(func() {
+				configReloader.ReloadReturns(errors.New("the following settings cannot be changed without restarting the broker: bosh"))
+			})
+
+			It("responds with HTTP 422 and the reason", func() {
+				Expect(reloadResp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(ioutil.ReadAll(reloadResp.Body)).To(MatchJSON(`{"description": "the following settings cannot be changed without restarting the broker: bosh"}`))
+			})
+
+			It("logs the error", func() {
+				Eventually(logs).Should(gbytes.Say("error reloading config: the following settings cannot be changed without restarting the broker: bosh"))
+			})
+		})
+	})
+
+	Describe("instance status", func() {
+		var statusResp *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+			statusResp, err = http.Get(fmt.Sprintf("%s/mgmt/service_instances/283974/status", server.URL))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the adapter reports the status", func() {
+			BeforeEach(func() {
+				manageableBroker.InstanceStatusReturns(serviceadapter.InstanceStatus{"version": "3.2.1", "leader": "redis/0"}, nil)
+			})
+
+			It("responds with HTTP 200 and the status", func() {
+				Expect(statusResp.StatusCode).To(Equal(http.StatusOK))
+				Expect(ioutil.ReadAll(statusResp.Body)).To(MatchJSON(`{"version": "3.2.1", "leader": "redis/0"}`))
+
+				_, instanceID, _ := manageableBroker.InstanceStatusArgsForCall(0)
+				Expect(instanceID).To(Equal("283974"))
+			})
+		})
+
+		Context("when the deployment does not exist", func() {
+			BeforeEach(func() {
+				manageableBroker.InstanceStatusReturns(nil, task.NewDeploymentNotFoundError(errors.New("not found")))
+			})
+
+			It("responds with HTTP 404", func() {
+				Expect(statusResp.StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("when the adapter does not support instance-status", func() {
+			BeforeEach(func() {
+				manageableBroker.InstanceStatusReturns(nil, serviceadapter.NewNotImplementedError("service adapter at /adapter does not support instance-status"))
+			})
+
+			It("responds with HTTP 501 and the reason", func() {
+				Expect(statusResp.StatusCode).To(Equal(http.StatusNotImplemented))
+				Expect(ioutil.ReadAll(statusResp.Body)).To(MatchJSON(`{"description": "service adapter at /adapter does not support instance-status"}`))
+			})
+		})
+
+		Context("when getting the status fails", func() {
+			BeforeEach(func() {
+				manageableBroker.InstanceStatusReturns(nil, errors.New("bosh is down"))
+			})
+
+			It("responds with HTTP 500", func() {
+				Expect(statusResp.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("service adapter", func() {
+		var adapterResp *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+			adapterResp, err = http.Get(fmt.Sprintf("%s/mgmt/service_adapter", server.URL))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the adapter reports its capabilities", func() {
+			BeforeEach(func() {
+				manageableBroker.AdapterCapabilitiesReturns(serviceadapter.Capabilities{
+					Subcommands: []string{"generate-manifest", "create-binding", "delete-binding"},
+					SDKVersion:  "1.2.3",
+					Features:    []string{"some-feature"},
+					InputModes:  []string{"argv", "stdin"},
+				}, nil)
+			})
+
+			It("responds with HTTP 200 and the capabilities", func() {
+				Expect(adapterResp.StatusCode).To(Equal(http.StatusOK))
+				Expect(ioutil.ReadAll(adapterResp.Body)).To(MatchJSON(`{
+					"subcommands": ["generate-manifest", "create-binding", "delete-binding"],
+					"sdk_version": "1.2.3",
+					"features": ["some-feature"],
+					"input_modes": ["argv", "stdin"]
+				}`))
+			})
+		})
+
+		Context("when the capabilities cannot be retrieved", func() {
+			BeforeEach(func() {
+				manageableBroker.AdapterCapabilitiesReturns(serviceadapter.Capabilities{}, errors.New("adapter timed out"))
+			})
+
+			It("responds with HTTP 500", func() {
+				Expect(adapterResp.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("listing backups of an instance", func() {
+		var listResp *http.Response
+
 		JustBeforeEach(func() {
 			var err error
-			upgradeResp, err = Patch(fmt.Sprintf("%s/mgmt/service_instances/"+instanceID, server.URL))
+			listResp, err = http.Get(fmt.Sprintf("%s/mgmt/service_instances/283974/backups", server.URL))
 			Expect(err).NotTo(HaveOccurred())
 		})
 
-		Context("when it succeeds", func() {
-			contextID := "some-context-id"
-			planID := "some-plan-id"
+		Context("when the broker returns backups", func() {
+			BeforeEach(func() {
+				manageableBroker.BackupsReturns([]broker.Backup{
+					{TaskID: 3, State: boshdirector.TaskDone, Timestamp: 300, ContextID: "ctx-3"},
+					{TaskID: 1, State: boshdirector.TaskError, Timestamp: 100},
+				}, nil)
+			})
+
+			It("returns HTTP 200 with the backups", func() {
+				Expect(listResp.StatusCode).To(Equal(http.StatusOK))
+				Expect(ioutil.ReadAll(listResp.Body)).To(MatchJSON(`[
+					{"bosh_task_id": 3, "state": "done", "timestamp": 300, "context_id": "ctx-3"},
+					{"bosh_task_id": 1, "state": "error", "timestamp": 100}
+				]`))
+				actualInstanceID, _ := manageableBroker.BackupsArgsForCall(0)
+				Expect(actualInstanceID).To(Equal("283974"))
+			})
+		})
 
+		Context("when the plan has no backup errand", func() {
 			BeforeEach(func() {
-				manageableBroker.UpgradeReturns(broker.OperationData{
-					BoshTaskID:    taskID,
-					BoshContextID: contextID,
-					PlanID:        planID,
-					OperationType: broker.OperationTypeUpgrade,
+				manageableBroker.BackupsReturns(nil, broker.NewBackupNotConfiguredError(errors.New("no backup errand")))
+			})
+
+			It("responds with HTTP 422", func() {
+				Expect(listResp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+			})
+		})
+
+		Context("when the broker fails to list backups", func() {
+			BeforeEach(func() {
+				manageableBroker.BackupsReturns(nil, errors.New("bosh is down"))
+			})
+
+			It("returns HTTP 500 and logs the error", func() {
+				Expect(listResp.StatusCode).To(Equal(http.StatusInternalServerError))
+				Eventually(logs).Should(gbytes.Say("error occurred querying backups of instance 283974: bosh is down"))
+			})
+		})
+	})
+
+	Describe("getting the maintenance window of an instance", func() {
+		var windowResp *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+			windowResp, err = http.Get(fmt.Sprintf("%s/mgmt/service_instances/283974/maintenance_window", server.URL))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the instance has a maintenance window", func() {
+			BeforeEach(func() {
+				manageableBroker.MaintenanceWindowReturns(&config.MaintenanceWindow{Days: []string{"sat"}, Start: "02:00", Duration: "4h"}, nil)
+			})
+
+			It("returns HTTP 200 with the window", func() {
+				Expect(windowResp.StatusCode).To(Equal(http.StatusOK))
+				Expect(ioutil.ReadAll(windowResp.Body)).To(MatchJSON(`{"maintenance_window": {"days": ["sat"], "start": "02:00", "duration": "4h"}}`))
+				actualInstanceID, _ := manageableBroker.MaintenanceWindowArgsForCall(0)
+				Expect(actualInstanceID).To(Equal("283974"))
+			})
+		})
+
+		Context("when the instance has no maintenance window", func() {
+			It("returns HTTP 200 with a null window", func() {
+				Expect(windowResp.StatusCode).To(Equal(http.StatusOK))
+				Expect(ioutil.ReadAll(windowResp.Body)).To(MatchJSON(`{"maintenance_window": null}`))
+			})
+		})
+
+		Context("when the instance cannot be found", func() {
+			BeforeEach(func() {
+				manageableBroker.MaintenanceWindowReturns(nil, cf.NewResourceNotFoundError("not found"))
+			})
+
+			It("responds with HTTP 404", func() {
+				Expect(windowResp.StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("when the broker fails to get the window", func() {
+			BeforeEach(func() {
+				manageableBroker.MaintenanceWindowReturns(nil, errors.New("bosh is down"))
+			})
+
+			It("returns HTTP 500 and logs the error", func() {
+				Expect(windowResp.StatusCode).To(Equal(http.StatusInternalServerError))
+				Eventually(logs).Should(gbytes.Say("error occurred querying maintenance window of instance 283974: bosh is down"))
+			})
+		})
+	})
+
+	Describe("listing tasks of an instance", func() {
+		var (
+			instanceID = "283974"
+			listResp   *http.Response
+		)
+
+		JustBeforeEach(func() {
+			var err error
+			listResp, err = http.Get(fmt.Sprintf("%s/mgmt/service_instances/%s/tasks", server.URL, instanceID))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the broker returns tasks", func() {
+			BeforeEach(func() {
+				manageableBroker.InstanceTasksReturns(boshdirector.BoshTasks{
+					{ID: 2, State: boshdirector.TaskError, Description: "create deployment", Result: "failed"},
+					{ID: 1, State: boshdirector.TaskDone, Description: "run errand", ContextID: "some-context"},
 				}, nil)
 			})
 
-			It("upgrades the instance using the broker", func() {
-				Expect(manageableBroker.UpgradeCallCount()).To(Equal(1))
-				_, actualInstanceID, _ := manageableBroker.UpgradeArgsForCall(0)
+			It("lists tasks of the instance", func() {
+				Expect(manageableBroker.InstanceTasksCallCount()).To(Equal(1))
+				actualInstanceID, _ := manageableBroker.InstanceTasksArgsForCall(0)
 				Expect(actualInstanceID).To(Equal(instanceID))
 			})
 
-			It("responds with HTTP 202", func() {
-				Expect(upgradeResp.StatusCode).To(Equal(http.StatusAccepted))
+			It("returns HTTP 200 with the tasks", func() {
+				Expect(listResp.StatusCode).To(Equal(http.StatusOK))
+				Expect(ioutil.ReadAll(listResp.Body)).To(MatchJSON(`[
+					{"id": 2, "state": "error", "description": "create deployment", "result": "failed"},
+					{"id": 1, "state": "done", "description": "run errand", "result": "", "context_id": "some-context"}
+				]`))
 			})
+		})
 
-			It("responds with operation data", func() {
-				var upgradeRespBody broker.OperationData
-				Expect(json.NewDecoder(upgradeResp.Body).Decode(&upgradeRespBody)).To(Succeed())
-				Expect(upgradeRespBody.BoshTaskID).To(Equal(taskID))
-				Expect(upgradeRespBody.BoshContextID).To(Equal(contextID))
-				Expect(upgradeRespBody.PlanID).To(Equal(planID))
-				Expect(upgradeRespBody.OperationType).To(Equal(broker.OperationTypeUpgrade))
+		Context("when the broker fails to list tasks", func() {
+			BeforeEach(func() {
+				manageableBroker.InstanceTasksReturns(nil, errors.New("bosh is down"))
+			})
+
+			It("returns HTTP 500", func() {
+				Expect(listResp.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+
+			It("logs the error", func() {
+				Eventually(logs).Should(gbytes.Say(fmt.Sprintf("error occurred querying tasks of instance %s: bosh is down", instanceID)))
 			})
 		})
+	})
 
-		Context("when the CF service instance is not found", func() {
+	Describe("streaming task output of an instance", func() {
+		var (
+			instanceID = "283974"
+			taskPath   string
+			outputResp *http.Response
+		)
+
+		BeforeEach(func() {
+			taskPath = "tasks/42/output?type=debug"
+			manageableBroker.InstanceTaskReturns(boshdirector.BoshTask{ID: 42}, nil)
+			manageableBroker.StreamTaskOutputStub = func(_ int, _ string, writer io.Writer, _ *log.Logger) error {
+				_, err := writer.Write([]byte("some debug output"))
+				return err
+			}
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			outputResp, err = http.Get(fmt.Sprintf("%s/mgmt/service_instances/%s/%s", server.URL, instanceID, taskPath))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("streams the requested output of the task of the instance", func() {
+			Expect(manageableBroker.InstanceTaskCallCount()).To(Equal(1))
+			actualInstanceID, actualTaskID, _ := manageableBroker.InstanceTaskArgsForCall(0)
+			Expect(actualInstanceID).To(Equal(instanceID))
+			Expect(actualTaskID).To(Equal(42))
+
+			Expect(manageableBroker.StreamTaskOutputCallCount()).To(Equal(1))
+			actualTaskID, actualOutputType, _, _ := manageableBroker.StreamTaskOutputArgsForCall(0)
+			Expect(actualTaskID).To(Equal(42))
+			Expect(actualOutputType).To(Equal("debug"))
+
+			Expect(outputResp.StatusCode).To(Equal(http.StatusOK))
+			Expect(ioutil.ReadAll(outputResp.Body)).To(Equal([]byte("some debug output")))
+		})
+
+		Context("when no output type is requested", func() {
 			BeforeEach(func() {
-				manageableBroker.UpgradeReturns(broker.OperationData{}, cf.ResourceNotFoundError{})
+				taskPath = "tasks/42/output"
 			})
 
-			It("responds with HTTP 404 Not Found", func() {
-				Expect(upgradeResp.StatusCode).To(Equal(http.StatusNotFound))
+			It("streams the event output", func() {
+				_, actualOutputType, _, _ := manageableBroker.StreamTaskOutputArgsForCall(0)
+				Expect(actualOutputType).To(Equal("event"))
 			})
 		})
 
-		Context("when the bosh deployment is not found", func() {
+		Context("when the output type is invalid", func() {
 			BeforeEach(func() {
-				manageableBroker.UpgradeReturns(broker.OperationData{}, task.NewDeploymentNotFoundError(errors.New("error finding deployment")))
+				taskPath = "tasks/42/output?type=cpi"
 			})
 
-			It("responds with HTTP 410 Gone", func() {
-				Expect(upgradeResp.StatusCode).To(Equal(http.StatusGone))
+			It("returns HTTP 400 without streaming", func() {
+				Expect(outputResp.StatusCode).To(Equal(http.StatusBadRequest))
+				Expect(manageableBroker.StreamTaskOutputCallCount()).To(BeZero())
 			})
 		})
 
-		Context("when there is an operation in progress", func() {
+		Context("when the task ID is not a number", func() {
 			BeforeEach(func() {
-				manageableBroker.UpgradeReturns(broker.OperationData{}, broker.NewOperationInProgressError(errors.New("operation in progress error")))
+				taskPath = "tasks/latest/output"
 			})
 
-			It("responds with HTTP 409 Conflict", func() {
-				Expect(upgradeResp.StatusCode).To(Equal(http.StatusConflict))
+			It("returns HTTP 400 without streaming", func() {
+				Expect(outputResp.StatusCode).To(Equal(http.StatusBadRequest))
+				Expect(manageableBroker.StreamTaskOutputCallCount()).To(BeZero())
 			})
 		})
 
-		Context("when it fails", func() {
+		Context("when the task does not belong to the instance", func() {
 			BeforeEach(func() {
-				manageableBroker.UpgradeReturns(broker.OperationData{}, errors.New("upgrade error"))
+				manageableBroker.InstanceTaskReturns(boshdirector.BoshTask{}, broker.NewTaskNotFoundError(errors.New("not this instance")))
 			})
 
-			It("responds with HTTP 500", func() {
-				Expect(upgradeResp.StatusCode).To(Equal(http.StatusInternalServerError))
+			It("returns HTTP 404 without streaming", func() {
+				Expect(outputResp.StatusCode).To(Equal(http.StatusNotFound))
+				Expect(manageableBroker.StreamTaskOutputCallCount()).To(BeZero())
 			})
+		})
 
-			It("includes the upgrade error in the response", func() {
-				Expect(ioutil.ReadAll(upgradeResp.Body)).To(MatchJSON(`{"description": "upgrade error"}`))
+		Context("when the task cannot be retrieved", func() {
+			BeforeEach(func() {
+				manageableBroker.InstanceTaskReturns(boshdirector.BoshTask{}, errors.New("bosh is down"))
+			})
+
+			It("returns HTTP 500 without streaming", func() {
+				Expect(outputResp.StatusCode).To(Equal(http.StatusInternalServerError))
+				Expect(manageableBroker.StreamTaskOutputCallCount()).To(BeZero())
 			})
 
 			It("logs the error", func() {
-				Eventually(logs).Should(gbytes.Say(fmt.Sprintf("error occurred upgrading instance %s: upgrade error", instanceID)))
+				Eventually(logs).Should(gbytes.Say(fmt.Sprintf("error occurred getting task 42 of instance %s: bosh is down", instanceID)))
+			})
+		})
+
+		Context("when streaming fails part way through", func() {
+			BeforeEach(func() {
+				manageableBroker.StreamTaskOutputStub = func(_ int, _ string, writer io.Writer, _ *log.Logger) error {
+					writer.Write([]byte("some debug output"))
+					return errors.New("bosh is down")
+				}
+			})
+
+			It("keeps the HTTP 200 and reports the failure at the end of the output", func() {
+				Expect(outputResp.StatusCode).To(Equal(http.StatusOK))
+				Expect(ioutil.ReadAll(outputResp.Body)).To(Equal([]byte("some debug output\nerror streaming output of task 42, the output is incomplete\n")))
+			})
+
+			It("logs the error", func() {
+				Eventually(logs).Should(gbytes.Say(fmt.Sprintf("error occurred streaming output of task 42 for instance %s: bosh is down", instanceID)))
 			})
 		})
 	})
@@ -512,6 +1297,212 @@ var _ = Describe("Management API", func() {
 			})
 		})
 	})
+
+	Describe("listing missing deployments", func() {
+		var listResp *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+			listResp, err = http.Get(fmt.Sprintf("%s/mgmt/missing_deployments", server.URL))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when there are no missing deployments", func() {
+			It("returns HTTP 200 with an empty list", func() {
+				Expect(listResp.StatusCode).To(Equal(http.StatusOK))
+				defer listResp.Body.Close()
+				body, err := ioutil.ReadAll(listResp.Body)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(body).To(MatchJSON(`[]`))
+			})
+		})
+
+		Context("when there are missing deployments", func() {
+			BeforeEach(func() {
+				manageableBroker.MissingDeploymentsReturns([]broker.MissingDeployment{
+					{InstanceID: "one", DeploymentName: "service-instance_one", PlanID: "foo_id", SpaceGUID: "space", OrganizationGUID: "org"},
+					{InstanceID: "two", DeploymentName: "service-instance_two", PlanID: "unknown_id", SpaceGUID: "space", OrganizationGUID: "org"},
+				}, nil)
+			})
+
+			It("returns the instances with their plan and organization", func() {
+				Expect(listResp.StatusCode).To(Equal(http.StatusOK))
+				var missing []mgmtapi.MissingDeployment
+				Expect(json.NewDecoder(listResp.Body).Decode(&missing)).To(Succeed())
+				Expect(missing).To(Equal([]mgmtapi.MissingDeployment{
+					{InstanceID: "one", DeploymentName: "service-instance_one", PlanID: "foo_id", PlanName: "foo_plan", SpaceGUID: "space", OrganizationGUID: "org"},
+					{InstanceID: "two", DeploymentName: "service-instance_two", PlanID: "unknown_id", SpaceGUID: "space", OrganizationGUID: "org"},
+				}))
+			})
+		})
+
+		Context("when the broker returns an error", func() {
+			BeforeEach(func() {
+				manageableBroker.MissingDeploymentsReturns(nil, errors.New("Broker errored."))
+			})
+
+			It("returns HTTP 500 and logs the error", func() {
+				Expect(listResp.StatusCode).To(Equal(http.StatusInternalServerError))
+				Eventually(logs).Should(gbytes.Say("error occurred querying missing deployments: Broker errored."))
+			})
+		})
+	})
+
+	Describe("recreating a missing deployment", func() {
+		var recreateResp *http.Response
+
+		BeforeEach(func() {
+			manageableBroker.RecreateMissingDeploymentReturns(broker.OperationData{BoshTaskID: 7, OperationType: broker.OperationTypeCreate}, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			recreateResp, err = http.Post(fmt.Sprintf("%s/mgmt/missing_deployments/some-instance", server.URL), "application/json", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns HTTP 202 with the operation data", func() {
+			Expect(recreateResp.StatusCode).To(Equal(http.StatusAccepted))
+			var operationData broker.OperationData
+			Expect(json.NewDecoder(recreateResp.Body).Decode(&operationData)).To(Succeed())
+			Expect(operationData).To(Equal(broker.OperationData{BoshTaskID: 7, OperationType: broker.OperationTypeCreate}))
+
+			_, actualInstanceID, _ := manageableBroker.RecreateMissingDeploymentArgsForCall(0)
+			Expect(actualInstanceID).To(Equal("some-instance"))
+		})
+
+		Context("when the deployment is not missing", func() {
+			BeforeEach(func() {
+				manageableBroker.RecreateMissingDeploymentReturns(broker.OperationData{}, broker.NewDeploymentNotMissingError(errors.New("not missing")))
+			})
+
+			It("returns HTTP 404", func() {
+				Expect(recreateResp.StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("when an operation is in progress", func() {
+			BeforeEach(func() {
+				manageableBroker.RecreateMissingDeploymentReturns(broker.OperationData{}, broker.NewOperationInProgressError(errors.New("in progress")))
+			})
+
+			It("returns HTTP 409", func() {
+				Expect(recreateResp.StatusCode).To(Equal(http.StatusConflict))
+			})
+		})
+
+		Context("when the broker returns an error", func() {
+			BeforeEach(func() {
+				manageableBroker.RecreateMissingDeploymentReturns(broker.OperationData{}, errors.New("adapter error"))
+			})
+
+			It("returns HTTP 500 and logs the error", func() {
+				Expect(recreateResp.StatusCode).To(Equal(http.StatusInternalServerError))
+				Eventually(logs).Should(gbytes.Say("error occurred recreating missing deployment of instance some-instance: adapter error"))
+			})
+		})
+	})
+
+	Describe("deleting an orphan deployment", func() {
+		var (
+			query     string
+			deleteRsp *http.Response
+		)
+
+		BeforeEach(func() {
+			query = ""
+			manageableBroker.DeleteOrphanDeploymentReturns(broker.OrphanDeletionResult{
+				DeploymentName: "service-instance_orphan",
+				Deleted:        true,
+				DeleteTaskID:   42,
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			deleteRsp, err = Delete(fmt.Sprintf("%s/mgmt/orphan_deployments/service-instance_orphan%s", server.URL, query))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns HTTP 200 with the result", func() {
+			Expect(deleteRsp.StatusCode).To(Equal(http.StatusOK))
+			defer deleteRsp.Body.Close()
+			body, err := ioutil.ReadAll(deleteRsp.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(body).To(MatchJSON(`{"deployment_name":"service-instance_orphan","deleted":true,"dry_run":false,"delete_task_id":42}`))
+		})
+
+		It("deletes the deployment with default options", func() {
+			Expect(manageableBroker.DeleteOrphanDeploymentCallCount()).To(Equal(1))
+			actualName, actualOptions, _ := manageableBroker.DeleteOrphanDeploymentArgsForCall(0)
+			Expect(actualName).To(Equal("service-instance_orphan"))
+			Expect(actualOptions).To(Equal(broker.OrphanDeletionOptions{}))
+		})
+
+		Context("when options are given", func() {
+			BeforeEach(func() {
+				query = "?dry_run=true&min_age=24h&run_pre_delete_errand=true&plan_id=some-plan"
+			})
+
+			It("passes them to the broker", func() {
+				_, actualOptions, _ := manageableBroker.DeleteOrphanDeploymentArgsForCall(0)
+				Expect(actualOptions).To(Equal(broker.OrphanDeletionOptions{
+					DryRun:             true,
+					MinAge:             24 * time.Hour,
+					RunPreDeleteErrand: true,
+					PlanID:             "some-plan",
+				}))
+			})
+		})
+
+		Context("when the minimum age is invalid", func() {
+			BeforeEach(func() {
+				query = "?min_age=yesterday"
+			})
+
+			It("returns HTTP 400 without deleting", func() {
+				Expect(deleteRsp.StatusCode).To(Equal(http.StatusBadRequest))
+				Expect(manageableBroker.DeleteOrphanDeploymentCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the deployment does not exist", func() {
+			BeforeEach(func() {
+				manageableBroker.DeleteOrphanDeploymentReturns(broker.OrphanDeletionResult{}, broker.NewDeploymentNotFoundError(errors.New("not found")))
+			})
+
+			It("returns HTTP 404", func() {
+				Expect(deleteRsp.StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("when the deployment is not an orphan", func() {
+			BeforeEach(func() {
+				manageableBroker.DeleteOrphanDeploymentReturns(broker.OrphanDeletionResult{}, broker.NewNotAnOrphanError(errors.New("instance exists")))
+			})
+
+			It("returns HTTP 409 with the reason", func() {
+				Expect(deleteRsp.StatusCode).To(Equal(http.StatusConflict))
+				var errorResponse brokerapi.ErrorResponse
+				Expect(json.NewDecoder(deleteRsp.Body).Decode(&errorResponse)).To(Succeed())
+				Expect(errorResponse.Description).To(Equal("instance exists"))
+			})
+		})
+
+		Context("when the broker returns an error", func() {
+			BeforeEach(func() {
+				manageableBroker.DeleteOrphanDeploymentReturns(broker.OrphanDeletionResult{}, errors.New("director error"))
+			})
+
+			It("returns HTTP 500", func() {
+				Expect(deleteRsp.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+
+			It("logs an error", func() {
+				Eventually(logs).Should(gbytes.Say("error occurred deleting orphan deployment service-instance_orphan: director error"))
+			})
+		})
+	})
 })
 
 func Patch(url string) (resp *http.Response, err error) {
@@ -521,3 +1512,11 @@ func Patch(url string) (resp *http.Response, err error) {
 	}
 	return http.DefaultClient.Do(req)
 }
+
+func Delete(url string) (resp *http.Response, err error) {
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return http.DefaultClient.Do(req)
+}
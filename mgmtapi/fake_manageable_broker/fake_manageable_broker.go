@@ -3,11 +3,15 @@ package fake_manageable_broker
 
 import (
 	"context"
+	"io"
 	"log"
 	"sync"
 
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/mgmtapi"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 )
 
 type FakeManageableBroker struct {
@@ -37,11 +41,55 @@ type FakeManageableBroker struct {
 		result1 []string
 		result2 error
 	}
-	UpgradeStub        func(ctx context.Context, instanceID string, logger *log.Logger) (broker.OperationData, error)
+	DeleteOrphanDeploymentStub        func(deploymentName string, options broker.OrphanDeletionOptions, logger *log.Logger) (broker.OrphanDeletionResult, error)
+	deleteOrphanDeploymentMutex       sync.RWMutex
+	deleteOrphanDeploymentArgsForCall []struct {
+		deploymentName string
+		options        broker.OrphanDeletionOptions
+		logger         *log.Logger
+	}
+	deleteOrphanDeploymentReturns struct {
+		result1 broker.OrphanDeletionResult
+		result2 error
+	}
+	deleteOrphanDeploymentReturnsOnCall map[int]struct {
+		result1 broker.OrphanDeletionResult
+		result2 error
+	}
+	MissingDeploymentsStub        func(logger *log.Logger) ([]broker.MissingDeployment, error)
+	missingDeploymentsMutex       sync.RWMutex
+	missingDeploymentsArgsForCall []struct {
+		logger *log.Logger
+	}
+	missingDeploymentsReturns struct {
+		result1 []broker.MissingDeployment
+		result2 error
+	}
+	missingDeploymentsReturnsOnCall map[int]struct {
+		result1 []broker.MissingDeployment
+		result2 error
+	}
+	RecreateMissingDeploymentStub        func(ctx context.Context, instanceID string, logger *log.Logger) (broker.OperationData, error)
+	recreateMissingDeploymentMutex       sync.RWMutex
+	recreateMissingDeploymentArgsForCall []struct {
+		ctx        context.Context
+		instanceID string
+		logger     *log.Logger
+	}
+	recreateMissingDeploymentReturns struct {
+		result1 broker.OperationData
+		result2 error
+	}
+	recreateMissingDeploymentReturnsOnCall map[int]struct {
+		result1 broker.OperationData
+		result2 error
+	}
+	UpgradeStub        func(ctx context.Context, instanceID string, options broker.UpgradeOptions, logger *log.Logger) (broker.OperationData, error)
 	upgradeMutex       sync.RWMutex
 	upgradeArgsForCall []struct {
 		ctx        context.Context
 		instanceID string
+		options    broker.UpgradeOptions
 		logger     *log.Logger
 	}
 	upgradeReturns struct {
@@ -52,6 +100,21 @@ type FakeManageableBroker struct {
 		result1 broker.OperationData
 		result2 error
 	}
+	RollbackStub        func(ctx context.Context, instanceID string, logger *log.Logger) (broker.OperationData, error)
+	rollbackMutex       sync.RWMutex
+	rollbackArgsForCall []struct {
+		ctx        context.Context
+		instanceID string
+		logger     *log.Logger
+	}
+	rollbackReturns struct {
+		result1 broker.OperationData
+		result2 error
+	}
+	rollbackReturnsOnCall map[int]struct {
+		result1 broker.OperationData
+		result2 error
+	}
 	CountInstancesOfPlansStub        func(logger *log.Logger) (map[string]int, error)
 	countInstancesOfPlansMutex       sync.RWMutex
 	countInstancesOfPlansArgsForCall []struct {
@@ -65,6 +128,160 @@ type FakeManageableBroker struct {
 		result1 map[string]int
 		result2 error
 	}
+	ChangeInstanceStateStub        func(ctx context.Context, instanceID string, operationType broker.OperationType, logger *log.Logger) (broker.OperationData, error)
+	changeInstanceStateMutex       sync.RWMutex
+	changeInstanceStateArgsForCall []struct {
+		ctx           context.Context
+		instanceID    string
+		operationType broker.OperationType
+		logger        *log.Logger
+	}
+	changeInstanceStateReturns struct {
+		result1 broker.OperationData
+		result2 error
+	}
+	changeInstanceStateReturnsOnCall map[int]struct {
+		result1 broker.OperationData
+		result2 error
+	}
+	InstanceTasksStub        func(instanceID string, logger *log.Logger) (boshdirector.BoshTasks, error)
+	instanceTasksMutex       sync.RWMutex
+	instanceTasksArgsForCall []struct {
+		instanceID string
+		logger     *log.Logger
+	}
+	instanceTasksReturns struct {
+		result1 boshdirector.BoshTasks
+		result2 error
+	}
+	instanceTasksReturnsOnCall map[int]struct {
+		result1 boshdirector.BoshTasks
+		result2 error
+	}
+	InstanceTaskStub        func(instanceID string, taskID int, logger *log.Logger) (boshdirector.BoshTask, error)
+	instanceTaskMutex       sync.RWMutex
+	instanceTaskArgsForCall []struct {
+		instanceID string
+		taskID     int
+		logger     *log.Logger
+	}
+	instanceTaskReturns struct {
+		result1 boshdirector.BoshTask
+		result2 error
+	}
+	instanceTaskReturnsOnCall map[int]struct {
+		result1 boshdirector.BoshTask
+		result2 error
+	}
+	StreamTaskOutputStub        func(taskID int, outputType string, writer io.Writer, logger *log.Logger) error
+	streamTaskOutputMutex       sync.RWMutex
+	streamTaskOutputArgsForCall []struct {
+		taskID     int
+		outputType string
+		writer     io.Writer
+		logger     *log.Logger
+	}
+	streamTaskOutputReturns struct {
+		result1 error
+	}
+	streamTaskOutputReturnsOnCall map[int]struct {
+		result1 error
+	}
+	BackupStub        func(ctx context.Context, instanceID string, logger *log.Logger) (broker.OperationData, error)
+	backupMutex       sync.RWMutex
+	backupArgsForCall []struct {
+		ctx        context.Context
+		instanceID string
+		logger     *log.Logger
+	}
+	backupReturns struct {
+		result1 broker.OperationData
+		result2 error
+	}
+	backupReturnsOnCall map[int]struct {
+		result1 broker.OperationData
+		result2 error
+	}
+	RestoreStub        func(ctx context.Context, instanceID string, logger *log.Logger) (broker.OperationData, error)
+	restoreMutex       sync.RWMutex
+	restoreArgsForCall []struct {
+		ctx        context.Context
+		instanceID string
+		logger     *log.Logger
+	}
+	restoreReturns struct {
+		result1 broker.OperationData
+		result2 error
+	}
+	restoreReturnsOnCall map[int]struct {
+		result1 broker.OperationData
+		result2 error
+	}
+	BackupsStub        func(instanceID string, logger *log.Logger) ([]broker.Backup, error)
+	backupsMutex       sync.RWMutex
+	backupsArgsForCall []struct {
+		instanceID string
+		logger     *log.Logger
+	}
+	backupsReturns struct {
+		result1 []broker.Backup
+		result2 error
+	}
+	backupsReturnsOnCall map[int]struct {
+		result1 []broker.Backup
+		result2 error
+	}
+	MaintenanceWindowStub        func(instanceID string, logger *log.Logger) (*config.MaintenanceWindow, error)
+	maintenanceWindowMutex       sync.RWMutex
+	maintenanceWindowArgsForCall []struct {
+		instanceID string
+		logger     *log.Logger
+	}
+	maintenanceWindowReturns struct {
+		result1 *config.MaintenanceWindow
+		result2 error
+	}
+	maintenanceWindowReturnsOnCall map[int]struct {
+		result1 *config.MaintenanceWindow
+		result2 error
+	}
+	HealthStub        func() error
+	healthMutex       sync.RWMutex
+	healthArgsForCall []struct{}
+	healthReturns     struct {
+		result1 error
+	}
+	healthReturnsOnCall map[int]struct {
+		result1 error
+	}
+	AdapterCapabilitiesStub        func(logger *log.Logger) (serviceadapter.Capabilities, error)
+	adapterCapabilitiesMutex       sync.RWMutex
+	adapterCapabilitiesArgsForCall []struct {
+		logger *log.Logger
+	}
+	adapterCapabilitiesReturns struct {
+		result1 serviceadapter.Capabilities
+		result2 error
+	}
+	adapterCapabilitiesReturnsOnCall map[int]struct {
+		result1 serviceadapter.Capabilities
+		result2 error
+	}
+	InstanceStatusStub        func(ctx context.Context, instanceID string, logger *log.Logger) (serviceadapter.InstanceStatus, error)
+	instanceStatusMutex       sync.RWMutex
+	instanceStatusArgsForCall []struct {
+		ctx        context.Context
+		instanceID string
+		logger     *log.Logger
+	}
+	instanceStatusReturns struct {
+		result1 serviceadapter.InstanceStatus
+		result2 error
+	}
+	instanceStatusReturnsOnCall map[int]struct {
+		result1 serviceadapter.InstanceStatus
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -171,18 +388,176 @@ func (fake *FakeManageableBroker) OrphanDeploymentsReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
-func (fake *FakeManageableBroker) Upgrade(ctx context.Context, instanceID string, logger *log.Logger) (broker.OperationData, error) {
+func (fake *FakeManageableBroker) DeleteOrphanDeployment(deploymentName string, options broker.OrphanDeletionOptions, logger *log.Logger) (broker.OrphanDeletionResult, error) {
+	fake.deleteOrphanDeploymentMutex.Lock()
+	ret, specificReturn := fake.deleteOrphanDeploymentReturnsOnCall[len(fake.deleteOrphanDeploymentArgsForCall)]
+	fake.deleteOrphanDeploymentArgsForCall = append(fake.deleteOrphanDeploymentArgsForCall, struct {
+		deploymentName string
+		options        broker.OrphanDeletionOptions
+		logger         *log.Logger
+	}{deploymentName, options, logger})
+	fake.recordInvocation("DeleteOrphanDeployment", []interface{}{deploymentName, options, logger})
+	fake.deleteOrphanDeploymentMutex.Unlock()
+	if fake.DeleteOrphanDeploymentStub != nil {
+		return fake.DeleteOrphanDeploymentStub(deploymentName, options, logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.deleteOrphanDeploymentReturns.result1, fake.deleteOrphanDeploymentReturns.result2
+}
+
+func (fake *FakeManageableBroker) DeleteOrphanDeploymentCallCount() int {
+	fake.deleteOrphanDeploymentMutex.RLock()
+	defer fake.deleteOrphanDeploymentMutex.RUnlock()
+	return len(fake.deleteOrphanDeploymentArgsForCall)
+}
+
+func (fake *FakeManageableBroker) DeleteOrphanDeploymentArgsForCall(i int) (string, broker.OrphanDeletionOptions, *log.Logger) {
+	fake.deleteOrphanDeploymentMutex.RLock()
+	defer fake.deleteOrphanDeploymentMutex.RUnlock()
+	return fake.deleteOrphanDeploymentArgsForCall[i].deploymentName, fake.deleteOrphanDeploymentArgsForCall[i].options, fake.deleteOrphanDeploymentArgsForCall[i].logger
+}
+
+func (fake *FakeManageableBroker) DeleteOrphanDeploymentReturns(result1 broker.OrphanDeletionResult, result2 error) {
+	fake.DeleteOrphanDeploymentStub = nil
+	fake.deleteOrphanDeploymentReturns = struct {
+		result1 broker.OrphanDeletionResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) DeleteOrphanDeploymentReturnsOnCall(i int, result1 broker.OrphanDeletionResult, result2 error) {
+	fake.DeleteOrphanDeploymentStub = nil
+	if fake.deleteOrphanDeploymentReturnsOnCall == nil {
+		fake.deleteOrphanDeploymentReturnsOnCall = make(map[int]struct {
+			result1 broker.OrphanDeletionResult
+			result2 error
+		})
+	}
+	fake.deleteOrphanDeploymentReturnsOnCall[i] = struct {
+		result1 broker.OrphanDeletionResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) MissingDeployments(logger *log.Logger) ([]broker.MissingDeployment, error) {
+	fake.missingDeploymentsMutex.Lock()
+	ret, specificReturn := fake.missingDeploymentsReturnsOnCall[len(fake.missingDeploymentsArgsForCall)]
+	fake.missingDeploymentsArgsForCall = append(fake.missingDeploymentsArgsForCall, struct {
+		logger *log.Logger
+	}{logger})
+	fake.recordInvocation("MissingDeployments", []interface{}{logger})
+	fake.missingDeploymentsMutex.Unlock()
+	if fake.MissingDeploymentsStub != nil {
+		return fake.MissingDeploymentsStub(logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.missingDeploymentsReturns.result1, fake.missingDeploymentsReturns.result2
+}
+
+func (fake *FakeManageableBroker) MissingDeploymentsCallCount() int {
+	fake.missingDeploymentsMutex.RLock()
+	defer fake.missingDeploymentsMutex.RUnlock()
+	return len(fake.missingDeploymentsArgsForCall)
+}
+
+func (fake *FakeManageableBroker) MissingDeploymentsArgsForCall(i int) *log.Logger {
+	fake.missingDeploymentsMutex.RLock()
+	defer fake.missingDeploymentsMutex.RUnlock()
+	return fake.missingDeploymentsArgsForCall[i].logger
+}
+
+func (fake *FakeManageableBroker) MissingDeploymentsReturns(result1 []broker.MissingDeployment, result2 error) {
+	fake.MissingDeploymentsStub = nil
+	fake.missingDeploymentsReturns = struct {
+		result1 []broker.MissingDeployment
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) MissingDeploymentsReturnsOnCall(i int, result1 []broker.MissingDeployment, result2 error) {
+	fake.MissingDeploymentsStub = nil
+	if fake.missingDeploymentsReturnsOnCall == nil {
+		fake.missingDeploymentsReturnsOnCall = make(map[int]struct {
+			result1 []broker.MissingDeployment
+			result2 error
+		})
+	}
+	fake.missingDeploymentsReturnsOnCall[i] = struct {
+		result1 []broker.MissingDeployment
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) RecreateMissingDeployment(ctx context.Context, instanceID string, logger *log.Logger) (broker.OperationData, error) {
+	fake.recreateMissingDeploymentMutex.Lock()
+	ret, specificReturn := fake.recreateMissingDeploymentReturnsOnCall[len(fake.recreateMissingDeploymentArgsForCall)]
+	fake.recreateMissingDeploymentArgsForCall = append(fake.recreateMissingDeploymentArgsForCall, struct {
+		ctx        context.Context
+		instanceID string
+		logger     *log.Logger
+	}{ctx, instanceID, logger})
+	fake.recordInvocation("RecreateMissingDeployment", []interface{}{ctx, instanceID, logger})
+	fake.recreateMissingDeploymentMutex.Unlock()
+	if fake.RecreateMissingDeploymentStub != nil {
+		return fake.RecreateMissingDeploymentStub(ctx, instanceID, logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.recreateMissingDeploymentReturns.result1, fake.recreateMissingDeploymentReturns.result2
+}
+
+func (fake *FakeManageableBroker) RecreateMissingDeploymentCallCount() int {
+	fake.recreateMissingDeploymentMutex.RLock()
+	defer fake.recreateMissingDeploymentMutex.RUnlock()
+	return len(fake.recreateMissingDeploymentArgsForCall)
+}
+
+func (fake *FakeManageableBroker) RecreateMissingDeploymentArgsForCall(i int) (context.Context, string, *log.Logger) {
+	fake.recreateMissingDeploymentMutex.RLock()
+	defer fake.recreateMissingDeploymentMutex.RUnlock()
+	return fake.recreateMissingDeploymentArgsForCall[i].ctx, fake.recreateMissingDeploymentArgsForCall[i].instanceID, fake.recreateMissingDeploymentArgsForCall[i].logger
+}
+
+func (fake *FakeManageableBroker) RecreateMissingDeploymentReturns(result1 broker.OperationData, result2 error) {
+	fake.RecreateMissingDeploymentStub = nil
+	fake.recreateMissingDeploymentReturns = struct {
+		result1 broker.OperationData
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) RecreateMissingDeploymentReturnsOnCall(i int, result1 broker.OperationData, result2 error) {
+	fake.RecreateMissingDeploymentStub = nil
+	if fake.recreateMissingDeploymentReturnsOnCall == nil {
+		fake.recreateMissingDeploymentReturnsOnCall = make(map[int]struct {
+			result1 broker.OperationData
+			result2 error
+		})
+	}
+	fake.recreateMissingDeploymentReturnsOnCall[i] = struct {
+		result1 broker.OperationData
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) Upgrade(ctx context.Context, instanceID string, options broker.UpgradeOptions, logger *log.Logger) (broker.OperationData, error) {
 	fake.upgradeMutex.Lock()
 	ret, specificReturn := fake.upgradeReturnsOnCall[len(fake.upgradeArgsForCall)]
 	fake.upgradeArgsForCall = append(fake.upgradeArgsForCall, struct {
 		ctx        context.Context
 		instanceID string
+		options    broker.UpgradeOptions
 		logger     *log.Logger
-	}{ctx, instanceID, logger})
-	fake.recordInvocation("Upgrade", []interface{}{ctx, instanceID, logger})
+	}{ctx, instanceID, options, logger})
+	fake.recordInvocation("Upgrade", []interface{}{ctx, instanceID, options, logger})
 	fake.upgradeMutex.Unlock()
 	if fake.UpgradeStub != nil {
-		return fake.UpgradeStub(ctx, instanceID, logger)
+		return fake.UpgradeStub(ctx, instanceID, options, logger)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -196,10 +571,10 @@ func (fake *FakeManageableBroker) UpgradeCallCount() int {
 	return len(fake.upgradeArgsForCall)
 }
 
-func (fake *FakeManageableBroker) UpgradeArgsForCall(i int) (context.Context, string, *log.Logger) {
+func (fake *FakeManageableBroker) UpgradeArgsForCall(i int) (context.Context, string, broker.UpgradeOptions, *log.Logger) {
 	fake.upgradeMutex.RLock()
 	defer fake.upgradeMutex.RUnlock()
-	return fake.upgradeArgsForCall[i].ctx, fake.upgradeArgsForCall[i].instanceID, fake.upgradeArgsForCall[i].logger
+	return fake.upgradeArgsForCall[i].ctx, fake.upgradeArgsForCall[i].instanceID, fake.upgradeArgsForCall[i].options, fake.upgradeArgsForCall[i].logger
 }
 
 func (fake *FakeManageableBroker) UpgradeReturns(result1 broker.OperationData, result2 error) {
@@ -224,6 +599,59 @@ func (fake *FakeManageableBroker) UpgradeReturnsOnCall(i int, result1 broker.Ope
 	}{result1, result2}
 }
 
+func (fake *FakeManageableBroker) Rollback(ctx context.Context, instanceID string, logger *log.Logger) (broker.OperationData, error) {
+	fake.rollbackMutex.Lock()
+	ret, specificReturn := fake.rollbackReturnsOnCall[len(fake.rollbackArgsForCall)]
+	fake.rollbackArgsForCall = append(fake.rollbackArgsForCall, struct {
+		ctx        context.Context
+		instanceID string
+		logger     *log.Logger
+	}{ctx, instanceID, logger})
+	fake.recordInvocation("Rollback", []interface{}{ctx, instanceID, logger})
+	fake.rollbackMutex.Unlock()
+	if fake.RollbackStub != nil {
+		return fake.RollbackStub(ctx, instanceID, logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.rollbackReturns.result1, fake.rollbackReturns.result2
+}
+
+func (fake *FakeManageableBroker) RollbackCallCount() int {
+	fake.rollbackMutex.RLock()
+	defer fake.rollbackMutex.RUnlock()
+	return len(fake.rollbackArgsForCall)
+}
+
+func (fake *FakeManageableBroker) RollbackArgsForCall(i int) (context.Context, string, *log.Logger) {
+	fake.rollbackMutex.RLock()
+	defer fake.rollbackMutex.RUnlock()
+	return fake.rollbackArgsForCall[i].ctx, fake.rollbackArgsForCall[i].instanceID, fake.rollbackArgsForCall[i].logger
+}
+
+func (fake *FakeManageableBroker) RollbackReturns(result1 broker.OperationData, result2 error) {
+	fake.RollbackStub = nil
+	fake.rollbackReturns = struct {
+		result1 broker.OperationData
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) RollbackReturnsOnCall(i int, result1 broker.OperationData, result2 error) {
+	fake.RollbackStub = nil
+	if fake.rollbackReturnsOnCall == nil {
+		fake.rollbackReturnsOnCall = make(map[int]struct {
+			result1 broker.OperationData
+			result2 error
+		})
+	}
+	fake.rollbackReturnsOnCall[i] = struct {
+		result1 broker.OperationData
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeManageableBroker) CountInstancesOfPlans(logger *log.Logger) (map[string]int, error) {
 	fake.countInstancesOfPlansMutex.Lock()
 	ret, specificReturn := fake.countInstancesOfPlansReturnsOnCall[len(fake.countInstancesOfPlansArgsForCall)]
@@ -275,6 +703,570 @@ func (fake *FakeManageableBroker) CountInstancesOfPlansReturnsOnCall(i int, resu
 	}{result1, result2}
 }
 
+func (fake *FakeManageableBroker) ChangeInstanceState(ctx context.Context, instanceID string, operationType broker.OperationType, logger *log.Logger) (broker.OperationData, error) {
+	fake.changeInstanceStateMutex.Lock()
+	ret, specificReturn := fake.changeInstanceStateReturnsOnCall[len(fake.changeInstanceStateArgsForCall)]
+	fake.changeInstanceStateArgsForCall = append(fake.changeInstanceStateArgsForCall, struct {
+		ctx           context.Context
+		instanceID    string
+		operationType broker.OperationType
+		logger        *log.Logger
+	}{ctx, instanceID, operationType, logger})
+	fake.recordInvocation("ChangeInstanceState", []interface{}{ctx, instanceID, operationType, logger})
+	fake.changeInstanceStateMutex.Unlock()
+	if fake.ChangeInstanceStateStub != nil {
+		return fake.ChangeInstanceStateStub(ctx, instanceID, operationType, logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.changeInstanceStateReturns.result1, fake.changeInstanceStateReturns.result2
+}
+
+func (fake *FakeManageableBroker) ChangeInstanceStateCallCount() int {
+	fake.changeInstanceStateMutex.RLock()
+	defer fake.changeInstanceStateMutex.RUnlock()
+	return len(fake.changeInstanceStateArgsForCall)
+}
+
+func (fake *FakeManageableBroker) ChangeInstanceStateArgsForCall(i int) (context.Context, string, broker.OperationType, *log.Logger) {
+	fake.changeInstanceStateMutex.RLock()
+	defer fake.changeInstanceStateMutex.RUnlock()
+	return fake.changeInstanceStateArgsForCall[i].ctx, fake.changeInstanceStateArgsForCall[i].instanceID, fake.changeInstanceStateArgsForCall[i].operationType, fake.changeInstanceStateArgsForCall[i].logger
+}
+
+func (fake *FakeManageableBroker) ChangeInstanceStateReturns(result1 broker.OperationData, result2 error) {
+	fake.ChangeInstanceStateStub = nil
+	fake.changeInstanceStateReturns = struct {
+		result1 broker.OperationData
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) ChangeInstanceStateReturnsOnCall(i int, result1 broker.OperationData, result2 error) {
+	fake.ChangeInstanceStateStub = nil
+	if fake.changeInstanceStateReturnsOnCall == nil {
+		fake.changeInstanceStateReturnsOnCall = make(map[int]struct {
+			result1 broker.OperationData
+			result2 error
+		})
+	}
+	fake.changeInstanceStateReturnsOnCall[i] = struct {
+		result1 broker.OperationData
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) InstanceTasks(instanceID string, logger *log.Logger) (boshdirector.BoshTasks, error) {
+	fake.instanceTasksMutex.Lock()
+	ret, specificReturn := fake.instanceTasksReturnsOnCall[len(fake.instanceTasksArgsForCall)]
+	fake.instanceTasksArgsForCall = append(fake.instanceTasksArgsForCall, struct {
+		instanceID string
+		logger     *log.Logger
+	}{instanceID, logger})
+	fake.recordInvocation("InstanceTasks", []interface{}{instanceID, logger})
+	fake.instanceTasksMutex.Unlock()
+	if fake.InstanceTasksStub != nil {
+		return fake.InstanceTasksStub(instanceID, logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.instanceTasksReturns.result1, fake.instanceTasksReturns.result2
+}
+
+func (fake *FakeManageableBroker) InstanceTasksCallCount() int {
+	fake.instanceTasksMutex.RLock()
+	defer fake.instanceTasksMutex.RUnlock()
+	return len(fake.instanceTasksArgsForCall)
+}
+
+func (fake *FakeManageableBroker) InstanceTasksArgsForCall(i int) (string, *log.Logger) {
+	fake.instanceTasksMutex.RLock()
+	defer fake.instanceTasksMutex.RUnlock()
+	return fake.instanceTasksArgsForCall[i].instanceID, fake.instanceTasksArgsForCall[i].logger
+}
+
+func (fake *FakeManageableBroker) InstanceTasksReturns(result1 boshdirector.BoshTasks, result2 error) {
+	fake.InstanceTasksStub = nil
+	fake.instanceTasksReturns = struct {
+		result1 boshdirector.BoshTasks
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) InstanceTasksReturnsOnCall(i int, result1 boshdirector.BoshTasks, result2 error) {
+	fake.InstanceTasksStub = nil
+	if fake.instanceTasksReturnsOnCall == nil {
+		fake.instanceTasksReturnsOnCall = make(map[int]struct {
+			result1 boshdirector.BoshTasks
+			result2 error
+		})
+	}
+	fake.instanceTasksReturnsOnCall[i] = struct {
+		result1 boshdirector.BoshTasks
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) InstanceTask(instanceID string, taskID int, logger *log.Logger) (boshdirector.BoshTask, error) {
+	fake.instanceTaskMutex.Lock()
+	ret, specificReturn := fake.instanceTaskReturnsOnCall[len(fake.instanceTaskArgsForCall)]
+	fake.instanceTaskArgsForCall = append(fake.instanceTaskArgsForCall, struct {
+		instanceID string
+		taskID     int
+		logger     *log.Logger
+	}{instanceID, taskID, logger})
+	fake.recordInvocation("InstanceTask", []interface{}{instanceID, taskID, logger})
+	fake.instanceTaskMutex.Unlock()
+	if fake.InstanceTaskStub != nil {
+		return fake.InstanceTaskStub(instanceID, taskID, logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.instanceTaskReturns.result1, fake.instanceTaskReturns.result2
+}
+
+func (fake *FakeManageableBroker) InstanceTaskCallCount() int {
+	fake.instanceTaskMutex.RLock()
+	defer fake.instanceTaskMutex.RUnlock()
+	return len(fake.instanceTaskArgsForCall)
+}
+
+func (fake *FakeManageableBroker) InstanceTaskArgsForCall(i int) (string, int, *log.Logger) {
+	fake.instanceTaskMutex.RLock()
+	defer fake.instanceTaskMutex.RUnlock()
+	return fake.instanceTaskArgsForCall[i].instanceID, fake.instanceTaskArgsForCall[i].taskID, fake.instanceTaskArgsForCall[i].logger
+}
+
+func (fake *FakeManageableBroker) InstanceTaskReturns(result1 boshdirector.BoshTask, result2 error) {
+	fake.InstanceTaskStub = nil
+	fake.instanceTaskReturns = struct {
+		result1 boshdirector.BoshTask
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) InstanceTaskReturnsOnCall(i int, result1 boshdirector.BoshTask, result2 error) {
+	fake.InstanceTaskStub = nil
+	if fake.instanceTaskReturnsOnCall == nil {
+		fake.instanceTaskReturnsOnCall = make(map[int]struct {
+			result1 boshdirector.BoshTask
+			result2 error
+		})
+	}
+	fake.instanceTaskReturnsOnCall[i] = struct {
+		result1 boshdirector.BoshTask
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) StreamTaskOutput(taskID int, outputType string, writer io.Writer, logger *log.Logger) error {
+	fake.streamTaskOutputMutex.Lock()
+	ret, specificReturn := fake.streamTaskOutputReturnsOnCall[len(fake.streamTaskOutputArgsForCall)]
+	fake.streamTaskOutputArgsForCall = append(fake.streamTaskOutputArgsForCall, struct {
+		taskID     int
+		outputType string
+		writer     io.Writer
+		logger     *log.Logger
+	}{taskID, outputType, writer, logger})
+	fake.recordInvocation("StreamTaskOutput", []interface{}{taskID, outputType, writer, logger})
+	fake.streamTaskOutputMutex.Unlock()
+	if fake.StreamTaskOutputStub != nil {
+		return fake.StreamTaskOutputStub(taskID, outputType, writer, logger)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.streamTaskOutputReturns.result1
+}
+
+func (fake *FakeManageableBroker) StreamTaskOutputCallCount() int {
+	fake.streamTaskOutputMutex.RLock()
+	defer fake.streamTaskOutputMutex.RUnlock()
+	return len(fake.streamTaskOutputArgsForCall)
+}
+
+func (fake *FakeManageableBroker) StreamTaskOutputArgsForCall(i int) (int, string, io.Writer, *log.Logger) {
+	fake.streamTaskOutputMutex.RLock()
+	defer fake.streamTaskOutputMutex.RUnlock()
+	return fake.streamTaskOutputArgsForCall[i].taskID, fake.streamTaskOutputArgsForCall[i].outputType, fake.streamTaskOutputArgsForCall[i].writer, fake.streamTaskOutputArgsForCall[i].logger
+}
+
+func (fake *FakeManageableBroker) StreamTaskOutputReturns(result1 error) {
+	fake.StreamTaskOutputStub = nil
+	fake.streamTaskOutputReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManageableBroker) StreamTaskOutputReturnsOnCall(i int, result1 error) {
+	fake.StreamTaskOutputStub = nil
+	if fake.streamTaskOutputReturnsOnCall == nil {
+		fake.streamTaskOutputReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.streamTaskOutputReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManageableBroker) Backup(ctx context.Context, instanceID string, logger *log.Logger) (broker.OperationData, error) {
+	fake.backupMutex.Lock()
+	ret, specificReturn := fake.backupReturnsOnCall[len(fake.backupArgsForCall)]
+	fake.backupArgsForCall = append(fake.backupArgsForCall, struct {
+		ctx        context.Context
+		instanceID string
+		logger     *log.Logger
+	}{ctx, instanceID, logger})
+	fake.recordInvocation("Backup", []interface{}{ctx, instanceID, logger})
+	fake.backupMutex.Unlock()
+	if fake.BackupStub != nil {
+		return fake.BackupStub(ctx, instanceID, logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.backupReturns.result1, fake.backupReturns.result2
+}
+
+func (fake *FakeManageableBroker) BackupCallCount() int {
+	fake.backupMutex.RLock()
+	defer fake.backupMutex.RUnlock()
+	return len(fake.backupArgsForCall)
+}
+
+func (fake *FakeManageableBroker) BackupArgsForCall(i int) (context.Context, string, *log.Logger) {
+	fake.backupMutex.RLock()
+	defer fake.backupMutex.RUnlock()
+	return fake.backupArgsForCall[i].ctx, fake.backupArgsForCall[i].instanceID, fake.backupArgsForCall[i].logger
+}
+
+func (fake *FakeManageableBroker) BackupReturns(result1 broker.OperationData, result2 error) {
+	fake.BackupStub = nil
+	fake.backupReturns = struct {
+		result1 broker.OperationData
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) BackupReturnsOnCall(i int, result1 broker.OperationData, result2 error) {
+	fake.BackupStub = nil
+	if fake.backupReturnsOnCall == nil {
+		fake.backupReturnsOnCall = make(map[int]struct {
+			result1 broker.OperationData
+			result2 error
+		})
+	}
+	fake.backupReturnsOnCall[i] = struct {
+		result1 broker.OperationData
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) Restore(ctx context.Context, instanceID string, logger *log.Logger) (broker.OperationData, error) {
+	fake.restoreMutex.Lock()
+	ret, specificReturn := fake.restoreReturnsOnCall[len(fake.restoreArgsForCall)]
+	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct {
+		ctx        context.Context
+		instanceID string
+		logger     *log.Logger
+	}{ctx, instanceID, logger})
+	fake.recordInvocation("Restore", []interface{}{ctx, instanceID, logger})
+	fake.restoreMutex.Unlock()
+	if fake.RestoreStub != nil {
+		return fake.RestoreStub(ctx, instanceID, logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.restoreReturns.result1, fake.restoreReturns.result2
+}
+
+func (fake *FakeManageableBroker) RestoreCallCount() int {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return len(fake.restoreArgsForCall)
+}
+
+func (fake *FakeManageableBroker) RestoreArgsForCall(i int) (context.Context, string, *log.Logger) {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return fake.restoreArgsForCall[i].ctx, fake.restoreArgsForCall[i].instanceID, fake.restoreArgsForCall[i].logger
+}
+
+func (fake *FakeManageableBroker) RestoreReturns(result1 broker.OperationData, result2 error) {
+	fake.RestoreStub = nil
+	fake.restoreReturns = struct {
+		result1 broker.OperationData
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) RestoreReturnsOnCall(i int, result1 broker.OperationData, result2 error) {
+	fake.RestoreStub = nil
+	if fake.restoreReturnsOnCall == nil {
+		fake.restoreReturnsOnCall = make(map[int]struct {
+			result1 broker.OperationData
+			result2 error
+		})
+	}
+	fake.restoreReturnsOnCall[i] = struct {
+		result1 broker.OperationData
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) Backups(instanceID string, logger *log.Logger) ([]broker.Backup, error) {
+	fake.backupsMutex.Lock()
+	ret, specificReturn := fake.backupsReturnsOnCall[len(fake.backupsArgsForCall)]
+	fake.backupsArgsForCall = append(fake.backupsArgsForCall, struct {
+		instanceID string
+		logger     *log.Logger
+	}{instanceID, logger})
+	fake.recordInvocation("Backups", []interface{}{instanceID, logger})
+	fake.backupsMutex.Unlock()
+	if fake.BackupsStub != nil {
+		return fake.BackupsStub(instanceID, logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.backupsReturns.result1, fake.backupsReturns.result2
+}
+
+func (fake *FakeManageableBroker) BackupsCallCount() int {
+	fake.backupsMutex.RLock()
+	defer fake.backupsMutex.RUnlock()
+	return len(fake.backupsArgsForCall)
+}
+
+func (fake *FakeManageableBroker) BackupsArgsForCall(i int) (string, *log.Logger) {
+	fake.backupsMutex.RLock()
+	defer fake.backupsMutex.RUnlock()
+	return fake.backupsArgsForCall[i].instanceID, fake.backupsArgsForCall[i].logger
+}
+
+func (fake *FakeManageableBroker) BackupsReturns(result1 []broker.Backup, result2 error) {
+	fake.BackupsStub = nil
+	fake.backupsReturns = struct {
+		result1 []broker.Backup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) BackupsReturnsOnCall(i int, result1 []broker.Backup, result2 error) {
+	fake.BackupsStub = nil
+	if fake.backupsReturnsOnCall == nil {
+		fake.backupsReturnsOnCall = make(map[int]struct {
+			result1 []broker.Backup
+			result2 error
+		})
+	}
+	fake.backupsReturnsOnCall[i] = struct {
+		result1 []broker.Backup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) MaintenanceWindow(instanceID string, logger *log.Logger) (*config.MaintenanceWindow, error) {
+	fake.maintenanceWindowMutex.Lock()
+	ret, specificReturn := fake.maintenanceWindowReturnsOnCall[len(fake.maintenanceWindowArgsForCall)]
+	fake.maintenanceWindowArgsForCall = append(fake.maintenanceWindowArgsForCall, struct {
+		instanceID string
+		logger     *log.Logger
+	}{instanceID, logger})
+	fake.recordInvocation("MaintenanceWindow", []interface{}{instanceID, logger})
+	fake.maintenanceWindowMutex.Unlock()
+	if fake.MaintenanceWindowStub != nil {
+		return fake.MaintenanceWindowStub(instanceID, logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.maintenanceWindowReturns.result1, fake.maintenanceWindowReturns.result2
+}
+
+func (fake *FakeManageableBroker) MaintenanceWindowCallCount() int {
+	fake.maintenanceWindowMutex.RLock()
+	defer fake.maintenanceWindowMutex.RUnlock()
+	return len(fake.maintenanceWindowArgsForCall)
+}
+
+func (fake *FakeManageableBroker) MaintenanceWindowArgsForCall(i int) (string, *log.Logger) {
+	fake.maintenanceWindowMutex.RLock()
+	defer fake.maintenanceWindowMutex.RUnlock()
+	return fake.maintenanceWindowArgsForCall[i].instanceID, fake.maintenanceWindowArgsForCall[i].logger
+}
+
+func (fake *FakeManageableBroker) MaintenanceWindowReturns(result1 *config.MaintenanceWindow, result2 error) {
+	fake.MaintenanceWindowStub = nil
+	fake.maintenanceWindowReturns = struct {
+		result1 *config.MaintenanceWindow
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) MaintenanceWindowReturnsOnCall(i int, result1 *config.MaintenanceWindow, result2 error) {
+	fake.MaintenanceWindowStub = nil
+	if fake.maintenanceWindowReturnsOnCall == nil {
+		fake.maintenanceWindowReturnsOnCall = make(map[int]struct {
+			result1 *config.MaintenanceWindow
+			result2 error
+		})
+	}
+	fake.maintenanceWindowReturnsOnCall[i] = struct {
+		result1 *config.MaintenanceWindow
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) Health() error {
+	fake.healthMutex.Lock()
+	ret, specificReturn := fake.healthReturnsOnCall[len(fake.healthArgsForCall)]
+	fake.healthArgsForCall = append(fake.healthArgsForCall, struct{}{})
+	fake.recordInvocation("Health", []interface{}{})
+	fake.healthMutex.Unlock()
+	if fake.HealthStub != nil {
+		return fake.HealthStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.healthReturns.result1
+}
+
+func (fake *FakeManageableBroker) HealthCallCount() int {
+	fake.healthMutex.RLock()
+	defer fake.healthMutex.RUnlock()
+	return len(fake.healthArgsForCall)
+}
+
+func (fake *FakeManageableBroker) HealthReturns(result1 error) {
+	fake.HealthStub = nil
+	fake.healthReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManageableBroker) HealthReturnsOnCall(i int, result1 error) {
+	fake.HealthStub = nil
+	if fake.healthReturnsOnCall == nil {
+		fake.healthReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.healthReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeManageableBroker) AdapterCapabilities(logger *log.Logger) (serviceadapter.Capabilities, error) {
+	fake.adapterCapabilitiesMutex.Lock()
+	ret, specificReturn := fake.adapterCapabilitiesReturnsOnCall[len(fake.adapterCapabilitiesArgsForCall)]
+	fake.adapterCapabilitiesArgsForCall = append(fake.adapterCapabilitiesArgsForCall, struct {
+		logger *log.Logger
+	}{logger})
+	fake.recordInvocation("AdapterCapabilities", []interface{}{logger})
+	fake.adapterCapabilitiesMutex.Unlock()
+	if fake.AdapterCapabilitiesStub != nil {
+		return fake.AdapterCapabilitiesStub(logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.adapterCapabilitiesReturns.result1, fake.adapterCapabilitiesReturns.result2
+}
+
+func (fake *FakeManageableBroker) AdapterCapabilitiesCallCount() int {
+	fake.adapterCapabilitiesMutex.RLock()
+	defer fake.adapterCapabilitiesMutex.RUnlock()
+	return len(fake.adapterCapabilitiesArgsForCall)
+}
+
+func (fake *FakeManageableBroker) AdapterCapabilitiesArgsForCall(i int) *log.Logger {
+	fake.adapterCapabilitiesMutex.RLock()
+	defer fake.adapterCapabilitiesMutex.RUnlock()
+	return fake.adapterCapabilitiesArgsForCall[i].logger
+}
+
+func (fake *FakeManageableBroker) AdapterCapabilitiesReturns(result1 serviceadapter.Capabilities, result2 error) {
+	fake.AdapterCapabilitiesStub = nil
+	fake.adapterCapabilitiesReturns = struct {
+		result1 serviceadapter.Capabilities
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) AdapterCapabilitiesReturnsOnCall(i int, result1 serviceadapter.Capabilities, result2 error) {
+	fake.AdapterCapabilitiesStub = nil
+	if fake.adapterCapabilitiesReturnsOnCall == nil {
+		fake.adapterCapabilitiesReturnsOnCall = make(map[int]struct {
+			result1 serviceadapter.Capabilities
+			result2 error
+		})
+	}
+	fake.adapterCapabilitiesReturnsOnCall[i] = struct {
+		result1 serviceadapter.Capabilities
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) InstanceStatus(ctx context.Context, instanceID string, logger *log.Logger) (serviceadapter.InstanceStatus, error) {
+	fake.instanceStatusMutex.Lock()
+	ret, specificReturn := fake.instanceStatusReturnsOnCall[len(fake.instanceStatusArgsForCall)]
+	fake.instanceStatusArgsForCall = append(fake.instanceStatusArgsForCall, struct {
+		ctx        context.Context
+		instanceID string
+		logger     *log.Logger
+	}{ctx, instanceID, logger})
+	fake.recordInvocation("InstanceStatus", []interface{}{ctx, instanceID, logger})
+	fake.instanceStatusMutex.Unlock()
+	if fake.InstanceStatusStub != nil {
+		return fake.InstanceStatusStub(ctx, instanceID, logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.instanceStatusReturns.result1, fake.instanceStatusReturns.result2
+}
+
+func (fake *FakeManageableBroker) InstanceStatusCallCount() int {
+	fake.instanceStatusMutex.RLock()
+	defer fake.instanceStatusMutex.RUnlock()
+	return len(fake.instanceStatusArgsForCall)
+}
+
+func (fake *FakeManageableBroker) InstanceStatusArgsForCall(i int) (context.Context, string, *log.Logger) {
+	fake.instanceStatusMutex.RLock()
+	defer fake.instanceStatusMutex.RUnlock()
+	return fake.instanceStatusArgsForCall[i].ctx, fake.instanceStatusArgsForCall[i].instanceID, fake.instanceStatusArgsForCall[i].logger
+}
+
+func (fake *FakeManageableBroker) InstanceStatusReturns(result1 serviceadapter.InstanceStatus, result2 error) {
+	fake.InstanceStatusStub = nil
+	fake.instanceStatusReturns = struct {
+		result1 serviceadapter.InstanceStatus
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeManageableBroker) InstanceStatusReturnsOnCall(i int, result1 serviceadapter.InstanceStatus, result2 error) {
+	fake.InstanceStatusStub = nil
+	if fake.instanceStatusReturnsOnCall == nil {
+		fake.instanceStatusReturnsOnCall = make(map[int]struct {
+			result1 serviceadapter.InstanceStatus
+			result2 error
+		})
+	}
+	fake.instanceStatusReturnsOnCall[i] = struct {
+		result1 serviceadapter.InstanceStatus
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeManageableBroker) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -282,10 +1274,40 @@ func (fake *FakeManageableBroker) Invocations() map[string][][]interface{} {
 	defer fake.instancesMutex.RUnlock()
 	fake.orphanDeploymentsMutex.RLock()
 	defer fake.orphanDeploymentsMutex.RUnlock()
+	fake.deleteOrphanDeploymentMutex.RLock()
+	defer fake.deleteOrphanDeploymentMutex.RUnlock()
+	fake.missingDeploymentsMutex.RLock()
+	defer fake.missingDeploymentsMutex.RUnlock()
+	fake.recreateMissingDeploymentMutex.RLock()
+	defer fake.recreateMissingDeploymentMutex.RUnlock()
 	fake.upgradeMutex.RLock()
 	defer fake.upgradeMutex.RUnlock()
+	fake.rollbackMutex.RLock()
+	defer fake.rollbackMutex.RUnlock()
 	fake.countInstancesOfPlansMutex.RLock()
 	defer fake.countInstancesOfPlansMutex.RUnlock()
+	fake.changeInstanceStateMutex.RLock()
+	defer fake.changeInstanceStateMutex.RUnlock()
+	fake.instanceTasksMutex.RLock()
+	defer fake.instanceTasksMutex.RUnlock()
+	fake.instanceTaskMutex.RLock()
+	defer fake.instanceTaskMutex.RUnlock()
+	fake.streamTaskOutputMutex.RLock()
+	defer fake.streamTaskOutputMutex.RUnlock()
+	fake.backupMutex.RLock()
+	defer fake.backupMutex.RUnlock()
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	fake.backupsMutex.RLock()
+	defer fake.backupsMutex.RUnlock()
+	fake.maintenanceWindowMutex.RLock()
+	defer fake.maintenanceWindowMutex.RUnlock()
+	fake.healthMutex.RLock()
+	defer fake.healthMutex.RUnlock()
+	fake.adapterCapabilitiesMutex.RLock()
+	defer fake.adapterCapabilitiesMutex.RUnlock()
+	fake.instanceStatusMutex.RLock()
+	defer fake.instanceStatusMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -0,0 +1,95 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"log"
+	"sync"
+
+	"github.com/pivotal-cf/on-demand-service-broker/mgmtapi"
+)
+
+type FakeConfigReloader struct {
+	ReloadStub        func(logger *log.Logger) error
+	reloadMutex       sync.RWMutex
+	reloadArgsForCall []struct {
+		logger *log.Logger
+	}
+	reloadReturns struct {
+		result1 error
+	}
+	reloadReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeConfigReloader) Reload(logger *log.Logger) error {
+	fake.reloadMutex.Lock()
+	ret, specificReturn := fake.reloadReturnsOnCall[len(fake.reloadArgsForCall)]
+	fake.reloadArgsForCall = append(fake.reloadArgsForCall, struct {
+		logger *log.Logger
+	}{logger})
+	fake.recordInvocation("Reload", []interface{}{logger})
+	fake.reloadMutex.Unlock()
+	if fake.ReloadStub != nil {
+		return fake.ReloadStub(logger)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.reloadReturns.result1
+}
+
+func (fake *FakeConfigReloader) ReloadCallCount() int {
+	fake.reloadMutex.RLock()
+	defer fake.reloadMutex.RUnlock()
+	return len(fake.reloadArgsForCall)
+}
+
+func (fake *FakeConfigReloader) ReloadArgsForCall(i int) *log.Logger {
+	fake.reloadMutex.RLock()
+	defer fake.reloadMutex.RUnlock()
+	return fake.reloadArgsForCall[i].logger
+}
+
+func (fake *FakeConfigReloader) ReloadReturns(result1 error) {
+	fake.ReloadStub = nil
+	fake.reloadReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConfigReloader) ReloadReturnsOnCall(i int, result1 error) {
+	fake.ReloadStub = nil
+	if fake.reloadReturnsOnCall == nil {
+		fake.reloadReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.reloadReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConfigReloader) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.reloadMutex.RLock()
+	defer fake.reloadMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeConfigReloader) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ mgmtapi.ConfigReloader = new(FakeConfigReloader)
@@ -67,6 +67,18 @@ func (i *Handler) RespondsForbiddenWith(body string) *Handler {
 	return i
 }
 
+func (i *Handler) RespondsConflictWith(body string) *Handler {
+	i.responseBody = body
+	i.responseStatus = http.StatusConflict
+	return i
+}
+
+func (i *Handler) RespondsUnprocessableEntityWith(body string) *Handler {
+	i.responseBody = body
+	i.responseStatus = http.StatusUnprocessableEntity
+	return i
+}
+
 func (i *Handler) RespondsOKWithJSON(obj interface{}) *Handler {
 	data, err := json.Marshal(obj)
 	Expect(err).NotTo(HaveOccurred())
@@ -0,0 +1,52 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package mockadapter
+
+import (
+	"github.com/pivotal-cf/on-demand-service-broker/mockhttp"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
+)
+
+func New() *mockhttp.Server {
+	return mockhttp.StartServer("mock-service-adapter")
+}
+
+type subcommandMock struct {
+	*mockhttp.Handler
+}
+
+func Capabilities() *subcommandMock {
+	return &subcommandMock{mockhttp.NewMockedHttpRequest("POST", "/capabilities")}
+}
+
+func ValidateParams() *subcommandMock {
+	return &subcommandMock{mockhttp.NewMockedHttpRequest("POST", "/validate-params")}
+}
+
+func GenerateManifest() *subcommandMock {
+	return &subcommandMock{mockhttp.NewMockedHttpRequest("POST", "/generate-manifest")}
+}
+
+func CreateBinding() *subcommandMock {
+	return &subcommandMock{mockhttp.NewMockedHttpRequest("POST", "/create-binding")}
+}
+
+func DeleteBinding() *subcommandMock {
+	return &subcommandMock{mockhttp.NewMockedHttpRequest("POST", "/delete-binding")}
+}
+
+func DashboardURL() *subcommandMock {
+	return &subcommandMock{mockhttp.NewMockedHttpRequest("POST", "/dashboard-url")}
+}
+
+func (m *subcommandMock) RespondsWith(stdout, stderr string, exitCode int) *mockhttp.Handler {
+	return m.RespondsOKWithJSON(serviceadapter.HTTPResponse{Stdout: stdout, Stderr: stderr, ExitCode: exitCode})
+}
+
+func (m *subcommandMock) Succeeds(stdout string) *mockhttp.Handler {
+	return m.RespondsWith(stdout, "", 0)
+}
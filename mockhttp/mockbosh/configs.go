@@ -0,0 +1,40 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package mockbosh
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/pivotal-cf/on-demand-service-broker/mockhttp"
+)
+
+type configsMock struct {
+	*mockhttp.Handler
+}
+
+func Config(configType, name string) *configsMock {
+	query := url.Values{"type": {configType}, "name": {name}, "latest": {"true"}}
+	return &configsMock{
+		Handler: mockhttp.NewMockedHttpRequest("GET", fmt.Sprintf("/configs?%s", query.Encode())),
+	}
+}
+
+func (c *configsMock) RespondsWithContent(content []byte) *mockhttp.Handler {
+	return c.RespondsOKWithJSON([]map[string]string{{"content": string(content)}})
+}
+
+func UpdateConfig(configType, name string, content []byte) *mockhttp.Handler {
+	return mockhttp.NewMockedHttpRequest("POST", "/configs").
+		WithContentType("application/json").
+		WithJSONBody(map[string]interface{}{"type": configType, "name": name, "content": string(content)})
+}
+
+func DeleteConfig(configType, name string) *mockhttp.Handler {
+	query := url.Values{"type": {configType}, "name": {name}}
+	return mockhttp.NewMockedHttpRequest("DELETE", fmt.Sprintf("/configs?%s", query.Encode()))
+}
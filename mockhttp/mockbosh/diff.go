@@ -7,6 +7,8 @@
 package mockbosh
 
 import (
+	"fmt"
+
 	"github.com/pivotal-cf/on-demand-service-broker/mockhttp"
 	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
 )
@@ -19,11 +21,24 @@ type DeploymentDiff struct {
 	Diff [][]interface{} `json:"diff"`
 }
 
+func Diff(deploymentName string) *diffMock {
+	mock := &diffMock{
+		Handler: mockhttp.NewMockedHttpRequest("POST", fmt.Sprintf("/deployments/%s/diff", deploymentName)),
+	}
+	mock.WithContentType("text/yaml")
+	return mock
+}
+
 func (d *diffMock) WithManifest(manifest bosh.BoshManifest) *diffMock {
 	d.WithBody(toYaml(manifest))
 	return d
 }
 
+func (d *diffMock) WithRawManifest(manifest []byte) *diffMock {
+	d.WithBody(string(manifest))
+	return d
+}
+
 func (d *diffMock) RespondsWithNoDiff() *mockhttp.Handler {
 	return d.RespondsOKWithJSON(nil)
 }
@@ -31,3 +31,11 @@ func (t *taskMock) RespondsWithTaskContainingState(provisioningTaskState string)
 		State: provisioningTaskState,
 	})
 }
+
+func (t *taskMock) RespondsWithTaskForDeployment(deploymentName string) *mockhttp.Handler {
+	return t.RespondsOKWithJSON(boshdirector.BoshTask{
+		ID:         t.taskID,
+		State:      boshdirector.TaskDone,
+		Deployment: deploymentName,
+	})
+}
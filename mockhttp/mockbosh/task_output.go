@@ -26,6 +26,12 @@ func TaskOutput(taskId int) *taskOutputMock {
 	}
 }
 
+func TaskOutputOfType(taskId int, outputType string) *taskOutputMock {
+	return &taskOutputMock{
+		Handler: mockhttp.NewMockedHttpRequest("GET", fmt.Sprintf("/tasks/%d/output?type=%s", taskId, outputType)),
+	}
+}
+
 func (t *taskOutputMock) RespondsWithVMsOutput(vms []boshdirector.BoshVMsOutput) *mockhttp.Handler {
 	output := bytes.NewBuffer([]byte{})
 	encoder := json.NewEncoder(output)
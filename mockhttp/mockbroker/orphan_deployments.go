@@ -6,8 +6,16 @@
 
 package mockbroker
 
-import "github.com/pivotal-cf/on-demand-service-broker/mockhttp"
+import (
+	"fmt"
+
+	"github.com/pivotal-cf/on-demand-service-broker/mockhttp"
+)
 
 func OrphanDeployments() *mockhttp.Handler {
 	return mockhttp.NewMockedHttpRequest("GET", "/mgmt/orphan_deployments")
 }
+
+func DeleteOrphanDeployment(deploymentName, encodedQuery string) *mockhttp.Handler {
+	return mockhttp.NewMockedHttpRequest("DELETE", fmt.Sprintf("/mgmt/orphan_deployments/%s?%s", deploymentName, encodedQuery))
+}
@@ -45,8 +45,8 @@ func (b *BasicAuthHTTPClient) Get(path string, query map[string]string) (*http.R
 	return b.do(request)
 }
 
-func (b *BasicAuthHTTPClient) Patch(path string) (*http.Response, error) {
-	u, err := b.buildURL(path, nil)
+func (b *BasicAuthHTTPClient) Patch(path string, query map[string]string) (*http.Response, error) {
+	u, err := b.buildURL(path, query)
 	if err != nil {
 		return nil, err
 	}
@@ -59,6 +59,30 @@ func (b *BasicAuthHTTPClient) Patch(path string) (*http.Response, error) {
 	return b.do(request)
 }
 
+func (b *BasicAuthHTTPClient) Post(path string) (*http.Response, error) {
+	u, err := b.buildURL(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequest("POST", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	return b.do(request)
+}
+
+func (b *BasicAuthHTTPClient) Delete(path string, query map[string]string) (*http.Response, error) {
+	u, err := b.buildURL(path, query)
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	return b.do(request)
+}
+
 func (b *BasicAuthHTTPClient) buildURL(path string, query map[string]string) (string, error) {
 	base := b.baseURL
 	if strings.HasSuffix(b.baseURL, "/") {
@@ -100,7 +100,7 @@ var _ = Describe("Basic Auth HTTP Client", func() {
 			doer := new(fakes.FakeDoer)
 			client := network.NewBasicAuthHTTPClient(doer, username, password, baseURL)
 
-			_, err := client.Patch("path/to/resource")
+			_, err := client.Patch("path/to/resource", nil)
 
 			Expect(err).NotTo(HaveOccurred())
 			actualRequest := doer.DoArgsForCall(0)
@@ -108,11 +108,21 @@ var _ = Describe("Basic Auth HTTP Client", func() {
 			Expect(actualRequest.URL.String()).To(Equal("http://example.com:8080/path/to/resource"))
 		})
 
+		It("sets the query", func() {
+			doer := new(fakes.FakeDoer)
+			client := network.NewBasicAuthHTTPClient(doer, username, password, baseURL)
+
+			_, err := client.Patch("path/to/resource", map[string]string{"param": "value"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(doer.DoArgsForCall(0).URL.String()).To(Equal("http://example.com:8080/path/to/resource?param=value"))
+		})
+
 		It("sets basic auth", func() {
 			doer := new(fakes.FakeDoer)
 			client := network.NewBasicAuthHTTPClient(doer, username, password, baseURL)
 
-			_, err := client.Patch("path/to/resource")
+			_, err := client.Patch("path/to/resource", nil)
 
 			Expect(err).NotTo(HaveOccurred())
 			actualUsername, actualPassword, ok := doer.DoArgsForCall(0).BasicAuth()
@@ -124,7 +134,7 @@ var _ = Describe("Basic Auth HTTP Client", func() {
 		It("errors when the base URL is invalid", func() {
 			client := network.NewBasicAuthHTTPClient(nil, username, password, invalidURL)
 
-			_, err := client.Patch("path/to/resource")
+			_, err := client.Patch("path/to/resource", nil)
 
 			Expect(err).To(HaveOccurred())
 		})
@@ -132,7 +142,69 @@ var _ = Describe("Basic Auth HTTP Client", func() {
 		It("errors when the path is invalid", func() {
 			client := network.NewBasicAuthHTTPClient(nil, username, password, baseURL)
 
-			_, err := client.Patch(invalidPath)
+			_, err := client.Patch(invalidPath, nil)
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("POST", func() {
+		It("sets the URL", func() {
+			doer := new(fakes.FakeDoer)
+			client := network.NewBasicAuthHTTPClient(doer, username, password, baseURL)
+
+			_, err := client.Post("path/to/resource")
+
+			Expect(err).NotTo(HaveOccurred())
+			actualRequest := doer.DoArgsForCall(0)
+			Expect(actualRequest.Method).To(Equal("POST"))
+			Expect(actualRequest.URL.String()).To(Equal("http://example.com:8080/path/to/resource"))
+		})
+
+		It("sets basic auth", func() {
+			doer := new(fakes.FakeDoer)
+			client := network.NewBasicAuthHTTPClient(doer, username, password, baseURL)
+
+			_, err := client.Post("path/to/resource")
+
+			Expect(err).NotTo(HaveOccurred())
+			actualUsername, actualPassword, ok := doer.DoArgsForCall(0).BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(actualUsername).To(Equal(username))
+			Expect(actualPassword).To(Equal(password))
+		})
+	})
+
+	Describe("DELETE", func() {
+		It("sets the URL and query params", func() {
+			doer := new(fakes.FakeDoer)
+			client := network.NewBasicAuthHTTPClient(doer, username, password, baseURL)
+
+			_, err := client.Delete("path/to/resource", map[string]string{"param": "value"})
+
+			Expect(err).NotTo(HaveOccurred())
+			actualRequest := doer.DoArgsForCall(0)
+			Expect(actualRequest.Method).To(Equal("DELETE"))
+			Expect(actualRequest.URL.String()).To(Equal("http://example.com:8080/path/to/resource?param=value"))
+		})
+
+		It("sets basic auth", func() {
+			doer := new(fakes.FakeDoer)
+			client := network.NewBasicAuthHTTPClient(doer, username, password, baseURL)
+
+			_, err := client.Delete("path/to/resource", nil)
+
+			Expect(err).NotTo(HaveOccurred())
+			actualUsername, actualPassword, ok := doer.DoArgsForCall(0).BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(actualUsername).To(Equal(username))
+			Expect(actualPassword).To(Equal(password))
+		})
+
+		It("errors when the path is invalid", func() {
+			client := network.NewBasicAuthHTTPClient(nil, username, password, baseURL)
+
+			_, err := client.Delete(invalidPath, nil)
 
 			Expect(err).To(HaveOccurred())
 		})
@@ -0,0 +1,112 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package serviceadapter
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+)
+
+const CapabilitiesSubcommand = "capabilities"
+
+// MandatorySubcommands must be supported by any adapter that reports its subcommands.
+var MandatorySubcommands = []string{"generate-manifest", "create-binding", "delete-binding"}
+
+type Capabilities struct {
+	Subcommands []string `json:"subcommands,omitempty"`
+	SDKVersion  string   `json:"sdk_version,omitempty"`
+	Features    []string `json:"features,omitempty"`
+	InputModes  []string `json:"input_modes,omitempty"`
+}
+
+// SupportsSubcommand assumes adapters that don't report their subcommands support them all.
+func (c Capabilities) SupportsSubcommand(subcommand string) bool {
+	return len(c.Subcommands) == 0 || contains(c.Subcommands, subcommand)
+}
+
+func (c Capabilities) SupportsFeature(feature string) bool {
+	return contains(c.Features, feature)
+}
+
+func (c Capabilities) SupportsInputMode(mode string) bool {
+	return mode == InputModeArgv || contains(c.InputModes, mode)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Capabilities asks the adapter what it supports, caching the answer for the lifetime of
+// the client. Adapters that predate the capabilities subcommand fail it, and are treated as
+// reporting no capabilities.
+func (c *Client) Capabilities(ctx context.Context, logger *log.Logger) (Capabilities, error) {
+	c.capabilitiesLock.Lock()
+	defer c.capabilitiesLock.Unlock()
+
+	if c.capabilities != nil {
+		return *c.capabilities, nil
+	}
+
+	var capabilities Capabilities
+
+	stdout, stderr, exitCode, err := c.run(ctx, CapabilitiesSubcommand)
+	if err != nil {
+		return capabilities, err
+	}
+
+	if *exitCode != SuccessExitCode {
+		logger.Printf("service adapter at %s does not report its capabilities, exit code %d, stderr: '%s'\n", c.ExternalBinPath, *exitCode, stderr)
+		c.capabilities = &capabilities
+		return capabilities, nil
+	}
+
+	if err := json.Unmarshal(stdout, &capabilities); err != nil {
+		return capabilities, invalidJSONError(c.ExternalBinPath, stdout, stderr, err)
+	}
+
+	c.capabilities = &capabilities
+	return capabilities, nil
+}
+
+func (c *Client) cachedCapabilities() Capabilities {
+	c.capabilitiesLock.Lock()
+	defer c.capabilitiesLock.Unlock()
+
+	if c.capabilities == nil {
+		return Capabilities{}
+	}
+	return *c.capabilities
+}
+
+// UseInputMode switches the client to the requested input mode if the adapter supports it,
+// otherwise the client keeps passing inputs as arguments.
+func (c *Client) UseInputMode(ctx context.Context, mode string, logger *log.Logger) error {
+	if mode == "" || mode == InputModeArgv {
+		c.InputMode = InputModeArgv
+		return nil
+	}
+
+	capabilities, err := c.Capabilities(ctx, logger)
+	if err != nil {
+		return err
+	}
+
+	if !capabilities.SupportsInputMode(mode) {
+		logger.Printf("service adapter at %s does not support the %s input mode, passing inputs as arguments instead\n", c.ExternalBinPath, mode)
+		c.InputMode = InputModeArgv
+		return nil
+	}
+
+	c.InputMode = mode
+	return nil
+}
@@ -0,0 +1,212 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package serviceadapter_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter/fakes"
+	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+)
+
+var _ = Describe("adapter capabilities", func() {
+	const externalBinPath = "/thing"
+
+	var (
+		a         *serviceadapter.Client
+		cmdRunner *fakes.FakeCommandRunner
+		logs      *gbytes.Buffer
+		logger    *log.Logger
+	)
+
+	BeforeEach(func() {
+		logs = gbytes.NewBuffer()
+		logger = log.New(io.MultiWriter(GinkgoWriter, logs), "[unit-tests] ", log.LstdFlags)
+		cmdRunner = new(fakes.FakeCommandRunner)
+		a = &serviceadapter.Client{
+			CommandRunner:   cmdRunner,
+			ExternalBinPath: externalBinPath,
+		}
+	})
+
+	Describe("Capabilities", func() {
+		var (
+			capabilities serviceadapter.Capabilities
+			err          error
+		)
+
+		JustBeforeEach(func() {
+			capabilities, err = a.Capabilities(context.Background(), logger)
+		})
+
+		Context("when the adapter reports its capabilities", func() {
+			BeforeEach(func() {
+				cmdRunner.RunReturns([]byte(`{
+					"subcommands": ["generate-manifest", "create-binding", "delete-binding"],
+					"sdk_version": "1.2.3",
+					"features": ["some-feature"],
+					"input_modes": ["argv", "stdin"]
+				}`), nil, intPtr(serviceadapter.SuccessExitCode), nil)
+			})
+
+			It("invokes the capabilities subcommand", func() {
+				_, argsPassed := cmdRunner.RunArgsForCall(0)
+				Expect(argsPassed).To(Equal([]string{externalBinPath, "capabilities"}))
+			})
+
+			It("returns the capabilities", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(capabilities).To(Equal(serviceadapter.Capabilities{
+					Subcommands: []string{"generate-manifest", "create-binding", "delete-binding"},
+					SDKVersion:  "1.2.3",
+					Features:    []string{"some-feature"},
+					InputModes:  []string{"argv", "stdin"},
+				}))
+				Expect(capabilities.SupportsInputMode(serviceadapter.InputModeStdin)).To(BeTrue())
+				Expect(capabilities.SupportsFeature("some-feature")).To(BeTrue())
+				Expect(capabilities.SupportsSubcommand("dashboard-url")).To(BeFalse())
+			})
+
+			It("caches the capabilities", func() {
+				cachedCapabilities, err := a.Capabilities(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cachedCapabilities).To(Equal(capabilities))
+				Expect(cmdRunner.RunCallCount()).To(Equal(1))
+			})
+
+			It("does not invoke subcommands the adapter does not support", func() {
+				_, err := a.GenerateDashboardUrl(context.Background(), "an-instance", sdk.Plan{}, nil, logger)
+				Expect(err).To(BeAssignableToTypeOf(serviceadapter.NotImplementedError{}))
+				Expect(err).To(MatchError("service adapter at /thing does not support dashboard-url"))
+				Expect(cmdRunner.RunCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when the adapter does not implement the capabilities subcommand", func() {
+			BeforeEach(func() {
+				cmdRunner.RunReturns(nil, nil, intPtr(sdk.NotImplementedExitCode), nil)
+			})
+
+			It("returns no capabilities", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(capabilities).To(Equal(serviceadapter.Capabilities{}))
+				Expect(capabilities.SupportsInputMode(serviceadapter.InputModeArgv)).To(BeTrue())
+				Expect(capabilities.SupportsInputMode(serviceadapter.InputModeStdin)).To(BeFalse())
+				Expect(capabilities.SupportsSubcommand("dashboard-url")).To(BeTrue())
+			})
+		})
+
+		Context("when the adapter fails the capabilities subcommand", func() {
+			BeforeEach(func() {
+				cmdRunner.RunReturns(nil, []byte("unknown subcommand"), intPtr(sdk.ErrorExitCode), nil)
+			})
+
+			It("returns no capabilities", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(capabilities).To(Equal(serviceadapter.Capabilities{}))
+				Expect(logs).To(gbytes.Say("service adapter at /thing does not report its capabilities, exit code 1, stderr: 'unknown subcommand'"))
+			})
+		})
+
+		Context("when the adapter cannot be run", func() {
+			BeforeEach(func() {
+				cmdRunner.RunReturns(nil, nil, nil, errors.New("no adapter"))
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError(ContainSubstring("no adapter")))
+			})
+
+			It("does not cache the failure", func() {
+				cmdRunner.RunReturns([]byte(`{"sdk_version": "1.2.3"}`), nil, intPtr(serviceadapter.SuccessExitCode), nil)
+				capabilities, err := a.Capabilities(context.Background(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(capabilities.SDKVersion).To(Equal("1.2.3"))
+			})
+		})
+
+		Context("when the adapter outputs invalid JSON", func() {
+			BeforeEach(func() {
+				cmdRunner.RunReturns([]byte("not json"), nil, intPtr(serviceadapter.SuccessExitCode), nil)
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError(ContainSubstring("external service adapter returned invalid JSON at /thing")))
+			})
+		})
+	})
+
+	Describe("UseInputMode", func() {
+		var (
+			mode string
+			err  error
+		)
+
+		JustBeforeEach(func() {
+			err = a.UseInputMode(context.Background(), mode, logger)
+		})
+
+		Context("when argv is requested", func() {
+			BeforeEach(func() {
+				mode = serviceadapter.InputModeArgv
+			})
+
+			It("does not ask the adapter for its capabilities", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cmdRunner.RunCallCount()).To(Equal(0))
+				Expect(a.InputMode).To(Equal(serviceadapter.InputModeArgv))
+			})
+		})
+
+		Context("when stdin is requested", func() {
+			BeforeEach(func() {
+				mode = serviceadapter.InputModeStdin
+			})
+
+			Context("and the adapter supports it", func() {
+				BeforeEach(func() {
+					cmdRunner.RunReturns([]byte(`{"input_modes": ["stdin"]}`), nil, intPtr(serviceadapter.SuccessExitCode), nil)
+				})
+
+				It("uses stdin", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(a.InputMode).To(Equal(serviceadapter.InputModeStdin))
+				})
+			})
+
+			Context("and the adapter does not support it", func() {
+				BeforeEach(func() {
+					cmdRunner.RunReturns(nil, nil, intPtr(sdk.NotImplementedExitCode), nil)
+				})
+
+				It("falls back to argv", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(a.InputMode).To(Equal(serviceadapter.InputModeArgv))
+					Expect(logs).To(gbytes.Say("service adapter at /thing does not support the stdin input mode, passing inputs as arguments instead"))
+				})
+			})
+
+			Context("and the capabilities check fails", func() {
+				BeforeEach(func() {
+					cmdRunner.RunReturns(nil, nil, nil, errors.New("no adapter"))
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError(ContainSubstring("no adapter")))
+				})
+			})
+		})
+	})
+})
@@ -7,22 +7,112 @@
 package serviceadapter
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
 )
 
-const SuccessExitCode = 0
+const (
+	SuccessExitCode = 0
+
+	DefaultTimeoutKey = "default"
+	// DefaultTimeout bounds subcommands when no timeout is configured, so that a hung adapter
+	// cannot block the broker indefinitely
+	DefaultTimeout = 10 * time.Minute
+
+	InputModeArgv  = "argv"
+	InputModeStdin = "stdin"
+
+	// StdinInputFlag tells the adapter to read its inputs from a JSON envelope on stdin
+	// rather than from positional arguments.
+	StdinInputFlag = "--stdin"
+)
 
 //go:generate counterfeiter -o fakes/fake_command_runner.go . CommandRunner
 type CommandRunner interface {
-	Run(arg ...string) ([]byte, []byte, *int, error)
+	Run(ctx context.Context, arg ...string) ([]byte, []byte, *int, error)
+	RunWithInput(ctx context.Context, input []byte, arg ...string) ([]byte, []byte, *int, error)
 }
 
 type Client struct {
 	ExternalBinPath string
 	CommandRunner   CommandRunner
+	// Timeouts bounds how long each subcommand may run for, falling back to the
+	// DefaultTimeoutKey entry and then to DefaultTimeout.
+	Timeouts map[string]time.Duration
+	// InputMode is InputModeArgv unless the adapter has agreed to InputModeStdin, see UseInputMode.
+	InputMode string
+
+	capabilitiesLock sync.Mutex
+	capabilities     *Capabilities
+}
+
+type adapterInput struct {
+	name  string
+	value []byte
+	json  bool
+}
+
+func textInput(name string, value string) adapterInput {
+	return adapterInput{name: name, value: []byte(value)}
+}
+
+func jsonInput(name string, value []byte) adapterInput {
+	return adapterInput{name: name, value: value, json: true}
+}
+
+func (c *Client) run(ctx context.Context, subcommand string, inputs ...adapterInput) ([]byte, []byte, *int, error) {
+	if subcommand != CapabilitiesSubcommand && !c.cachedCapabilities().SupportsSubcommand(subcommand) {
+		return nil, nil, nil, NewNotImplementedError(fmt.Sprintf("service adapter at %s does not support %s", c.ExternalBinPath, subcommand))
+	}
+
+	timeout, found := c.Timeouts[subcommand]
+	if !found {
+		timeout, found = c.Timeouts[DefaultTimeoutKey]
+	}
+	if !found {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	stdout, stderr, exitCode, err := c.runWithInputs(ctx, subcommand, inputs)
+	if ctx.Err() == context.DeadlineExceeded {
+		return stdout, stderr, nil, timeoutError(c.ExternalBinPath, subcommand, timeout, stdout, stderr)
+	}
+	if err != nil {
+		return stdout, stderr, nil, adapterError(c.ExternalBinPath, stdout, stderr, err)
+	}
+	return stdout, stderr, exitCode, nil
+}
+
+func (c *Client) runWithInputs(ctx context.Context, subcommand string, inputs []adapterInput) ([]byte, []byte, *int, error) {
+	if c.InputMode != InputModeStdin {
+		args := []string{c.ExternalBinPath, subcommand}
+		for _, input := range inputs {
+			args = append(args, string(input.value))
+		}
+		return c.CommandRunner.Run(ctx, args...)
+	}
+
+	envelope := map[string]interface{}{}
+	for _, input := range inputs {
+		if input.json {
+			envelope[input.name] = json.RawMessage(input.value)
+		} else {
+			envelope[input.name] = string(input.value)
+		}
+	}
+	serialisedEnvelope, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return c.CommandRunner.RunWithInput(ctx, serialisedEnvelope, c.ExternalBinPath, subcommand, StdinInputFlag)
 }
 
 func SanitiseForJSON(properties sdk.Properties) sdk.Properties {
@@ -72,9 +162,43 @@ func ErrorForExitCode(code int, message string) error {
 		return err
 	}
 
+	if structuredErr, ok := parseStructuredError(message); ok {
+		return structuredErr
+	}
+
 	return UnknownFailureError{errors.New(message)}
 }
 
+// StructuredError is reported by an adapter that fails with {"error": {...}} on stdout,
+// giving the broker a message it can show to the CF user as is.
+type StructuredError struct {
+	UserMessage     string `json:"user_message"`
+	OperatorMessage string `json:"operator_message"`
+	Code            string `json:"code"`
+	Retryable       bool   `json:"retryable"`
+}
+
+func (e StructuredError) Error() string {
+	message := e.OperatorMessage
+	if message == "" {
+		message = e.UserMessage
+	}
+	if e.Code != "" {
+		message = fmt.Sprintf("%s: %s", e.Code, message)
+	}
+	return message
+}
+
+func parseStructuredError(stdout string) (StructuredError, bool) {
+	var output struct {
+		Error *StructuredError `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil || output.Error == nil || output.Error.UserMessage == "" {
+		return StructuredError{}, false
+	}
+	return *output.Error, true
+}
+
 type UnknownFailureError struct {
 	error
 }
@@ -95,6 +219,10 @@ type BindingNotFoundError struct {
 	error
 }
 
+type TimeoutError struct {
+	error
+}
+
 func NewNotImplementedError(msg string) NotImplementedError {
 	return NotImplementedError{errors.New(msg)}
 }
@@ -103,6 +231,10 @@ func NewUnknownFailureError(msg string) UnknownFailureError {
 	return UnknownFailureError{errors.New(msg)}
 }
 
+func NewTimeoutError(msg string) TimeoutError {
+	return TimeoutError{errors.New(msg)}
+}
+
 func invalidJSONError(adapterPath string, stdout, stderr []byte, err error) error {
 	return fmt.Errorf("external service adapter returned invalid JSON at %s: stdout: '%s', stderr: '%s', JSON error: '%s'", adapterPath, string(stdout), string(stderr), err)
 }
@@ -111,6 +243,10 @@ func invalidYAMLError(adapterPath string, stderr []byte) error {
 	return fmt.Errorf("external service adapter generated manifest that is not valid YAML at %s. stderr: '%s'", adapterPath, string(stderr))
 }
 
+func timeoutError(adapterPath, subcommand string, timeout time.Duration, stdout, stderr []byte) error {
+	return NewTimeoutError(fmt.Sprintf("external service adapter at %s timed out after %s running %s. stdout: '%s', stderr: '%s'", adapterPath, timeout, subcommand, string(stdout), string(stderr)))
+}
+
 func adapterError(adapterPath string, stdout, stderr []byte, err error) error {
 	return fmt.Errorf("an error occurred running external service adapter at %s: '%s'. stdout: '%s', stderr: '%s'", adapterPath, err, string(stdout), string(stderr))
 }
@@ -123,6 +259,34 @@ func invalidVersionError(adapterPath string, stderr []byte, version string) erro
 	return fmt.Errorf("external service adapter generated manifest with an incorrect version at %s. expected exact version but returned version: '%s', stderr: '%s'", adapterPath, version, stderr)
 }
 
+func unconfiguredStemcellError(adapterPath string, stderr []byte, alias, os, version string) error {
+	return fmt.Errorf("external service adapter generated manifest with a stemcell not configured in service_deployment.stemcells at %s. alias: '%s', os: '%s', version: '%s', stderr: '%s'", adapterPath, alias, os, version, stderr)
+}
+
+func unconfiguredReleaseError(adapterPath string, stderr []byte, name string) error {
+	return fmt.Errorf("external service adapter generated manifest with a release not configured in service_deployment.releases at %s. release: '%s', stderr: '%s'", adapterPath, name, stderr)
+}
+
+func releaseVersionMismatchError(adapterPath string, stderr []byte, name, expectedVersion, actualVersion string) error {
+	return fmt.Errorf("external service adapter generated manifest with a release version that does not match service_deployment.releases at %s. release: '%s', expected version: '%s', returned version: '%s', stderr: '%s'", adapterPath, name, expectedVersion, actualVersion, stderr)
+}
+
+func stemcellMismatchError(adapterPath string, stderr []byte, expectedOS, expectedVersion, actualOS, actualVersion string) error {
+	return fmt.Errorf("external service adapter generated manifest with a stemcell that does not match service_deployment.stemcell at %s. expected os: '%s', version: '%s', returned os: '%s', version: '%s', stderr: '%s'", adapterPath, expectedOS, expectedVersion, actualOS, actualVersion, stderr)
+}
+
+func noInstancesError(adapterPath string, stderr []byte, instanceGroup string) error {
+	return fmt.Errorf("external service adapter generated manifest with an instance group with no instances at %s. instance group: '%s', stderr: '%s'", adapterPath, instanceGroup, stderr)
+}
+
+func unconfiguredJobError(adapterPath string, stderr []byte, instanceGroup, job, release string) error {
+	return fmt.Errorf("external service adapter generated manifest with a job not configured in service_deployment.releases at %s. instance group: '%s', job: '%s', release: '%s', stderr: '%s'", adapterPath, instanceGroup, job, release, stderr)
+}
+
+func missingUpdateBlockError(adapterPath string, stderr []byte) error {
+	return fmt.Errorf("external service adapter generated manifest without an update block at %s. stderr: '%s'", adapterPath, stderr)
+}
+
 func adapterFailedMessage(exitCode int, adapterPath string, stdout, stderr []byte) string {
 	return fmt.Sprintf("external service adapter exited with %d at %s: stdout: '%s', stderr: '%s'\n", exitCode, adapterPath, stdout, stderr)
 }
@@ -69,5 +69,33 @@ var _ = Describe("Adapter Client", func() {
 			BeAssignableToTypeOf(serviceadapter.UnknownFailureError{}),
 			Equal("some other error"),
 		),
+		Entry(
+			"structured error",
+			sdk.ErrorExitCode, `{"error": {"user_message": "size must be small or large", "operator_message": "invalid size 'medium'", "code": "invalid-params"}}`,
+			Equal(serviceadapter.StructuredError{
+				UserMessage:     "size must be small or large",
+				OperatorMessage: "invalid size 'medium'",
+				Code:            "invalid-params",
+			}),
+			Equal("invalid-params: invalid size 'medium'"),
+		),
+		Entry(
+			"structured error without an operator message",
+			sdk.ErrorExitCode, `{"error": {"user_message": "try again later", "retryable": true}}`,
+			Equal(serviceadapter.StructuredError{UserMessage: "try again later", Retryable: true}),
+			Equal("try again later"),
+		),
+		Entry(
+			"JSON error without a user message",
+			sdk.ErrorExitCode, `{"error": {"operator_message": "for the operator"}}`,
+			BeAssignableToTypeOf(serviceadapter.UnknownFailureError{}),
+			Equal(`{"error": {"operator_message": "for the operator"}}`),
+		),
+		Entry(
+			"structured error with a mapped exit code",
+			sdk.BindingNotFoundErrorExitCode, `{"error": {"user_message": "should not appear"}}`,
+			BeAssignableToTypeOf(serviceadapter.BindingNotFoundError{}),
+			Equal("binding not found"),
+		),
 	)
 })
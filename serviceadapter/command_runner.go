@@ -8,6 +8,7 @@ package serviceadapter
 
 import (
 	"bytes"
+	"context"
 	"os/exec"
 	"syscall"
 )
@@ -18,12 +19,38 @@ func NewCommandRunner() CommandRunner {
 
 type commandRunner struct{}
 
-func (c commandRunner) Run(arg ...string) ([]byte, []byte, *int, error) {
-	var stderr bytes.Buffer
+func (c commandRunner) Run(ctx context.Context, arg ...string) ([]byte, []byte, *int, error) {
+	return c.RunWithInput(ctx, nil, arg...)
+}
+
+func (c commandRunner) RunWithInput(ctx context.Context, input []byte, arg ...string) ([]byte, []byte, *int, error) {
+	var stdout, stderr bytes.Buffer
 	cmd := exec.Command(arg[0], arg[1:]...)
+	if input != nil {
+		cmd.Stdin = bytes.NewReader(input)
+	}
+	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
+	// the adapter runs in its own process group so anything it spawns is killed along with it
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 
-	stdout, err := cmd.Output()
+	if err := cmd.Start(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		<-done
+		return stdout.Bytes(), stderr.Bytes(), nil, ctx.Err()
+	}
 
 	var exitCode *int
 
@@ -36,7 +63,7 @@ func (c commandRunner) Run(arg ...string) ([]byte, []byte, *int, error) {
 		exitCode = intPtr(0)
 	}
 
-	return stdout, stderr.Bytes(), exitCode, err
+	return stdout.Bytes(), stderr.Bytes(), exitCode, err
 }
 
 func intPtr(val int) *int {
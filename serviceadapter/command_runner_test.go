@@ -7,8 +7,12 @@
 package serviceadapter_test
 
 import (
+	"context"
+	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -19,16 +23,21 @@ var _ = Describe("CommandRunner", func() {
 	var (
 		scriptPath string
 
+		ctx            context.Context
 		stdout         string
 		stderr         string
 		runErr         error
 		actualExitCode *int
 	)
 
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
 	JustBeforeEach(func() {
 		runner := serviceadapter.NewCommandRunner()
 		var stdoutBytes, stderrBytes []byte
-		stdoutBytes, stderrBytes, actualExitCode, runErr = runner.Run(scriptPath)
+		stdoutBytes, stderrBytes, actualExitCode, runErr = runner.Run(ctx, scriptPath)
 		stdout = string(stdoutBytes)
 		stderr = string(stderrBytes)
 	})
@@ -127,4 +136,58 @@ var _ = Describe("CommandRunner", func() {
 			Expect(*actualExitCode).To(Equal(23))
 		})
 	})
+
+	Context("when the context is done before the command exits", func() {
+		var (
+			markerPath string
+			cancel     context.CancelFunc
+			started    time.Time
+		)
+
+		BeforeEach(func() {
+			markerDir, err := ioutil.TempDir("", "cmd")
+			Expect(err).NotTo(HaveOccurred())
+			markerPath = filepath.Join(markerDir, "marker")
+
+			scriptPath = createScript(fmt.Sprintf("echo output; (sleep 1; touch %s) & sleep 5", markerPath))
+			ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+			started = time.Now()
+		})
+
+		AfterEach(func() {
+			cancel()
+			os.RemoveAll(filepath.Dir(markerPath))
+		})
+
+		It("returns the context's error promptly", func() {
+			Expect(runErr).To(Equal(context.DeadlineExceeded))
+			Expect(time.Since(started)).To(BeNumerically("<", 3*time.Second))
+		})
+
+		It("returns the output so far", func() {
+			Expect(stdout).To(Equal("output\n"))
+		})
+
+		It("returns no exit code", func() {
+			Expect(actualExitCode).To(BeNil())
+		})
+
+		It("kills the processes the command started", func() {
+			Consistently(func() bool {
+				_, err := os.Stat(markerPath)
+				return os.IsNotExist(err)
+			}, 1500*time.Millisecond).Should(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("CommandRunner with input", func() {
+	It("writes the input to the command's standard input", func() {
+		runner := serviceadapter.NewCommandRunner()
+		stdout, _, exitCode, err := runner.RunWithInput(context.Background(), []byte(`{"some":"input"}`), "/bin/sh", "-c", "cat")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*exitCode).To(Equal(0))
+		Expect(string(stdout)).To(Equal(`{"some":"input"}`))
+	})
 })
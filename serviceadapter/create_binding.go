@@ -7,6 +7,7 @@
 package serviceadapter
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 
@@ -14,7 +15,7 @@ import (
 	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
 )
 
-func (c *Client) CreateBinding(bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, logger *log.Logger) (sdk.Binding, error) {
+func (c *Client) CreateBinding(ctx context.Context, bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, logger *log.Logger) (sdk.Binding, error) {
 	var binding sdk.Binding
 
 	serialisedBoshVMs, err := json.Marshal(deploymentTopology)
@@ -27,9 +28,15 @@ func (c *Client) CreateBinding(bindingID string, deploymentTopology bosh.BoshVMs
 		return binding, err
 	}
 
-	stdout, stderr, exitCode, err := c.CommandRunner.Run(c.ExternalBinPath, "create-binding", bindingID, string(serialisedBoshVMs), string(manifest), string(serialisedRequestParams))
+	stdout, stderr, exitCode, err := c.run(
+		ctx, "create-binding",
+		textInput("binding_id", bindingID),
+		jsonInput("bosh_vms", serialisedBoshVMs),
+		textInput("manifest", string(manifest)),
+		jsonInput("request_params", serialisedRequestParams),
+	)
 	if err != nil {
-		return binding, adapterError(c.ExternalBinPath, stdout, stderr, err)
+		return binding, err
 	}
 
 	if err := ErrorForExitCode(*exitCode, string(stdout)); err != nil {
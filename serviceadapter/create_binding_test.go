@@ -7,6 +7,7 @@
 package serviceadapter_test
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -63,7 +64,7 @@ var _ = Describe("external service adapter", func() {
 	})
 
 	JustBeforeEach(func() {
-		adapterBinding, createBindingErr = a.CreateBinding(bindingID, deploymentTopology, manifest, requestParams, logger)
+		adapterBinding, createBindingErr = a.CreateBinding(context.Background(), bindingID, deploymentTopology, manifest, requestParams, logger)
 	})
 
 	It("invokes external binding creator with serialised params", func() {
@@ -74,10 +75,35 @@ var _ = Describe("external service adapter", func() {
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(cmdRunner.RunCallCount()).To(Equal(1))
-		argsPassed := cmdRunner.RunArgsForCall(0)
+		_, argsPassed := cmdRunner.RunArgsForCall(0)
 		Expect(argsPassed).To(ConsistOf(externalBinPath, "create-binding", bindingID, string(serialisedVMs), string(manifest), string(serialisedRequestParams)))
 	})
 
+	Context("when the client uses the stdin input mode", func() {
+		BeforeEach(func() {
+			a.InputMode = serviceadapter.InputModeStdin
+			cmdRunner.RunWithInputReturns([]byte(`{"credentials": {"username": "user1"}}`), []byte(""), intPtr(serviceadapter.SuccessExitCode), nil)
+		})
+
+		It("passes the inputs to the adapter as a JSON envelope on stdin", func() {
+			Expect(createBindingErr).NotTo(HaveOccurred())
+			Expect(cmdRunner.RunWithInputCallCount()).To(Equal(1))
+
+			_, input, argsPassed := cmdRunner.RunWithInputArgsForCall(0)
+			Expect(argsPassed).To(Equal([]string{externalBinPath, "create-binding", serviceadapter.StdinInputFlag}))
+			Expect(input).To(MatchJSON(`{
+				"binding_id": "the-binding",
+				"bosh_vms": {"the-deployment": ["a-vm"]},
+				"manifest": "a-manifest",
+				"request_params": {"foo": "bar"}
+			}`))
+		})
+
+		It("returns the binding from stdout", func() {
+			Expect(adapterBinding.Credentials).To(HaveKeyWithValue("username", "user1"))
+		})
+	})
+
 	Context("when the external adapter succeeds", func() {
 		It("returns the service-specific binding output", func() {
 			Expect(createBindingErr).ToNot(HaveOccurred())
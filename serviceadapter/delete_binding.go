@@ -7,13 +7,14 @@
 package serviceadapter
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 
 	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
 )
 
-func (c *Client) DeleteBinding(bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, logger *log.Logger) error {
+func (c *Client) DeleteBinding(ctx context.Context, bindingID string, deploymentTopology bosh.BoshVMs, manifest []byte, requestParams map[string]interface{}, logger *log.Logger) error {
 	serialisedBoshVMs, err := json.Marshal(deploymentTopology)
 	if err != nil {
 		return err
@@ -24,9 +25,15 @@ func (c *Client) DeleteBinding(bindingID string, deploymentTopology bosh.BoshVMs
 		return err
 	}
 
-	stdout, stderr, exitCode, err := c.CommandRunner.Run(c.ExternalBinPath, "delete-binding", bindingID, string(serialisedBoshVMs), string(manifest), string(serialisedRequestParams))
+	stdout, stderr, exitCode, err := c.run(
+		ctx, "delete-binding",
+		textInput("binding_id", bindingID),
+		jsonInput("bosh_vms", serialisedBoshVMs),
+		textInput("manifest", string(manifest)),
+		jsonInput("request_params", serialisedRequestParams),
+	)
 	if err != nil {
-		return adapterError(c.ExternalBinPath, stdout, stderr, err)
+		return err
 	}
 
 	if err := ErrorForExitCode(*exitCode, string(stdout)); err != nil {
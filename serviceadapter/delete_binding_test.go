@@ -7,6 +7,7 @@
 package serviceadapter_test
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -57,7 +58,7 @@ var _ = Describe("external service adapter", func() {
 	})
 
 	JustBeforeEach(func() {
-		deleteBindingError = a.DeleteBinding(bindingID, deploymentTopology, manifest, requestParams, logger)
+		deleteBindingError = a.DeleteBinding(context.Background(), bindingID, deploymentTopology, manifest, requestParams, logger)
 	})
 
 	It("invokes external executable with params to delete binding", func() {
@@ -68,7 +69,7 @@ var _ = Describe("external service adapter", func() {
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(cmdRunner.RunCallCount()).To(Equal(1))
-		argsPassed := cmdRunner.RunArgsForCall(0)
+		_, argsPassed := cmdRunner.RunArgsForCall(0)
 		Expect(argsPassed).To(ConsistOf(externalBinPath, "delete-binding", bindingID, string(serialisedBoshVMs), string(manifest), string(serialisedRequestParams)))
 	})
 
@@ -2,15 +2,17 @@
 package fakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 )
 
 type FakeCommandRunner struct {
-	RunStub        func(arg ...string) ([]byte, []byte, *int, error)
+	RunStub        func(ctx context.Context, arg ...string) ([]byte, []byte, *int, error)
 	runMutex       sync.RWMutex
 	runArgsForCall []struct {
+		ctx context.Context
 		arg []string
 	}
 	runReturns struct {
@@ -25,20 +27,40 @@ type FakeCommandRunner struct {
 		result3 *int
 		result4 error
 	}
+	RunWithInputStub        func(ctx context.Context, input []byte, arg ...string) ([]byte, []byte, *int, error)
+	runWithInputMutex       sync.RWMutex
+	runWithInputArgsForCall []struct {
+		ctx   context.Context
+		input []byte
+		arg   []string
+	}
+	runWithInputReturns struct {
+		result1 []byte
+		result2 []byte
+		result3 *int
+		result4 error
+	}
+	runWithInputReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 []byte
+		result3 *int
+		result4 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeCommandRunner) Run(arg ...string) ([]byte, []byte, *int, error) {
+func (fake *FakeCommandRunner) Run(ctx context.Context, arg ...string) ([]byte, []byte, *int, error) {
 	fake.runMutex.Lock()
 	ret, specificReturn := fake.runReturnsOnCall[len(fake.runArgsForCall)]
 	fake.runArgsForCall = append(fake.runArgsForCall, struct {
+		ctx context.Context
 		arg []string
-	}{arg})
-	fake.recordInvocation("Run", []interface{}{arg})
+	}{ctx, arg})
+	fake.recordInvocation("Run", []interface{}{ctx, arg})
 	fake.runMutex.Unlock()
 	if fake.RunStub != nil {
-		return fake.RunStub(arg...)
+		return fake.RunStub(ctx, arg...)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3, ret.result4
@@ -52,10 +74,10 @@ func (fake *FakeCommandRunner) RunCallCount() int {
 	return len(fake.runArgsForCall)
 }
 
-func (fake *FakeCommandRunner) RunArgsForCall(i int) []string {
+func (fake *FakeCommandRunner) RunArgsForCall(i int) (context.Context, []string) {
 	fake.runMutex.RLock()
 	defer fake.runMutex.RUnlock()
-	return fake.runArgsForCall[i].arg
+	return fake.runArgsForCall[i].ctx, fake.runArgsForCall[i].arg
 }
 
 func (fake *FakeCommandRunner) RunReturns(result1 []byte, result2 []byte, result3 *int, result4 error) {
@@ -86,11 +108,77 @@ func (fake *FakeCommandRunner) RunReturnsOnCall(i int, result1 []byte, result2 [
 	}{result1, result2, result3, result4}
 }
 
+func (fake *FakeCommandRunner) RunWithInput(ctx context.Context, input []byte, arg ...string) ([]byte, []byte, *int, error) {
+	var inputCopy []byte
+	if input != nil {
+		inputCopy = make([]byte, len(input))
+		copy(inputCopy, input)
+	}
+	fake.runWithInputMutex.Lock()
+	ret, specificReturn := fake.runWithInputReturnsOnCall[len(fake.runWithInputArgsForCall)]
+	fake.runWithInputArgsForCall = append(fake.runWithInputArgsForCall, struct {
+		ctx   context.Context
+		input []byte
+		arg   []string
+	}{ctx, inputCopy, arg})
+	fake.recordInvocation("RunWithInput", []interface{}{ctx, inputCopy, arg})
+	fake.runWithInputMutex.Unlock()
+	if fake.RunWithInputStub != nil {
+		return fake.RunWithInputStub(ctx, input, arg...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3, ret.result4
+	}
+	return fake.runWithInputReturns.result1, fake.runWithInputReturns.result2, fake.runWithInputReturns.result3, fake.runWithInputReturns.result4
+}
+
+func (fake *FakeCommandRunner) RunWithInputCallCount() int {
+	fake.runWithInputMutex.RLock()
+	defer fake.runWithInputMutex.RUnlock()
+	return len(fake.runWithInputArgsForCall)
+}
+
+func (fake *FakeCommandRunner) RunWithInputArgsForCall(i int) (context.Context, []byte, []string) {
+	fake.runWithInputMutex.RLock()
+	defer fake.runWithInputMutex.RUnlock()
+	return fake.runWithInputArgsForCall[i].ctx, fake.runWithInputArgsForCall[i].input, fake.runWithInputArgsForCall[i].arg
+}
+
+func (fake *FakeCommandRunner) RunWithInputReturns(result1 []byte, result2 []byte, result3 *int, result4 error) {
+	fake.RunWithInputStub = nil
+	fake.runWithInputReturns = struct {
+		result1 []byte
+		result2 []byte
+		result3 *int
+		result4 error
+	}{result1, result2, result3, result4}
+}
+
+func (fake *FakeCommandRunner) RunWithInputReturnsOnCall(i int, result1 []byte, result2 []byte, result3 *int, result4 error) {
+	fake.RunWithInputStub = nil
+	if fake.runWithInputReturnsOnCall == nil {
+		fake.runWithInputReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 []byte
+			result3 *int
+			result4 error
+		})
+	}
+	fake.runWithInputReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 []byte
+		result3 *int
+		result4 error
+	}{result1, result2, result3, result4}
+}
+
 func (fake *FakeCommandRunner) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.runMutex.RLock()
 	defer fake.runMutex.RUnlock()
+	fake.runWithInputMutex.RLock()
+	defer fake.runWithInputMutex.RUnlock()
 	return fake.invocations
 }
 
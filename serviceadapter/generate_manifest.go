@@ -7,6 +7,7 @@
 package serviceadapter
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 	"strings"
@@ -18,18 +19,47 @@ import (
 type manifest struct {
 	Name     string
 	Releases []struct {
+		Name    string
 		Version string
 	}
 	Stemcells []struct {
+		Alias   string
+		OS      string
 		Version string
 	}
+	InstanceGroups []struct {
+		Name      string
+		Instances int
+		Jobs      []struct {
+			Name    string
+			Release string
+		}
+		Update map[string]interface{}
+	} `yaml:"instance_groups"`
+	Update map[string]interface{}
+}
+
+// ServiceDeployment adds aliased stemcells to the SDK's service deployment. The SDK's
+// Stemcell is still populated so that adapters unaware of Stemcells keep working.
+type ServiceDeployment struct {
+	sdk.ServiceDeployment
+	Stemcells []Stemcell `json:"stemcells,omitempty"`
+}
+
+type Stemcell struct {
+	Alias   string `json:"alias"`
+	OS      string `json:"stemcell_os"`
+	Version string `json:"stemcell_version"`
 }
 
 type manifestValidator struct {
 	deploymentName string
+	releases       sdk.ServiceReleases
+	stemcell       sdk.Stemcell
+	stemcells      []Stemcell
 }
 
-func (c *Client) GenerateManifest(serviceDeployment sdk.ServiceDeployment, plan sdk.Plan, requestParams map[string]interface{}, previousManifest []byte, previousPlan *sdk.Plan, logger *log.Logger) ([]byte, error) {
+func (c *Client) GenerateManifest(ctx context.Context, serviceDeployment ServiceDeployment, plan sdk.Plan, requestParams map[string]interface{}, previousManifest []byte, previousPlan *sdk.Plan, logger *log.Logger) ([]byte, error) {
 	serialisedServiceDeployment, err := json.Marshal(serviceDeployment)
 	if err != nil {
 		return nil, err
@@ -54,14 +84,17 @@ func (c *Client) GenerateManifest(serviceDeployment sdk.ServiceDeployment, plan
 		return nil, err
 	}
 
-	stdout, stderr, exitCode, err := c.CommandRunner.Run(
-		c.ExternalBinPath, "generate-manifest", string(serialisedServiceDeployment),
-		string(serialisedPlan), string(serialisedRequestParams),
-		string(previousManifest), string(serialisedPreviousPlan),
+	stdout, stderr, exitCode, err := c.run(
+		ctx, "generate-manifest",
+		jsonInput("service_deployment", serialisedServiceDeployment),
+		jsonInput("plan", serialisedPlan),
+		jsonInput("request_params", serialisedRequestParams),
+		textInput("previous_manifest", string(previousManifest)),
+		jsonInput("previous_plan", serialisedPreviousPlan),
 	)
 
 	if err != nil {
-		return nil, adapterError(c.ExternalBinPath, stdout, stderr, err)
+		return nil, err
 	}
 
 	if err := ErrorForExitCode(*exitCode, string(stdout)); err != nil {
@@ -71,7 +104,12 @@ func (c *Client) GenerateManifest(serviceDeployment sdk.ServiceDeployment, plan
 
 	logger.Printf("service adapter ran generate-manifest successfully, stderr logs: %s", string(stderr))
 
-	validator := manifestValidator{deploymentName: serviceDeployment.DeploymentName}
+	validator := manifestValidator{
+		deploymentName: serviceDeployment.DeploymentName,
+		releases:       serviceDeployment.Releases,
+		stemcell:       serviceDeployment.Stemcell,
+		stemcells:      serviceDeployment.Stemcells,
+	}
 	if err := validator.validateManifest(c.ExternalBinPath, stdout, stderr); err != nil {
 		return nil, err
 	}
@@ -94,13 +132,73 @@ func (v manifestValidator) validateManifest(adapterPath string, stdout, stderr [
 		if strings.HasSuffix(release.Version, "latest") {
 			return invalidVersionError(adapterPath, stderr, release.Version)
 		}
+		configuredRelease, found := v.release(release.Name)
+		if !found {
+			return unconfiguredReleaseError(adapterPath, stderr, release.Name)
+		}
+		if release.Version != configuredRelease.Version {
+			return releaseVersionMismatchError(adapterPath, stderr, release.Name, configuredRelease.Version, release.Version)
+		}
 	}
 
 	for _, stemcell := range generatedManifest.Stemcells {
 		if strings.HasSuffix(stemcell.Version, "latest") {
 			return invalidVersionError(adapterPath, stderr, stemcell.Version)
 		}
+		if len(v.stemcells) > 0 {
+			if !v.stemcellConfigured(stemcell.Alias, stemcell.OS, stemcell.Version) {
+				return unconfiguredStemcellError(adapterPath, stderr, stemcell.Alias, stemcell.OS, stemcell.Version)
+			}
+		} else if !v.defaultStemcellMatches(stemcell.OS, stemcell.Version) {
+			return stemcellMismatchError(adapterPath, stderr, v.stemcell.OS, v.stemcell.Version, stemcell.OS, stemcell.Version)
+		}
+	}
+
+	everyInstanceGroupHasUpdate := true
+	for _, instanceGroup := range generatedManifest.InstanceGroups {
+		if instanceGroup.Instances < 1 {
+			return noInstancesError(adapterPath, stderr, instanceGroup.Name)
+		}
+		for _, job := range instanceGroup.Jobs {
+			if !v.jobConfigured(job.Release, job.Name) {
+				return unconfiguredJobError(adapterPath, stderr, instanceGroup.Name, job.Name, job.Release)
+			}
+		}
+		if instanceGroup.Update == nil {
+			everyInstanceGroupHasUpdate = false
+		}
+	}
+
+	if len(generatedManifest.InstanceGroups) > 0 && generatedManifest.Update == nil && !everyInstanceGroupHasUpdate {
+		return missingUpdateBlockError(adapterPath, stderr)
 	}
 
 	return nil
 }
+
+func (v manifestValidator) release(name string) (sdk.ServiceRelease, bool) {
+	for _, release := range v.releases {
+		if release.Name == name {
+			return release, true
+		}
+	}
+	return sdk.ServiceRelease{}, false
+}
+
+func (v manifestValidator) jobConfigured(releaseName, jobName string) bool {
+	release, found := v.release(releaseName)
+	return found && contains(release.Jobs, jobName)
+}
+
+func (v manifestValidator) defaultStemcellMatches(os, version string) bool {
+	return (os == "" || os == v.stemcell.OS) && version == v.stemcell.Version
+}
+
+func (v manifestValidator) stemcellConfigured(alias, os, version string) bool {
+	for _, stemcell := range v.stemcells {
+		if stemcell.Alias == alias && stemcell.OS == os && stemcell.Version == version {
+			return true
+		}
+	}
+	return false
+}
@@ -7,10 +7,13 @@
 package serviceadapter_test
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
 	"log"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -30,7 +33,7 @@ var _ = Describe("external service adapter", func() {
 		cmdRunner         *fakes.FakeCommandRunner
 		logs              *gbytes.Buffer
 		logger            *log.Logger
-		serviceDeployment sdk.ServiceDeployment
+		serviceDeployment serviceadapter.ServiceDeployment
 		plan              sdk.Plan
 		previousPlan      *sdk.Plan
 		params            map[string]interface{}
@@ -50,14 +53,16 @@ var _ = Describe("external service adapter", func() {
 		}
 		cmdRunner.RunReturns([]byte(validManifestContent), []byte(""), intPtr(serviceadapter.SuccessExitCode), nil)
 
-		serviceDeployment = sdk.ServiceDeployment{
-			DeploymentName: "a-service-deployment",
-			Releases: sdk.ServiceReleases{
-				{Name: "a-bosh-release"},
-			},
-			Stemcell: sdk.Stemcell{
-				OS:      "BeOS",
-				Version: "2",
+		serviceDeployment = serviceadapter.ServiceDeployment{
+			ServiceDeployment: sdk.ServiceDeployment{
+				DeploymentName: "a-service-deployment",
+				Releases: sdk.ServiceReleases{
+					{Name: "a-bosh-release"},
+				},
+				Stemcell: sdk.Stemcell{
+					OS:      "BeOS",
+					Version: "2",
+				},
 			},
 		}
 
@@ -87,7 +92,7 @@ var _ = Describe("external service adapter", func() {
 	})
 
 	JustBeforeEach(func() {
-		manifest, generateErr = a.GenerateManifest(serviceDeployment, plan, params, previousManifest, previousPlan, logger)
+		manifest, generateErr = a.GenerateManifest(context.Background(), serviceDeployment, plan, params, previousManifest, previousPlan, logger)
 	})
 
 	It("invokes external manifest generator with serialised parameters", func() {
@@ -105,12 +110,50 @@ var _ = Describe("external service adapter", func() {
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(cmdRunner.RunCallCount()).To(Equal(1))
-		argsPassed := cmdRunner.RunArgsForCall(0)
+		_, argsPassed := cmdRunner.RunArgsForCall(0)
 		Expect(argsPassed).To(ConsistOf(externalBinPath, "generate-manifest",
 			string(serialisedServiceDeployment), string(serialisedPlan),
 			string(serialisedParams), string(previousManifest), string(serialisedPreviousPlan)))
 	})
 
+	Context("when the client uses the stdin input mode", func() {
+		BeforeEach(func() {
+			a.InputMode = serviceadapter.InputModeStdin
+			cmdRunner.RunWithInputReturns([]byte(validManifestContent), []byte(""), intPtr(serviceadapter.SuccessExitCode), nil)
+		})
+
+		It("passes the inputs to the adapter as a JSON envelope on stdin", func() {
+			Expect(generateErr).NotTo(HaveOccurred())
+			Expect(cmdRunner.RunCallCount()).To(Equal(0))
+			Expect(cmdRunner.RunWithInputCallCount()).To(Equal(1))
+
+			_, input, argsPassed := cmdRunner.RunWithInputArgsForCall(0)
+			Expect(argsPassed).To(Equal([]string{externalBinPath, "generate-manifest", serviceadapter.StdinInputFlag}))
+
+			var envelope map[string]interface{}
+			Expect(json.Unmarshal(input, &envelope)).To(Succeed())
+			Expect(envelope).To(HaveKeyWithValue("previous_manifest", "a-manifest"))
+			Expect(envelope).To(HaveKeyWithValue("request_params", map[string]interface{}{
+				"key":        "value",
+				"anotherkey": map[string]interface{}{"innerkey": "innervalue"},
+			}))
+			Expect(envelope).To(HaveKeyWithValue("plan", HaveKeyWithValue("properties", map[string]interface{}{
+				"foo": "bar",
+				"baz": map[string]interface{}{"qux": "quux"},
+			})))
+			Expect(envelope).To(HaveKeyWithValue("service_deployment", HaveKeyWithValue("deployment_name", "a-service-deployment")))
+			Expect(envelope).To(HaveKey("previous_plan"))
+		})
+
+		It("does not pass the inputs as arguments", func() {
+			_, input, argsPassed := cmdRunner.RunWithInputArgsForCall(0)
+			for _, arg := range argsPassed {
+				Expect(arg).NotTo(ContainSubstring("a-manifest"))
+			}
+			Expect(string(input)).To(ContainSubstring("a-manifest"))
+		})
+	})
+
 	Context("when the external service adapter succeeds", func() {
 		Context("when the generated manifest is valid", func() {
 			It("returns no error", func() {
@@ -162,6 +205,183 @@ stemcells:
 				})
 			})
 
+			Context("with aliased stemcells configured", func() {
+				BeforeEach(func() {
+					serviceDeployment.Stemcells = []serviceadapter.Stemcell{
+						{Alias: "linux", OS: "BeOS", Version: "2"},
+						{Alias: "windows", OS: "windows2012R2", Version: "1200.3"},
+					}
+				})
+
+				Context("and the manifest uses them", func() {
+					BeforeEach(func() {
+						manifestContent := `---
+name: a-service-deployment
+stemcells:
+- alias: linux
+  os: BeOS
+  version: "2"
+- alias: windows
+  os: windows2012R2
+  version: "1200.3"`
+						cmdRunner.RunReturns([]byte(manifestContent), []byte(""), intPtr(serviceadapter.SuccessExitCode), nil)
+					})
+
+					It("returns no error", func() {
+						Expect(generateErr).NotTo(HaveOccurred())
+					})
+
+					It("passes the stemcells to the adapter alongside the default stemcell", func() {
+						_, argsPassed := cmdRunner.RunArgsForCall(0)
+						serialisedServiceDeployment := argsPassed[2]
+						Expect(serialisedServiceDeployment).To(MatchJSON(`{
+							"deployment_name": "a-service-deployment",
+							"releases": [{"name": "a-bosh-release", "version": "", "jobs": null}],
+							"stemcell": {"stemcell_os": "BeOS", "stemcell_version": "2"},
+							"stemcells": [
+								{"alias": "linux", "stemcell_os": "BeOS", "stemcell_version": "2"},
+								{"alias": "windows", "stemcell_os": "windows2012R2", "stemcell_version": "1200.3"}
+							]
+						}`))
+					})
+				})
+
+				Context("and the manifest uses a stemcell that is not configured", func() {
+					BeforeEach(func() {
+						manifestContent := `---
+name: a-service-deployment
+stemcells:
+- alias: windows
+  os: windows2016
+  version: "1200.3"`
+						cmdRunner.RunReturns([]byte(manifestContent), []byte(""), intPtr(serviceadapter.SuccessExitCode), nil)
+					})
+
+					It("returns an error", func() {
+						Expect(generateErr).To(MatchError(ContainSubstring("external service adapter generated manifest with a stemcell not configured in service_deployment.stemcells at /thing. alias: 'windows', os: 'windows2016', version: '1200.3'")))
+					})
+				})
+			})
+
+			Context("that deploys instance groups", func() {
+				var manifestContent string
+
+				BeforeEach(func() {
+					serviceDeployment.Releases = sdk.ServiceReleases{
+						{Name: "a-bosh-release", Version: "1.2.3", Jobs: []string{"a-job", "another-job"}},
+					}
+					manifestContent = `---
+name: a-service-deployment
+releases:
+- name: a-bosh-release
+  version: 1.2.3
+stemcells:
+- alias: only-stemcell
+  os: BeOS
+  version: "2"
+instance_groups:
+- name: a-group
+  instances: 2
+  jobs:
+  - name: a-job
+    release: a-bosh-release
+update:
+  canaries: 1
+  max_in_flight: 1
+`
+				})
+
+				Context("and matches the service deployment", func() {
+					BeforeEach(func() {
+						cmdRunner.RunReturns([]byte(manifestContent), []byte(""), intPtr(serviceadapter.SuccessExitCode), nil)
+					})
+
+					It("returns no error", func() {
+						Expect(generateErr).NotTo(HaveOccurred())
+					})
+				})
+
+				Context("and uses a release that is not configured", func() {
+					BeforeEach(func() {
+						invalidManifestContent := strings.Replace(manifestContent, "- name: a-bosh-release", "- name: another-release", 1)
+						cmdRunner.RunReturns([]byte(invalidManifestContent), []byte(""), intPtr(serviceadapter.SuccessExitCode), nil)
+					})
+
+					It("returns an error", func() {
+						Expect(generateErr).To(MatchError(ContainSubstring("external service adapter generated manifest with a release not configured in service_deployment.releases at /thing. release: 'another-release'")))
+					})
+				})
+
+				Context("and uses a release version that is not configured", func() {
+					BeforeEach(func() {
+						invalidManifestContent := strings.Replace(manifestContent, "version: 1.2.3", "version: 1.2.4", 1)
+						cmdRunner.RunReturns([]byte(invalidManifestContent), []byte(""), intPtr(serviceadapter.SuccessExitCode), nil)
+					})
+
+					It("returns an error", func() {
+						Expect(generateErr).To(MatchError(ContainSubstring("external service adapter generated manifest with a release version that does not match service_deployment.releases at /thing. release: 'a-bosh-release', expected version: '1.2.3', returned version: '1.2.4'")))
+					})
+				})
+
+				Context("and uses a stemcell that is not configured", func() {
+					BeforeEach(func() {
+						invalidManifestContent := strings.Replace(manifestContent, `version: "2"`, `version: "3"`, 1)
+						cmdRunner.RunReturns([]byte(invalidManifestContent), []byte(""), intPtr(serviceadapter.SuccessExitCode), nil)
+					})
+
+					It("returns an error", func() {
+						Expect(generateErr).To(MatchError(ContainSubstring("external service adapter generated manifest with a stemcell that does not match service_deployment.stemcell at /thing. expected os: 'BeOS', version: '2', returned os: 'BeOS', version: '3'")))
+					})
+				})
+
+				Context("and an instance group has no instances", func() {
+					BeforeEach(func() {
+						invalidManifestContent := strings.Replace(manifestContent, "instances: 2", "instances: 0", 1)
+						cmdRunner.RunReturns([]byte(invalidManifestContent), []byte(""), intPtr(serviceadapter.SuccessExitCode), nil)
+					})
+
+					It("returns an error", func() {
+						Expect(generateErr).To(MatchError(ContainSubstring("external service adapter generated manifest with an instance group with no instances at /thing. instance group: 'a-group'")))
+					})
+				})
+
+				Context("and an instance group uses a job that is not configured", func() {
+					BeforeEach(func() {
+						invalidManifestContent := strings.Replace(manifestContent, "- name: a-job", "- name: a-rogue-job", 1)
+						cmdRunner.RunReturns([]byte(invalidManifestContent), []byte(""), intPtr(serviceadapter.SuccessExitCode), nil)
+					})
+
+					It("returns an error", func() {
+						Expect(generateErr).To(MatchError(ContainSubstring("external service adapter generated manifest with a job not configured in service_deployment.releases at /thing. instance group: 'a-group', job: 'a-rogue-job', release: 'a-bosh-release'")))
+					})
+				})
+
+				Context("and there is no update block", func() {
+					BeforeEach(func() {
+						invalidManifestContent := manifestContent[:strings.Index(manifestContent, "update:")]
+						cmdRunner.RunReturns([]byte(invalidManifestContent), []byte(""), intPtr(serviceadapter.SuccessExitCode), nil)
+					})
+
+					It("returns an error", func() {
+						Expect(generateErr).To(MatchError(ContainSubstring("external service adapter generated manifest without an update block at /thing.")))
+					})
+				})
+
+				Context("and every instance group has its own update block", func() {
+					BeforeEach(func() {
+						validManifestContent := strings.Replace(
+							manifestContent[:strings.Index(manifestContent, "update:")],
+							"  instances: 2\n", "  instances: 2\n  update:\n    canaries: 1\n", 1,
+						)
+						cmdRunner.RunReturns([]byte(validManifestContent), []byte(""), intPtr(serviceadapter.SuccessExitCode), nil)
+					})
+
+					It("returns no error", func() {
+						Expect(generateErr).NotTo(HaveOccurred())
+					})
+				})
+			})
+
 			Context("that cannot be unmarshalled", func() {
 				BeforeEach(func() {
 					cmdRunner.RunReturns([]byte("unparseable"), []byte(""), intPtr(serviceadapter.SuccessExitCode), nil)
@@ -219,6 +439,47 @@ stemcells:
 		})
 	})
 
+	Context("when the external service adapter runs for longer than the generate-manifest timeout", func() {
+		BeforeEach(func() {
+			a.Timeouts = map[string]time.Duration{
+				serviceadapter.DefaultTimeoutKey: time.Hour,
+				"generate-manifest":              10 * time.Millisecond,
+			}
+			cmdRunner.RunStub = func(ctx context.Context, args ...string) ([]byte, []byte, *int, error) {
+				<-ctx.Done()
+				return []byte("I'm stdout"), []byte("I'm stderr"), nil, ctx.Err()
+			}
+		})
+
+		It("returns a timeout error", func() {
+			Expect(generateErr).To(BeAssignableToTypeOf(serviceadapter.TimeoutError{}))
+			Expect(generateErr).To(MatchError("external service adapter at /thing timed out after 10ms running generate-manifest. stdout: 'I'm stdout', stderr: 'I'm stderr'"))
+		})
+	})
+
+	Context("when the external service adapter runs for longer than the default timeout", func() {
+		BeforeEach(func() {
+			a.Timeouts = map[string]time.Duration{serviceadapter.DefaultTimeoutKey: 10 * time.Millisecond}
+			cmdRunner.RunStub = func(ctx context.Context, args ...string) ([]byte, []byte, *int, error) {
+				<-ctx.Done()
+				return nil, nil, nil, ctx.Err()
+			}
+		})
+
+		It("returns a timeout error", func() {
+			Expect(generateErr).To(BeAssignableToTypeOf(serviceadapter.TimeoutError{}))
+		})
+	})
+
+	Context("when no timeout is configured", func() {
+		It("runs the external service adapter with the built-in default timeout", func() {
+			ctx, _ := cmdRunner.RunArgsForCall(0)
+			deadline, hasDeadline := ctx.Deadline()
+			Expect(hasDeadline).To(BeTrue())
+			Expect(deadline).To(BeTemporally("~", time.Now().Add(serviceadapter.DefaultTimeout), time.Minute))
+		})
+	})
+
 	Context("previous plan is nil", func() {
 		BeforeEach(func() {
 			previousPlan = nil
@@ -229,7 +490,7 @@ stemcells:
 		})
 
 		It("it writes 'null' to the argument list", func() {
-			argsPassed := cmdRunner.RunArgsForCall(0)
+			_, argsPassed := cmdRunner.RunArgsForCall(0)
 			Expect(argsPassed[6]).To(Equal("null"))
 		})
 	})
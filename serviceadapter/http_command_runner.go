@@ -0,0 +1,112 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package serviceadapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+
+	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+)
+
+// HTTPRequest and HTTPResponse are what a long-running adapter exchanges with the broker
+// for each subcommand: the arguments and stdin input an exec adapter would receive, and
+// the stdout, stderr and exit code it would produce.
+type HTTPRequest struct {
+	Arguments []string        `json:"arguments"`
+	Input     json.RawMessage `json:"input,omitempty"`
+}
+
+type HTTPResponse struct {
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// NewHTTPCommandRunner runs subcommands by POSTing them to /<subcommand> of an adapter
+// listening on a unix socket (unix:///path/to/socket) or on localhost (http://127.0.0.1:port).
+func NewHTTPCommandRunner(adapterURL string) (CommandRunner, error) {
+	u, err := url.Parse(adapterURL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "unix":
+		socketPath := u.Path
+		dialer := new(net.Dialer)
+		return httpCommandRunner{
+			baseURL: "http://adapter",
+			client: &http.Client{
+				Transport: &http.Transport{
+					DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+						return dialer.DialContext(ctx, "unix", socketPath)
+					},
+				},
+			},
+		}, nil
+	case "http":
+		return httpCommandRunner{baseURL: u.Scheme + "://" + u.Host, client: new(http.Client)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported service adapter URL scheme '%s', expected unix or http", u.Scheme)
+	}
+}
+
+type httpCommandRunner struct {
+	baseURL string
+	client  *http.Client
+}
+
+func (r httpCommandRunner) Run(ctx context.Context, arg ...string) ([]byte, []byte, *int, error) {
+	return r.RunWithInput(ctx, nil, arg...)
+}
+
+func (r httpCommandRunner) RunWithInput(ctx context.Context, input []byte, arg ...string) ([]byte, []byte, *int, error) {
+	body, err := json.Marshal(HTTPRequest{Arguments: arg[2:], Input: input})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	req, err := http.NewRequest("POST", r.baseURL+"/"+arg[1], bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req.WithContext(ctx))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, nil, nil, ctx.Err()
+		}
+		return nil, nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	// an adapter without a handler for the subcommand behaves like an exec adapter that doesn't implement it
+	if resp.StatusCode == http.StatusNotFound {
+		responseBody, _ := ioutil.ReadAll(resp.Body)
+		return nil, responseBody, intPtr(sdk.NotImplementedExitCode), nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		responseBody, _ := ioutil.ReadAll(resp.Body)
+		return nil, nil, nil, fmt.Errorf("adapter responded with status %d: %s", resp.StatusCode, string(responseBody))
+	}
+
+	var response HTTPResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, nil, nil, fmt.Errorf("adapter responded with invalid JSON: %s", err)
+	}
+
+	return []byte(response.Stdout), []byte(response.Stderr), &response.ExitCode, nil
+}
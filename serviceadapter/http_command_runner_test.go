@@ -0,0 +1,195 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package serviceadapter_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
+	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+)
+
+var _ = Describe("HTTPCommandRunner", func() {
+	var (
+		server          *httptest.Server
+		receivedPath    string
+		receivedRequest serviceadapter.HTTPRequest
+		handler         func(w http.ResponseWriter)
+
+		ctx      context.Context
+		stdout   []byte
+		stderr   []byte
+		exitCode *int
+		runErr   error
+	)
+
+	recordingHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		Expect(json.NewDecoder(r.Body).Decode(&receivedRequest)).To(Succeed())
+		handler(w)
+	})
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		handler = func(w http.ResponseWriter) {
+			json.NewEncoder(w).Encode(serviceadapter.HTTPResponse{Stdout: "output", Stderr: "error", ExitCode: 10})
+		}
+	})
+
+	run := func(adapterURL string) {
+		runner, err := serviceadapter.NewHTTPCommandRunner(adapterURL)
+		Expect(err).NotTo(HaveOccurred())
+		stdout, stderr, exitCode, runErr = runner.Run(ctx, adapterURL, "generate-manifest", "arg1", "arg2")
+	}
+
+	Context("over localhost", func() {
+		BeforeEach(func() {
+			server = httptest.NewServer(recordingHandler)
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		JustBeforeEach(func() {
+			run(server.URL)
+		})
+
+		It("posts the arguments to the subcommand path", func() {
+			Expect(runErr).NotTo(HaveOccurred())
+			Expect(receivedPath).To(Equal("/generate-manifest"))
+			Expect(receivedRequest.Arguments).To(Equal([]string{"arg1", "arg2"}))
+		})
+
+		It("returns the stdout, stderr and exit code from the response", func() {
+			Expect(string(stdout)).To(Equal("output"))
+			Expect(string(stderr)).To(Equal("error"))
+			Expect(*exitCode).To(Equal(10))
+		})
+
+		Context("when input is provided", func() {
+			JustBeforeEach(func() {
+				runner, err := serviceadapter.NewHTTPCommandRunner(server.URL)
+				Expect(err).NotTo(HaveOccurred())
+				_, _, _, runErr = runner.RunWithInput(ctx, []byte(`{"manifest":"a-manifest"}`), server.URL, "generate-manifest", serviceadapter.StdinInputFlag)
+			})
+
+			It("sends the input in the request", func() {
+				Expect(runErr).NotTo(HaveOccurred())
+				Expect(receivedRequest.Arguments).To(Equal([]string{serviceadapter.StdinInputFlag}))
+				Expect(receivedRequest.Input).To(MatchJSON(`{"manifest":"a-manifest"}`))
+			})
+		})
+
+		Context("when the adapter responds with a non-200 status", func() {
+			BeforeEach(func() {
+				handler = func(w http.ResponseWriter) {
+					w.WriteHeader(http.StatusInternalServerError)
+					w.Write([]byte("adapter broke"))
+				}
+			})
+
+			It("returns an error", func() {
+				Expect(runErr).To(MatchError("adapter responded with status 500: adapter broke"))
+				Expect(exitCode).To(BeNil())
+			})
+		})
+
+		Context("when the adapter does not handle the subcommand", func() {
+			BeforeEach(func() {
+				handler = func(w http.ResponseWriter) {
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte("no such subcommand"))
+				}
+			})
+
+			It("returns the not implemented exit code", func() {
+				Expect(runErr).NotTo(HaveOccurred())
+				Expect(*exitCode).To(Equal(sdk.NotImplementedExitCode))
+				Expect(string(stderr)).To(Equal("no such subcommand"))
+			})
+		})
+
+		Context("when the adapter responds with invalid JSON", func() {
+			BeforeEach(func() {
+				handler = func(w http.ResponseWriter) {
+					w.Write([]byte("not json"))
+				}
+			})
+
+			It("returns an error", func() {
+				Expect(runErr).To(MatchError(ContainSubstring("adapter responded with invalid JSON")))
+			})
+		})
+
+		Context("when the context is done before the adapter responds", func() {
+			var cancel context.CancelFunc
+
+			BeforeEach(func() {
+				handler = func(w http.ResponseWriter) {
+					time.Sleep(time.Second)
+				}
+				ctx, cancel = context.WithTimeout(ctx, 10*time.Millisecond)
+			})
+
+			AfterEach(func() {
+				cancel()
+			})
+
+			It("returns the context error", func() {
+				Expect(runErr).To(Equal(context.DeadlineExceeded))
+			})
+		})
+	})
+
+	Context("over a unix socket", func() {
+		var socketDir string
+
+		BeforeEach(func() {
+			var err error
+			socketDir, err = ioutil.TempDir("", "adapter-socket")
+			Expect(err).NotTo(HaveOccurred())
+
+			listener, err := net.Listen("unix", filepath.Join(socketDir, "adapter.sock"))
+			Expect(err).NotTo(HaveOccurred())
+			server = httptest.NewUnstartedServer(recordingHandler)
+			server.Listener = listener
+			server.Start()
+		})
+
+		AfterEach(func() {
+			server.Close()
+			os.RemoveAll(socketDir)
+		})
+
+		JustBeforeEach(func() {
+			run("unix://" + filepath.Join(socketDir, "adapter.sock"))
+		})
+
+		It("calls the adapter listening on the socket", func() {
+			Expect(runErr).NotTo(HaveOccurred())
+			Expect(receivedPath).To(Equal("/generate-manifest"))
+			Expect(receivedRequest.Arguments).To(Equal([]string{"arg1", "arg2"}))
+			Expect(string(stdout)).To(Equal("output"))
+		})
+	})
+
+	It("rejects unsupported URL schemes", func() {
+		_, err := serviceadapter.NewHTTPCommandRunner("https://localhost:8080")
+		Expect(err).To(MatchError("unsupported service adapter URL scheme 'https', expected unix or http"))
+	})
+})
@@ -0,0 +1,59 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package serviceadapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
+)
+
+const InstanceStatusSubcommand = "instance-status"
+
+// InstanceStatus is whatever the adapter reports about a running instance, such as the
+// version of the service, cluster health or the leader node.
+type InstanceStatus map[string]interface{}
+
+// InstanceStatus is only run when the adapter reports supporting it, as adapters that
+// predate instance-status fail it as an unknown subcommand.
+func (c *Client) InstanceStatus(ctx context.Context, instanceID string, deploymentTopology bosh.BoshVMs, manifest []byte, logger *log.Logger) (InstanceStatus, error) {
+	if !contains(c.cachedCapabilities().Subcommands, InstanceStatusSubcommand) {
+		return nil, NewNotImplementedError(fmt.Sprintf("service adapter at %s does not support %s", c.ExternalBinPath, InstanceStatusSubcommand))
+	}
+
+	serialisedBoshVMs, err := json.Marshal(deploymentTopology)
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, stderr, exitCode, err := c.run(
+		ctx, InstanceStatusSubcommand,
+		textInput("instance_id", instanceID),
+		jsonInput("bosh_vms", serialisedBoshVMs),
+		textInput("manifest", string(manifest)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ErrorForExitCode(*exitCode, string(stdout)); err != nil {
+		logger.Print(adapterFailedMessage(*exitCode, c.ExternalBinPath, stdout, stderr))
+		return nil, err
+	}
+
+	logger.Printf("service adapter ran instance-status successfully, stderr logs: %s", string(stderr))
+
+	var status InstanceStatus
+	if err := json.Unmarshal(stdout, &status); err != nil {
+		return nil, invalidJSONError(c.ExternalBinPath, stdout, stderr, err)
+	}
+
+	return status, nil
+}
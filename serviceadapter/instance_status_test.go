@@ -0,0 +1,94 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package serviceadapter_test
+
+import (
+	"context"
+	"io"
+	"log"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter/fakes"
+	"github.com/pivotal-cf/on-demand-services-sdk/bosh"
+	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+)
+
+var _ = Describe("InstanceStatus", func() {
+	const externalBinPath = "/thing"
+
+	var (
+		a         *serviceadapter.Client
+		cmdRunner *fakes.FakeCommandRunner
+		logger    *log.Logger
+
+		status    serviceadapter.InstanceStatus
+		statusErr error
+	)
+
+	BeforeEach(func() {
+		logger = log.New(io.MultiWriter(GinkgoWriter, gbytes.NewBuffer()), "[unit-tests] ", log.LstdFlags)
+		cmdRunner = new(fakes.FakeCommandRunner)
+		a = &serviceadapter.Client{
+			CommandRunner:   cmdRunner,
+			ExternalBinPath: externalBinPath,
+		}
+	})
+
+	JustBeforeEach(func() {
+		status, statusErr = a.InstanceStatus(context.Background(), "an-instance", bosh.BoshVMs{"redis": []string{"10.0.0.1"}}, []byte("a-manifest"), logger)
+	})
+
+	Context("when the adapter reports supporting instance-status", func() {
+		BeforeEach(func() {
+			cmdRunner.RunReturnsOnCall(0, []byte(`{"subcommands": ["generate-manifest", "create-binding", "delete-binding", "instance-status"]}`), nil, intPtr(serviceadapter.SuccessExitCode), nil)
+			cmdRunner.RunReturnsOnCall(1, []byte(`{"version": "3.2.1", "healthy": true}`), nil, intPtr(serviceadapter.SuccessExitCode), nil)
+			_, err := a.Capabilities(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("invokes the adapter with the instance ID, VMs and manifest", func() {
+			_, argsPassed := cmdRunner.RunArgsForCall(1)
+			Expect(argsPassed).To(Equal([]string{externalBinPath, "instance-status", "an-instance", `{"redis":["10.0.0.1"]}`, "a-manifest"}))
+		})
+
+		It("returns the status", func() {
+			Expect(statusErr).NotTo(HaveOccurred())
+			Expect(status).To(Equal(serviceadapter.InstanceStatus{"version": "3.2.1", "healthy": true}))
+		})
+
+		Context("and the adapter fails", func() {
+			BeforeEach(func() {
+				cmdRunner.RunReturnsOnCall(1, []byte("cluster unreachable"), nil, intPtr(sdk.ErrorExitCode), nil)
+			})
+
+			It("returns an error", func() {
+				Expect(statusErr).To(MatchError("cluster unreachable"))
+			})
+		})
+
+		Context("and the adapter outputs invalid JSON", func() {
+			BeforeEach(func() {
+				cmdRunner.RunReturnsOnCall(1, []byte("healthy"), nil, intPtr(serviceadapter.SuccessExitCode), nil)
+			})
+
+			It("returns an error", func() {
+				Expect(statusErr).To(MatchError(ContainSubstring("external service adapter returned invalid JSON at /thing")))
+			})
+		})
+	})
+
+	Context("when the adapter does not report supporting instance-status", func() {
+		It("returns a not implemented error without invoking the adapter", func() {
+			Expect(statusErr).To(BeAssignableToTypeOf(serviceadapter.NotImplementedError{}))
+			Expect(cmdRunner.RunCallCount()).To(BeZero())
+		})
+	})
+})
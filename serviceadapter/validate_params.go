@@ -0,0 +1,66 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package serviceadapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+)
+
+const ValidateParamsSubcommand = "validate-params"
+
+// ValidateParams lets the adapter reject a plan and request params before anything is deployed.
+// Adapters that predate validate-params fail it as an unknown subcommand, so it is only run
+// when the adapter reports supporting it.
+func (c *Client) ValidateParams(ctx context.Context, plan sdk.Plan, previousPlan *sdk.Plan, requestParams map[string]interface{}, logger *log.Logger) error {
+	if !contains(c.cachedCapabilities().Subcommands, ValidateParamsSubcommand) {
+		return NewNotImplementedError(fmt.Sprintf("service adapter at %s does not support %s", c.ExternalBinPath, ValidateParamsSubcommand))
+	}
+
+	plan.Properties = SanitiseForJSON(plan.Properties)
+	serialisedPlan, err := json.Marshal(plan)
+	if err != nil {
+		return err
+	}
+
+	if previousPlan != nil {
+		sanitisedPreviousPlan := *previousPlan
+		sanitisedPreviousPlan.Properties = SanitiseForJSON(previousPlan.Properties)
+		previousPlan = &sanitisedPreviousPlan
+	}
+	serialisedPreviousPlan, err := json.Marshal(previousPlan)
+	if err != nil {
+		return err
+	}
+
+	serialisedRequestParams, err := json.Marshal(requestParams)
+	if err != nil {
+		return err
+	}
+
+	stdout, stderr, exitCode, err := c.run(
+		ctx, ValidateParamsSubcommand,
+		jsonInput("plan", serialisedPlan),
+		jsonInput("previous_plan", serialisedPreviousPlan),
+		jsonInput("request_params", serialisedRequestParams),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := ErrorForExitCode(*exitCode, string(stdout)); err != nil {
+		logger.Print(adapterFailedMessage(*exitCode, c.ExternalBinPath, stdout, stderr))
+		return err
+	}
+
+	logger.Printf("service adapter ran validate-params successfully, stderr logs: %s", string(stderr))
+	return nil
+}
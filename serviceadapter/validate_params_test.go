@@ -0,0 +1,111 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package serviceadapter_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter/fakes"
+	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+)
+
+var _ = Describe("ValidateParams", func() {
+	const externalBinPath = "/thing"
+
+	var (
+		a             *serviceadapter.Client
+		cmdRunner     *fakes.FakeCommandRunner
+		logs          *gbytes.Buffer
+		logger        *log.Logger
+		plan          sdk.Plan
+		previousPlan  *sdk.Plan
+		requestParams map[string]interface{}
+
+		validateErr error
+	)
+
+	BeforeEach(func() {
+		logs = gbytes.NewBuffer()
+		logger = log.New(io.MultiWriter(GinkgoWriter, logs), "[unit-tests] ", log.LstdFlags)
+		cmdRunner = new(fakes.FakeCommandRunner)
+		a = &serviceadapter.Client{
+			CommandRunner:   cmdRunner,
+			ExternalBinPath: externalBinPath,
+		}
+		plan = sdk.Plan{Properties: sdk.Properties{"size": map[interface{}]interface{}{"max": 3}}}
+		previousPlan = &sdk.Plan{Properties: sdk.Properties{"size": "small"}}
+		requestParams = map[string]interface{}{"parameters": map[string]interface{}{"size": "large"}}
+	})
+
+	JustBeforeEach(func() {
+		validateErr = a.ValidateParams(context.Background(), plan, previousPlan, requestParams, logger)
+	})
+
+	Context("when the adapter reports supporting validate-params", func() {
+		BeforeEach(func() {
+			cmdRunner.RunReturnsOnCall(0, []byte(`{"subcommands": ["generate-manifest", "create-binding", "delete-binding", "validate-params"]}`), nil, intPtr(serviceadapter.SuccessExitCode), nil)
+			cmdRunner.RunReturnsOnCall(1, nil, []byte("validated"), intPtr(serviceadapter.SuccessExitCode), nil)
+			_, err := a.Capabilities(context.Background(), logger)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("invokes the adapter with the serialised plans and params", func() {
+			Expect(validateErr).NotTo(HaveOccurred())
+			Expect(cmdRunner.RunCallCount()).To(Equal(2))
+			_, argsPassed := cmdRunner.RunArgsForCall(1)
+			Expect(argsPassed).To(HaveLen(5))
+			Expect(argsPassed[:2]).To(Equal([]string{externalBinPath, "validate-params"}))
+
+			var passedPlan, passedPreviousPlan sdk.Plan
+			Expect(json.Unmarshal([]byte(argsPassed[2]), &passedPlan)).To(Succeed())
+			Expect(passedPlan.Properties).To(Equal(sdk.Properties{"size": map[string]interface{}{"max": float64(3)}}))
+			Expect(json.Unmarshal([]byte(argsPassed[3]), &passedPreviousPlan)).To(Succeed())
+			Expect(passedPreviousPlan.Properties).To(Equal(sdk.Properties{"size": "small"}))
+			Expect(argsPassed[4]).To(MatchJSON(`{"parameters": {"size": "large"}}`))
+		})
+
+		It("logs the adapter's stderr", func() {
+			Expect(logs).To(gbytes.Say("service adapter ran validate-params successfully, stderr logs: validated"))
+		})
+
+		Context("and there is no previous plan", func() {
+			BeforeEach(func() {
+				previousPlan = nil
+			})
+
+			It("passes null as the previous plan", func() {
+				_, argsPassed := cmdRunner.RunArgsForCall(1)
+				Expect(argsPassed[3]).To(Equal("null"))
+			})
+		})
+
+		Context("and the adapter rejects the params with a structured error", func() {
+			BeforeEach(func() {
+				cmdRunner.RunReturnsOnCall(1, []byte(`{"error": {"user_message": "size must be small"}}`), nil, intPtr(sdk.ErrorExitCode), nil)
+			})
+
+			It("returns the structured error", func() {
+				Expect(validateErr).To(Equal(serviceadapter.StructuredError{UserMessage: "size must be small"}))
+			})
+		})
+	})
+
+	Context("when the adapter does not report supporting validate-params", func() {
+		It("does not invoke the adapter", func() {
+			Expect(validateErr).To(BeAssignableToTypeOf(serviceadapter.NotImplementedError{}))
+			Expect(cmdRunner.RunCallCount()).To(BeZero())
+		})
+	})
+})
@@ -43,3 +43,19 @@ func NewServiceError(e error) error {
 type PendingChangesNotAppliedError struct {
 	error
 }
+
+type NoPreviousManifestError struct {
+	error
+}
+
+func NewNoPreviousManifestError(e error) error {
+	return NoPreviousManifestError{e}
+}
+
+type MissingDependenciesError struct {
+	error
+}
+
+func NewMissingDependenciesError(e error) error {
+	return MissingDependenciesError{e}
+}
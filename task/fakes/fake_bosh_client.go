@@ -55,6 +55,123 @@ type FakeBoshClient struct {
 		result2 bool
 		result3 error
 	}
+	GetDeploymentDiffStub        func(name string, manifest []byte, logger *log.Logger) (boshdirector.DeploymentDiff, error)
+	getDeploymentDiffMutex       sync.RWMutex
+	getDeploymentDiffArgsForCall []struct {
+		name     string
+		manifest []byte
+		logger   *log.Logger
+	}
+	getDeploymentDiffReturns struct {
+		result1 boshdirector.DeploymentDiff
+		result2 error
+	}
+	getDeploymentDiffReturnsOnCall map[int]struct {
+		result1 boshdirector.DeploymentDiff
+		result2 error
+	}
+	DeployForTeamStub        func(manifest []byte, contextID, team string, logger *log.Logger) (int, error)
+	deployForTeamMutex       sync.RWMutex
+	deployForTeamArgsForCall []struct {
+		manifest  []byte
+		contextID string
+		team      string
+		logger    *log.Logger
+	}
+	deployForTeamReturns struct {
+		result1 int
+		result2 error
+	}
+	deployForTeamReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	ChangeJobStateStub        func(deploymentName string, state boshdirector.JobState, contextID string, logger *log.Logger) (int, error)
+	changeJobStateMutex       sync.RWMutex
+	changeJobStateArgsForCall []struct {
+		deploymentName string
+		state          boshdirector.JobState
+		contextID      string
+		logger         *log.Logger
+	}
+	changeJobStateReturns struct {
+		result1 int
+		result2 error
+	}
+	changeJobStateReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	GetConfigStub        func(configType, name string, logger *log.Logger) ([]byte, bool, error)
+	getConfigMutex       sync.RWMutex
+	getConfigArgsForCall []struct {
+		configType string
+		name       string
+		logger     *log.Logger
+	}
+	getConfigReturns struct {
+		result1 []byte
+		result2 bool
+		result3 error
+	}
+	getConfigReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 bool
+		result3 error
+	}
+	UpdateConfigStub        func(configType, name string, content []byte, logger *log.Logger) error
+	updateConfigMutex       sync.RWMutex
+	updateConfigArgsForCall []struct {
+		configType string
+		name       string
+		content    []byte
+		logger     *log.Logger
+	}
+	updateConfigReturns struct {
+		result1 error
+	}
+	updateConfigReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteConfigStub        func(configType, name string, logger *log.Logger) error
+	deleteConfigMutex       sync.RWMutex
+	deleteConfigArgsForCall []struct {
+		configType string
+		name       string
+		logger     *log.Logger
+	}
+	deleteConfigReturns struct {
+		result1 error
+	}
+	deleteConfigReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetReleasesStub        func(logger *log.Logger) (boshdirector.Releases, error)
+	getReleasesMutex       sync.RWMutex
+	getReleasesArgsForCall []struct {
+		logger *log.Logger
+	}
+	getReleasesReturns struct {
+		result1 boshdirector.Releases
+		result2 error
+	}
+	getReleasesReturnsOnCall map[int]struct {
+		result1 boshdirector.Releases
+		result2 error
+	}
+	GetStemcellsStub        func(logger *log.Logger) (boshdirector.Stemcells, error)
+	getStemcellsMutex       sync.RWMutex
+	getStemcellsArgsForCall []struct {
+		logger *log.Logger
+	}
+	getStemcellsReturns struct {
+		result1 boshdirector.Stemcells
+		result2 error
+	}
+	getStemcellsReturnsOnCall map[int]struct {
+		result1 boshdirector.Stemcells
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -224,6 +341,441 @@ func (fake *FakeBoshClient) GetDeploymentReturnsOnCall(i int, result1 []byte, re
 	}{result1, result2, result3}
 }
 
+func (fake *FakeBoshClient) GetDeploymentDiff(name string, manifest []byte, logger *log.Logger) (boshdirector.DeploymentDiff, error) {
+	var manifestCopy []byte
+	if manifest != nil {
+		manifestCopy = make([]byte, len(manifest))
+		copy(manifestCopy, manifest)
+	}
+	fake.getDeploymentDiffMutex.Lock()
+	ret, specificReturn := fake.getDeploymentDiffReturnsOnCall[len(fake.getDeploymentDiffArgsForCall)]
+	fake.getDeploymentDiffArgsForCall = append(fake.getDeploymentDiffArgsForCall, struct {
+		name     string
+		manifest []byte
+		logger   *log.Logger
+	}{name, manifestCopy, logger})
+	fake.recordInvocation("GetDeploymentDiff", []interface{}{name, manifestCopy, logger})
+	fake.getDeploymentDiffMutex.Unlock()
+	if fake.GetDeploymentDiffStub != nil {
+		return fake.GetDeploymentDiffStub(name, manifest, logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getDeploymentDiffReturns.result1, fake.getDeploymentDiffReturns.result2
+}
+
+func (fake *FakeBoshClient) GetDeploymentDiffCallCount() int {
+	fake.getDeploymentDiffMutex.RLock()
+	defer fake.getDeploymentDiffMutex.RUnlock()
+	return len(fake.getDeploymentDiffArgsForCall)
+}
+
+func (fake *FakeBoshClient) GetDeploymentDiffArgsForCall(i int) (string, []byte, *log.Logger) {
+	fake.getDeploymentDiffMutex.RLock()
+	defer fake.getDeploymentDiffMutex.RUnlock()
+	return fake.getDeploymentDiffArgsForCall[i].name, fake.getDeploymentDiffArgsForCall[i].manifest, fake.getDeploymentDiffArgsForCall[i].logger
+}
+
+func (fake *FakeBoshClient) GetDeploymentDiffReturns(result1 boshdirector.DeploymentDiff, result2 error) {
+	fake.GetDeploymentDiffStub = nil
+	fake.getDeploymentDiffReturns = struct {
+		result1 boshdirector.DeploymentDiff
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) GetDeploymentDiffReturnsOnCall(i int, result1 boshdirector.DeploymentDiff, result2 error) {
+	fake.GetDeploymentDiffStub = nil
+	if fake.getDeploymentDiffReturnsOnCall == nil {
+		fake.getDeploymentDiffReturnsOnCall = make(map[int]struct {
+			result1 boshdirector.DeploymentDiff
+			result2 error
+		})
+	}
+	fake.getDeploymentDiffReturnsOnCall[i] = struct {
+		result1 boshdirector.DeploymentDiff
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) DeployForTeam(manifest []byte, contextID string, team string, logger *log.Logger) (int, error) {
+	var manifestCopy []byte
+	if manifest != nil {
+		manifestCopy = make([]byte, len(manifest))
+		copy(manifestCopy, manifest)
+	}
+	fake.deployForTeamMutex.Lock()
+	ret, specificReturn := fake.deployForTeamReturnsOnCall[len(fake.deployForTeamArgsForCall)]
+	fake.deployForTeamArgsForCall = append(fake.deployForTeamArgsForCall, struct {
+		manifest  []byte
+		contextID string
+		team      string
+		logger    *log.Logger
+	}{manifestCopy, contextID, team, logger})
+	fake.recordInvocation("DeployForTeam", []interface{}{manifestCopy, contextID, team, logger})
+	fake.deployForTeamMutex.Unlock()
+	if fake.DeployForTeamStub != nil {
+		return fake.DeployForTeamStub(manifest, contextID, team, logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.deployForTeamReturns.result1, fake.deployForTeamReturns.result2
+}
+
+func (fake *FakeBoshClient) DeployForTeamCallCount() int {
+	fake.deployForTeamMutex.RLock()
+	defer fake.deployForTeamMutex.RUnlock()
+	return len(fake.deployForTeamArgsForCall)
+}
+
+func (fake *FakeBoshClient) DeployForTeamArgsForCall(i int) ([]byte, string, string, *log.Logger) {
+	fake.deployForTeamMutex.RLock()
+	defer fake.deployForTeamMutex.RUnlock()
+	return fake.deployForTeamArgsForCall[i].manifest, fake.deployForTeamArgsForCall[i].contextID, fake.deployForTeamArgsForCall[i].team, fake.deployForTeamArgsForCall[i].logger
+}
+
+func (fake *FakeBoshClient) DeployForTeamReturns(result1 int, result2 error) {
+	fake.DeployForTeamStub = nil
+	fake.deployForTeamReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) DeployForTeamReturnsOnCall(i int, result1 int, result2 error) {
+	fake.DeployForTeamStub = nil
+	if fake.deployForTeamReturnsOnCall == nil {
+		fake.deployForTeamReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.deployForTeamReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) ChangeJobState(deploymentName string, state boshdirector.JobState, contextID string, logger *log.Logger) (int, error) {
+	fake.changeJobStateMutex.Lock()
+	ret, specificReturn := fake.changeJobStateReturnsOnCall[len(fake.changeJobStateArgsForCall)]
+	fake.changeJobStateArgsForCall = append(fake.changeJobStateArgsForCall, struct {
+		deploymentName string
+		state          boshdirector.JobState
+		contextID      string
+		logger         *log.Logger
+	}{deploymentName, state, contextID, logger})
+	fake.recordInvocation("ChangeJobState", []interface{}{deploymentName, state, contextID, logger})
+	fake.changeJobStateMutex.Unlock()
+	if fake.ChangeJobStateStub != nil {
+		return fake.ChangeJobStateStub(deploymentName, state, contextID, logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.changeJobStateReturns.result1, fake.changeJobStateReturns.result2
+}
+
+func (fake *FakeBoshClient) ChangeJobStateCallCount() int {
+	fake.changeJobStateMutex.RLock()
+	defer fake.changeJobStateMutex.RUnlock()
+	return len(fake.changeJobStateArgsForCall)
+}
+
+func (fake *FakeBoshClient) ChangeJobStateArgsForCall(i int) (string, boshdirector.JobState, string, *log.Logger) {
+	fake.changeJobStateMutex.RLock()
+	defer fake.changeJobStateMutex.RUnlock()
+	return fake.changeJobStateArgsForCall[i].deploymentName, fake.changeJobStateArgsForCall[i].state, fake.changeJobStateArgsForCall[i].contextID, fake.changeJobStateArgsForCall[i].logger
+}
+
+func (fake *FakeBoshClient) ChangeJobStateReturns(result1 int, result2 error) {
+	fake.ChangeJobStateStub = nil
+	fake.changeJobStateReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) ChangeJobStateReturnsOnCall(i int, result1 int, result2 error) {
+	fake.ChangeJobStateStub = nil
+	if fake.changeJobStateReturnsOnCall == nil {
+		fake.changeJobStateReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.changeJobStateReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) GetConfig(configType string, name string, logger *log.Logger) ([]byte, bool, error) {
+	fake.getConfigMutex.Lock()
+	ret, specificReturn := fake.getConfigReturnsOnCall[len(fake.getConfigArgsForCall)]
+	fake.getConfigArgsForCall = append(fake.getConfigArgsForCall, struct {
+		configType string
+		name       string
+		logger     *log.Logger
+	}{configType, name, logger})
+	fake.recordInvocation("GetConfig", []interface{}{configType, name, logger})
+	fake.getConfigMutex.Unlock()
+	if fake.GetConfigStub != nil {
+		return fake.GetConfigStub(configType, name, logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.getConfigReturns.result1, fake.getConfigReturns.result2, fake.getConfigReturns.result3
+}
+
+func (fake *FakeBoshClient) GetConfigCallCount() int {
+	fake.getConfigMutex.RLock()
+	defer fake.getConfigMutex.RUnlock()
+	return len(fake.getConfigArgsForCall)
+}
+
+func (fake *FakeBoshClient) GetConfigArgsForCall(i int) (string, string, *log.Logger) {
+	fake.getConfigMutex.RLock()
+	defer fake.getConfigMutex.RUnlock()
+	return fake.getConfigArgsForCall[i].configType, fake.getConfigArgsForCall[i].name, fake.getConfigArgsForCall[i].logger
+}
+
+func (fake *FakeBoshClient) GetConfigReturns(result1 []byte, result2 bool, result3 error) {
+	fake.GetConfigStub = nil
+	fake.getConfigReturns = struct {
+		result1 []byte
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeBoshClient) GetConfigReturnsOnCall(i int, result1 []byte, result2 bool, result3 error) {
+	fake.GetConfigStub = nil
+	if fake.getConfigReturnsOnCall == nil {
+		fake.getConfigReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 bool
+			result3 error
+		})
+	}
+	fake.getConfigReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeBoshClient) UpdateConfig(configType string, name string, content []byte, logger *log.Logger) error {
+	var contentCopy []byte
+	if content != nil {
+		contentCopy = make([]byte, len(content))
+		copy(contentCopy, content)
+	}
+	fake.updateConfigMutex.Lock()
+	ret, specificReturn := fake.updateConfigReturnsOnCall[len(fake.updateConfigArgsForCall)]
+	fake.updateConfigArgsForCall = append(fake.updateConfigArgsForCall, struct {
+		configType string
+		name       string
+		content    []byte
+		logger     *log.Logger
+	}{configType, name, contentCopy, logger})
+	fake.recordInvocation("UpdateConfig", []interface{}{configType, name, contentCopy, logger})
+	fake.updateConfigMutex.Unlock()
+	if fake.UpdateConfigStub != nil {
+		return fake.UpdateConfigStub(configType, name, content, logger)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.updateConfigReturns.result1
+}
+
+func (fake *FakeBoshClient) UpdateConfigCallCount() int {
+	fake.updateConfigMutex.RLock()
+	defer fake.updateConfigMutex.RUnlock()
+	return len(fake.updateConfigArgsForCall)
+}
+
+func (fake *FakeBoshClient) UpdateConfigArgsForCall(i int) (string, string, []byte, *log.Logger) {
+	fake.updateConfigMutex.RLock()
+	defer fake.updateConfigMutex.RUnlock()
+	return fake.updateConfigArgsForCall[i].configType, fake.updateConfigArgsForCall[i].name, fake.updateConfigArgsForCall[i].content, fake.updateConfigArgsForCall[i].logger
+}
+
+func (fake *FakeBoshClient) UpdateConfigReturns(result1 error) {
+	fake.UpdateConfigStub = nil
+	fake.updateConfigReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBoshClient) UpdateConfigReturnsOnCall(i int, result1 error) {
+	fake.UpdateConfigStub = nil
+	if fake.updateConfigReturnsOnCall == nil {
+		fake.updateConfigReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateConfigReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBoshClient) DeleteConfig(configType string, name string, logger *log.Logger) error {
+	fake.deleteConfigMutex.Lock()
+	ret, specificReturn := fake.deleteConfigReturnsOnCall[len(fake.deleteConfigArgsForCall)]
+	fake.deleteConfigArgsForCall = append(fake.deleteConfigArgsForCall, struct {
+		configType string
+		name       string
+		logger     *log.Logger
+	}{configType, name, logger})
+	fake.recordInvocation("DeleteConfig", []interface{}{configType, name, logger})
+	fake.deleteConfigMutex.Unlock()
+	if fake.DeleteConfigStub != nil {
+		return fake.DeleteConfigStub(configType, name, logger)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.deleteConfigReturns.result1
+}
+
+func (fake *FakeBoshClient) DeleteConfigCallCount() int {
+	fake.deleteConfigMutex.RLock()
+	defer fake.deleteConfigMutex.RUnlock()
+	return len(fake.deleteConfigArgsForCall)
+}
+
+func (fake *FakeBoshClient) DeleteConfigArgsForCall(i int) (string, string, *log.Logger) {
+	fake.deleteConfigMutex.RLock()
+	defer fake.deleteConfigMutex.RUnlock()
+	return fake.deleteConfigArgsForCall[i].configType, fake.deleteConfigArgsForCall[i].name, fake.deleteConfigArgsForCall[i].logger
+}
+
+func (fake *FakeBoshClient) DeleteConfigReturns(result1 error) {
+	fake.DeleteConfigStub = nil
+	fake.deleteConfigReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBoshClient) DeleteConfigReturnsOnCall(i int, result1 error) {
+	fake.DeleteConfigStub = nil
+	if fake.deleteConfigReturnsOnCall == nil {
+		fake.deleteConfigReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteConfigReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBoshClient) GetReleases(logger *log.Logger) (boshdirector.Releases, error) {
+	fake.getReleasesMutex.Lock()
+	ret, specificReturn := fake.getReleasesReturnsOnCall[len(fake.getReleasesArgsForCall)]
+	fake.getReleasesArgsForCall = append(fake.getReleasesArgsForCall, struct {
+		logger *log.Logger
+	}{logger})
+	fake.recordInvocation("GetReleases", []interface{}{logger})
+	fake.getReleasesMutex.Unlock()
+	if fake.GetReleasesStub != nil {
+		return fake.GetReleasesStub(logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getReleasesReturns.result1, fake.getReleasesReturns.result2
+}
+
+func (fake *FakeBoshClient) GetReleasesCallCount() int {
+	fake.getReleasesMutex.RLock()
+	defer fake.getReleasesMutex.RUnlock()
+	return len(fake.getReleasesArgsForCall)
+}
+
+func (fake *FakeBoshClient) GetReleasesArgsForCall(i int) *log.Logger {
+	fake.getReleasesMutex.RLock()
+	defer fake.getReleasesMutex.RUnlock()
+	return fake.getReleasesArgsForCall[i].logger
+}
+
+func (fake *FakeBoshClient) GetReleasesReturns(result1 boshdirector.Releases, result2 error) {
+	fake.GetReleasesStub = nil
+	fake.getReleasesReturns = struct {
+		result1 boshdirector.Releases
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) GetReleasesReturnsOnCall(i int, result1 boshdirector.Releases, result2 error) {
+	fake.GetReleasesStub = nil
+	if fake.getReleasesReturnsOnCall == nil {
+		fake.getReleasesReturnsOnCall = make(map[int]struct {
+			result1 boshdirector.Releases
+			result2 error
+		})
+	}
+	fake.getReleasesReturnsOnCall[i] = struct {
+		result1 boshdirector.Releases
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) GetStemcells(logger *log.Logger) (boshdirector.Stemcells, error) {
+	fake.getStemcellsMutex.Lock()
+	ret, specificReturn := fake.getStemcellsReturnsOnCall[len(fake.getStemcellsArgsForCall)]
+	fake.getStemcellsArgsForCall = append(fake.getStemcellsArgsForCall, struct {
+		logger *log.Logger
+	}{logger})
+	fake.recordInvocation("GetStemcells", []interface{}{logger})
+	fake.getStemcellsMutex.Unlock()
+	if fake.GetStemcellsStub != nil {
+		return fake.GetStemcellsStub(logger)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getStemcellsReturns.result1, fake.getStemcellsReturns.result2
+}
+
+func (fake *FakeBoshClient) GetStemcellsCallCount() int {
+	fake.getStemcellsMutex.RLock()
+	defer fake.getStemcellsMutex.RUnlock()
+	return len(fake.getStemcellsArgsForCall)
+}
+
+func (fake *FakeBoshClient) GetStemcellsArgsForCall(i int) *log.Logger {
+	fake.getStemcellsMutex.RLock()
+	defer fake.getStemcellsMutex.RUnlock()
+	return fake.getStemcellsArgsForCall[i].logger
+}
+
+func (fake *FakeBoshClient) GetStemcellsReturns(result1 boshdirector.Stemcells, result2 error) {
+	fake.GetStemcellsStub = nil
+	fake.getStemcellsReturns = struct {
+		result1 boshdirector.Stemcells
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBoshClient) GetStemcellsReturnsOnCall(i int, result1 boshdirector.Stemcells, result2 error) {
+	fake.GetStemcellsStub = nil
+	if fake.getStemcellsReturnsOnCall == nil {
+		fake.getStemcellsReturnsOnCall = make(map[int]struct {
+			result1 boshdirector.Stemcells
+			result2 error
+		})
+	}
+	fake.getStemcellsReturnsOnCall[i] = struct {
+		result1 boshdirector.Stemcells
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBoshClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -233,6 +785,22 @@ func (fake *FakeBoshClient) Invocations() map[string][][]interface{} {
 	defer fake.getTasksMutex.RUnlock()
 	fake.getDeploymentMutex.RLock()
 	defer fake.getDeploymentMutex.RUnlock()
+	fake.getDeploymentDiffMutex.RLock()
+	defer fake.getDeploymentDiffMutex.RUnlock()
+	fake.deployForTeamMutex.RLock()
+	defer fake.deployForTeamMutex.RUnlock()
+	fake.changeJobStateMutex.RLock()
+	defer fake.changeJobStateMutex.RUnlock()
+	fake.getConfigMutex.RLock()
+	defer fake.getConfigMutex.RUnlock()
+	fake.updateConfigMutex.RLock()
+	defer fake.updateConfigMutex.RUnlock()
+	fake.deleteConfigMutex.RLock()
+	defer fake.deleteConfigMutex.RUnlock()
+	fake.getReleasesMutex.RLock()
+	defer fake.getReleasesMutex.RUnlock()
+	fake.getStemcellsMutex.RLock()
+	defer fake.getStemcellsMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -0,0 +1,96 @@
+// This file was generated by counterfeiter
+package fakes
+
+import (
+	"sync"
+
+	"github.com/pivotal-cf/on-demand-service-broker/task"
+)
+
+type FakeBoshTeams struct {
+	TeamForStub        func(planID, orgGUID string) string
+	teamForMutex       sync.RWMutex
+	teamForArgsForCall []struct {
+		planID  string
+		orgGUID string
+	}
+	teamForReturns struct {
+		result1 string
+	}
+	teamForReturnsOnCall map[int]struct {
+		result1 string
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeBoshTeams) TeamFor(planID string, orgGUID string) string {
+	fake.teamForMutex.Lock()
+	ret, specificReturn := fake.teamForReturnsOnCall[len(fake.teamForArgsForCall)]
+	fake.teamForArgsForCall = append(fake.teamForArgsForCall, struct {
+		planID  string
+		orgGUID string
+	}{planID, orgGUID})
+	fake.recordInvocation("TeamFor", []interface{}{planID, orgGUID})
+	fake.teamForMutex.Unlock()
+	if fake.TeamForStub != nil {
+		return fake.TeamForStub(planID, orgGUID)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.teamForReturns.result1
+}
+
+func (fake *FakeBoshTeams) TeamForCallCount() int {
+	fake.teamForMutex.RLock()
+	defer fake.teamForMutex.RUnlock()
+	return len(fake.teamForArgsForCall)
+}
+
+func (fake *FakeBoshTeams) TeamForArgsForCall(i int) (string, string) {
+	fake.teamForMutex.RLock()
+	defer fake.teamForMutex.RUnlock()
+	return fake.teamForArgsForCall[i].planID, fake.teamForArgsForCall[i].orgGUID
+}
+
+func (fake *FakeBoshTeams) TeamForReturns(result1 string) {
+	fake.TeamForStub = nil
+	fake.teamForReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeBoshTeams) TeamForReturnsOnCall(i int, result1 string) {
+	fake.TeamForStub = nil
+	if fake.teamForReturnsOnCall == nil {
+		fake.teamForReturnsOnCall = make(map[int]struct {
+			result1 string
+		})
+	}
+	fake.teamForReturnsOnCall[i] = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeBoshTeams) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.teamForMutex.RLock()
+	defer fake.teamForMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeBoshTeams) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ task.BoshTeams = new(FakeBoshTeams)
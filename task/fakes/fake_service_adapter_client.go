@@ -2,23 +2,26 @@
 package fakes
 
 import (
+	"context"
 	"log"
 	"sync"
 
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 	"github.com/pivotal-cf/on-demand-service-broker/task"
-	"github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
 )
 
 type FakeServiceAdapterClient struct {
-	GenerateManifestStub        func(serviceReleases serviceadapter.ServiceDeployment, plan serviceadapter.Plan, requestParams map[string]interface{}, previousManifest []byte, previousPlan *serviceadapter.Plan, logger *log.Logger) ([]byte, error)
+	GenerateManifestStub        func(ctx context.Context, serviceDeployment serviceadapter.ServiceDeployment, plan sdk.Plan, requestParams map[string]interface{}, previousManifest []byte, previousPlan *sdk.Plan, logger *log.Logger) ([]byte, error)
 	generateManifestMutex       sync.RWMutex
 	generateManifestArgsForCall []struct {
-		serviceReleases  serviceadapter.ServiceDeployment
-		plan             serviceadapter.Plan
-		requestParams    map[string]interface{}
-		previousManifest []byte
-		previousPlan     *serviceadapter.Plan
-		logger           *log.Logger
+		ctx               context.Context
+		serviceDeployment serviceadapter.ServiceDeployment
+		plan              sdk.Plan
+		requestParams     map[string]interface{}
+		previousManifest  []byte
+		previousPlan      *sdk.Plan
+		logger            *log.Logger
 	}
 	generateManifestReturns struct {
 		result1 []byte
@@ -32,7 +35,7 @@ type FakeServiceAdapterClient struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeServiceAdapterClient) GenerateManifest(serviceReleases serviceadapter.ServiceDeployment, plan serviceadapter.Plan, requestParams map[string]interface{}, previousManifest []byte, previousPlan *serviceadapter.Plan, logger *log.Logger) ([]byte, error) {
+func (fake *FakeServiceAdapterClient) GenerateManifest(ctx context.Context, serviceDeployment serviceadapter.ServiceDeployment, plan sdk.Plan, requestParams map[string]interface{}, previousManifest []byte, previousPlan *sdk.Plan, logger *log.Logger) ([]byte, error) {
 	var previousManifestCopy []byte
 	if previousManifest != nil {
 		previousManifestCopy = make([]byte, len(previousManifest))
@@ -41,17 +44,18 @@ func (fake *FakeServiceAdapterClient) GenerateManifest(serviceReleases servicead
 	fake.generateManifestMutex.Lock()
 	ret, specificReturn := fake.generateManifestReturnsOnCall[len(fake.generateManifestArgsForCall)]
 	fake.generateManifestArgsForCall = append(fake.generateManifestArgsForCall, struct {
-		serviceReleases  serviceadapter.ServiceDeployment
-		plan             serviceadapter.Plan
-		requestParams    map[string]interface{}
-		previousManifest []byte
-		previousPlan     *serviceadapter.Plan
-		logger           *log.Logger
-	}{serviceReleases, plan, requestParams, previousManifestCopy, previousPlan, logger})
-	fake.recordInvocation("GenerateManifest", []interface{}{serviceReleases, plan, requestParams, previousManifestCopy, previousPlan, logger})
+		ctx               context.Context
+		serviceDeployment serviceadapter.ServiceDeployment
+		plan              sdk.Plan
+		requestParams     map[string]interface{}
+		previousManifest  []byte
+		previousPlan      *sdk.Plan
+		logger            *log.Logger
+	}{ctx, serviceDeployment, plan, requestParams, previousManifestCopy, previousPlan, logger})
+	fake.recordInvocation("GenerateManifest", []interface{}{ctx, serviceDeployment, plan, requestParams, previousManifestCopy, previousPlan, logger})
 	fake.generateManifestMutex.Unlock()
 	if fake.GenerateManifestStub != nil {
-		return fake.GenerateManifestStub(serviceReleases, plan, requestParams, previousManifest, previousPlan, logger)
+		return fake.GenerateManifestStub(ctx, serviceDeployment, plan, requestParams, previousManifest, previousPlan, logger)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -65,10 +69,10 @@ func (fake *FakeServiceAdapterClient) GenerateManifestCallCount() int {
 	return len(fake.generateManifestArgsForCall)
 }
 
-func (fake *FakeServiceAdapterClient) GenerateManifestArgsForCall(i int) (serviceadapter.ServiceDeployment, serviceadapter.Plan, map[string]interface{}, []byte, *serviceadapter.Plan, *log.Logger) {
+func (fake *FakeServiceAdapterClient) GenerateManifestArgsForCall(i int) (context.Context, serviceadapter.ServiceDeployment, sdk.Plan, map[string]interface{}, []byte, *sdk.Plan, *log.Logger) {
 	fake.generateManifestMutex.RLock()
 	defer fake.generateManifestMutex.RUnlock()
-	return fake.generateManifestArgsForCall[i].serviceReleases, fake.generateManifestArgsForCall[i].plan, fake.generateManifestArgsForCall[i].requestParams, fake.generateManifestArgsForCall[i].previousManifest, fake.generateManifestArgsForCall[i].previousPlan, fake.generateManifestArgsForCall[i].logger
+	return fake.generateManifestArgsForCall[i].ctx, fake.generateManifestArgsForCall[i].serviceDeployment, fake.generateManifestArgsForCall[i].plan, fake.generateManifestArgsForCall[i].requestParams, fake.generateManifestArgsForCall[i].previousManifest, fake.generateManifestArgsForCall[i].previousPlan, fake.generateManifestArgsForCall[i].logger
 }
 
 func (fake *FakeServiceAdapterClient) GenerateManifestReturns(result1 []byte, result2 error) {
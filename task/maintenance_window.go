@@ -0,0 +1,96 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/pivotal-cf/on-demand-service-broker/config"
+)
+
+// MaintenanceWindowParameterKey is the arbitrary parameter under which a developer can
+// restrict when the instance is upgraded, overriding the plan's maintenance window
+const MaintenanceWindowParameterKey = "maintenance_window"
+
+// MaintenanceWindowConfigType is the type of the director configs in which instances' maintenance
+// windows are kept, named after the deployment, so that they outlive the request that set them
+const MaintenanceWindowConfigType = "odb-maintenance-window"
+
+// MaintenanceWindowFromParams returns the maintenance window set by the request, and whether the
+// request set one at all. A null window clears a previously set window.
+func MaintenanceWindowFromParams(requestParams map[string]interface{}) (*config.MaintenanceWindow, bool, error) {
+	parameters, ok := requestParams["parameters"].(map[string]interface{})
+	if !ok {
+		return nil, false, nil
+	}
+
+	rawWindow, found := parameters[MaintenanceWindowParameterKey]
+	if !found {
+		return nil, false, nil
+	}
+	if rawWindow == nil {
+		return nil, true, nil
+	}
+
+	window, err := parseMaintenanceWindow(rawWindow)
+	if err != nil {
+		return nil, true, fmt.Errorf("parameter %s is invalid: %s", MaintenanceWindowParameterKey, err)
+	}
+	return window, true, nil
+}
+
+// MaintenanceWindowOf parses the content of a maintenance window config
+func MaintenanceWindowOf(content []byte) (*config.MaintenanceWindow, error) {
+	window := new(config.MaintenanceWindow)
+	if err := json.Unmarshal(content, window); err != nil {
+		return nil, fmt.Errorf("%s config is invalid: %s", MaintenanceWindowConfigType, err)
+	}
+	return window, nil
+}
+
+// storeMaintenanceWindow keeps the window set by the request, or forgets the instance's window
+// when the request clears it. Requests that do not set a window leave the kept one as it is.
+func (d deployer) storeMaintenanceWindow(deploymentName string, requestParams map[string]interface{}, logger *log.Logger) error {
+	window, set, err := MaintenanceWindowFromParams(requestParams)
+	if err != nil || !set {
+		return err
+	}
+
+	if window == nil {
+		if err := d.boshClient.DeleteConfig(MaintenanceWindowConfigType, deploymentName, logger); err != nil {
+			return fmt.Errorf("error clearing maintenance window of deployment %s: %s", deploymentName, err)
+		}
+		return nil
+	}
+
+	serialised, err := json.Marshal(window)
+	if err != nil {
+		return err
+	}
+	if err := d.boshClient.UpdateConfig(MaintenanceWindowConfigType, deploymentName, serialised, logger); err != nil {
+		return fmt.Errorf("error storing maintenance window of deployment %s: %s", deploymentName, err)
+	}
+	return nil
+}
+
+func parseMaintenanceWindow(rawWindow interface{}) (*config.MaintenanceWindow, error) {
+	asJSON, err := json.Marshal(rawWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	window := new(config.MaintenanceWindow)
+	if err := json.Unmarshal(asJSON, window); err != nil {
+		return nil, fmt.Errorf("must be an object with days, start and duration")
+	}
+	if err := window.Validate(); err != nil {
+		return nil, err
+	}
+	return window, nil
+}
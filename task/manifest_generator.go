@@ -7,45 +7,50 @@
 package task
 
 import (
+	"context"
+	"fmt"
 	"log"
-	"reflect"
 
 	"github.com/pivotal-cf/on-demand-service-broker/config"
-	"github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
+	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
 	yaml "gopkg.in/yaml.v2"
 )
 
 //go:generate counterfeiter -o fakes/fake_service_adapter_client.go . ServiceAdapterClient
 type ServiceAdapterClient interface {
-	GenerateManifest(serviceReleases serviceadapter.ServiceDeployment, plan serviceadapter.Plan, requestParams map[string]interface{}, previousManifest []byte, previousPlan *serviceadapter.Plan, logger *log.Logger) ([]byte, error)
+	GenerateManifest(ctx context.Context, serviceDeployment serviceadapter.ServiceDeployment, plan sdk.Plan, requestParams map[string]interface{}, previousManifest []byte, previousPlan *sdk.Plan, logger *log.Logger) ([]byte, error)
 }
 
 type manifestGenerator struct {
-	adapterClient   ServiceAdapterClient
-	serviceOffering config.ServiceOffering
-	serviceStemcell serviceadapter.Stemcell
-	serviceReleases serviceadapter.ServiceReleases
+	adapterClient     ServiceAdapterClient
+	serviceOffering   *config.ServiceOfferingStore
+	serviceDeployment config.ServiceDeployment
+	tagDeployments    bool
 }
 
 func NewManifestGenerator(
 	serviceAdapter ServiceAdapterClient,
-	serviceOffering config.ServiceOffering,
-	serviceStemcell serviceadapter.Stemcell,
-	serviceReleases serviceadapter.ServiceReleases,
+	serviceOffering *config.ServiceOfferingStore,
+	serviceDeployment config.ServiceDeployment,
+	tagDeployments bool,
 ) manifestGenerator {
 	return manifestGenerator{
-		adapterClient:   serviceAdapter,
-		serviceOffering: serviceOffering,
-		serviceStemcell: serviceStemcell,
-		serviceReleases: serviceReleases,
+		adapterClient:     serviceAdapter,
+		serviceOffering:   serviceOffering,
+		serviceDeployment: serviceDeployment,
+		tagDeployments:    tagDeployments,
 	}
 }
 
 type BoshManifest []byte
 
+// Equals compares manifests structurally. The broker interpolates placeholders of variables that
+// are not declared in the manifest before deploying, so those match whatever value is on the other
+// side. Any other placeholder only matches itself.
 func (m BoshManifest) Equals(other BoshManifest) (bool, error) {
-	var thisMap map[string]interface{}
-	var thatMap map[string]interface{}
+	var thisMap map[interface{}]interface{}
+	var thatMap map[interface{}]interface{}
 
 	err := yaml.Unmarshal(m, &thisMap)
 	if err != nil {
@@ -56,10 +61,22 @@ func (m BoshManifest) Equals(other BoshManifest) (bool, error) {
 		return false, err
 	}
 
-	return reflect.DeepEqual(thisMap, thatMap), nil
+	declared := map[string]bool{}
+	for _, manifest := range []BoshManifest{m, other} {
+		manifestDeclared, err := declaredVariables(manifest)
+		if err != nil {
+			return false, err
+		}
+		for name := range manifestDeclared {
+			declared[name] = true
+		}
+	}
+
+	return manifestValuesEqual(thisMap, thatMap, declared), nil
 }
 
 func (m manifestGenerator) GenerateManifest(
+	ctx context.Context,
 	deploymentName, planID string,
 	requestParams map[string]interface{},
 	oldManifest []byte,
@@ -68,9 +85,18 @@ func (m manifestGenerator) GenerateManifest(
 ) (BoshManifest, error) {
 
 	serviceDeployment := serviceadapter.ServiceDeployment{
-		DeploymentName: deploymentName,
-		Releases:       m.serviceReleases,
-		Stemcell:       m.serviceStemcell,
+		ServiceDeployment: sdk.ServiceDeployment{
+			DeploymentName: deploymentName,
+			Releases:       m.serviceDeployment.Releases,
+			Stemcell:       m.serviceDeployment.DefaultStemcell(),
+		},
+	}
+	for _, stemcell := range m.serviceDeployment.Stemcells {
+		serviceDeployment.Stemcells = append(serviceDeployment.Stemcells, serviceadapter.Stemcell{
+			Alias:   stemcell.Alias,
+			OS:      stemcell.OS,
+			Version: stemcell.Version,
+		})
 	}
 
 	plan, previousPlan, err := m.findPlans(planID, previousPlanID)
@@ -79,20 +105,42 @@ func (m manifestGenerator) GenerateManifest(
 		return nil, err
 	}
 
+	if _, _, err := MaintenanceWindowFromParams(requestParams); err != nil {
+		logger.Println(err)
+		return nil, err
+	}
+
+	if _, _, err := UpgradePolicyFromParams(requestParams); err != nil {
+		logger.Println(err)
+		return nil, err
+	}
+
 	logger.Printf("service adapter will generate manifest for deployment %s\n", deploymentName)
 
-	manifest, err := m.adapterClient.GenerateManifest(serviceDeployment, plan, requestParams, oldManifest, previousPlan, logger)
+	manifest, err := m.adapterClient.GenerateManifest(ctx, serviceDeployment, plan, requestParams, oldManifest, previousPlan, logger)
 	if err != nil {
 		logger.Printf("generate manifest: %v\n", err)
+		return manifest, err
+	}
+
+	var tags []yaml.MapItem
+	if m.tagDeployments {
+		tags = append(tags, yaml.MapItem{Key: ServiceOfferingTag, Value: m.serviceOffering.Load().ID})
+	}
+
+	manifest, err = tagManifest(manifest, tags...)
+	if err != nil {
+		logger.Printf("error tagging manifest: %s\n", err)
+		return nil, fmt.Errorf("error tagging manifest for deployment %s: %s", deploymentName, err)
 	}
 
-	return manifest, err
+	return manifest, nil
 }
 
-func (m manifestGenerator) findPlans(planID string, previousPlanID *string) (serviceadapter.Plan, *serviceadapter.Plan, error) {
+func (m manifestGenerator) findPlans(planID string, previousPlanID *string) (sdk.Plan, *sdk.Plan, error) {
 	plan, err := m.findPlan(planID)
 	if err != nil {
-		return serviceadapter.Plan{}, nil, err
+		return sdk.Plan{}, nil, err
 	}
 
 	if previousPlanID == nil {
@@ -101,27 +149,29 @@ func (m manifestGenerator) findPlans(planID string, previousPlanID *string) (ser
 
 	previousPlan, err := m.findPreviousPlan(*previousPlanID)
 	if err != nil {
-		return serviceadapter.Plan{}, nil, err
+		return sdk.Plan{}, nil, err
 	}
 
 	return plan, previousPlan, nil
 }
 
-func (m manifestGenerator) findPlan(planID string) (serviceadapter.Plan, error) {
-	plan, found := m.serviceOffering.FindPlanByID(planID)
+func (m manifestGenerator) findPlan(planID string) (sdk.Plan, error) {
+	serviceOffering := m.serviceOffering.Load()
+	plan, found := serviceOffering.FindPlanByID(planID)
 	if !found {
-		return serviceadapter.Plan{}, PlanNotFoundError{PlanGUID: planID}
+		return sdk.Plan{}, PlanNotFoundError{PlanGUID: planID}
 	}
 
-	return plan.AdapterPlan(m.serviceOffering.GlobalProperties), nil
+	return plan.AdapterPlan(serviceOffering.GlobalProperties), nil
 }
 
-func (m manifestGenerator) findPreviousPlan(previousPlanID string) (*serviceadapter.Plan, error) {
-	previousPlan, found := m.serviceOffering.FindPlanByID(previousPlanID)
+func (m manifestGenerator) findPreviousPlan(previousPlanID string) (*sdk.Plan, error) {
+	serviceOffering := m.serviceOffering.Load()
+	previousPlan, found := serviceOffering.FindPlanByID(previousPlanID)
 	if !found {
-		return new(serviceadapter.Plan), PlanNotFoundError{PlanGUID: previousPlanID}
+		return new(sdk.Plan), PlanNotFoundError{PlanGUID: previousPlanID}
 	}
 
-	abridgedPlan := previousPlan.AdapterPlan(m.serviceOffering.GlobalProperties)
+	abridgedPlan := previousPlan.AdapterPlan(serviceOffering.GlobalProperties)
 	return &abridgedPlan, nil
 }
@@ -7,23 +7,24 @@
 package task_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pivotal-cf/on-demand-service-broker/config"
+	"github.com/pivotal-cf/on-demand-service-broker/serviceadapter"
 	. "github.com/pivotal-cf/on-demand-service-broker/task"
 	"github.com/pivotal-cf/on-demand-service-broker/task/fakes"
-	"github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
+	sdk "github.com/pivotal-cf/on-demand-services-sdk/serviceadapter"
 )
 
 var _ = Describe("Manifest Generator", func() {
 	Describe("GenerateManifest", func() {
 		var (
 			mg              ManifestGenerator
-			serviceReleases serviceadapter.ServiceReleases
-			serviceStemcell serviceadapter.Stemcell
+			serviceDeployment config.ServiceDeployment
 
 			manifest []byte
 
@@ -38,6 +39,7 @@ var _ = Describe("Manifest Generator", func() {
 			previousPlanID *string
 			requestParams  map[string]interface{}
 			oldManifest    []byte
+			tagDeployments bool
 		)
 
 		BeforeEach(func() {
@@ -54,7 +56,7 @@ var _ = Describe("Manifest Generator", func() {
 			existingPlan = config.Plan{
 				ID:   existingPlanID,
 				Name: existingPlanName,
-				Update: &serviceadapter.Update{
+				Update: &sdk.Update{
 					Canaries:        1,
 					CanaryWatchTime: "100-200",
 					UpdateWatchTime: "100-200",
@@ -63,10 +65,10 @@ var _ = Describe("Manifest Generator", func() {
 				Quotas: config.Quotas{
 					ServiceInstanceLimit: &planServiceInstanceLimit,
 				},
-				Properties: serviceadapter.Properties{
+				Properties: sdk.Properties{
 					"super": "no",
 				},
-				InstanceGroups: []serviceadapter.InstanceGroup{
+				InstanceGroups: []sdk.InstanceGroup{
 					{
 						Name:               existingPlanInstanceGroupName,
 						VMType:             "vm-type",
@@ -86,11 +88,11 @@ var _ = Describe("Manifest Generator", func() {
 
 			secondPlan = config.Plan{
 				ID: secondPlanID,
-				Properties: serviceadapter.Properties{
+				Properties: sdk.Properties{
 					"super":             "yes",
 					"a_global_property": "overrides_global_value",
 				},
-				InstanceGroups: []serviceadapter.InstanceGroup{
+				InstanceGroups: []sdk.InstanceGroup{
 					{
 						Name:               existingPlanInstanceGroupName,
 						VMType:             "vm-type1",
@@ -105,7 +107,7 @@ var _ = Describe("Manifest Generator", func() {
 			serviceCatalog = config.ServiceOffering{
 				ID:               serviceOfferingID,
 				Name:             "a-cool-redis-service",
-				GlobalProperties: serviceadapter.Properties{"a_global_property": "global_value", "some_other_global_property": "other_global_value"},
+				GlobalProperties: sdk.Properties{"a_global_property": "global_value", "some_other_global_property": "other_global_value"},
 				GlobalQuotas: config.Quotas{
 					ServiceInstanceLimit: &globalServiceInstanceLimit,
 				},
@@ -115,30 +117,32 @@ var _ = Describe("Manifest Generator", func() {
 				},
 			}
 
-			serviceReleases = serviceadapter.ServiceReleases{{
-				Name:    "name",
-				Version: "vers",
-				Jobs:    []string{"a", "b"},
-			}}
-
-			serviceStemcell = serviceadapter.Stemcell{
-				OS:      "ubuntu-trusty",
-				Version: "1234",
+			serviceDeployment = config.ServiceDeployment{
+				Releases: sdk.ServiceReleases{{
+					Name:    "name",
+					Version: "vers",
+					Jobs:    []string{"a", "b"},
+				}},
+				Stemcell: sdk.Stemcell{
+					OS:      "ubuntu-trusty",
+					Version: "1234",
+				},
 			}
 
 			serviceAdapter = new(fakes.FakeServiceAdapterClient)
 
-			mg = NewManifestGenerator(
-				serviceAdapter,
-				serviceCatalog,
-				serviceStemcell,
-				serviceReleases,
-			)
-			oldManifest = []byte("oldmanifest")
+			tagDeployments = false
+			oldManifest = []byte("name: old-deployment")
 		})
 
 		JustBeforeEach(func() {
-			manifest, err = mg.GenerateManifest(deploymentName, planGUID, requestParams, oldManifest, previousPlanID, logger)
+			mg = NewManifestGenerator(
+				serviceAdapter,
+				config.NewServiceOfferingStore(serviceCatalog),
+				serviceDeployment,
+				tagDeployments,
+			)
+			manifest, err = mg.GenerateManifest(context.Background(), deploymentName, planGUID, requestParams, oldManifest, previousPlanID, logger)
 		})
 
 		Context("when called with correct arguments", func() {
@@ -165,33 +169,54 @@ var _ = Describe("Manifest Generator", func() {
 			})
 
 			It("calls the service adapter with the service deployment", func() {
-				passedServiceDeployment, _, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
+				_, passedServiceDeployment, _, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
 				expectedServiceDeployment := serviceadapter.ServiceDeployment{
-					DeploymentName: deploymentName,
-					Releases:       serviceReleases,
-					Stemcell:       serviceStemcell,
+					ServiceDeployment: sdk.ServiceDeployment{
+						DeploymentName: deploymentName,
+						Releases:       serviceDeployment.Releases,
+						Stemcell:       serviceDeployment.Stemcell,
+					},
 				}
 				Expect(passedServiceDeployment).To(Equal(expectedServiceDeployment))
 			})
 
+			Context("and multiple aliased stemcells are configured", func() {
+				BeforeEach(func() {
+					serviceDeployment.Stemcell = sdk.Stemcell{}
+					serviceDeployment.Stemcells = []config.Stemcell{
+						{Alias: "linux", OS: "ubuntu-trusty", Version: "1234"},
+						{Alias: "windows", OS: "windows2012R2", Version: "1200.3"},
+					}
+				})
+
+				It("calls the service adapter with the stemcells, defaulting the single stemcell to the first", func() {
+					_, passedServiceDeployment, _, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
+					Expect(passedServiceDeployment.Stemcell).To(Equal(sdk.Stemcell{OS: "ubuntu-trusty", Version: "1234"}))
+					Expect(passedServiceDeployment.Stemcells).To(Equal([]serviceadapter.Stemcell{
+						{Alias: "linux", OS: "ubuntu-trusty", Version: "1234"},
+						{Alias: "windows", OS: "windows2012R2", Version: "1200.3"},
+					}))
+				})
+			})
+
 			It("calls the service adapter with the plan", func() {
-				_, passedPlan, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
+				_, _, passedPlan, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
 				Expect(passedPlan.InstanceGroups).To(Equal(existingPlan.InstanceGroups))
 			})
 
 			It("calls the service adapter with the request params", func() {
-				_, _, passedRequestParams, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
+				_, _, _, passedRequestParams, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
 				Expect(passedRequestParams).To(Equal(requestParams))
 			})
 
 			It("calls the service adapter with the old manifest", func() {
-				_, _, _, passedOldManifest, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
+				_, _, _, _, passedOldManifest, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
 				Expect(passedOldManifest).To(Equal(oldManifest))
 			})
 
 			It("merges global and plan properties", func() {
-				_, actualPlan, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
-				expectedProperties := serviceadapter.Properties{
+				_, _, actualPlan, _, _, _, _ := serviceAdapter.GenerateManifestArgsForCall(0)
+				expectedProperties := sdk.Properties{
 					"a_global_property":          "global_value",
 					"some_other_global_property": "other_global_value",
 					"super": "no",
@@ -206,13 +231,13 @@ var _ = Describe("Manifest Generator", func() {
 				})
 
 				It("calls the service adapter with the previous plan", func() {
-					_, _, _, _, passedPreviousPlan, _ := serviceAdapter.GenerateManifestArgsForCall(0)
+					_, _, _, _, _, passedPreviousPlan, _ := serviceAdapter.GenerateManifestArgsForCall(0)
 					Expect(passedPreviousPlan.InstanceGroups).To(Equal(secondPlan.InstanceGroups))
 				})
 
 				It("merges global and previous plan properties, overriding global with plan props", func() {
-					_, _, _, _, previousPlan, _ := serviceAdapter.GenerateManifestArgsForCall(0)
-					expectedProperties := serviceadapter.Properties{
+					_, _, _, _, _, previousPlan, _ := serviceAdapter.GenerateManifestArgsForCall(0)
+					expectedProperties := sdk.Properties{
 						"a_global_property":          "overrides_global_value",
 						"some_other_global_property": "other_global_value",
 						"super": "yes",
@@ -228,12 +253,118 @@ var _ = Describe("Manifest Generator", func() {
 				})
 
 				It("calls the service adapter with the nil previous plan", func() {
-					_, _, _, _, passedPreviousPlan, _ := serviceAdapter.GenerateManifestArgsForCall(0)
+					_, _, _, _, _, passedPreviousPlan, _ := serviceAdapter.GenerateManifestArgsForCall(0)
 					Expect(passedPreviousPlan).To(BeNil())
 				})
 			})
 		})
 
+		Context("when deployments are tagged", func() {
+			BeforeEach(func() {
+				tagDeployments = true
+			})
+
+			Context("and the adapter manifest has no tags", func() {
+				BeforeEach(func() {
+					serviceAdapter.GenerateManifestReturns([]byte("name: some-deployment\nreleases: []\n"), nil)
+				})
+
+				It("adds the service offering tag after the adapter's content", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(manifest)).To(Equal(fmt.Sprintf("name: some-deployment\nreleases: []\ntags:\n  odb-service-offering-id: %s\n", serviceOfferingID)))
+				})
+			})
+
+			Context("and the adapter manifest has tags", func() {
+				BeforeEach(func() {
+					serviceAdapter.GenerateManifestReturns([]byte("name: some-deployment\ntags:\n  team: data\n  odb-service-offering-id: wrong\n"), nil)
+				})
+
+				It("keeps the adapter's tags and sets the service offering tag", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(manifest)).To(Equal(fmt.Sprintf("name: some-deployment\ntags:\n  team: data\n  odb-service-offering-id: %s\n", serviceOfferingID)))
+				})
+			})
+
+			Context("and the adapter manifest is not valid yaml", func() {
+				BeforeEach(func() {
+					serviceAdapter.GenerateManifestReturns([]byte("{invalid"), nil)
+				})
+
+				It("returns an error", func() {
+					Expect(err).To(MatchError(ContainSubstring("error tagging manifest for deployment")))
+				})
+			})
+		})
+
+		Describe("maintenance windows", func() {
+			BeforeEach(func() {
+				serviceAdapter.GenerateManifestReturns([]byte("name: some-deployment\n"), nil)
+			})
+
+			Context("when the request sets a maintenance window", func() {
+				BeforeEach(func() {
+					requestParams = map[string]interface{}{
+						"parameters": map[string]interface{}{
+							"maintenance_window": map[string]interface{}{"days": []interface{}{"sat"}, "start": "02:00", "duration": "4h"},
+						},
+					}
+				})
+
+				It("does not put the window in the manifest", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(manifest)).To(Equal("name: some-deployment\n"))
+				})
+			})
+
+			Context("when the request sets an invalid maintenance window", func() {
+				BeforeEach(func() {
+					requestParams = map[string]interface{}{
+						"parameters": map[string]interface{}{
+							"maintenance_window": map[string]interface{}{"start": "noon", "duration": "4h"},
+						},
+					}
+				})
+
+				It("fails without generating a manifest", func() {
+					Expect(serviceAdapter.GenerateManifestCallCount()).To(Equal(0))
+					Expect(err).To(MatchError("parameter maintenance_window is invalid: start must be a UTC time of day in the form HH:MM, got 'noon'"))
+				})
+			})
+		})
+
+		Describe("upgrade policies", func() {
+			BeforeEach(func() {
+				serviceAdapter.GenerateManifestReturns([]byte("name: some-deployment\n"), nil)
+			})
+
+			Context("when the request defers upgrades", func() {
+				BeforeEach(func() {
+					requestParams = map[string]interface{}{
+						"parameters": map[string]interface{}{"upgrade_policy": "defer"},
+					}
+				})
+
+				It("does not put the deferral in the manifest", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(manifest)).To(Equal("name: some-deployment\n"))
+				})
+			})
+
+			Context("when the request sets an unknown policy", func() {
+				BeforeEach(func() {
+					requestParams = map[string]interface{}{
+						"parameters": map[string]interface{}{"upgrade_policy": "never"},
+					}
+				})
+
+				It("fails without generating a manifest", func() {
+					Expect(serviceAdapter.GenerateManifestCallCount()).To(Equal(0))
+					Expect(err).To(MatchError("parameter upgrade_policy must be one of 'auto' or 'defer'"))
+				})
+			})
+		})
+
 		Context("when the plan cannot be found", func() {
 			BeforeEach(func() {
 				planGUID = "invalid-id"
@@ -312,6 +443,110 @@ age: old`)
 			_, err := manifestOne.Equals(invalidManifest)
 			Expect(err).To(HaveOccurred())
 		})
+
+		Context("when the manifests contain variable placeholders", func() {
+			withPlaceholders := BoshManifest(`---
+name: foo
+properties:
+  password: ((password))
+  url: https://((host)):8443
+variables:
+- name: password
+  type: password`)
+
+			It("returns true when the other manifest has the same placeholders", func() {
+				Expect(withPlaceholders.Equals(withPlaceholders)).To(BeTrue())
+			})
+
+			It("returns true when the other manifest has values the broker interpolated", func() {
+				interpolated := BoshManifest(`---
+name: foo
+properties:
+  password: ((password))
+  url: https://example.com:8443
+variables:
+- name: password
+  type: password`)
+				Expect(withPlaceholders.Equals(interpolated)).To(BeTrue())
+				Expect(interpolated.Equals(withPlaceholders)).To(BeTrue())
+			})
+
+			It("returns false when the other manifest has a value for a declared variable", func() {
+				literalPassword := BoshManifest(`---
+name: foo
+properties:
+  password: some-password
+  url: https://((host)):8443
+variables:
+- name: password
+  type: password`)
+				Expect(withPlaceholders.Equals(literalPassword)).To(BeFalse())
+				Expect(literalPassword.Equals(withPlaceholders)).To(BeFalse())
+			})
+
+			It("returns false when the other manifest has different placeholders", func() {
+				differentPlaceholders := BoshManifest(`---
+name: foo
+properties:
+  password: ((other-password))
+  url: https://((other-host)):8443
+variables:
+- name: password
+  type: password`)
+				Expect(withPlaceholders.Equals(differentPlaceholders)).To(BeFalse())
+				Expect(differentPlaceholders.Equals(withPlaceholders)).To(BeFalse())
+			})
+
+			It("matches declared placeholders literally within interpolated strings", func() {
+				mixed := BoshManifest(`---
+name: foo
+properties:
+  credentials: ((username)):((password))
+variables:
+- name: password
+  type: password`)
+				interpolated := BoshManifest(`---
+name: foo
+properties:
+  credentials: admin:((password))
+variables:
+- name: password
+  type: password`)
+				literal := BoshManifest(`---
+name: foo
+properties:
+  credentials: admin:some-password
+variables:
+- name: password
+  type: password`)
+				Expect(mixed.Equals(interpolated)).To(BeTrue())
+				Expect(mixed.Equals(literal)).To(BeFalse())
+			})
+
+			It("returns false when the literal parts of an interpolated string differ", func() {
+				differentPort := BoshManifest(`---
+name: foo
+properties:
+  password: ((password))
+  url: https://example.com:9443
+variables:
+- name: password
+  type: password`)
+				Expect(withPlaceholders.Equals(differentPort)).To(BeFalse())
+			})
+
+			It("returns false when the variables block differs", func() {
+				differentVariables := BoshManifest(`---
+name: foo
+properties:
+  password: ((password))
+  url: https://((host)):8443
+variables:
+- name: password
+  type: certificate`)
+				Expect(withPlaceholders.Equals(differentVariables)).To(BeFalse())
+			})
+		})
 	})
 
 })
@@ -0,0 +1,119 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package task
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// PreviousManifestConfigType is the type of the director configs in which upgrades retain the
+// manifest they replace, named after the deployment
+const PreviousManifestConfigType = "odb-previous-manifest"
+
+type manifestDependencies struct {
+	Releases []struct {
+		Name    string `yaml:"name"`
+		Version string `yaml:"version"`
+	} `yaml:"releases"`
+	Stemcells []struct {
+		Alias   string `yaml:"alias"`
+		OS      string `yaml:"os"`
+		Name    string `yaml:"name"`
+		Version string `yaml:"version"`
+	} `yaml:"stemcells"`
+}
+
+func (d deployer) Rollback(deploymentName, boshContextID string, logger *log.Logger) (int, []byte, error) {
+	if _, err := d.assertNoOperationsInProgress(deploymentName, logger); err != nil {
+		return 0, nil, err
+	}
+
+	if _, err := d.getDeploymentManifest(deploymentName, logger); err != nil {
+		return 0, nil, err
+	}
+
+	previousManifest, found, err := d.boshClient.GetConfig(PreviousManifestConfigType, deploymentName, logger)
+	if err != nil {
+		return 0, nil, NewServiceError(fmt.Errorf("error getting previous manifest of deployment %s: %s", deploymentName, err))
+	}
+	if !found {
+		return 0, nil, NewNoPreviousManifestError(fmt.Errorf("no previous manifest retained for deployment %s", deploymentName))
+	}
+
+	if err := d.assertDependenciesUploaded(deploymentName, previousManifest, logger); err != nil {
+		return 0, nil, err
+	}
+
+	boshTaskID, err := d.boshClient.Deploy(previousManifest, boshContextID, logger)
+	if err != nil {
+		return 0, nil, fmt.Errorf("error deploying instance: %s\n", err)
+	}
+	logger.Printf("Bosh task ID for rollback deployment %s is %d\n", deploymentName, boshTaskID)
+
+	return boshTaskID, previousManifest, nil
+}
+
+// retainPreviousManifest only retains manifests whose deployment succeeded, so that upgrading again
+// after a failed upgrade keeps the last good manifest. It does not fail the upgrade, as directors
+// without the configs API cannot retain manifests; the deployment just cannot be rolled back.
+func (d deployer) retainPreviousManifest(deploymentName string, manifest []byte, tasks boshdirector.BoshTasks, logger *log.Logger) {
+	if len(tasks) == 0 || tasks[0].StateType() != boshdirector.TaskComplete {
+		logger.Printf("not retaining manifest of deployment %s, as its last task did not succeed\n", deploymentName)
+		return
+	}
+
+	if err := d.boshClient.UpdateConfig(PreviousManifestConfigType, deploymentName, manifest, logger); err != nil {
+		logger.Printf("warning: error retaining previous manifest of deployment %s, it will not be possible to roll it back: %s\n", deploymentName, err)
+	}
+}
+
+// assertDependenciesUploaded refuses manifests referencing releases or stemcells that have since
+// been deleted from the director, which would otherwise fail part way through the deploy
+func (d deployer) assertDependenciesUploaded(deploymentName string, manifest []byte, logger *log.Logger) error {
+	var dependencies manifestDependencies
+	if err := yaml.Unmarshal(manifest, &dependencies); err != nil {
+		return fmt.Errorf("error parsing previous manifest of deployment %s: %s", deploymentName, err)
+	}
+
+	releases, err := d.boshClient.GetReleases(logger)
+	if err != nil {
+		return NewServiceError(fmt.Errorf("error getting releases: %s", err))
+	}
+
+	stemcells, err := d.boshClient.GetStemcells(logger)
+	if err != nil {
+		return NewServiceError(fmt.Errorf("error getting stemcells: %s", err))
+	}
+
+	var missing []string
+	for _, release := range dependencies.Releases {
+		if !releases.Contains(release.Name, release.Version) {
+			missing = append(missing, fmt.Sprintf("release %s/%s", release.Name, release.Version))
+		}
+	}
+	for _, stemcell := range dependencies.Stemcells {
+		if stemcell.OS != "" && !stemcells.ContainsOS(stemcell.OS, stemcell.Version) {
+			missing = append(missing, fmt.Sprintf("stemcell %s/%s", stemcell.OS, stemcell.Version))
+		}
+		if stemcell.OS == "" && !stemcells.ContainsName(stemcell.Name, stemcell.Version) {
+			missing = append(missing, fmt.Sprintf("stemcell %s/%s", stemcell.Name, stemcell.Version))
+		}
+	}
+
+	if len(missing) > 0 {
+		return NewMissingDependenciesError(fmt.Errorf(
+			"previous manifest of deployment %s references %s, which are no longer uploaded",
+			deploymentName, strings.Join(missing, ", "),
+		))
+	}
+	return nil
+}
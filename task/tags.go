@@ -0,0 +1,85 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package task
+
+import (
+	"fmt"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ServiceOfferingTag is the manifest tag identifying the broker that owns a deployment,
+// so brokers sharing a director can tell their deployments apart
+const ServiceOfferingTag = "odb-service-offering-id"
+
+type manifestWithTags struct {
+	Tags map[string]interface{} `yaml:"tags"`
+}
+
+// tagManifest sets tags, keeping the order of the adapter's manifest
+func tagManifest(manifest []byte, newTags ...yaml.MapItem) ([]byte, error) {
+	if len(newTags) == 0 {
+		return manifest, nil
+	}
+
+	var parsed yaml.MapSlice
+	if err := yaml.Unmarshal(manifest, &parsed); err != nil {
+		return nil, err
+	}
+
+	for i, item := range parsed {
+		if item.Key != "tags" {
+			continue
+		}
+
+		tags, ok := item.Value.(yaml.MapSlice)
+		if !ok && item.Value != nil {
+			return nil, fmt.Errorf("manifest tags must be a map")
+		}
+
+		parsed[i].Value = setTags(tags, newTags)
+		return yaml.Marshal(parsed)
+	}
+
+	parsed = append(parsed, yaml.MapItem{Key: "tags", Value: setTags(nil, newTags)})
+	return yaml.Marshal(parsed)
+}
+
+func setTags(tags yaml.MapSlice, newTags []yaml.MapItem) yaml.MapSlice {
+	for _, tag := range newTags {
+		tags = setTag(tags, tag)
+	}
+	return tags
+}
+
+func setTag(tags yaml.MapSlice, tag yaml.MapItem) yaml.MapSlice {
+	for i, item := range tags {
+		if item.Key == tag.Key {
+			tags[i] = tag
+			return tags
+		}
+	}
+	return append(tags, tag)
+}
+
+// ServiceOfferingOf returns the service offering a manifest is tagged with, if any
+func ServiceOfferingOf(manifest []byte) (string, bool, error) {
+	return manifestTag(manifest, ServiceOfferingTag)
+}
+
+func manifestTag(manifest []byte, key string) (string, bool, error) {
+	var parsed manifestWithTags
+	if err := yaml.Unmarshal(manifest, &parsed); err != nil {
+		return "", false, err
+	}
+
+	value, found := parsed.Tags[key]
+	if !found {
+		return "", false, nil
+	}
+	return fmt.Sprintf("%v", value), true, nil
+}
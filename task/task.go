@@ -7,8 +7,10 @@
 package task
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/pivotal-cf/on-demand-service-broker/boshdirector"
 )
@@ -18,37 +20,65 @@ type BoshClient interface {
 	Deploy(manifest []byte, contextID string, logger *log.Logger) (int, error)
 	GetTasks(deploymentName string, logger *log.Logger) (boshdirector.BoshTasks, error)
 	GetDeployment(name string, logger *log.Logger) ([]byte, bool, error) // TODO SF found = false => manifest => nil, drop the found flag?
+	GetDeploymentDiff(name string, manifest []byte, logger *log.Logger) (boshdirector.DeploymentDiff, error)
+	DeployForTeam(manifest []byte, contextID, team string, logger *log.Logger) (int, error)
+	ChangeJobState(deploymentName string, state boshdirector.JobState, contextID string, logger *log.Logger) (int, error)
+	GetConfig(configType, name string, logger *log.Logger) ([]byte, bool, error)
+	UpdateConfig(configType, name string, content []byte, logger *log.Logger) error
+	DeleteConfig(configType, name string, logger *log.Logger) error
+	GetReleases(logger *log.Logger) (boshdirector.Releases, error)
+	GetStemcells(logger *log.Logger) (boshdirector.Stemcells, error)
+}
+
+//go:generate counterfeiter -o fakes/fake_bosh_teams.go . BoshTeams
+type BoshTeams interface {
+	TeamFor(planID, orgGUID string) string
 }
 
 // TODO SF previousPlanID is a pointer because it might not exist. Should we have a nil value instead? Should we have a specific type?
 //go:generate counterfeiter -o fakes/fake_manifest_generator.go . ManifestGenerator
 type ManifestGenerator interface {
-	GenerateManifest(deploymentName, planID string, requestParams map[string]interface{}, oldManifest []byte, previousPlanID *string, logger *log.Logger) (BoshManifest, error)
+	GenerateManifest(ctx context.Context, deploymentName, planID string, requestParams map[string]interface{}, oldManifest []byte, previousPlanID *string, logger *log.Logger) (BoshManifest, error)
 }
 
 type deployer struct {
-	boshClient        BoshClient
-	manifestGenerator ManifestGenerator
+	boshClient                    BoshClient
+	manifestGenerator             ManifestGenerator
+	strictPendingChangesDetection bool
+	boshTeams                     BoshTeams
 }
 
-func NewDeployer(boshClient BoshClient, manifestGenerator ManifestGenerator) deployer {
+func NewDeployer(boshClient BoshClient, manifestGenerator ManifestGenerator, strictPendingChangesDetection bool, boshTeams BoshTeams) deployer {
 	return deployer{
-		boshClient:        boshClient,
-		manifestGenerator: manifestGenerator,
+		boshClient:                    boshClient,
+		manifestGenerator:             manifestGenerator,
+		strictPendingChangesDetection: strictPendingChangesDetection,
+		boshTeams:                     boshTeams,
 	}
 }
 
-func (d deployer) Create(deploymentName, planID string, requestParams map[string]interface{}, boshContextID string, logger *log.Logger) (int, []byte, error) {
-	err := d.assertNoOperationsInProgress(deploymentName, logger)
+func (d deployer) Create(ctx context.Context, deploymentName, planID string, requestParams map[string]interface{}, boshContextID string, logger *log.Logger) (int, []byte, error) {
+	_, err := d.assertNoOperationsInProgress(deploymentName, logger)
 	if err != nil {
 		return 0, nil, err
 	}
 
-	return d.doDeploy(deploymentName, planID, "create", requestParams, nil, nil, boshContextID, logger)
+	boshTeam := d.boshTeamFor(planID, requestParams)
+	return d.doDeploy(ctx, deploymentName, planID, "create", requestParams, nil, nil, boshContextID, boshTeam, logger)
+}
+
+// boshTeamFor only matters on create: the director keeps a deployment's teams when the
+// broker's own identity redeploys it later
+func (d deployer) boshTeamFor(planID string, requestParams map[string]interface{}) string {
+	if d.boshTeams == nil {
+		return ""
+	}
+	orgGUID, _ := requestParams["organization_guid"].(string)
+	return d.boshTeams.TeamFor(planID, orgGUID)
 }
 
-func (d deployer) Upgrade(deploymentName, planID string, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error) {
-	err := d.assertNoOperationsInProgress(deploymentName, logger)
+func (d deployer) Upgrade(ctx context.Context, deploymentName, planID string, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error) {
+	tasks, err := d.assertNoOperationsInProgress(deploymentName, logger)
 	if err != nil {
 		return 0, nil, err
 	}
@@ -58,10 +88,13 @@ func (d deployer) Upgrade(deploymentName, planID string, previousPlanID *string,
 		return 0, nil, err
 	}
 
-	return d.doDeploy(deploymentName, planID, "upgrade", nil, oldManifest, previousPlanID, boshContextID, logger)
+	d.retainPreviousManifest(deploymentName, oldManifest, tasks, logger)
+
+	return d.doDeploy(ctx, deploymentName, planID, "upgrade", nil, oldManifest, previousPlanID, boshContextID, "", logger)
 }
 
 func (d deployer) Update(
+	ctx context.Context,
 	deploymentName,
 	planID string,
 	requestParams map[string]interface{},
@@ -69,7 +102,7 @@ func (d deployer) Update(
 	boshContextID string,
 	logger *log.Logger,
 ) (boshTaskID int, manifest []byte, err error) {
-	if err := d.assertNoOperationsInProgress(deploymentName, logger); err != nil {
+	if _, err := d.assertNoOperationsInProgress(deploymentName, logger); err != nil {
 		return 0, nil, err
 	}
 
@@ -78,11 +111,29 @@ func (d deployer) Update(
 		return 0, nil, err
 	}
 
-	if err := d.checkForPendingChanges(deploymentName, previousPlanID, oldManifest, logger); err != nil {
+	if err := d.checkForPendingChanges(ctx, deploymentName, previousPlanID, oldManifest, logger); err != nil {
 		return 0, nil, err
 	}
 
-	return d.doDeploy(deploymentName, planID, "update", requestParams, oldManifest, previousPlanID, boshContextID, logger)
+	return d.doDeploy(ctx, deploymentName, planID, "update", requestParams, oldManifest, previousPlanID, boshContextID, "", logger)
+}
+
+func (d deployer) ChangeJobState(deploymentName string, state boshdirector.JobState, logger *log.Logger) (int, error) {
+	if _, err := d.assertNoOperationsInProgress(deploymentName, logger); err != nil {
+		return 0, err
+	}
+
+	boshTaskID, err := d.boshClient.ChangeJobState(deploymentName, state, "", logger)
+	switch err.(type) {
+	case nil:
+	case boshdirector.DeploymentNotFoundError:
+		return 0, NewDeploymentNotFoundError(fmt.Errorf("bosh deployment '%s' not found", deploymentName))
+	default:
+		return 0, NewServiceError(fmt.Errorf("error changing job state of deployment %s to %s: %s", deploymentName, state, err))
+	}
+	logger.Printf("Bosh task ID for changing job state of deployment %s to %s is %d\n", deploymentName, state, boshTaskID)
+
+	return boshTaskID, nil
 }
 
 func (d deployer) getDeploymentManifest(deploymentName string, logger *log.Logger) ([]byte, error) {
@@ -98,27 +149,28 @@ func (d deployer) getDeploymentManifest(deploymentName string, logger *log.Logge
 	return oldManifest, nil
 }
 
-func (d deployer) assertNoOperationsInProgress(deploymentName string, logger *log.Logger) error {
+func (d deployer) assertNoOperationsInProgress(deploymentName string, logger *log.Logger) (boshdirector.BoshTasks, error) {
 	clientTasks, err := d.boshClient.GetTasks(deploymentName, logger)
 	if err != nil {
-		return NewServiceError(fmt.Errorf("error getting tasks for deployment %s: %s\n", deploymentName, err))
+		return nil, NewServiceError(fmt.Errorf("error getting tasks for deployment %s: %s\n", deploymentName, err))
 	}
 
 	if incompleteTasks := clientTasks.IncompleteTasks(); len(incompleteTasks) != 0 {
 		logger.Printf("deployment %s is still in progress: tasks %s\n", deploymentName, incompleteTasks.ToLog())
-		return TaskInProgressError{Message: "task in progress"}
+		return nil, TaskInProgressError{Message: "task in progress"}
 	}
 
-	return nil
+	return clientTasks, nil
 }
 
 func (d deployer) checkForPendingChanges(
+	ctx context.Context,
 	deploymentName string,
 	previousPlanID *string,
 	oldManifest BoshManifest,
 	logger *log.Logger,
 ) error {
-	regeneratedManifest, err := d.manifestGenerator.GenerateManifest(deploymentName, *previousPlanID, map[string]interface{}{}, oldManifest, previousPlanID, logger)
+	regeneratedManifest, err := d.manifestGenerator.GenerateManifest(ctx, deploymentName, *previousPlanID, map[string]interface{}{}, oldManifest, previousPlanID, logger)
 	if err != nil {
 		return err
 	}
@@ -134,10 +186,41 @@ func (d deployer) checkForPendingChanges(
 		return PendingChangesNotAppliedError{}
 	}
 
+	if d.strictPendingChangesDetection {
+		return d.checkForPendingDirectorChanges(deploymentName, regeneratedManifest, oldManifest, logger)
+	}
+
+	return nil
+}
+
+// checkForPendingDirectorChanges asks the director what a redeploy would change, which
+// includes cloud-config and runtime-config changes that the manifest comparison cannot see
+func (d deployer) checkForPendingDirectorChanges(
+	deploymentName string,
+	regeneratedManifest BoshManifest,
+	oldManifest BoshManifest,
+	logger *log.Logger,
+) error {
+	manifest, err := interpolateVariables(regeneratedManifest, oldManifest, nil, logger)
+	if err != nil {
+		return fmt.Errorf("error interpolating manifest variables: %s", err)
+	}
+
+	diff, err := d.boshClient.GetDeploymentDiff(deploymentName, manifest, logger)
+	if err != nil {
+		return NewServiceError(fmt.Errorf("error getting diff for deployment %s: %s", deploymentName, err))
+	}
+
+	if diff.HasChanges() {
+		logger.Printf("deployment %s has pending changes from the director\n", deploymentName)
+		return PendingChangesNotAppliedError{}
+	}
+
 	return nil
 }
 
 func (d deployer) doDeploy(
+	ctx context.Context,
 	deploymentName,
 	planID string,
 	operationType string,
@@ -145,19 +228,43 @@ func (d deployer) doDeploy(
 	oldManifest []byte,
 	previousPlanID *string,
 	boshContextID string,
+	boshTeam string,
 	logger *log.Logger,
 ) (int, []byte, error) {
 
-	manifest, err := d.manifestGenerator.GenerateManifest(deploymentName, planID, requestParams, oldManifest, previousPlanID, logger)
+	manifest, err := d.manifestGenerator.GenerateManifest(ctx, deploymentName, planID, requestParams, oldManifest, previousPlanID, logger)
 	if err != nil {
 		return 0, nil, err
 	}
 
-	boshTaskID, err := d.boshClient.Deploy(manifest, boshContextID, logger)
+	overrides, err := variableOverrides(requestParams)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	manifest, err = interpolateVariables(manifest, oldManifest, overrides, logger)
+	if err != nil {
+		return 0, nil, fmt.Errorf("error interpolating manifest variables: %s", err)
+	}
+
+	var boshTaskID int
+	if boshTeam != "" {
+		boshTaskID, err = d.boshClient.DeployForTeam(manifest, boshContextID, boshTeam, logger)
+	} else {
+		boshTaskID, err = d.boshClient.Deploy(manifest, boshContextID, logger)
+	}
 	if err != nil {
 		return 0, nil, fmt.Errorf("error deploying instance: %s\n", err)
 	}
 	logger.Printf("Bosh task ID for %s deployment %s is %d\n", operationType, deploymentName, boshTaskID)
 
+	// the deployment has started, so failing to store what the request set must not fail the request
+	if err := d.storeMaintenanceWindow(deploymentName, requestParams, logger); err != nil {
+		logger.Printf("warning: %s\n", err)
+	}
+	if err := d.storeUpgradePolicy(deploymentName, requestParams, time.Now(), logger); err != nil {
+		logger.Printf("warning: %s\n", err)
+	}
+
 	return boshTaskID, manifest, nil
 }
@@ -7,9 +7,11 @@
 package task_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -19,9 +21,11 @@ import (
 )
 
 type deployer interface {
-	Create(deploymentName, planID string, requestParams map[string]interface{}, boshContextID string, logger *log.Logger) (int, []byte, error)
-	Update(deploymentName, planID string, requestParams map[string]interface{}, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error)
-	Upgrade(deploymentName, planID string, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error)
+	Create(ctx context.Context, deploymentName, planID string, requestParams map[string]interface{}, boshContextID string, logger *log.Logger) (int, []byte, error)
+	Update(ctx context.Context, deploymentName, planID string, requestParams map[string]interface{}, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error)
+	Upgrade(ctx context.Context, deploymentName, planID string, previousPlanID *string, boshContextID string, logger *log.Logger) (int, []byte, error)
+	ChangeJobState(deploymentName string, state boshdirector.JobState, logger *log.Logger) (int, error)
+	Rollback(deploymentName, boshContextID string, logger *log.Logger) (int, []byte, error)
 }
 
 var _ = Describe("Deployer", func() {
@@ -42,13 +46,16 @@ var _ = Describe("Deployer", func() {
 		manifest       = []byte("---\nmanifest: deployment")
 		oldManifest    []byte
 
-		manifestGenerator *fakes.FakeManifestGenerator
+		manifestGenerator             *fakes.FakeManifestGenerator
+		strictPendingChangesDetection bool
+		boshTeams                     *fakes.FakeBoshTeams
 	)
 
 	BeforeEach(func() {
 		boshClient = new(fakes.FakeBoshClient)
 		manifestGenerator = new(fakes.FakeManifestGenerator)
-		deployer = task.NewDeployer(boshClient, manifestGenerator)
+		strictPendingChangesDetection = false
+		boshTeams = new(fakes.FakeBoshTeams)
 
 		planID = existingPlanID
 		previousPlanID = nil
@@ -60,9 +67,14 @@ var _ = Describe("Deployer", func() {
 		boshContextID = ""
 	})
 
+	JustBeforeEach(func() {
+		deployer = task.NewDeployer(boshClient, manifestGenerator, strictPendingChangesDetection, boshTeams)
+	})
+
 	Describe("Create()", func() {
 		JustBeforeEach(func() {
 			returnedTaskID, deployedManifest, deployError = deployer.Create(
+				context.Background(),
 				deploymentName,
 				planID,
 				requestParams,
@@ -96,7 +108,7 @@ var _ = Describe("Deployer", func() {
 
 			It("calls new manifest with correct params", func() {
 				Expect(manifestGenerator.GenerateManifestCallCount()).To(Equal(1))
-				passedDeploymentName, passedPlanID, passedRequestParams, passedPreviousManifest, passedPreviousPlanID, _ := manifestGenerator.GenerateManifestArgsForCall(0)
+				_, passedDeploymentName, passedPlanID, passedRequestParams, passedPreviousManifest, passedPreviousPlanID, _ := manifestGenerator.GenerateManifestArgsForCall(0)
 
 				Expect(passedDeploymentName).To(Equal(deploymentName))
 				Expect(passedPlanID).To(Equal(planID))
@@ -134,6 +146,38 @@ var _ = Describe("Deployer", func() {
 					Expect(actualBoshContextID).To(Equal(boshContextID))
 				})
 			})
+
+			It("looks up the BOSH team for the plan and org", func() {
+				Expect(boshTeams.TeamForCallCount()).To(Equal(1))
+				actualPlanID, actualOrgGUID := boshTeams.TeamForArgsForCall(0)
+				Expect(actualPlanID).To(Equal(planID))
+				Expect(actualOrgGUID).To(BeEmpty())
+			})
+
+			Context("when the plan or org is mapped to a BOSH team", func() {
+				BeforeEach(func() {
+					requestParams["organization_guid"] = "some-org-guid"
+					boshTeams.TeamForReturns("some-team")
+					boshClient.DeployForTeamReturns(43, nil)
+				})
+
+				It("passes the org to the team lookup", func() {
+					_, actualOrgGUID := boshTeams.TeamForArgsForCall(0)
+					Expect(actualOrgGUID).To(Equal("some-org-guid"))
+				})
+
+				It("deploys as the team", func() {
+					Expect(boshClient.DeployCallCount()).To(BeZero())
+					Expect(boshClient.DeployForTeamCallCount()).To(Equal(1))
+					deployedManifest, _, actualTeam, _ := boshClient.DeployForTeamArgsForCall(0)
+					Expect(deployedManifest).To(Equal(manifest))
+					Expect(actualTeam).To(Equal("some-team"))
+				})
+
+				It("returns the bosh task ID", func() {
+					Expect(returnedTaskID).To(Equal(43))
+				})
+			})
 		})
 
 		Context("logging", func() {
@@ -252,6 +296,7 @@ var _ = Describe("Deployer", func() {
 	Describe("Upgrade()", func() {
 		JustBeforeEach(func() {
 			returnedTaskID, deployedManifest, deployError = deployer.Upgrade(
+				context.Background(),
 				deploymentName,
 				planID,
 				previousPlanID,
@@ -297,7 +342,7 @@ var _ = Describe("Deployer", func() {
 
 			It("calls new manifest with correct params", func() {
 				Expect(manifestGenerator.GenerateManifestCallCount()).To(Equal(1))
-				passedDeploymentName, passedPlanID, passedRequestParams, passedPreviousManifest, passedPreviousPlanID, _ := manifestGenerator.GenerateManifestArgsForCall(0)
+				_, passedDeploymentName, passedPlanID, passedRequestParams, passedPreviousManifest, passedPreviousPlanID, _ := manifestGenerator.GenerateManifestArgsForCall(0)
 
 				Expect(passedDeploymentName).To(Equal(deploymentName))
 				Expect(passedPlanID).To(Equal(planID))
@@ -370,6 +415,50 @@ var _ = Describe("Deployer", func() {
 			})
 		})
 
+		Context("when the last task of the deployment succeeded", func() {
+			BeforeEach(func() {
+				boshClient.GetTasksReturns([]boshdirector.BoshTask{{State: boshdirector.TaskDone}, {State: boshdirector.TaskError}}, nil)
+			})
+
+			It("retains the previous manifest in the director", func() {
+				Expect(boshClient.UpdateConfigCallCount()).To(Equal(1))
+				actualType, actualName, actualContent, _ := boshClient.UpdateConfigArgsForCall(0)
+				Expect(actualType).To(Equal(task.PreviousManifestConfigType))
+				Expect(actualName).To(Equal(deploymentName))
+				Expect(actualContent).To(Equal(oldManifest))
+			})
+		})
+
+		Context("when the last task of the deployment failed", func() {
+			BeforeEach(func() {
+				boshClient.GetTasksReturns([]boshdirector.BoshTask{{State: boshdirector.TaskError}, {State: boshdirector.TaskDone}}, nil)
+			})
+
+			It("keeps the manifest retained before it and upgrades the deployment", func() {
+				Expect(deployError).NotTo(HaveOccurred())
+				Expect(boshClient.UpdateConfigCallCount()).To(BeZero())
+				Expect(boshClient.DeployCallCount()).To(Equal(1))
+				Expect(logBuffer.String()).To(ContainSubstring(
+					fmt.Sprintf("not retaining manifest of deployment %s, as its last task did not succeed", deploymentName),
+				))
+			})
+		})
+
+		Context("when retaining the previous manifest fails", func() {
+			BeforeEach(func() {
+				boshClient.GetTasksReturns([]boshdirector.BoshTask{{State: boshdirector.TaskDone}}, nil)
+				boshClient.UpdateConfigReturns(errors.New("director says no"))
+			})
+
+			It("logs a warning and upgrades the deployment", func() {
+				Expect(deployError).NotTo(HaveOccurred())
+				Expect(boshClient.DeployCallCount()).To(Equal(1))
+				Expect(logBuffer.String()).To(ContainSubstring(
+					fmt.Sprintf("warning: error retaining previous manifest of deployment %s, it will not be possible to roll it back: director says no", deploymentName),
+				))
+			})
+		})
+
 		It("does not check for pending changes", func() {
 			Expect(manifestGenerator.GenerateManifestCallCount()).To(Equal(1))
 		})
@@ -498,6 +587,7 @@ var _ = Describe("Deployer", func() {
 	Describe("Update()", func() {
 		JustBeforeEach(func() {
 			returnedTaskID, deployedManifest, deployError = deployer.Update(
+				context.Background(),
 				deploymentName,
 				planID,
 				requestParams,
@@ -530,6 +620,7 @@ var _ = Describe("Deployer", func() {
 				BeforeEach(func() {
 					requestParams = map[string]interface{}{"foo": "bar"}
 					manifestGenerator.GenerateManifestStub = func(
+						_ context.Context,
 						_, _ string,
 						requestParams map[string]interface{},
 						previousManifest []byte,
@@ -559,13 +650,13 @@ var _ = Describe("Deployer", func() {
 
 				It("generate manifest without arbitrary params", func() {
 					Expect(manifestGenerator.GenerateManifestCallCount()).To(Equal(2))
-					_, _, passedRequestParams, _, _, _ := manifestGenerator.GenerateManifestArgsForCall(0)
+					_, _, _, passedRequestParams, _, _, _ := manifestGenerator.GenerateManifestArgsForCall(0)
 					Expect(passedRequestParams).To(BeEmpty())
 				})
 
 				It("generates new manifest with arbitrary params", func() {
 					Expect(manifestGenerator.GenerateManifestCallCount()).To(Equal(2))
-					_, _, passedRequestParams, _, _, _ := manifestGenerator.GenerateManifestArgsForCall(1)
+					_, _, _, passedRequestParams, _, _, _ := manifestGenerator.GenerateManifestArgsForCall(1)
 					Expect(passedRequestParams).To(Equal(requestParams))
 				})
 
@@ -579,6 +670,61 @@ var _ = Describe("Deployer", func() {
 					Expect(returnedTaskID).To(Equal(boshTaskID))
 				})
 
+				It("does not ask the director for a diff", func() {
+					Expect(boshClient.GetDeploymentDiffCallCount()).To(BeZero())
+				})
+
+				Context("and strict pending changes detection is enabled", func() {
+					BeforeEach(func() {
+						strictPendingChangesDetection = true
+					})
+
+					Context("and the director reports no changes", func() {
+						BeforeEach(func() {
+							boshClient.GetDeploymentDiffReturns(boshdirector.DeploymentDiff{
+								Diff: [][]interface{}{{"name: some-deployment", nil}},
+							}, nil)
+						})
+
+						It("asks the director for a diff of the regenerated manifest", func() {
+							Expect(boshClient.GetDeploymentDiffCallCount()).To(Equal(1))
+							actualDeploymentName, diffedManifest, _ := boshClient.GetDeploymentDiffArgsForCall(0)
+							Expect(actualDeploymentName).To(Equal(deploymentName))
+							Expect(diffedManifest).To(Equal(oldManifest))
+						})
+
+						It("deploys", func() {
+							Expect(deployError).NotTo(HaveOccurred())
+							Expect(boshClient.DeployCallCount()).To(Equal(1))
+						})
+					})
+
+					Context("and the director reports changes from the cloud-config or runtime-configs", func() {
+						BeforeEach(func() {
+							boshClient.GetDeploymentDiffReturns(boshdirector.DeploymentDiff{
+								Diff: [][]interface{}{{"addons:", "added"}},
+							}, nil)
+						})
+
+						It("fails without deploying", func() {
+							Expect(deployError).To(BeAssignableToTypeOf(task.PendingChangesNotAppliedError{}))
+							Expect(boshClient.DeployCallCount()).To(BeZero())
+						})
+					})
+
+					Context("and getting the diff fails", func() {
+						BeforeEach(func() {
+							boshClient.GetDeploymentDiffReturns(boshdirector.DeploymentDiff{}, errors.New("diff failed"))
+						})
+
+						It("returns a service error without deploying", func() {
+							Expect(deployError).To(BeAssignableToTypeOf(task.ServiceError{}))
+							Expect(deployError).To(MatchError(ContainSubstring("diff failed")))
+							Expect(boshClient.DeployCallCount()).To(BeZero())
+						})
+					})
+				})
+
 				Context("and there are no parameters configured", func() {
 					BeforeEach(func() {
 						requestParams = map[string]interface{}{}
@@ -593,6 +739,7 @@ var _ = Describe("Deployer", func() {
 			Context("and the manifest generator fails to generate the manifest the second time", func() {
 				BeforeEach(func() {
 					manifestGenerator.GenerateManifestStub = func(
+						_ context.Context,
 						_, _ string,
 						requestParams map[string]interface{},
 						previousManifest []byte,
@@ -622,6 +769,17 @@ var _ = Describe("Deployer", func() {
 				Expect(deployError).To(BeAssignableToTypeOf(task.PendingChangesNotAppliedError{}))
 				Expect(boshClient.DeployCallCount()).To(BeZero())
 			})
+
+			Context("and strict pending changes detection is enabled", func() {
+				BeforeEach(func() {
+					strictPendingChangesDetection = true
+				})
+
+				It("does not need to ask the director for a diff", func() {
+					Expect(deployError).To(BeAssignableToTypeOf(task.PendingChangesNotAppliedError{}))
+					Expect(boshClient.GetDeploymentDiffCallCount()).To(BeZero())
+				})
+			})
 		})
 
 		Context("when the deployment cannot be found", func() {
@@ -655,6 +813,433 @@ var _ = Describe("Deployer", func() {
 				Expect(boshClient.DeployCallCount()).To(Equal(0))
 			})
 		})
+
+		Context("and the generated manifest references variables", func() {
+			BeforeEach(func() {
+				oldManifest = []byte("---\nname: foo\nproperties:\n  admin_password: ((admin_password))\n  port: 1234\nvariables:\n- name: admin_password\n  type: password\n")
+				boshClient.GetDeploymentReturns(oldManifest, true, nil)
+				boshClient.DeployReturns(42, nil)
+
+				manifestGenerator.GenerateManifestStub = func(
+					_ context.Context,
+					_, _ string,
+					requestParams map[string]interface{},
+					previousManifest []byte,
+					_ *string,
+					_ *log.Logger,
+				) (task.BoshManifest, error) {
+					return []byte("---\nname: foo\nproperties:\n  admin_password: ((admin_password))\n  port: ((port))\nvariables:\n- name: admin_password\n  type: password\n"), nil
+				}
+			})
+
+			Context("and a previously interpolated value is still referenced", func() {
+				BeforeEach(func() {
+					requestParams = map[string]interface{}{"parameters": map[string]interface{}{}}
+				})
+
+				It("does not treat the interpolated value as a pending change", func() {
+					Expect(deployError).NotTo(HaveOccurred())
+				})
+
+				It("deploys with the previously interpolated value", func() {
+					Expect(boshClient.DeployCallCount()).To(Equal(1))
+					deployedManifest, _, _ := boshClient.DeployArgsForCall(0)
+					Expect(string(deployedManifest)).To(ContainSubstring("port: 1234"))
+				})
+
+				It("leaves config server variables for the director to resolve", func() {
+					deployedManifest, _, _ := boshClient.DeployArgsForCall(0)
+					Expect(string(deployedManifest)).To(ContainSubstring("admin_password: ((admin_password))"))
+				})
+			})
+
+			Context("and variable overrides are provided", func() {
+				BeforeEach(func() {
+					requestParams = map[string]interface{}{
+						"parameters": map[string]interface{}{
+							task.VariablesParameterKey: map[string]interface{}{
+								"port":           4321,
+								"admin_password": "not-from-the-config-server",
+							},
+						},
+					}
+				})
+
+				It("interpolates the override", func() {
+					deployedManifest, _, _ := boshClient.DeployArgsForCall(0)
+					Expect(string(deployedManifest)).To(ContainSubstring("port: 4321"))
+				})
+
+				It("does not override config server variables", func() {
+					deployedManifest, _, _ := boshClient.DeployArgsForCall(0)
+					Expect(string(deployedManifest)).To(ContainSubstring("admin_password: ((admin_password))"))
+					Expect(logBuffer.String()).To(ContainSubstring("ignoring override for variable admin_password"))
+				})
+			})
+
+			Context("and the variable overrides are not a map", func() {
+				BeforeEach(func() {
+					requestParams = map[string]interface{}{
+						"parameters": map[string]interface{}{task.VariablesParameterKey: "port=4321"},
+					}
+				})
+
+				It("fails without deploying", func() {
+					Expect(deployError).To(MatchError(ContainSubstring("must be a map of variable names to values")))
+					Expect(boshClient.DeployCallCount()).To(BeZero())
+				})
+			})
+		})
+
+		Context("and the request sets a maintenance window", func() {
+			BeforeEach(func() {
+				requestParams = map[string]interface{}{
+					"parameters": map[string]interface{}{
+						task.MaintenanceWindowParameterKey: map[string]interface{}{"days": []interface{}{"sat"}, "start": "02:00", "duration": "4h"},
+					},
+				}
+				manifestGenerator.GenerateManifestStub = func(_ context.Context, _, _ string, _ map[string]interface{}, previousManifest []byte, _ *string, _ *log.Logger) (task.BoshManifest, error) {
+					return previousManifest, nil
+				}
+			})
+
+			It("keeps the window in a director config named after the deployment", func() {
+				Expect(deployError).NotTo(HaveOccurred())
+				Expect(boshClient.UpdateConfigCallCount()).To(Equal(1))
+				actualType, actualName, actualContent, _ := boshClient.UpdateConfigArgsForCall(0)
+				Expect(actualType).To(Equal(task.MaintenanceWindowConfigType))
+				Expect(actualName).To(Equal(deploymentName))
+				Expect(actualContent).To(MatchJSON(`{"days":["sat"],"start":"02:00","duration":"4h"}`))
+				Expect(boshClient.DeployCallCount()).To(Equal(1))
+			})
+
+			Context("and storing the window fails", func() {
+				BeforeEach(func() {
+					boshClient.UpdateConfigReturns(errors.New("director says no"))
+				})
+
+				It("still deploys and logs a warning", func() {
+					Expect(deployError).NotTo(HaveOccurred())
+					Expect(boshClient.DeployCallCount()).To(Equal(1))
+					Expect(logBuffer.String()).To(ContainSubstring(
+						fmt.Sprintf("warning: error storing maintenance window of deployment %s: director says no", deploymentName),
+					))
+				})
+			})
+
+			Context("and the director rejects the deployment", func() {
+				BeforeEach(func() {
+					boshClient.DeployReturns(0, errors.New("deployment is locked"))
+				})
+
+				It("keeps the previous window", func() {
+					Expect(deployError).To(HaveOccurred())
+					Expect(boshClient.UpdateConfigCallCount()).To(BeZero())
+				})
+			})
+		})
+
+		Context("and the request clears the maintenance window", func() {
+			BeforeEach(func() {
+				requestParams = map[string]interface{}{
+					"parameters": map[string]interface{}{task.MaintenanceWindowParameterKey: nil},
+				}
+				manifestGenerator.GenerateManifestStub = func(_ context.Context, _, _ string, _ map[string]interface{}, previousManifest []byte, _ *string, _ *log.Logger) (task.BoshManifest, error) {
+					return previousManifest, nil
+				}
+			})
+
+			It("deletes the window's config", func() {
+				Expect(deployError).NotTo(HaveOccurred())
+				Expect(boshClient.DeleteConfigCallCount()).To(Equal(1))
+				actualType, actualName, _ := boshClient.DeleteConfigArgsForCall(0)
+				Expect(actualType).To(Equal(task.MaintenanceWindowConfigType))
+				Expect(actualName).To(Equal(deploymentName))
+			})
+		})
+
+		Context("and the request does not set a maintenance window", func() {
+			BeforeEach(func() {
+				manifestGenerator.GenerateManifestStub = func(_ context.Context, _, _ string, _ map[string]interface{}, previousManifest []byte, _ *string, _ *log.Logger) (task.BoshManifest, error) {
+					return previousManifest, nil
+				}
+			})
+
+			It("leaves the kept window as it is", func() {
+				Expect(deployError).NotTo(HaveOccurred())
+				Expect(boshClient.UpdateConfigCallCount()).To(BeZero())
+				Expect(boshClient.DeleteConfigCallCount()).To(BeZero())
+			})
+		})
+
+		Context("and the request defers upgrades", func() {
+			BeforeEach(func() {
+				requestParams = map[string]interface{}{
+					"parameters": map[string]interface{}{"upgrade_policy": "defer"},
+				}
+				manifestGenerator.GenerateManifestStub = func(_ context.Context, _, _ string, _ map[string]interface{}, previousManifest []byte, _ *string, _ *log.Logger) (task.BoshManifest, error) {
+					return previousManifest, nil
+				}
+			})
+
+			It("keeps the time of deferral in a director config named after the deployment", func() {
+				Expect(deployError).NotTo(HaveOccurred())
+				Expect(boshClient.UpdateConfigCallCount()).To(Equal(1))
+				actualType, actualName, actualContent, _ := boshClient.UpdateConfigArgsForCall(0)
+				Expect(actualType).To(Equal(task.UpgradeDeferredSinceConfigType))
+				Expect(actualName).To(Equal(deploymentName))
+				since, err := task.UpgradeDeferralOf(actualContent)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(since).To(BeTemporally("~", time.Now(), time.Minute))
+				Expect(boshClient.DeployCallCount()).To(Equal(1))
+			})
+
+			Context("and upgrades were already deferred", func() {
+				BeforeEach(func() {
+					boshClient.GetConfigReturns([]byte("2017-06-03T02:00:00Z"), true, nil)
+				})
+
+				It("keeps the original time of deferral", func() {
+					Expect(deployError).NotTo(HaveOccurred())
+					actualType, actualName, _ := boshClient.GetConfigArgsForCall(0)
+					Expect(actualType).To(Equal(task.UpgradeDeferredSinceConfigType))
+					Expect(actualName).To(Equal(deploymentName))
+					Expect(boshClient.UpdateConfigCallCount()).To(BeZero())
+				})
+			})
+
+			Context("and storing the deferral fails", func() {
+				BeforeEach(func() {
+					boshClient.UpdateConfigReturns(errors.New("director says no"))
+				})
+
+				It("still deploys and logs a warning", func() {
+					Expect(deployError).NotTo(HaveOccurred())
+					Expect(boshClient.DeployCallCount()).To(Equal(1))
+					Expect(logBuffer.String()).To(ContainSubstring(
+						fmt.Sprintf("warning: error storing upgrade deferral of deployment %s: director says no", deploymentName),
+					))
+				})
+			})
+
+			Context("and the director rejects the deployment", func() {
+				BeforeEach(func() {
+					boshClient.DeployReturns(0, errors.New("deployment is locked"))
+				})
+
+				It("does not defer upgrades", func() {
+					Expect(deployError).To(HaveOccurred())
+					Expect(boshClient.GetConfigCallCount()).To(BeZero())
+					Expect(boshClient.UpdateConfigCallCount()).To(BeZero())
+				})
+			})
+		})
+
+		Context("and the request sets the auto upgrade policy", func() {
+			BeforeEach(func() {
+				requestParams = map[string]interface{}{
+					"parameters": map[string]interface{}{"upgrade_policy": "auto"},
+				}
+				manifestGenerator.GenerateManifestStub = func(_ context.Context, _, _ string, _ map[string]interface{}, previousManifest []byte, _ *string, _ *log.Logger) (task.BoshManifest, error) {
+					return previousManifest, nil
+				}
+			})
+
+			It("ends the deferral by deleting its config", func() {
+				Expect(deployError).NotTo(HaveOccurred())
+				Expect(boshClient.DeleteConfigCallCount()).To(Equal(1))
+				actualType, actualName, _ := boshClient.DeleteConfigArgsForCall(0)
+				Expect(actualType).To(Equal(task.UpgradeDeferredSinceConfigType))
+				Expect(actualName).To(Equal(deploymentName))
+			})
+		})
+	})
+
+	Describe("Rollback()", func() {
+		previousManifest := []byte(`---
+name: some-deployment
+releases:
+- name: redis
+  version: "1"
+- name: syslog
+  version: latest
+stemcells:
+- alias: trusty
+  os: ubuntu-trusty
+  version: "3312"
+`)
+
+		BeforeEach(func() {
+			boshClient.GetTasksReturns([]boshdirector.BoshTask{{State: boshdirector.TaskError}}, nil)
+			boshClient.GetDeploymentReturns(manifest, true, nil)
+			boshClient.GetConfigReturns(previousManifest, true, nil)
+			boshClient.GetReleasesReturns(boshdirector.Releases{
+				{Name: "redis", ReleaseVersions: []boshdirector.ReleaseVersion{{Version: "1"}, {Version: "2"}}},
+				{Name: "syslog", ReleaseVersions: []boshdirector.ReleaseVersion{{Version: "7"}}},
+			}, nil)
+			boshClient.GetStemcellsReturns(boshdirector.Stemcells{
+				{Name: "bosh-warden-boshlite-ubuntu-trusty-go_agent", OperatingSystem: "ubuntu-trusty", Version: "3312"},
+			}, nil)
+			boshClient.DeployReturns(boshTaskID, nil)
+		})
+
+		JustBeforeEach(func() {
+			returnedTaskID, deployedManifest, deployError = deployer.Rollback(deploymentName, boshContextID, logger)
+		})
+
+		It("deploys the previous manifest", func() {
+			Expect(deployError).NotTo(HaveOccurred())
+			actualType, actualName, _ := boshClient.GetConfigArgsForCall(0)
+			Expect(actualType).To(Equal(task.PreviousManifestConfigType))
+			Expect(actualName).To(Equal(deploymentName))
+			Expect(boshClient.DeployCallCount()).To(Equal(1))
+			actualManifest, _, _ := boshClient.DeployArgsForCall(0)
+			Expect(actualManifest).To(Equal(previousManifest))
+			Expect(returnedTaskID).To(Equal(boshTaskID))
+			Expect(deployedManifest).To(Equal(previousManifest))
+		})
+
+		Context("when a bosh task is in progress for the deployment", func() {
+			BeforeEach(func() {
+				boshClient.GetTasksReturns([]boshdirector.BoshTask{{State: boshdirector.TaskProcessing}}, nil)
+			})
+
+			It("fails without deploying", func() {
+				Expect(deployError).To(BeAssignableToTypeOf(task.TaskInProgressError{}))
+				Expect(boshClient.DeployCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the deployment does not exist", func() {
+			BeforeEach(func() {
+				boshClient.GetDeploymentReturns(nil, false, nil)
+			})
+
+			It("returns a deployment not found error", func() {
+				Expect(deployError).To(BeAssignableToTypeOf(task.DeploymentNotFoundError{}))
+				Expect(boshClient.DeployCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when no previous manifest was retained", func() {
+			BeforeEach(func() {
+				boshClient.GetConfigReturns(nil, false, nil)
+			})
+
+			It("returns a no previous manifest error", func() {
+				Expect(deployError).To(BeAssignableToTypeOf(task.NoPreviousManifestError{}))
+				Expect(deployError).To(MatchError("no previous manifest retained for deployment " + deploymentName))
+				Expect(boshClient.DeployCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when getting the previous manifest fails", func() {
+			BeforeEach(func() {
+				boshClient.GetConfigReturns(nil, false, errors.New("director says no"))
+			})
+
+			It("returns a service error", func() {
+				Expect(deployError).To(BeAssignableToTypeOf(task.ServiceError{}))
+				Expect(boshClient.DeployCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when releases or stemcells of the previous manifest are no longer uploaded", func() {
+			BeforeEach(func() {
+				boshClient.GetReleasesReturns(boshdirector.Releases{
+					{Name: "redis", ReleaseVersions: []boshdirector.ReleaseVersion{{Version: "2"}}},
+				}, nil)
+				boshClient.GetStemcellsReturns(boshdirector.Stemcells{
+					{OperatingSystem: "ubuntu-xenial", Version: "3312"},
+				}, nil)
+			})
+
+			It("refuses to deploy, listing what is missing", func() {
+				Expect(deployError).To(BeAssignableToTypeOf(task.MissingDependenciesError{}))
+				Expect(deployError).To(MatchError(fmt.Sprintf(
+					"previous manifest of deployment %s references release redis/1, release syslog/latest, stemcell ubuntu-trusty/3312, which are no longer uploaded",
+					deploymentName,
+				)))
+				Expect(boshClient.DeployCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when getting the releases fails", func() {
+			BeforeEach(func() {
+				boshClient.GetReleasesReturns(nil, errors.New("director says no"))
+			})
+
+			It("returns a service error", func() {
+				Expect(deployError).To(BeAssignableToTypeOf(task.ServiceError{}))
+				Expect(deployError).To(MatchError("error getting releases: director says no"))
+			})
+		})
+
+		Context("when getting the stemcells fails", func() {
+			BeforeEach(func() {
+				boshClient.GetStemcellsReturns(nil, errors.New("director says no"))
+			})
+
+			It("returns a service error", func() {
+				Expect(deployError).To(BeAssignableToTypeOf(task.ServiceError{}))
+				Expect(deployError).To(MatchError("error getting stemcells: director says no"))
+			})
+		})
+	})
+
+	Describe("ChangeJobState()", func() {
+		BeforeEach(func() {
+			boshClient.GetTasksReturns([]boshdirector.BoshTask{{State: boshdirector.TaskDone}}, nil)
+			boshClient.ChangeJobStateReturns(boshTaskID, nil)
+		})
+
+		JustBeforeEach(func() {
+			returnedTaskID, deployError = deployer.ChangeJobState(deploymentName, boshdirector.JobStateRecreate, logger)
+		})
+
+		It("changes the job state of the deployment", func() {
+			Expect(boshClient.ChangeJobStateCallCount()).To(Equal(1))
+			actualDeploymentName, actualState, _, _ := boshClient.ChangeJobStateArgsForCall(0)
+			Expect(actualDeploymentName).To(Equal(deploymentName))
+			Expect(actualState).To(Equal(boshdirector.JobStateRecreate))
+		})
+
+		It("returns the bosh task ID", func() {
+			Expect(deployError).NotTo(HaveOccurred())
+			Expect(returnedTaskID).To(Equal(boshTaskID))
+		})
+
+		Context("when a bosh task is in progress for the deployment", func() {
+			BeforeEach(func() {
+				boshClient.GetTasksReturns([]boshdirector.BoshTask{{State: boshdirector.TaskProcessing}}, nil)
+			})
+
+			It("fails without changing the job state", func() {
+				Expect(deployError).To(BeAssignableToTypeOf(task.TaskInProgressError{}))
+				Expect(boshClient.ChangeJobStateCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the deployment does not exist", func() {
+			BeforeEach(func() {
+				boshClient.ChangeJobStateReturns(0, boshdirector.DeploymentNotFoundError{})
+			})
+
+			It("returns a deployment not found error", func() {
+				Expect(deployError).To(BeAssignableToTypeOf(task.DeploymentNotFoundError{}))
+			})
+		})
+
+		Context("when the director fails to change the job state", func() {
+			BeforeEach(func() {
+				boshClient.ChangeJobStateReturns(0, errors.New("director says no"))
+			})
+
+			It("returns a service error", func() {
+				Expect(deployError).To(BeAssignableToTypeOf(task.ServiceError{}))
+				Expect(deployError).To(MatchError(ContainSubstring("director says no")))
+			})
+		})
 	})
 })
 
@@ -0,0 +1,87 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package task
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// UpgradePolicyParameterKey is the arbitrary parameter under which a developer can
+// defer operator-initiated upgrades of the instance
+const UpgradePolicyParameterKey = "upgrade_policy"
+
+const (
+	UpgradePolicyAuto  = "auto"
+	UpgradePolicyDefer = "defer"
+)
+
+// UpgradeDeferredSinceConfigType is the type of the director configs recording when the developer
+// deferred upgrades of an instance, named after the deployment and present only while they are deferred
+const UpgradeDeferredSinceConfigType = "odb-upgrade-deferred-since"
+
+// UpgradePolicyFromParams returns the upgrade policy set by the request, and whether the request set one at all
+func UpgradePolicyFromParams(requestParams map[string]interface{}) (string, bool, error) {
+	parameters, ok := requestParams["parameters"].(map[string]interface{})
+	if !ok {
+		return "", false, nil
+	}
+
+	rawPolicy, found := parameters[UpgradePolicyParameterKey]
+	if !found {
+		return "", false, nil
+	}
+
+	policy, ok := rawPolicy.(string)
+	if !ok || (policy != UpgradePolicyAuto && policy != UpgradePolicyDefer) {
+		return "", true, fmt.Errorf(
+			"parameter %s must be one of '%s' or '%s'", UpgradePolicyParameterKey, UpgradePolicyAuto, UpgradePolicyDefer,
+		)
+	}
+	return policy, true, nil
+}
+
+// UpgradeDeferralOf parses the content of an upgrade deferral config
+func UpgradeDeferralOf(content []byte) (time.Time, error) {
+	since, err := time.Parse(time.RFC3339, string(content))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s config is invalid: %s", UpgradeDeferredSinceConfigType, err)
+	}
+	return since, nil
+}
+
+// storeUpgradePolicy records when the request deferred upgrades, or forgets the deferral when the
+// request sets the auto policy. Deferring again keeps the original time, so that developers cannot
+// extend a deferral without first ending it.
+func (d deployer) storeUpgradePolicy(deploymentName string, requestParams map[string]interface{}, now time.Time, logger *log.Logger) error {
+	policy, set, err := UpgradePolicyFromParams(requestParams)
+	if err != nil || !set {
+		return err
+	}
+
+	if policy == UpgradePolicyAuto {
+		if err := d.boshClient.DeleteConfig(UpgradeDeferredSinceConfigType, deploymentName, logger); err != nil {
+			return fmt.Errorf("error ending upgrade deferral of deployment %s: %s", deploymentName, err)
+		}
+		return nil
+	}
+
+	_, deferred, err := d.boshClient.GetConfig(UpgradeDeferredSinceConfigType, deploymentName, logger)
+	if err != nil {
+		return fmt.Errorf("error getting upgrade deferral of deployment %s: %s", deploymentName, err)
+	}
+	if deferred {
+		return nil
+	}
+
+	since := []byte(now.UTC().Format(time.RFC3339))
+	if err := d.boshClient.UpdateConfig(UpgradeDeferredSinceConfigType, deploymentName, since, logger); err != nil {
+		return fmt.Errorf("error storing upgrade deferral of deployment %s: %s", deploymentName, err)
+	}
+	return nil
+}
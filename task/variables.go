@@ -0,0 +1,267 @@
+// Copyright (C) 2016-Present Pivotal Software, Inc. All rights reserved.
+// This program and the accompanying materials are made available under the terms of the under the Apache License, Version 2.0 (the "License"); you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software distributed under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the License for the specific language governing permissions and limitations under the License.
+
+package task
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"regexp"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// VariablesParameterKey is the arbitrary parameter under which a developer can
+// supply values for ((placeholders)) that are not managed by the config server
+const VariablesParameterKey = "bosh_variables"
+
+var placeholderRegexp = regexp.MustCompile(`\(\(([^()]+)\)\)`)
+
+type manifestVariable struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+}
+
+type manifestWithVariables struct {
+	Variables []manifestVariable `yaml:"variables"`
+}
+
+// placeholderName returns the variable a placeholder refers to, e.g. ((!password.secret)) refers to password
+func placeholderName(placeholder string) string {
+	name := strings.TrimPrefix(strings.TrimSpace(placeholder), "!")
+	return strings.SplitN(name, ".", 2)[0]
+}
+
+func fullPlaceholder(value string) (string, bool) {
+	match := placeholderRegexp.FindStringSubmatch(value)
+	if match == nil || match[0] != value {
+		return "", false
+	}
+	return placeholderName(match[1]), true
+}
+
+func containsPlaceholder(value string) bool {
+	return placeholderRegexp.MatchString(value)
+}
+
+// interpolatedValuePattern matches what the broker can have interpolated for a placeholder. The
+// director would resolve placeholders in an interpolated value, so none can contain one.
+const interpolatedValuePattern = `(?:[^(]|\([^(]|\($)*`
+
+// matchesPlaceholders reports whether a value could be the result of the broker interpolating
+// a string containing ((placeholders)). Declared variables are resolved by the director, which
+// never returns them interpolated, so they only match themselves.
+func matchesPlaceholders(withPlaceholders string, other interface{}, declared map[string]bool) bool {
+	otherString, ok := other.(string)
+	if !ok {
+		name, isFullPlaceholder := fullPlaceholder(withPlaceholders)
+		return isFullPlaceholder && !declared[name]
+	}
+
+	pattern := "^"
+	literalStart := 0
+	for _, match := range placeholderRegexp.FindAllStringSubmatchIndex(withPlaceholders, -1) {
+		pattern += regexp.QuoteMeta(withPlaceholders[literalStart:match[0]])
+		if declared[placeholderName(withPlaceholders[match[2]:match[3]])] {
+			pattern += regexp.QuoteMeta(withPlaceholders[match[0]:match[1]])
+		} else {
+			pattern += interpolatedValuePattern
+		}
+		literalStart = match[1]
+	}
+	pattern += regexp.QuoteMeta(withPlaceholders[literalStart:]) + "$"
+
+	return regexp.MustCompile(pattern).MatchString(otherString)
+}
+
+// manifestValuesEqual matches placeholders on one side against values the broker interpolated on
+// the other. The director returns placeholders as written, so placeholders on both sides only match
+// each other literally.
+func manifestValuesEqual(this, that interface{}, declared map[string]bool) bool {
+	thisString, thisIsString := this.(string)
+	thatString, thatIsString := that.(string)
+	thisHasPlaceholders := thisIsString && containsPlaceholder(thisString)
+	thatHasPlaceholders := thatIsString && containsPlaceholder(thatString)
+
+	switch {
+	case thisHasPlaceholders && thatHasPlaceholders:
+		return thisString == thatString ||
+			matchesPlaceholders(thisString, thatString, declared) ||
+			matchesPlaceholders(thatString, thisString, declared)
+	case thisHasPlaceholders:
+		return matchesPlaceholders(thisString, that, declared)
+	case thatHasPlaceholders:
+		return matchesPlaceholders(thatString, this, declared)
+	}
+
+	switch thisValue := this.(type) {
+	case map[interface{}]interface{}:
+		thatValue, ok := that.(map[interface{}]interface{})
+		if !ok || len(thisValue) != len(thatValue) {
+			return false
+		}
+		for key, value := range thisValue {
+			otherValue, found := thatValue[key]
+			if !found || !manifestValuesEqual(value, otherValue, declared) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		thatValue, ok := that.([]interface{})
+		if !ok || len(thisValue) != len(thatValue) {
+			return false
+		}
+		for i := range thisValue {
+			if !manifestValuesEqual(thisValue[i], thatValue[i], declared) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(this, that)
+	}
+}
+
+func declaredVariables(manifest []byte) (map[string]bool, error) {
+	var parsed manifestWithVariables
+	if err := yaml.Unmarshal(manifest, &parsed); err != nil {
+		return nil, err
+	}
+
+	declared := map[string]bool{}
+	for _, variable := range parsed.Variables {
+		declared[variable.Name] = true
+	}
+	return declared, nil
+}
+
+func variableOverrides(requestParams map[string]interface{}) (map[string]interface{}, error) {
+	parameters, ok := requestParams["parameters"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	rawOverrides, found := parameters[VariablesParameterKey]
+	if !found {
+		return nil, nil
+	}
+
+	overrides, ok := rawOverrides.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("parameter %s must be a map of variable names to values", VariablesParameterKey)
+	}
+	return overrides, nil
+}
+
+// previousVariableValues finds values that were interpolated into the deployed manifest
+// for placeholders that the regenerated manifest still references, so they survive redeploys
+func previousVariableValues(newValue, oldValue interface{}, declared map[string]bool, found map[string]interface{}) {
+	switch newTyped := newValue.(type) {
+	case string:
+		name, ok := fullPlaceholder(newTyped)
+		if !ok || declared[name] || oldValue == nil {
+			return
+		}
+		if oldString, isString := oldValue.(string); isString && containsPlaceholder(oldString) {
+			return
+		}
+		found[name] = oldValue
+	case map[interface{}]interface{}:
+		oldMap, ok := oldValue.(map[interface{}]interface{})
+		if !ok {
+			return
+		}
+		for key, value := range newTyped {
+			previousVariableValues(value, oldMap[key], declared, found)
+		}
+	case []interface{}:
+		oldSlice, ok := oldValue.([]interface{})
+		if !ok || len(oldSlice) != len(newTyped) {
+			return
+		}
+		for i := range newTyped {
+			previousVariableValues(newTyped[i], oldSlice[i], declared, found)
+		}
+	}
+}
+
+func interpolateValue(value interface{}, values map[string]interface{}) interface{} {
+	switch typed := value.(type) {
+	case string:
+		if name, ok := fullPlaceholder(typed); ok {
+			if replacement, found := values[name]; found {
+				return replacement
+			}
+			return typed
+		}
+		return placeholderRegexp.ReplaceAllStringFunc(typed, func(placeholder string) string {
+			name := placeholderName(placeholderRegexp.FindStringSubmatch(placeholder)[1])
+			if replacement, found := values[name]; found {
+				return fmt.Sprintf("%v", replacement)
+			}
+			return placeholder
+		})
+	case map[interface{}]interface{}:
+		interpolated := map[interface{}]interface{}{}
+		for key, v := range typed {
+			interpolated[key] = interpolateValue(v, values)
+		}
+		return interpolated
+	case []interface{}:
+		interpolated := make([]interface{}, len(typed))
+		for i, v := range typed {
+			interpolated[i] = interpolateValue(v, values)
+		}
+		return interpolated
+	default:
+		return value
+	}
+}
+
+// interpolateVariables replaces ((placeholders)) that are not declared in the manifest's
+// variables block with developer supplied overrides, or with the values previously deployed.
+// Declared variables are left for the director to resolve from the config server.
+func interpolateVariables(manifest, oldManifest []byte, overrides map[string]interface{}, logger *log.Logger) ([]byte, error) {
+	if !containsPlaceholder(string(manifest)) {
+		return manifest, nil
+	}
+
+	declared, err := declaredVariables(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsedManifest interface{}
+	if err := yaml.Unmarshal(manifest, &parsedManifest); err != nil {
+		return nil, err
+	}
+
+	values := map[string]interface{}{}
+	if oldManifest != nil {
+		var parsedOldManifest interface{}
+		if err := yaml.Unmarshal(oldManifest, &parsedOldManifest); err != nil {
+			return nil, err
+		}
+		previousVariableValues(parsedManifest, parsedOldManifest, declared, values)
+	}
+
+	for name, value := range overrides {
+		if declared[name] {
+			logger.Printf("ignoring override for variable %s as it is managed by the config server\n", name)
+			continue
+		}
+		values[name] = value
+	}
+
+	if len(values) == 0 {
+		return manifest, nil
+	}
+
+	return yaml.Marshal(interpolateValue(parsedManifest, values))
+}
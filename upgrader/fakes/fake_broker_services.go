@@ -7,6 +7,7 @@ import (
 	"github.com/pivotal-cf/brokerapi"
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/upgrader"
 )
 
@@ -22,10 +23,24 @@ type FakeBrokerServices struct {
 		result1 []string
 		result2 error
 	}
-	UpgradeInstanceStub        func(instance string) (services.UpgradeOperation, error)
+	MaintenanceWindowStub        func(instance string) (*config.MaintenanceWindow, error)
+	maintenanceWindowMutex       sync.RWMutex
+	maintenanceWindowArgsForCall []struct {
+		instance string
+	}
+	maintenanceWindowReturns struct {
+		result1 *config.MaintenanceWindow
+		result2 error
+	}
+	maintenanceWindowReturnsOnCall map[int]struct {
+		result1 *config.MaintenanceWindow
+		result2 error
+	}
+	UpgradeInstanceStub        func(instance string, options broker.UpgradeOptions) (services.UpgradeOperation, error)
 	upgradeInstanceMutex       sync.RWMutex
 	upgradeInstanceArgsForCall []struct {
 		instance string
+		options  broker.UpgradeOptions
 	}
 	upgradeInstanceReturns struct {
 		result1 services.UpgradeOperation
@@ -96,16 +111,68 @@ func (fake *FakeBrokerServices) InstancesReturnsOnCall(i int, result1 []string,
 	}{result1, result2}
 }
 
-func (fake *FakeBrokerServices) UpgradeInstance(instance string) (services.UpgradeOperation, error) {
+func (fake *FakeBrokerServices) MaintenanceWindow(instance string) (*config.MaintenanceWindow, error) {
+	fake.maintenanceWindowMutex.Lock()
+	ret, specificReturn := fake.maintenanceWindowReturnsOnCall[len(fake.maintenanceWindowArgsForCall)]
+	fake.maintenanceWindowArgsForCall = append(fake.maintenanceWindowArgsForCall, struct {
+		instance string
+	}{instance})
+	fake.recordInvocation("MaintenanceWindow", []interface{}{instance})
+	fake.maintenanceWindowMutex.Unlock()
+	if fake.MaintenanceWindowStub != nil {
+		return fake.MaintenanceWindowStub(instance)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.maintenanceWindowReturns.result1, fake.maintenanceWindowReturns.result2
+}
+
+func (fake *FakeBrokerServices) MaintenanceWindowCallCount() int {
+	fake.maintenanceWindowMutex.RLock()
+	defer fake.maintenanceWindowMutex.RUnlock()
+	return len(fake.maintenanceWindowArgsForCall)
+}
+
+func (fake *FakeBrokerServices) MaintenanceWindowArgsForCall(i int) string {
+	fake.maintenanceWindowMutex.RLock()
+	defer fake.maintenanceWindowMutex.RUnlock()
+	return fake.maintenanceWindowArgsForCall[i].instance
+}
+
+func (fake *FakeBrokerServices) MaintenanceWindowReturns(result1 *config.MaintenanceWindow, result2 error) {
+	fake.MaintenanceWindowStub = nil
+	fake.maintenanceWindowReturns = struct {
+		result1 *config.MaintenanceWindow
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBrokerServices) MaintenanceWindowReturnsOnCall(i int, result1 *config.MaintenanceWindow, result2 error) {
+	fake.MaintenanceWindowStub = nil
+	if fake.maintenanceWindowReturnsOnCall == nil {
+		fake.maintenanceWindowReturnsOnCall = make(map[int]struct {
+			result1 *config.MaintenanceWindow
+			result2 error
+		})
+	}
+	fake.maintenanceWindowReturnsOnCall[i] = struct {
+		result1 *config.MaintenanceWindow
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBrokerServices) UpgradeInstance(instance string, options broker.UpgradeOptions) (services.UpgradeOperation, error) {
 	fake.upgradeInstanceMutex.Lock()
 	ret, specificReturn := fake.upgradeInstanceReturnsOnCall[len(fake.upgradeInstanceArgsForCall)]
 	fake.upgradeInstanceArgsForCall = append(fake.upgradeInstanceArgsForCall, struct {
 		instance string
-	}{instance})
-	fake.recordInvocation("UpgradeInstance", []interface{}{instance})
+		options  broker.UpgradeOptions
+	}{instance, options})
+	fake.recordInvocation("UpgradeInstance", []interface{}{instance, options})
 	fake.upgradeInstanceMutex.Unlock()
 	if fake.UpgradeInstanceStub != nil {
-		return fake.UpgradeInstanceStub(instance)
+		return fake.UpgradeInstanceStub(instance, options)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -119,10 +186,10 @@ func (fake *FakeBrokerServices) UpgradeInstanceCallCount() int {
 	return len(fake.upgradeInstanceArgsForCall)
 }
 
-func (fake *FakeBrokerServices) UpgradeInstanceArgsForCall(i int) string {
+func (fake *FakeBrokerServices) UpgradeInstanceArgsForCall(i int) (string, broker.UpgradeOptions) {
 	fake.upgradeInstanceMutex.RLock()
 	defer fake.upgradeInstanceMutex.RUnlock()
-	return fake.upgradeInstanceArgsForCall[i].instance
+	return fake.upgradeInstanceArgsForCall[i].instance, fake.upgradeInstanceArgsForCall[i].options
 }
 
 func (fake *FakeBrokerServices) UpgradeInstanceReturns(result1 services.UpgradeOperation, result2 error) {
@@ -204,6 +271,8 @@ func (fake *FakeBrokerServices) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.instancesMutex.RLock()
 	defer fake.instancesMutex.RUnlock()
+	fake.maintenanceWindowMutex.RLock()
+	defer fake.maintenanceWindowMutex.RUnlock()
 	fake.upgradeInstanceMutex.RLock()
 	defer fake.upgradeInstanceMutex.RUnlock()
 	fake.lastOperationMutex.RLock()
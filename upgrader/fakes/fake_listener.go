@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/upgrader"
 )
 
@@ -30,6 +31,12 @@ type FakeListener struct {
 	instanceUpgradeStartResultArgsForCall []struct {
 		status services.UpgradeOperationType
 	}
+	InstanceDeferredStub        func(instance string, window config.MaintenanceWindow)
+	instanceDeferredMutex       sync.RWMutex
+	instanceDeferredArgsForCall []struct {
+		instance string
+		window   config.MaintenanceWindow
+	}
 	InstanceUpgradedStub        func(instance string, result string)
 	instanceUpgradedMutex       sync.RWMutex
 	instanceUpgradedArgsForCall []struct {
@@ -51,12 +58,13 @@ type FakeListener struct {
 		upgradesLeftCount int
 		deletedCount      int
 	}
-	FinishedStub        func(orphanCount, upgradedCount, deletedCount int)
+	FinishedStub        func(orphanCount, upgradedCount, deletedCount, deferredCount int)
 	finishedMutex       sync.RWMutex
 	finishedArgsForCall []struct {
 		orphanCount   int
 		upgradedCount int
 		deletedCount  int
+		deferredCount int
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
@@ -157,6 +165,31 @@ func (fake *FakeListener) InstanceUpgradeStartResultArgsForCall(i int) services.
 	return fake.instanceUpgradeStartResultArgsForCall[i].status
 }
 
+func (fake *FakeListener) InstanceDeferred(instance string, window config.MaintenanceWindow) {
+	fake.instanceDeferredMutex.Lock()
+	fake.instanceDeferredArgsForCall = append(fake.instanceDeferredArgsForCall, struct {
+		instance string
+		window   config.MaintenanceWindow
+	}{instance, window})
+	fake.recordInvocation("InstanceDeferred", []interface{}{instance, window})
+	fake.instanceDeferredMutex.Unlock()
+	if fake.InstanceDeferredStub != nil {
+		fake.InstanceDeferredStub(instance, window)
+	}
+}
+
+func (fake *FakeListener) InstanceDeferredCallCount() int {
+	fake.instanceDeferredMutex.RLock()
+	defer fake.instanceDeferredMutex.RUnlock()
+	return len(fake.instanceDeferredArgsForCall)
+}
+
+func (fake *FakeListener) InstanceDeferredArgsForCall(i int) (string, config.MaintenanceWindow) {
+	fake.instanceDeferredMutex.RLock()
+	defer fake.instanceDeferredMutex.RUnlock()
+	return fake.instanceDeferredArgsForCall[i].instance, fake.instanceDeferredArgsForCall[i].window
+}
+
 func (fake *FakeListener) InstanceUpgraded(instance string, result string) {
 	fake.instanceUpgradedMutex.Lock()
 	fake.instanceUpgradedArgsForCall = append(fake.instanceUpgradedArgsForCall, struct {
@@ -235,17 +268,18 @@ func (fake *FakeListener) ProgressArgsForCall(i int) (time.Duration, int, int, i
 	return fake.progressArgsForCall[i].pollingInterval, fake.progressArgsForCall[i].orphanCount, fake.progressArgsForCall[i].upgradedCount, fake.progressArgsForCall[i].upgradesLeftCount, fake.progressArgsForCall[i].deletedCount
 }
 
-func (fake *FakeListener) Finished(orphanCount int, upgradedCount int, deletedCount int) {
+func (fake *FakeListener) Finished(orphanCount int, upgradedCount int, deletedCount int, deferredCount int) {
 	fake.finishedMutex.Lock()
 	fake.finishedArgsForCall = append(fake.finishedArgsForCall, struct {
 		orphanCount   int
 		upgradedCount int
 		deletedCount  int
-	}{orphanCount, upgradedCount, deletedCount})
-	fake.recordInvocation("Finished", []interface{}{orphanCount, upgradedCount, deletedCount})
+		deferredCount int
+	}{orphanCount, upgradedCount, deletedCount, deferredCount})
+	fake.recordInvocation("Finished", []interface{}{orphanCount, upgradedCount, deletedCount, deferredCount})
 	fake.finishedMutex.Unlock()
 	if fake.FinishedStub != nil {
-		fake.FinishedStub(orphanCount, upgradedCount, deletedCount)
+		fake.FinishedStub(orphanCount, upgradedCount, deletedCount, deferredCount)
 	}
 }
 
@@ -255,10 +289,10 @@ func (fake *FakeListener) FinishedCallCount() int {
 	return len(fake.finishedArgsForCall)
 }
 
-func (fake *FakeListener) FinishedArgsForCall(i int) (int, int, int) {
+func (fake *FakeListener) FinishedArgsForCall(i int) (int, int, int, int) {
 	fake.finishedMutex.RLock()
 	defer fake.finishedMutex.RUnlock()
-	return fake.finishedArgsForCall[i].orphanCount, fake.finishedArgsForCall[i].upgradedCount, fake.finishedArgsForCall[i].deletedCount
+	return fake.finishedArgsForCall[i].orphanCount, fake.finishedArgsForCall[i].upgradedCount, fake.finishedArgsForCall[i].deletedCount, fake.finishedArgsForCall[i].deferredCount
 }
 
 func (fake *FakeListener) Invocations() map[string][][]interface{} {
@@ -272,6 +306,8 @@ func (fake *FakeListener) Invocations() map[string][][]interface{} {
 	defer fake.instanceUpgradeStartingMutex.RUnlock()
 	fake.instanceUpgradeStartResultMutex.RLock()
 	defer fake.instanceUpgradeStartResultMutex.RUnlock()
+	fake.instanceDeferredMutex.RLock()
+	defer fake.instanceDeferredMutex.RUnlock()
 	fake.instanceUpgradedMutex.RLock()
 	defer fake.instanceUpgradedMutex.RUnlock()
 	fake.waitingForMutex.RLock()
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
 )
 
 type LoggingListener struct {
@@ -51,6 +52,8 @@ func (ll LoggingListener) InstanceUpgradeStartResult(resultType services.Upgrade
 		message = "orphan CF service instance detected - no corresponding bosh deployment"
 	case services.OperationInProgress:
 		message = "operation in progress"
+	case services.UpgradeDeferred:
+		message = "deferred by the instance's upgrade policy"
 	default:
 		message = "unexpected result"
 	}
@@ -58,6 +61,10 @@ func (ll LoggingListener) InstanceUpgradeStartResult(resultType services.Upgrade
 	ll.logger.Printf("Result: %s", message)
 }
 
+func (ll LoggingListener) InstanceDeferred(instance string, window config.MaintenanceWindow) {
+	ll.logger.Printf("Result: deferred, outside maintenance window %s", window)
+}
+
 func (ll LoggingListener) InstanceUpgraded(instance string, result string) {
 	ll.logger.Printf("Result: Service Instance %s upgrade %s\n", instance, result)
 }
@@ -81,13 +88,15 @@ func (ll LoggingListener) Progress(pollingInterval time.Duration, orphanCount, u
 	)
 }
 
-func (ll LoggingListener) Finished(orphanCount, upgradedCount, deletedCount int) {
+func (ll LoggingListener) Finished(orphanCount, upgradedCount, deletedCount, deferredCount int) {
 	ll.logger.Printf("FINISHED UPGRADES Summary: "+
 		"Number of successful upgrades: %d; "+
 		"Number of CF service instance orphans detected: %d; "+
-		"Number of deleted instances before upgrade could occur: %d",
+		"Number of deleted instances before upgrade could occur: %d; "+
+		"Number of deferred instances: %d",
 		upgradedCount,
 		orphanCount,
 		deletedCount,
+		deferredCount,
 	)
 }
@@ -15,6 +15,7 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gbytes"
 	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/loggerfactory"
 	"github.com/pivotal-cf/on-demand-service-broker/upgrader"
 )
@@ -94,6 +95,16 @@ var _ = Describe("Logging Listener", func() {
 			})
 		})
 
+		Context("when deferred", func() {
+			BeforeEach(func() {
+				result = services.UpgradeDeferred
+			})
+
+			It("shows deferred by the upgrade policy", func() {
+				Expect(buffer).To(Say("Result: deferred by the instance's upgrade policy"))
+			})
+		})
+
 		Context("when error", func() {
 			BeforeEach(func() {
 				result = services.UpgradeOperationType(-1)
@@ -110,6 +121,12 @@ var _ = Describe("Logging Listener", func() {
 			To(Say("Waiting for upgrade to complete for one: bosh task id 999"))
 	})
 
+	It("Shows that an instance has been deferred to its maintenance window", func() {
+		Expect(logResultsFrom(func(listener upgrader.Listener) {
+			listener.InstanceDeferred("one", config.MaintenanceWindow{Days: []string{"sat", "sun"}, Start: "02:00", Duration: "4h"})
+		})).To(Say("Result: deferred, outside maintenance window sat,sun 02:00 UTC for 4h"))
+	})
+
 	It("Shows which instance has been upgraded", func() {
 		Expect(logResultsFrom(func(listener upgrader.Listener) { listener.InstanceUpgraded("one", "success") })).
 			To(Say("Result: Service Instance one upgrade success"))
@@ -129,13 +146,14 @@ var _ = Describe("Logging Listener", func() {
 
 	It("Shows a final summary", func() {
 		buffer := logResultsFrom(func(listener upgrader.Listener) {
-			listener.Finished(23, 34, 45)
+			listener.Finished(23, 34, 45, 56)
 		})
 
 		Expect(buffer).To(Say("FINISHED UPGRADES"))
 		Expect(buffer).To(Say("Number of successful upgrades: 34"))
 		Expect(buffer).To(Say("Number of CF service instance orphans detected: 23"))
 		Expect(buffer).To(Say("Number of deleted instances before upgrade could occur: 45"))
+		Expect(buffer).To(Say("Number of deferred instances: 56"))
 	})
 })
 
@@ -13,6 +13,7 @@ import (
 	"github.com/pivotal-cf/brokerapi"
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
 )
 
 //go:generate counterfeiter -o fakes/fake_listener.go . Listener
@@ -21,38 +22,55 @@ type Listener interface {
 	InstancesToUpgrade(instances []string)
 	InstanceUpgradeStarting(instance string, index, totalInstances int)
 	InstanceUpgradeStartResult(status services.UpgradeOperationType)
+	InstanceDeferred(instance string, window config.MaintenanceWindow)
 	InstanceUpgraded(instance string, result string)
 	WaitingFor(instance string, boshTaskId int)
 	Progress(pollingInterval time.Duration, orphanCount, upgradedCount, upgradesLeftCount, deletedCount int)
-	Finished(orphanCount, upgradedCount, deletedCount int)
+	Finished(orphanCount, upgradedCount, deletedCount, deferredCount int)
 }
 
 //go:generate counterfeiter -o fakes/fake_broker_services.go . BrokerServices
 type BrokerServices interface {
 	Instances() ([]string, error)
-	UpgradeInstance(instance string) (services.UpgradeOperation, error)
+	MaintenanceWindow(instance string) (*config.MaintenanceWindow, error)
+	UpgradeInstance(instance string, options broker.UpgradeOptions) (services.UpgradeOperation, error)
 	LastOperation(instance string, operationData broker.OperationData) (brokerapi.LastOperation, error)
 }
 
+//go:generate counterfeiter -o fakes/fake_clock.go . Clock
+type Clock interface {
+	Now() time.Time
+}
+
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
 type upgrader struct {
 	brokerServices  BrokerServices
 	brokerUsername  string
 	brokerPassword  string
 	brokerUrl       string
 	pollingInterval time.Duration
+	upgradeOptions  broker.UpgradeOptions
 	listener        Listener
+	clock           Clock
 }
 
-func New(brokerServices BrokerServices, pollingInterval int, listener Listener) upgrader {
+func New(brokerServices BrokerServices, pollingInterval int, upgradeOptions broker.UpgradeOptions, listener Listener, clock Clock) upgrader {
 	return upgrader{
 		brokerServices:  brokerServices,
 		pollingInterval: time.Duration(pollingInterval) * time.Second,
+		upgradeOptions:  upgradeOptions,
 		listener:        listener,
+		clock:           clock,
 	}
 }
 
 func (u upgrader) Upgrade() error {
-	var upgradedTotal, orphansTotal, deletedTotal int
+	var upgradedTotal, orphansTotal, deletedTotal, deferredTotal int
 
 	u.listener.Starting()
 
@@ -64,7 +82,7 @@ func (u upgrader) Upgrade() error {
 	u.listener.InstancesToUpgrade(instanceGUIDsToUpgrade)
 
 	for len(instanceGUIDsToUpgrade) > 0 {
-		upgradedCount, orphanCount, deletedCount, retryInstanceGUIDs, err := u.upgradeInstances(instanceGUIDsToUpgrade)
+		upgradedCount, orphanCount, deletedCount, deferredCount, retryInstanceGUIDs, err := u.upgradeInstances(instanceGUIDsToUpgrade)
 		if err != nil {
 			return err
 		}
@@ -72,6 +90,7 @@ func (u upgrader) Upgrade() error {
 		upgradedTotal += upgradedCount
 		orphansTotal += orphanCount
 		deletedTotal += deletedCount
+		deferredTotal += deferredCount
 
 		instanceGUIDsToUpgrade = retryInstanceGUIDs
 		retryCount := len(instanceGUIDsToUpgrade)
@@ -82,23 +101,36 @@ func (u upgrader) Upgrade() error {
 		}
 	}
 
-	u.listener.Finished(orphansTotal, upgradedTotal, deletedTotal)
+	u.listener.Finished(orphansTotal, upgradedTotal, deletedTotal, deferredTotal)
 
 	return nil
 }
 
-func (u upgrader) upgradeInstances(instances []string) (int, int, int, []string, error) {
+func (u upgrader) upgradeInstances(instances []string) (int, int, int, int, []string, error) {
 	var (
-		upgradedCount, orphanCount, deletedCount int
-		idsToRetry                               []string
+		upgradedCount, orphanCount, deletedCount, deferredCount int
+		idsToRetry                                              []string
 	)
 
 	instanceCount := len(instances)
 	for i, instance := range instances {
 		u.listener.InstanceUpgradeStarting(instance, i, instanceCount)
-		operation, err := u.brokerServices.UpgradeInstance(instance)
+
+		window, err := u.brokerServices.MaintenanceWindow(instance)
+		if err != nil {
+			return 0, 0, 0, 0, nil, fmt.Errorf(
+				"error getting maintenance window of service instance %s: %s", instance, err,
+			)
+		}
+		if window != nil && !window.Contains(u.clock.Now()) {
+			u.listener.InstanceDeferred(instance, *window)
+			deferredCount++
+			continue
+		}
+
+		operation, err := u.brokerServices.UpgradeInstance(instance, u.upgradeOptions)
 		if err != nil {
-			return 0, 0, 0, nil, fmt.Errorf(
+			return 0, 0, 0, 0, nil, fmt.Errorf(
 				"Upgrade failed for service instance %s: %s\n", instance, err,
 			)
 		}
@@ -110,19 +142,21 @@ func (u upgrader) upgradeInstances(instances []string) (int, int, int, []string,
 			orphanCount++
 		case services.InstanceNotFound:
 			deletedCount++
+		case services.UpgradeDeferred:
+			deferredCount++
 		case services.OperationInProgress:
 			idsToRetry = append(idsToRetry, instance)
 		case services.UpgradeAccepted:
 			if err := u.pollLastOperation(instance, operation.Data); err != nil {
 				u.listener.InstanceUpgraded(instance, "failure")
-				return 0, 0, 0, nil, err
+				return 0, 0, 0, 0, nil, err
 			}
 			u.listener.InstanceUpgraded(instance, "success")
 			upgradedCount++
 		}
 	}
 
-	return upgradedCount, orphanCount, deletedCount, idsToRetry, nil
+	return upgradedCount, orphanCount, deletedCount, deferredCount, idsToRetry, nil
 }
 
 func (u upgrader) pollLastOperation(instance string, data broker.OperationData) error {
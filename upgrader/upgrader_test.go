@@ -16,6 +16,7 @@ import (
 	"github.com/pivotal-cf/brokerapi"
 	"github.com/pivotal-cf/on-demand-service-broker/broker"
 	"github.com/pivotal-cf/on-demand-service-broker/broker/services"
+	"github.com/pivotal-cf/on-demand-service-broker/config"
 	"github.com/pivotal-cf/on-demand-service-broker/upgrader"
 	"github.com/pivotal-cf/on-demand-service-broker/upgrader/fakes"
 )
@@ -31,6 +32,8 @@ var _ = Describe("Upgrader", func() {
 		actualErr            error
 		fakeListener         *fakes.FakeListener
 		brokerServicesClient *fakes.FakeBrokerServices
+		fakeClock            *fakes.FakeClock
+		upgradeOptions       broker.UpgradeOptions
 
 		upgradeOperationAccepted = services.UpgradeOperation{
 			Type: services.UpgradeAccepted,
@@ -42,10 +45,13 @@ var _ = Describe("Upgrader", func() {
 	BeforeEach(func() {
 		fakeListener = new(fakes.FakeListener)
 		brokerServicesClient = new(fakes.FakeBrokerServices)
+		fakeClock = new(fakes.FakeClock)
+		fakeClock.NowReturns(time.Date(2017, 6, 3, 3, 0, 0, 0, time.UTC))
+		upgradeOptions = broker.UpgradeOptions{}
 	})
 
 	JustBeforeEach(func() {
-		upgrader := upgrader.New(brokerServicesClient, pollingInterval, fakeListener)
+		upgrader := upgrader.New(brokerServicesClient, pollingInterval, upgradeOptions, fakeListener, fakeClock)
 		actualErr = upgrader.Upgrade()
 	})
 
@@ -94,6 +100,92 @@ var _ = Describe("Upgrader", func() {
 		})
 	})
 
+	Context("when instances have maintenance windows", func() {
+		var (
+			openWindow   = &config.MaintenanceWindow{Days: []string{"sat"}, Start: "02:00", Duration: "4h"}
+			closedWindow = &config.MaintenanceWindow{Days: []string{"sun"}, Start: "02:00", Duration: "4h"}
+		)
+
+		BeforeEach(func() {
+			brokerServicesClient.InstancesReturns([]string{"open", "closed", "unrestricted"}, nil)
+			brokerServicesClient.MaintenanceWindowStub = func(instance string) (*config.MaintenanceWindow, error) {
+				switch instance {
+				case "open":
+					return openWindow, nil
+				case "closed":
+					return closedWindow, nil
+				}
+				return nil, nil
+			}
+			brokerServicesClient.UpgradeInstanceReturns(upgradeOperationAccepted, nil)
+			brokerServicesClient.LastOperationReturns(lastOperationSucceeded, nil)
+		})
+
+		It("upgrades instances within their window or without one", func() {
+			Expect(actualErr).NotTo(HaveOccurred())
+			Expect(brokerServicesClient.UpgradeInstanceCallCount()).To(Equal(2))
+			firstInstance, _ := brokerServicesClient.UpgradeInstanceArgsForCall(0)
+			Expect(firstInstance).To(Equal("open"))
+			secondInstance, _ := brokerServicesClient.UpgradeInstanceArgsForCall(1)
+			Expect(secondInstance).To(Equal("unrestricted"))
+			hasReportedUpgraded(fakeListener, "open", "unrestricted")
+		})
+
+		It("defers instances outside their window to the next run", func() {
+			Expect(fakeListener.InstanceDeferredCallCount()).To(Equal(1))
+			actualInstance, actualWindow := fakeListener.InstanceDeferredArgsForCall(0)
+			Expect(actualInstance).To(Equal("closed"))
+			Expect(actualWindow).To(Equal(*closedWindow))
+			hasReportedFinished(fakeListener, 0, 2, 0, 1)
+		})
+
+		Context("and getting a maintenance window fails", func() {
+			BeforeEach(func() {
+				brokerServicesClient.MaintenanceWindowStub = nil
+				brokerServicesClient.MaintenanceWindowReturns(nil, errors.New("broker error"))
+			})
+
+			It("returns an error", func() {
+				Expect(actualErr).To(MatchError("error getting maintenance window of service instance open: broker error"))
+				Expect(brokerServicesClient.UpgradeInstanceCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Context("when an instance's upgrade policy defers upgrades", func() {
+		BeforeEach(func() {
+			brokerServicesClient.InstancesReturns([]string{"deferred", "upgraded"}, nil)
+			brokerServicesClient.UpgradeInstanceStub = func(instance string, options broker.UpgradeOptions) (services.UpgradeOperation, error) {
+				if instance == "deferred" && !options.OverrideUpgradePolicy {
+					return services.UpgradeOperation{Type: services.UpgradeDeferred}, nil
+				}
+				return upgradeOperationAccepted, nil
+			}
+			brokerServicesClient.LastOperationReturns(lastOperationSucceeded, nil)
+		})
+
+		It("reports the instance as deferred", func() {
+			Expect(actualErr).NotTo(HaveOccurred())
+			hasReportedInstanceUpgradeStartResult(fakeListener, services.UpgradeDeferred, services.UpgradeAccepted)
+			hasReportedUpgraded(fakeListener, "upgraded")
+			hasReportedFinished(fakeListener, 0, 1, 0, 1)
+		})
+
+		Context("and the upgrade policy is overridden", func() {
+			BeforeEach(func() {
+				upgradeOptions = broker.UpgradeOptions{OverrideUpgradePolicy: true}
+			})
+
+			It("upgrades the instance", func() {
+				Expect(actualErr).NotTo(HaveOccurred())
+				_, actualOptions := brokerServicesClient.UpgradeInstanceArgsForCall(0)
+				Expect(actualOptions).To(Equal(broker.UpgradeOptions{OverrideUpgradePolicy: true}))
+				hasReportedUpgraded(fakeListener, "deferred", "upgraded")
+				hasReportedFinished(fakeListener, 0, 2, 0, 0)
+			})
+		})
+	})
+
 	Context("when upgrading an instance is not instant", func() {
 		BeforeEach(func() {
 			brokerServicesClient.InstancesReturns([]string{serviceInstanceId}, nil)
@@ -126,7 +218,7 @@ var _ = Describe("Upgrader", func() {
 
 			hasReportedInstanceUpgradeStartResult(fakeListener, services.InstanceNotFound)
 			hasReportedProgress(fakeListener, zeroSeconds, 0, 0, 0, 1)
-			hasReportedFinished(fakeListener, 0, 0, 1)
+			hasReportedFinished(fakeListener, 0, 0, 1, 0)
 		})
 	})
 
@@ -143,7 +235,7 @@ var _ = Describe("Upgrader", func() {
 
 			hasReportedInstanceUpgradeStartResult(fakeListener, services.OrphanDeployment)
 			hasReportedProgress(fakeListener, zeroSeconds, 1, 0, 0, 0)
-			hasReportedFinished(fakeListener, 1, 0, 0)
+			hasReportedFinished(fakeListener, 1, 0, 0, 0)
 		})
 	})
 
@@ -172,7 +264,7 @@ var _ = Describe("Upgrader", func() {
 				services.UpgradeAccepted,
 			)
 			hasReportedRetries(fakeListener, 1, 1, 1, 0)
-			hasReportedFinished(fakeListener, 0, 1, 0)
+			hasReportedFinished(fakeListener, 0, 1, 0, 0)
 		})
 	})
 
@@ -197,7 +289,7 @@ var _ = Describe("Upgrader", func() {
 
 			hasReportedRetries(fakeListener, 1, 1, 1, 0)
 			hasReportedOrphans(fakeListener, 0, 0, 0, 1)
-			hasReportedFinished(fakeListener, 1, 0, 0)
+			hasReportedFinished(fakeListener, 1, 0, 0, 0)
 		})
 	})
 
@@ -237,7 +329,7 @@ var _ = Describe("Upgrader", func() {
 				hasReportedWaitingFor(fakeListener, map[string]int{serviceInstance1: upgradeTaskID1, serviceInstance2: upgradeTaskID2, serviceInstance3: upgradeTaskID3})
 				hasReportedUpgraded(fakeListener, serviceInstance1, serviceInstance2, serviceInstance3)
 				hasReportedProgress(fakeListener, zeroSeconds, 0, 3, 0, 0)
-				hasReportedFinished(fakeListener, 0, 3, 0)
+				hasReportedFinished(fakeListener, 0, 3, 0, 0)
 			})
 		})
 
@@ -321,7 +413,7 @@ var _ = Describe("Upgrader", func() {
 
 			It("reports one orphaned instance", func() {
 				Expect(actualErr).NotTo(HaveOccurred())
-				hasReportedFinished(fakeListener, 1, 2, 0)
+				hasReportedFinished(fakeListener, 1, 2, 0, 0)
 			})
 		})
 
@@ -348,7 +440,7 @@ var _ = Describe("Upgrader", func() {
 
 				upgradeServiceInstance2CallCount := 0
 				for x := 0; x < brokerServicesClient.UpgradeInstanceCallCount(); x++ {
-					instance := brokerServicesClient.UpgradeInstanceArgsForCall(x)
+					instance, _ := brokerServicesClient.UpgradeInstanceArgsForCall(x)
 					if instance == serviceInstance2 {
 						upgradeServiceInstance2CallCount++
 					}
@@ -356,7 +448,7 @@ var _ = Describe("Upgrader", func() {
 
 				Expect(upgradeServiceInstance2CallCount).To(Equal(4), "number of service requests")
 				hasReportedRetries(fakeListener, 1, 1, 1, 0)
-				hasReportedFinished(fakeListener, 0, 3, 0)
+				hasReportedFinished(fakeListener, 0, 3, 0, 0)
 			})
 		})
 	})
@@ -452,10 +544,11 @@ func hasReportedProgress(fakeListener *fakes.FakeListener, expectedInterval time
 	Expect(deletedCount).To(Equal(expectedDeleted), "deleted")
 }
 
-func hasReportedFinished(fakeListener *fakes.FakeListener, expectedOrphans, expectedUpgraded, expectedDeleted int) {
+func hasReportedFinished(fakeListener *fakes.FakeListener, expectedOrphans, expectedUpgraded, expectedDeleted, expectedDeferred int) {
 	Expect(fakeListener.FinishedCallCount()).To(Equal(1))
-	orphanCount, upgradedCount, deletedCount := fakeListener.FinishedArgsForCall(0)
+	orphanCount, upgradedCount, deletedCount, deferredCount := fakeListener.FinishedArgsForCall(0)
 	Expect(orphanCount).To(Equal(expectedOrphans), "orphans")
 	Expect(upgradedCount).To(Equal(expectedUpgraded), "upgraded")
 	Expect(deletedCount).To(Equal(expectedDeleted), "deleted")
+	Expect(deferredCount).To(Equal(expectedDeferred), "deferred")
 }